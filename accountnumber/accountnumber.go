@@ -0,0 +1,163 @@
+package accountnumber
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+)
+
+// CheckDigit is the algorithm computing the check digits at the end of an account number
+type CheckDigit string
+
+const (
+	// Luhn appends one digit and catches every single-digit typo and most swaps of adjacent digits
+	Luhn CheckDigit = "LUHN"
+	// Mod97 appends two digits as ISO 7064 MOD 97-10, the scheme of IBANs, and also catches most
+	// double typos
+	Mod97 CheckDigit = "MOD97"
+)
+
+// Scheme describes the account numbers the wallet issues
+type Scheme struct {
+	// Prefix starts every number, e.g. a bank or product code
+	Prefix string
+	// Length is the number of digits, including the prefix and the check digits
+	Length int
+	// CheckDigit is the algorithm of the check digits
+	CheckDigit CheckDigit
+	// LegacyLengths are the lengths of all-digit numbers issued before the scheme, without check digits,
+	// that are still accepted. Numbers of the scheme's length are always checked.
+	LegacyLengths []int
+}
+
+// DefaultScheme issues 12-digit numbers starting with 8 and ending with a Luhn check digit. The demo
+// accounts predate it, so their 8- and 10-digit numbers are still accepted.
+func DefaultScheme() *Scheme {
+	return &Scheme{
+		Prefix:        "8",
+		Length:        12,
+		CheckDigit:    Luhn,
+		LegacyLengths: []int{8, 10},
+	}
+}
+
+var current = DefaultScheme()
+
+// Use makes s the scheme Valid checks numbers against
+func Use(s *Scheme) {
+	current = s
+}
+
+// Valid reports whether number is well formed under the scheme in use
+func Valid(number string) bool {
+	return current.Valid(number)
+}
+
+// Check reports whether the scheme itself is usable
+func (s *Scheme) Check() error {
+	if !allDigits(s.Prefix) {
+		return fmt.Errorf("account number prefix %q is not all digits", s.Prefix)
+	}
+	checkLen, ok := checkDigits[s.CheckDigit]
+	if !ok {
+		return fmt.Errorf("unknown check digit algorithm %q", s.CheckDigit)
+	}
+	// leave room for at least 6 random digits, a million numbers
+	if s.Length < len(s.Prefix)+checkLen+6 {
+		return fmt.Errorf("account number length %d is too short", s.Length)
+	}
+	// a legacy length equal to the scheme's would never be used, those numbers are always checked
+	if slices.Contains(s.LegacyLengths, s.Length) {
+		return fmt.Errorf("legacy account number length %d is the scheme's length", s.Length)
+	}
+	return nil
+}
+
+// checkDigits is the number of check digits of each algorithm
+var checkDigits = map[CheckDigit]int{
+	Luhn:  1,
+	Mod97: 2,
+}
+
+// Generate returns a new random number under the scheme. It may already be taken, callers must make sure
+// it is not.
+func (s *Scheme) Generate() (string, error) {
+	if err := s.Check(); err != nil {
+		return "", err
+	}
+	n := s.Length - len(s.Prefix) - checkDigits[s.CheckDigit]
+	digits := make([]byte, n)
+	for i := range digits {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		digits[i] = byte('0' + d.Int64())
+	}
+	// numbers never start with 0, so they keep their length when read as numbers
+	if s.Prefix == "" && digits[0] == '0' {
+		digits[0] = '1'
+	}
+	body := s.Prefix + string(digits)
+	return body + s.checkDigitsOf(body), nil
+}
+
+// Valid reports whether number is well formed: all digits and either one of the legacy lengths or the
+// scheme's length, prefix and correct check digits
+func (s *Scheme) Valid(number string) bool {
+	if number == "" || !allDigits(number) {
+		return false
+	}
+	if len(number) != s.Length {
+		return slices.Contains(s.LegacyLengths, len(number))
+	}
+	if !strings.HasPrefix(number, s.Prefix) {
+		return false
+	}
+	n := len(number) - checkDigits[s.CheckDigit]
+	return n > 0 && s.checkDigitsOf(number[:n]) == number[n:]
+}
+
+func (s *Scheme) checkDigitsOf(body string) string {
+	if s.CheckDigit == Mod97 {
+		return mod97(body)
+	}
+	return luhn(body)
+}
+
+// luhn returns the Luhn check digit of body
+func luhn(body string) string {
+	sum := 0
+	// the check digit takes the rightmost place, so doubling starts from the last digit of body
+	for i := len(body) - 1; i >= 0; i -= 2 {
+		d := int(body[i]-'0') * 2
+		if d > 9 {
+			d -= 9
+		}
+		sum += d
+		if i > 0 {
+			sum += int(body[i-1] - '0')
+		}
+	}
+	return string(rune('0' + (10-sum%10)%10))
+}
+
+// mod97 returns the two ISO 7064 MOD 97-10 check digits of body: body followed by them is 1 modulo 97
+func mod97(body string) string {
+	rem := 0
+	for _, c := range body + "00" {
+		rem = (rem*10 + int(c-'0')) % 97
+	}
+	return fmt.Sprintf("%02d", 98-rem)
+}
+
+func allDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
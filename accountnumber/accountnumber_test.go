@@ -0,0 +1,60 @@
+package accountnumber
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScheme_Valid(t *testing.T) {
+	luhnScheme := &Scheme{Prefix: "7", Length: 11, CheckDigit: Luhn}
+	mod97Scheme := &Scheme{Prefix: "", Length: 12, CheckDigit: Mod97}
+	legacyScheme := &Scheme{Prefix: "7", Length: 11, CheckDigit: Luhn, LegacyLengths: []int{8, 10}}
+	tests := []struct {
+		name   string
+		scheme *Scheme
+		number string
+		want   bool
+	}{
+		{name: "luhn - valid", scheme: luhnScheme, number: "79927398713", want: true},
+		{name: "luhn - typo", scheme: luhnScheme, number: "79927398213"},
+		{name: "luhn - adjacent digits swapped", scheme: luhnScheme, number: "79927389713"},
+		{name: "luhn - wrong prefix", scheme: luhnScheme, number: "19927398713"},
+		{name: "mod97 - valid", scheme: mod97Scheme, number: "123456789092", want: true},
+		{name: "mod97 - typo", scheme: mod97Scheme, number: "123456789192"},
+		{name: "not digits", scheme: luhnScheme, number: "7992739871a"},
+		{name: "empty", scheme: legacyScheme, number: ""},
+		{name: "other length without legacy", scheme: luhnScheme, number: "12345678"},
+		{name: "legacy length", scheme: legacyScheme, number: "12345678", want: true},
+		{name: "length neither legacy nor the scheme's", scheme: legacyScheme, number: "123456789"},
+		{name: "legacy never skips the check of the scheme's length", scheme: legacyScheme, number: "79927398213"},
+		{name: "legacy must still be digits", scheme: legacyScheme, number: "1000000001-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.scheme.Valid(tt.number); got != tt.want {
+				t.Errorf("Valid(%s) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheme_Generate(t *testing.T) {
+	for _, s := range []*Scheme{DefaultScheme(), {Prefix: "60", Length: 14, CheckDigit: Mod97}, {Length: 10, CheckDigit: Luhn}} {
+		for i := 0; i < 100; i++ {
+			number, err := s.Generate()
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			if len(number) != s.Length || !strings.HasPrefix(number, s.Prefix) || number[0] == '0' || !s.Valid(number) {
+				t.Fatalf("Generate() = %s, not valid under %+v", number, s)
+			}
+		}
+	}
+
+	if _, err := (&Scheme{Prefix: "8", Length: 8, CheckDigit: Mod97}).Generate(); err == nil {
+		t.Errorf("Generate() on a scheme too short to hold random digits succeeded")
+	}
+	if _, err := (&Scheme{Prefix: "8", Length: 12, CheckDigit: Luhn, LegacyLengths: []int{12}}).Generate(); err == nil {
+		t.Errorf("Generate() on a scheme whose legacy length is its own succeeded")
+	}
+}
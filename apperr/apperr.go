@@ -0,0 +1,181 @@
+package apperr
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Code is a stable, machine-readable error code returned to clients
+type Code string
+
+const (
+	CodeInvalidRequest               Code = "INVALID_REQUEST"
+	CodeValidationFailed             Code = "VALIDATION_FAILED"
+	CodeInvalidNextToken             Code = "INVALID_NEXT_TOKEN"
+	CodeAccountNotFound              Code = "ACCOUNT_NOT_FOUND"
+	CodeInsufficientBalance          Code = "INSUFFICIENT_BALANCE"
+	CodeInvalidAmount                Code = "INVALID_AMOUNT"
+	CodeInvalidCurrency              Code = "INVALID_CURRENCY"
+	CodeInvalidSourceAccount         Code = "INVALID_SOURCE_ACCOUNT"
+	CodeInvalidDestinationAccount    Code = "INVALID_DESTINATION_ACCOUNT"
+	CodeIdempotencyKeyReused         Code = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyRequestInProgress Code = "IDEMPOTENCY_REQUEST_IN_PROGRESS"
+	CodeMissingReason                Code = "MISSING_REASON"
+	CodeFxRateUnavailable            Code = "FX_RATE_UNAVAILABLE"
+	CodeFxQuoteNotFound              Code = "FX_QUOTE_NOT_FOUND"
+	CodeFxQuoteExpired               Code = "FX_QUOTE_EXPIRED"
+	CodeFxQuoteMismatch              Code = "FX_QUOTE_MISMATCH"
+	CodeAmountBelowMinimum           Code = "AMOUNT_BELOW_MINIMUM"
+	CodeAmountAboveMaximum           Code = "AMOUNT_ABOVE_MAXIMUM"
+	CodeCumulativeLimitExceeded      Code = "CUMULATIVE_LIMIT_EXCEEDED"
+	CodeVelocityLimitExceeded        Code = "VELOCITY_LIMIT_EXCEEDED"
+	CodeBalanceLimitExceeded         Code = "BALANCE_LIMIT_EXCEEDED"
+	CodeHoldNotFound                 Code = "HOLD_NOT_FOUND"
+	CodeHoldNotActive                Code = "HOLD_NOT_ACTIVE"
+	CodeHoldAmountExceeded           Code = "HOLD_AMOUNT_EXCEEDED"
+	CodeInvalidExpiry                Code = "INVALID_EXPIRY"
+	CodeTransferNotFound             Code = "TRANSFER_NOT_FOUND"
+	CodeTransferNotReversible        Code = "TRANSFER_NOT_REVERSIBLE"
+	CodeRefundAmountExceeded         Code = "REFUND_AMOUNT_EXCEEDED"
+	CodeInvalidExecutionDate         Code = "INVALID_EXECUTION_DATE"
+	CodeTransferNotScheduled         Code = "TRANSFER_NOT_SCHEDULED"
+	CodeInvalidSchedule              Code = "INVALID_SCHEDULE"
+	CodeStandingInstructionNotFound  Code = "STANDING_INSTRUCTION_NOT_FOUND"
+	CodeInvalidInstructionStatus     Code = "INVALID_INSTRUCTION_STATUS"
+	CodeBatchNotFound                Code = "BATCH_NOT_FOUND"
+	CodeBatchRolledBack              Code = "BATCH_ROLLED_BACK"
+	CodeAccountClosed                Code = "ACCOUNT_CLOSED"
+	CodeAccountDebitFrozen           Code = "ACCOUNT_DEBIT_FROZEN"
+	CodeAccountCreditFrozen          Code = "ACCOUNT_CREDIT_FROZEN"
+	CodeAccountNotEmpty              Code = "ACCOUNT_NOT_EMPTY"
+	CodeInvalidAccountStatus         Code = "INVALID_ACCOUNT_STATUS"
+	CodeUserNotFound                 Code = "USER_NOT_FOUND"
+	CodeUserAlreadyExists            Code = "USER_ALREADY_EXISTS"
+	CodeInvalidUserStatus            Code = "INVALID_USER_STATUS"
+	CodeUserAccountNotFound          Code = "USER_ACCOUNT_NOT_FOUND"
+	CodeLastAccountOwner             Code = "LAST_ACCOUNT_OWNER"
+	CodeAccountAccessDenied          Code = "ACCOUNT_ACCESS_DENIED"
+	CodeUnauthenticated              Code = "UNAUTHENTICATED"
+	CodeInsufficientScope            Code = "INSUFFICIENT_SCOPE"
+	CodeAPIKeyNotFound               Code = "API_KEY_NOT_FOUND"
+	CodeAPIKeyNotActive              Code = "API_KEY_NOT_ACTIVE"
+	CodeInternal                     Code = "INTERNAL_ERROR"
+)
+
+var statuses = map[Code]int{
+	CodeInvalidRequest:               http.StatusBadRequest,
+	CodeValidationFailed:             http.StatusBadRequest,
+	CodeInvalidNextToken:             http.StatusBadRequest,
+	CodeAccountNotFound:              http.StatusNotFound,
+	CodeInsufficientBalance:          http.StatusUnprocessableEntity,
+	CodeInvalidAmount:                http.StatusBadRequest,
+	CodeInvalidCurrency:              http.StatusBadRequest,
+	CodeInvalidSourceAccount:         http.StatusNotFound,
+	CodeInvalidDestinationAccount:    http.StatusNotFound,
+	CodeIdempotencyKeyReused:         http.StatusUnprocessableEntity,
+	CodeIdempotencyRequestInProgress: http.StatusConflict,
+	CodeMissingReason:                http.StatusBadRequest,
+	CodeFxRateUnavailable:            http.StatusUnprocessableEntity,
+	CodeFxQuoteNotFound:              http.StatusNotFound,
+	CodeFxQuoteExpired:               http.StatusUnprocessableEntity,
+	CodeFxQuoteMismatch:              http.StatusUnprocessableEntity,
+	CodeAmountBelowMinimum:           http.StatusUnprocessableEntity,
+	CodeAmountAboveMaximum:           http.StatusUnprocessableEntity,
+	CodeCumulativeLimitExceeded:      http.StatusUnprocessableEntity,
+	CodeVelocityLimitExceeded:        http.StatusTooManyRequests,
+	CodeBalanceLimitExceeded:         http.StatusUnprocessableEntity,
+	CodeHoldNotFound:                 http.StatusNotFound,
+	CodeHoldNotActive:                http.StatusUnprocessableEntity,
+	CodeHoldAmountExceeded:           http.StatusUnprocessableEntity,
+	CodeInvalidExpiry:                http.StatusBadRequest,
+	CodeTransferNotFound:             http.StatusNotFound,
+	CodeTransferNotReversible:        http.StatusUnprocessableEntity,
+	CodeRefundAmountExceeded:         http.StatusUnprocessableEntity,
+	CodeInvalidExecutionDate:         http.StatusBadRequest,
+	CodeTransferNotScheduled:         http.StatusUnprocessableEntity,
+	CodeInvalidSchedule:              http.StatusBadRequest,
+	CodeStandingInstructionNotFound:  http.StatusNotFound,
+	CodeInvalidInstructionStatus:     http.StatusUnprocessableEntity,
+	CodeBatchNotFound:                http.StatusNotFound,
+	CodeBatchRolledBack:              http.StatusUnprocessableEntity,
+	CodeAccountClosed:                http.StatusUnprocessableEntity,
+	CodeAccountDebitFrozen:           http.StatusUnprocessableEntity,
+	CodeAccountCreditFrozen:          http.StatusUnprocessableEntity,
+	CodeAccountNotEmpty:              http.StatusUnprocessableEntity,
+	CodeInvalidAccountStatus:         http.StatusUnprocessableEntity,
+	CodeUserNotFound:                 http.StatusNotFound,
+	CodeUserAlreadyExists:            http.StatusConflict,
+	CodeInvalidUserStatus:            http.StatusUnprocessableEntity,
+	CodeUserAccountNotFound:          http.StatusNotFound,
+	CodeLastAccountOwner:             http.StatusUnprocessableEntity,
+	CodeAccountAccessDenied:          http.StatusForbidden,
+	CodeUnauthenticated:              http.StatusUnauthorized,
+	CodeInsufficientScope:            http.StatusForbidden,
+	CodeAPIKeyNotFound:               http.StatusNotFound,
+	CodeAPIKeyNotActive:              http.StatusUnprocessableEntity,
+	CodeInternal:                     http.StatusInternalServerError,
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field string `json:"field"`
+	Rule  string `json:"rule"`
+	Param string `json:"param,omitempty"`
+}
+
+// Error is an error with a code from the catalog. Two errors with the same code match with errors.Is,
+// so sentinels can be compared against copies carrying a cause or field details.
+type Error struct {
+	Code   Code
+	Fields []FieldError
+	cause  error
+}
+
+func New(code Code) *Error {
+	return &Error{Code: code}
+}
+
+// Error returns the English message of the code, followed by the cause if there is one
+func (e *Error) Error() string {
+	msg := Message(e.Code, DefaultLanguage)
+	if e.cause != nil {
+		return msg + ": " + e.cause.Error()
+	}
+	return msg
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+func (e *Error) Is(target error) bool {
+	var t *Error
+	return errors.As(target, &t) && t.Code == e.Code
+}
+
+// Status returns the HTTP status the code maps to
+func (e *Error) Status() int {
+	if status, ok := statuses[e.Code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// Wrap returns a copy of e caused by cause. The cause is kept for logging and never shown to clients.
+func (e *Error) Wrap(cause error) *Error {
+	return &Error{Code: e.Code, Fields: e.Fields, cause: cause}
+}
+
+// WithFields returns a copy of e carrying per-field details
+func (e *Error) WithFields(fields ...FieldError) *Error {
+	return &Error{Code: e.Code, Fields: fields, cause: e.cause}
+}
+
+// From returns err as an *Error. Errors outside the catalog become INTERNAL_ERROR wrapping err.
+func From(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+	return New(CodeInternal).Wrap(err)
+}
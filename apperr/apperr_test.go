@@ -0,0 +1,71 @@
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestError_Is(t *testing.T) {
+	sentinel := New(CodeInsufficientBalance)
+	wrapped := fmt.Errorf("posting leg: %w", sentinel.Wrap(errors.New("balance 100 < 500")))
+
+	if !errors.Is(wrapped, sentinel) {
+		t.Errorf("errors.Is(%v, %v) = false, want true", wrapped, sentinel)
+	}
+	if errors.Is(wrapped, New(CodeInvalidAmount)) {
+		t.Errorf("errors.Is(%v, INVALID_AMOUNT) = true, want false", wrapped)
+	}
+	if got := From(wrapped); got.Code != CodeInsufficientBalance || got.Status() != http.StatusUnprocessableEntity {
+		t.Errorf("From() = %v/%d, want %v/%d", got.Code, got.Status(), CodeInsufficientBalance, http.StatusUnprocessableEntity)
+	}
+}
+
+func TestFrom_unknownError(t *testing.T) {
+	cause := errors.New("connection refused")
+	got := From(cause)
+	if got.Code != CodeInternal || got.Status() != http.StatusInternalServerError {
+		t.Errorf("From() = %v/%d, want %v/%d", got.Code, got.Status(), CodeInternal, http.StatusInternalServerError)
+	}
+	if !errors.Is(got, cause) {
+		t.Errorf("From() does not wrap its cause")
+	}
+}
+
+func TestCatalogIsComplete(t *testing.T) {
+	for code := range statuses {
+		for _, lang := range supported {
+			if _, ok := messages[code][lang]; !ok {
+				t.Errorf("no %s message for %s", lang, code)
+			}
+		}
+	}
+	for code := range messages {
+		if _, ok := statuses[code]; !ok {
+			t.Errorf("no HTTP status for %s", code)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		acceptLanguage string
+		want           language.Tag
+	}{
+		{acceptLanguage: "", want: language.English},
+		{acceptLanguage: "ms", want: language.Malay},
+		{acceptLanguage: "ms-MY,en;q=0.5", want: language.Malay},
+		{acceptLanguage: "zh-CN,en;q=0.5", want: language.English},
+		{acceptLanguage: "not a language", want: language.English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.acceptLanguage, func(t *testing.T) {
+			if got := Negotiate(tt.acceptLanguage); got != tt.want {
+				t.Errorf("Negotiate(%q) = %v, want %v", tt.acceptLanguage, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,338 @@
+package apperr
+
+import (
+	"fmt"
+	"golang.org/x/text/language"
+	"strings"
+)
+
+// DefaultLanguage is used when the client accepts none of the supported languages
+var DefaultLanguage = language.English
+
+var supported = []language.Tag{language.English, language.Malay}
+
+var matcher = language.NewMatcher(supported)
+
+var messages = map[Code]map[language.Tag]string{
+	CodeInvalidRequest: {
+		language.English: "The request body is malformed.",
+		language.Malay:   "Kandungan permintaan tidak sah.",
+	},
+	CodeValidationFailed: {
+		language.English: "One or more fields are invalid.",
+		language.Malay:   "Satu atau lebih medan tidak sah.",
+	},
+	CodeInvalidNextToken: {
+		language.English: "The pagination token is invalid.",
+		language.Malay:   "Token penomboran tidak sah.",
+	},
+	CodeAccountNotFound: {
+		language.English: "The account does not exist.",
+		language.Malay:   "Akaun tidak wujud.",
+	},
+	CodeInsufficientBalance: {
+		language.English: "The account balance is insufficient.",
+		language.Malay:   "Baki akaun tidak mencukupi.",
+	},
+	CodeInvalidAmount: {
+		language.English: "The amount is invalid.",
+		language.Malay:   "Jumlah tidak sah.",
+	},
+	CodeInvalidCurrency: {
+		language.English: "The currency is not supported.",
+		language.Malay:   "Mata wang tidak disokong.",
+	},
+	CodeInvalidSourceAccount: {
+		language.English: "The source account does not exist.",
+		language.Malay:   "Akaun sumber tidak wujud.",
+	},
+	CodeInvalidDestinationAccount: {
+		language.English: "The destination account does not exist.",
+		language.Malay:   "Akaun destinasi tidak wujud.",
+	},
+	CodeIdempotencyKeyReused: {
+		language.English: "The idempotency key was already used for a different request.",
+		language.Malay:   "Kunci idempotensi telah digunakan untuk permintaan lain.",
+	},
+	CodeIdempotencyRequestInProgress: {
+		language.English: "A request with this idempotency key is still being processed.",
+		language.Malay:   "Permintaan dengan kunci idempotensi ini masih diproses.",
+	},
+	CodeMissingReason: {
+		language.English: "An audit reason is required.",
+		language.Malay:   "Sebab audit diperlukan.",
+	},
+	CodeFxRateUnavailable: {
+		language.English: "No exchange rate is available for this currency pair.",
+		language.Malay:   "Tiada kadar pertukaran bagi pasangan mata wang ini.",
+	},
+	CodeFxQuoteNotFound: {
+		language.English: "The exchange rate quote does not exist.",
+		language.Malay:   "Sebut harga kadar pertukaran tidak wujud.",
+	},
+	CodeFxQuoteExpired: {
+		language.English: "The exchange rate quote has expired or was already used.",
+		language.Malay:   "Sebut harga kadar pertukaran telah tamat tempoh atau telah digunakan.",
+	},
+	CodeFxQuoteMismatch: {
+		language.English: "The transfer does not match the exchange rate quote.",
+		language.Malay:   "Pemindahan tidak sepadan dengan sebut harga kadar pertukaran.",
+	},
+	CodeAmountBelowMinimum: {
+		language.English: "The amount is below the minimum allowed for this transaction.",
+		language.Malay:   "Jumlah adalah di bawah minimum yang dibenarkan bagi transaksi ini.",
+	},
+	CodeAmountAboveMaximum: {
+		language.English: "The amount is above the maximum allowed for this transaction.",
+		language.Malay:   "Jumlah melebihi maksimum yang dibenarkan bagi transaksi ini.",
+	},
+	CodeCumulativeLimitExceeded: {
+		language.English: "This transaction would exceed the daily or monthly limit of the account.",
+		language.Malay:   "Transaksi ini akan melebihi had harian atau bulanan akaun.",
+	},
+	CodeVelocityLimitExceeded: {
+		language.English: "Too many transactions in a short time. Please try again later.",
+		language.Malay:   "Terlalu banyak transaksi dalam masa yang singkat. Sila cuba lagi kemudian.",
+	},
+	CodeBalanceLimitExceeded: {
+		language.English: "This transaction would take the account above its maximum balance.",
+		language.Malay:   "Transaksi ini akan menyebabkan baki akaun melebihi had maksimum.",
+	},
+	CodeHoldNotFound: {
+		language.English: "The hold does not exist.",
+		language.Malay:   "Sekatan dana tidak wujud.",
+	},
+	CodeHoldNotActive: {
+		language.English: "The hold has already been captured, released or has expired.",
+		language.Malay:   "Sekatan dana telah ditangkap, dilepaskan atau tamat tempoh.",
+	},
+	CodeHoldAmountExceeded: {
+		language.English: "The amount is more than what remains on the hold.",
+		language.Malay:   "Jumlah melebihi baki sekatan dana.",
+	},
+	CodeInvalidExpiry: {
+		language.English: "The expiry is outside the allowed range.",
+		language.Malay:   "Tempoh tamat berada di luar julat yang dibenarkan.",
+	},
+	CodeTransferNotFound: {
+		language.English: "The transfer does not exist.",
+		language.Malay:   "Pemindahan tidak wujud.",
+	},
+	CodeTransferNotReversible: {
+		language.English: "This transfer cannot be reversed.",
+		language.Malay:   "Pemindahan ini tidak boleh diterbalikkan.",
+	},
+	CodeRefundAmountExceeded: {
+		language.English: "The amount is more than what remains to be refunded on the transfer.",
+		language.Malay:   "Jumlah melebihi baki yang boleh dipulangkan bagi pemindahan ini.",
+	},
+	CodeInvalidExecutionDate: {
+		language.English: "The execution date is not allowed for this transfer.",
+		language.Malay:   "Tarikh pelaksanaan tidak dibenarkan bagi pemindahan ini.",
+	},
+	CodeTransferNotScheduled: {
+		language.English: "The transfer is no longer scheduled.",
+		language.Malay:   "Pemindahan ini tidak lagi dijadualkan.",
+	},
+	CodeInvalidSchedule: {
+		language.English: "The schedule is invalid or has no occurrence.",
+		language.Malay:   "Jadual tidak sah atau tiada sebarang kejadian.",
+	},
+	CodeStandingInstructionNotFound: {
+		language.English: "The standing instruction does not exist.",
+		language.Malay:   "Arahan tetap tidak wujud.",
+	},
+	CodeInvalidInstructionStatus: {
+		language.English: "The standing instruction cannot be changed in its current status.",
+		language.Malay:   "Arahan tetap tidak boleh diubah dalam status semasanya.",
+	},
+	CodeBatchNotFound: {
+		language.English: "The batch does not exist.",
+		language.Malay:   "Kelompok tidak wujud.",
+	},
+	CodeBatchRolledBack: {
+		language.English: "Not made because another transfer of the batch failed.",
+		language.Malay:   "Tidak dibuat kerana pemindahan lain dalam kelompok ini gagal.",
+	},
+	CodeAccountClosed: {
+		language.English: "The account is closed.",
+		language.Malay:   "Akaun telah ditutup.",
+	},
+	CodeAccountDebitFrozen: {
+		language.English: "Debits from the account are frozen.",
+		language.Malay:   "Debit daripada akaun telah dibekukan.",
+	},
+	CodeAccountCreditFrozen: {
+		language.English: "Credits to the account are frozen.",
+		language.Malay:   "Kredit ke akaun telah dibekukan.",
+	},
+	CodeAccountNotEmpty: {
+		language.English: "The account still has a balance or active holds.",
+		language.Malay:   "Akaun masih mempunyai baki atau sekatan dana aktif.",
+	},
+	CodeInvalidAccountStatus: {
+		language.English: "The account cannot be changed in its current status.",
+		language.Malay:   "Akaun tidak boleh diubah dalam status semasanya.",
+	},
+	CodeUserNotFound: {
+		language.English: "The user does not exist.",
+		language.Malay:   "Pengguna tidak wujud.",
+	},
+	CodeUserAlreadyExists: {
+		language.English: "A user with this email already exists.",
+		language.Malay:   "Pengguna dengan e-mel ini sudah wujud.",
+	},
+	CodeInvalidUserStatus: {
+		language.English: "The user cannot be changed in their current status.",
+		language.Malay:   "Pengguna tidak boleh diubah dalam status semasanya.",
+	},
+	CodeUserAccountNotFound: {
+		language.English: "The account is not linked to the user.",
+		language.Malay:   "Akaun tidak dipautkan kepada pengguna.",
+	},
+	CodeLastAccountOwner: {
+		language.English: "The account must keep at least one owner.",
+		language.Malay:   "Akaun mesti mempunyai sekurang-kurangnya seorang pemilik.",
+	},
+	CodeAccountAccessDenied: {
+		language.English: "The user is not allowed to debit the account.",
+		language.Malay:   "Pengguna tidak dibenarkan mendebit akaun ini.",
+	},
+	CodeUnauthenticated: {
+		language.English: "A valid access token is required.",
+		language.Malay:   "Token akses yang sah diperlukan.",
+	},
+	CodeInsufficientScope: {
+		language.English: "The access token does not allow this operation.",
+		language.Malay:   "Token akses tidak membenarkan operasi ini.",
+	},
+	CodeAPIKeyNotFound: {
+		language.English: "The API key does not exist.",
+		language.Malay:   "Kunci API tidak wujud.",
+	},
+	CodeAPIKeyNotActive: {
+		language.English: "The API key has been revoked or has expired.",
+		language.Malay:   "Kunci API telah dibatalkan atau tamat tempoh.",
+	},
+	CodeInternal: {
+		language.English: "Something went wrong. Please try again later.",
+		language.Malay:   "Ralat telah berlaku. Sila cuba lagi kemudian.",
+	},
+}
+
+// Negotiate picks the supported language that best matches an Accept-Language header value
+func Negotiate(acceptLanguage string) language.Tag {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return DefaultLanguage
+	}
+	_, i, _ := matcher.Match(tags...)
+	return supported[i]
+}
+
+// Message returns the human-readable message of code in lang, falling back to English
+func Message(code Code, lang language.Tag) string {
+	byLang, ok := messages[code]
+	if !ok {
+		byLang = messages[CodeInternal]
+	}
+	if msg, ok := byLang[lang]; ok {
+		return msg
+	}
+	return byLang[DefaultLanguage]
+}
+
+var ruleMessages = map[string]map[language.Tag]string{
+	"required": {
+		language.English: "is required",
+		language.Malay:   "diperlukan",
+	},
+	"required_if": {
+		language.English: "is required",
+		language.Malay:   "diperlukan",
+	},
+	"gt": {
+		language.English: "must be greater than %s",
+		language.Malay:   "mestilah lebih besar daripada %s",
+	},
+	"gte": {
+		language.English: "must be at least %s",
+		language.Malay:   "mestilah sekurang-kurangnya %s",
+	},
+	"lt": {
+		language.English: "must be less than %s",
+		language.Malay:   "mestilah kurang daripada %s",
+	},
+	"lte": {
+		language.English: "must be at most %s",
+		language.Malay:   "mestilah tidak melebihi %s",
+	},
+	"min": {
+		language.English: "must be at least %s",
+		language.Malay:   "mestilah sekurang-kurangnya %s",
+	},
+	"max": {
+		language.English: "must be at most %s",
+		language.Malay:   "mestilah tidak melebihi %s",
+	},
+	"len": {
+		language.English: "must be %s characters long",
+		language.Malay:   "mestilah %s aksara",
+	},
+	"oneof": {
+		language.English: "must be one of: %s",
+		language.Malay:   "mestilah salah satu daripada: %s",
+	},
+	"currency": {
+		language.English: "is not a supported currency",
+		language.Malay:   "bukan mata wang yang disokong",
+	},
+	"accountnumber": {
+		language.English: "is not a valid account number",
+		language.Malay:   "bukan nombor akaun yang sah",
+	},
+	"numeric": {
+		language.English: "must be a whole number",
+		language.Malay:   "mestilah nombor bulat",
+	},
+	"account": {
+		language.English: "does not exist or is not held in the currency",
+		language.Malay:   "tidak wujud atau tidak dipegang dalam mata wang tersebut",
+	},
+	"email": {
+		language.English: "is not a valid email address",
+		language.Malay:   "bukan alamat e-mel yang sah",
+	},
+	"e164": {
+		language.English: "must be a phone number in international format, e.g. +60123456789",
+		language.Malay:   "mestilah nombor telefon dalam format antarabangsa, cth. +60123456789",
+	},
+	"uuid": {
+		language.English: "must be a UUID",
+		language.Malay:   "mestilah UUID",
+	},
+	"scope": {
+		language.English: "is not a known scope",
+		language.Malay:   "bukan skop yang dikenali",
+	},
+	"": {
+		language.English: "is invalid",
+		language.Malay:   "tidak sah",
+	},
+}
+
+// FieldMessage returns the human-readable message for a field that failed validation rule with param
+func FieldMessage(f FieldError, lang language.Tag) string {
+	byLang, ok := ruleMessages[f.Rule]
+	if !ok {
+		byLang = ruleMessages[""]
+	}
+	msg, ok := byLang[lang]
+	if !ok {
+		msg = byLang[DefaultLanguage]
+	}
+	if strings.Contains(msg, "%s") {
+		return fmt.Sprintf(msg, f.Param)
+	}
+	return msg
+}
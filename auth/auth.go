@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Scopes granted to tokens. Each route requires one of them.
+const (
+	ScopeAccountsRead   = "accounts:read"
+	ScopeAccountsWrite  = "accounts:write"
+	ScopeTransfersRead  = "transfers:read"
+	ScopeTransfersWrite = "transfers:write"
+	ScopeUsersRead      = "users:read"
+	ScopeUsersWrite     = "users:write"
+	ScopeAdmin          = "admin"
+)
+
+var scopes = map[string]bool{
+	ScopeAccountsRead:   true,
+	ScopeAccountsWrite:  true,
+	ScopeTransfersRead:  true,
+	ScopeTransfersWrite: true,
+	ScopeUsersRead:      true,
+	ScopeUsersWrite:     true,
+	ScopeAdmin:          true,
+}
+
+// KnownScope reports whether scope is one routes require
+func KnownScope(scope string) bool {
+	return scopes[scope]
+}
+
+// Principal is who a request is made by, as established by its credentials
+type Principal struct {
+	// Subject is the user ID of a user, or the client ID of a service acting for itself
+	Subject string
+	// ClientID is the application the credentials were issued to
+	ClientID string
+	Scopes   []string
+}
+
+// HasScope reports whether the principal was granted scope
+func (p *Principal) HasScope(scope string) bool {
+	for _, s := range p.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// UserID returns the user the principal acts for, or an empty string for a service acting for itself.
+// Services get tokens issued to themselves, whose subject is their own client ID.
+func (p *Principal) UserID() string {
+	if p.ClientID != "" && p.Subject == p.ClientID {
+		return ""
+	}
+	return p.Subject
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal ctx carries, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+type Config struct {
+	// HMACSecretFile holds the shared secret HS256 tokens are signed with. Leave it empty to reject HS256.
+	HMACSecretFile string
+	// RSAPublicKeyFile holds a PEM public key RS256 tokens are signed with
+	RSAPublicKeyFile string
+	// JWKSFile holds a JSON Web Key Set of RSA public keys RS256 tokens are signed with, picked by the kid
+	// of the token
+	JWKSFile string
+	// Issuer, if set, must be the iss of every token
+	Issuer string
+	// Audience, if set, must be among the aud of every token
+	Audience string
+	// Leeway absorbs clock skew with the issuer when checking exp and nbf
+	Leeway time.Duration
+}
+
+// Environment variables ConfigFromEnv reads
+const (
+	EnvHMACSecretFile   = "WALLET_JWT_HMAC_SECRET_FILE"
+	EnvRSAPublicKeyFile = "WALLET_JWT_RSA_PUBLIC_KEY_FILE"
+	EnvJWKSFile         = "WALLET_JWT_JWKS_FILE"
+	EnvIssuer           = "WALLET_JWT_ISSUER"
+	EnvAudience         = "WALLET_JWT_AUDIENCE"
+	EnvLeeway           = "WALLET_JWT_LEEWAY"
+)
+
+// DefaultConfig configures no signing key, so a verifier built from it refuses to start. Keys come from the
+// deployment, see ConfigFromEnv.
+func DefaultConfig() *Config {
+	return &Config{
+		Audience: "wallet",
+		Leeway:   30 * time.Second,
+	}
+}
+
+// ConfigFromEnv returns DefaultConfig overridden by the WALLET_JWT_* environment variables that are set
+func ConfigFromEnv() (*Config, error) {
+	cfg := DefaultConfig()
+	for env, field := range map[string]*string{
+		EnvHMACSecretFile:   &cfg.HMACSecretFile,
+		EnvRSAPublicKeyFile: &cfg.RSAPublicKeyFile,
+		EnvJWKSFile:         &cfg.JWKSFile,
+		EnvIssuer:           &cfg.Issuer,
+		EnvAudience:         &cfg.Audience,
+	} {
+		if value, ok := os.LookupEnv(env); ok {
+			*field = value
+		}
+	}
+	if value, ok := os.LookupEnv(EnvLeeway); ok {
+		leeway, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Leeway = leeway
+	}
+	return cfg, nil
+}
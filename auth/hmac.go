@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Headers of a request signed with an API key
+const (
+	HeaderKeyID     = "X-Api-Key"
+	HeaderTimestamp = "X-Timestamp" // Unix time in seconds the request was signed at
+	HeaderNonce     = "X-Nonce"     // random string used once per key, 16 to 64 characters
+	HeaderSignature = "X-Signature" // hex HMAC-SHA256 of StringToSign
+)
+
+// StringToSign is what a request's signature covers: its method, path with query, timestamp, nonce and the
+// hex SHA-256 of its body, one per line
+func StringToSign(method string, requestURI string, timestamp int64, nonce string, body []byte) string {
+	bodyHash := sha256.Sum256(body)
+	return strings.Join([]string{
+		strings.ToUpper(method),
+		requestURI,
+		strconv.FormatInt(timestamp, 10),
+		nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// SignRequest returns the hex signature of a request under secret
+func SignRequest(secret []byte, method string, requestURI string, timestamp int64, nonce string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(StringToSign(method, requestURI, timestamp, nonce, body)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether signature is the signature of the request under secret, in constant time
+func ValidSignature(secret []byte, signature string, method string, requestURI string, timestamp int64, nonce string, body []byte) bool {
+	got, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	want, _ := hex.DecodeString(SignRequest(secret, method, requestURI, timestamp, nonce, body))
+	return hmac.Equal(got, want)
+}
@@ -0,0 +1,84 @@
+package auth
+
+import "testing"
+
+func TestValidSignature(t *testing.T) {
+	key := []byte("sk_test-secret")
+	body := []byte(`{"amount":"10.00"}`)
+	signature := SignRequest(key, "post", "/v1/transfers?dryRun=true", 1772366400, "0123456789abcdef", body)
+
+	tests := []struct {
+		name       string
+		key        []byte
+		signature  string
+		method     string
+		requestURI string
+		timestamp  int64
+		body       string
+		want       bool
+	}{
+		{
+			name:       "happy path - method is case insensitive",
+			key:        key,
+			signature:  signature,
+			method:     "POST",
+			requestURI: "/v1/transfers?dryRun=true",
+			timestamp:  1772366400,
+			body:       string(body),
+			want:       true,
+		},
+		{
+			name:       "error - other secret",
+			key:        []byte("sk_other"),
+			signature:  signature,
+			method:     "POST",
+			requestURI: "/v1/transfers?dryRun=true",
+			timestamp:  1772366400,
+			body:       string(body),
+		},
+		{
+			name:       "error - query changed",
+			key:        key,
+			signature:  signature,
+			method:     "POST",
+			requestURI: "/v1/transfers",
+			timestamp:  1772366400,
+			body:       string(body),
+		},
+		{
+			name:       "error - timestamp changed",
+			key:        key,
+			signature:  signature,
+			method:     "POST",
+			requestURI: "/v1/transfers?dryRun=true",
+			timestamp:  1772366401,
+			body:       string(body),
+		},
+		{
+			name:       "error - body changed",
+			key:        key,
+			signature:  signature,
+			method:     "POST",
+			requestURI: "/v1/transfers?dryRun=true",
+			timestamp:  1772366400,
+			body:       `{"amount":"1000.00"}`,
+		},
+		{
+			name:       "error - signature not hex",
+			key:        key,
+			signature:  "not-hex",
+			method:     "POST",
+			requestURI: "/v1/transfers?dryRun=true",
+			timestamp:  1772366400,
+			body:       string(body),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidSignature(tt.key, tt.signature, tt.method, tt.requestURI, tt.timestamp, "0123456789abcdef", []byte(tt.body))
+			if got != tt.want {
+				t.Errorf("ValidSignature() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
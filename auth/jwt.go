@@ -0,0 +1,345 @@
+package auth
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+)
+
+// minHMACSecretLen is the shortest HS256 secret accepted, the size of the SHA-256 output
+const minHMACSecretLen = 32
+
+var (
+	ErrMalformedToken       = errors.New("malformed token")
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrUnknownKey           = errors.New("unknown signing key")
+	ErrInvalidSignature     = errors.New("invalid signature")
+	ErrTokenExpired         = errors.New("token expired")
+	ErrTokenNotYetValid     = errors.New("token not valid yet")
+	ErrInvalidIssuer        = errors.New("invalid issuer")
+	ErrInvalidAudience      = errors.New("invalid audience")
+	ErrMissingSubject       = errors.New("missing subject")
+	ErrMissingClient        = errors.New("missing client_id or azp")
+)
+
+// Claims are the JWT claims the wallet reads. Scopes come from the space-separated scope claim of OAuth 2.0
+// or the scp array some issuers use instead.
+type Claims struct {
+	Subject         string   `json:"sub"`
+	Issuer          string   `json:"iss,omitempty"`
+	Audience        Audience `json:"aud,omitempty"`
+	ExpiresAt       int64    `json:"exp"`
+	NotBefore       int64    `json:"nbf,omitempty"`
+	IssuedAt        int64    `json:"iat,omitempty"`
+	Scope           string   `json:"scope,omitempty"`
+	Scp             []string `json:"scp,omitempty"`
+	ClientID        string   `json:"client_id,omitempty"`
+	AuthorizedParty string   `json:"azp,omitempty"`
+}
+
+// Audience is the aud claim, a single string or an array of them
+type Audience []string
+
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid,omitempty"`
+	Typ string `json:"typ,omitempty"`
+}
+
+// Verifier checks JWTs and turns them into principals. HS256 tokens are checked against the HMAC secret and
+// RS256 tokens against the RSA keys, never the other way round.
+type Verifier struct {
+	hmacSecret []byte
+	// rsaKeys are the RSA public keys by kid. A key read from a PEM file has an empty kid.
+	rsaKeys  map[string]*rsa.PublicKey
+	issuer   string
+	audience string
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+// NewVerifier loads the keys of cfg. At least one key must be configured.
+func NewVerifier(cfg *Config) (*Verifier, error) {
+	v := &Verifier{
+		rsaKeys:  map[string]*rsa.PublicKey{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+		leeway:   cfg.Leeway,
+		now:      time.Now,
+	}
+	if cfg.HMACSecretFile != "" {
+		secret, err := os.ReadFile(cfg.HMACSecretFile)
+		if err != nil {
+			return nil, err
+		}
+		v.hmacSecret = bytes.TrimSpace(secret)
+		if len(v.hmacSecret) < minHMACSecretLen {
+			return nil, fmt.Errorf("HMAC secret in %s is shorter than %d bytes", cfg.HMACSecretFile, minHMACSecretLen)
+		}
+	}
+	if cfg.RSAPublicKeyFile != "" {
+		key, err := readRSAPublicKey(cfg.RSAPublicKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		v.rsaKeys[""] = key
+	}
+	if cfg.JWKSFile != "" {
+		if err := v.loadJWKS(cfg.JWKSFile); err != nil {
+			return nil, err
+		}
+	}
+	if v.hmacSecret == nil && len(v.rsaKeys) == 0 {
+		return nil, errors.New("no JWT signing key configured")
+	}
+	return v, nil
+}
+
+// Verify checks the signature and validity of token and returns the principal it was issued to
+func (v *Verifier) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformedToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, ErrMalformedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if err = v.checkSignature(h, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+	var claims Claims
+	if err = decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+	if err = v.checkClaims(&claims); err != nil {
+		return nil, err
+	}
+	return claims.principal(), nil
+}
+
+func (v *Verifier) checkSignature(h header, signed string, signature []byte) error {
+	switch h.Alg {
+	case AlgHS256:
+		if v.hmacSecret == nil {
+			return ErrUnsupportedAlgorithm
+		}
+		if !hmac.Equal(signature, hmacSHA256(v.hmacSecret, signed)) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case AlgRS256:
+		key := v.rsaKey(h.Kid)
+		if key == nil {
+			return ErrUnknownKey
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	return ErrUnsupportedAlgorithm
+}
+
+// rsaKey returns the key with kid. Tokens without a kid use the only key there is.
+func (v *Verifier) rsaKey(kid string) *rsa.PublicKey {
+	if key, ok := v.rsaKeys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key
+		}
+	}
+	return nil
+}
+
+func (v *Verifier) checkClaims(claims *Claims) error {
+	now := v.now()
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(v.leeway)) {
+		return ErrTokenExpired
+	}
+	if claims.NotBefore != 0 && now.Add(v.leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return ErrTokenNotYetValid
+	}
+	if v.issuer != "" && claims.Issuer != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if v.audience != "" && !contains(claims.Audience, v.audience) {
+		return ErrInvalidAudience
+	}
+	if claims.Subject == "" {
+		return ErrMissingSubject
+	}
+	// idempotency keys, holds and transfers are scoped to the client, so every token must name one
+	if claims.ClientID == "" && claims.AuthorizedParty == "" {
+		return ErrMissingClient
+	}
+	return nil
+}
+
+func (c *Claims) principal() *Principal {
+	scopes := strings.Fields(c.Scope)
+	if len(scopes) == 0 {
+		scopes = c.Scp
+	}
+	clientID := c.ClientID
+	if clientID == "" {
+		clientID = c.AuthorizedParty
+	}
+	return &Principal{Subject: c.Subject, ClientID: clientID, Scopes: scopes}
+}
+
+// SignHS256 returns claims as a JWT signed with secret
+func SignHS256(claims *Claims, secret []byte) (string, error) {
+	h, err := encodeSegment(header{Alg: AlgHS256, Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		return "", err
+	}
+	signed := h + "." + payload
+	return signed + "." + base64.RawURLEncoding.EncodeToString(hmacSHA256(secret, signed)), nil
+}
+
+func hmacSHA256(secret []byte, signed string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return mac.Sum(nil)
+}
+
+func encodeSegment(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func readRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block in %s", path)
+	}
+	switch block.Type {
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	case "CERTIFICATE":
+		cert, certErr := x509.ParseCertificate(block.Bytes)
+		if certErr != nil {
+			return nil, certErr
+		}
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	default:
+		parsed, parseErr := x509.ParsePKIXPublicKey(block.Bytes)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if key, ok := parsed.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("%s does not hold an RSA public key", path)
+}
+
+type jwks struct {
+	Keys []struct {
+		Kty string `json:"kty"`
+		Kid string `json:"kid"`
+		Use string `json:"use"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// loadJWKS adds the RSA signing keys of the JSON Web Key Set in path. Keys of other types are skipped.
+func (v *Verifier) loadJWKS(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var set jwks
+	if err = json.Unmarshal(data, &set); err != nil {
+		return fmt.Errorf("parse JWKS %s: %w", path, err)
+	}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, nErr := base64.RawURLEncoding.DecodeString(k.N)
+		e, eErr := base64.RawURLEncoding.DecodeString(k.E)
+		if nErr != nil || eErr != nil || len(e) == 0 {
+			return fmt.Errorf("JWKS %s: invalid RSA key %q", path, k.Kid)
+		}
+		v.rsaKeys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
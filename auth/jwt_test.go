@@ -0,0 +1,222 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+// signRS256 returns claims as a JWT signed with key, with kid in its header if set
+func signRS256(t *testing.T, claims *Claims, key *rsa.PrivateKey, kid string) string {
+	t.Helper()
+	h, err := encodeSegment(header{Alg: AlgRS256, Kid: kid, Typ: "JWT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := encodeSegment(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := sha256.Sum256([]byte(h + "." + payload))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	pemKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&pemKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	set, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "RSA",
+		"kid": "key-1",
+		"use": "sig",
+		"n":   base64.RawURLEncoding.EncodeToString(jwksKey.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(jwksKey.E)).Bytes()),
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewVerifier(&Config{
+		HMACSecretFile:   writeFile(t, "secret", append(testSecret, '\n')),
+		RSAPublicKeyFile: writeFile(t, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+		JWKSFile:         writeFile(t, "jwks.json", set),
+		Issuer:           "https://issuer.example",
+		Audience:         "wallet",
+		Leeway:           time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+	v.now = func() time.Time { return now }
+
+	valid := func() *Claims {
+		return &Claims{
+			Subject:   "user-1",
+			Issuer:    "https://issuer.example",
+			Audience:  Audience{"wallet", "other"},
+			ExpiresAt: now.Add(time.Hour).Unix(),
+			Scope:     "accounts:read transfers:write",
+			ClientID:  "mobile-app",
+		}
+	}
+	hs256 := func(c *Claims) string {
+		token, signErr := SignHS256(c, testSecret)
+		if signErr != nil {
+			t.Fatal(signErr)
+		}
+		return token
+	}
+	with := func(change func(c *Claims)) *Claims {
+		c := valid()
+		change(c)
+		return c
+	}
+	wantPrincipal := &Principal{Subject: "user-1", ClientID: "mobile-app", Scopes: []string{"accounts:read", "transfers:write"}}
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs256Header, _ := encodeSegment(header{Alg: AlgHS256})
+	noneHeader, _ := encodeSegment(header{Alg: "none"})
+	payload, _ := encodeSegment(valid())
+
+	tests := []struct {
+		name    string
+		token   string
+		want    *Principal
+		wantErr error
+	}{
+		{name: "HS256", token: hs256(valid()), want: wantPrincipal},
+		{name: "RS256 with the PEM key", token: signRS256(t, valid(), pemKey, ""), want: wantPrincipal},
+		{name: "RS256 with a JWKS key", token: signRS256(t, valid(), jwksKey, "key-1"), want: wantPrincipal},
+		{
+			name:  "scp array and azp",
+			token: hs256(with(func(c *Claims) { c.Scope, c.Scp, c.ClientID, c.AuthorizedParty = "", []string{"admin"}, "", "console" })),
+			want:  &Principal{Subject: "user-1", ClientID: "console", Scopes: []string{"admin"}},
+		},
+		{name: "expired within the leeway", token: hs256(with(func(c *Claims) { c.ExpiresAt = now.Add(-30 * time.Second).Unix() })), want: wantPrincipal},
+		{name: "expired", token: hs256(with(func(c *Claims) { c.ExpiresAt = now.Add(-2 * time.Minute).Unix() })), wantErr: ErrTokenExpired},
+		{name: "no expiry", token: hs256(with(func(c *Claims) { c.ExpiresAt = 0 })), wantErr: ErrTokenExpired},
+		{name: "not valid yet", token: hs256(with(func(c *Claims) { c.NotBefore = now.Add(2 * time.Minute).Unix() })), wantErr: ErrTokenNotYetValid},
+		{name: "other issuer", token: hs256(with(func(c *Claims) { c.Issuer = "https://evil.example" })), wantErr: ErrInvalidIssuer},
+		{name: "other audience", token: hs256(with(func(c *Claims) { c.Audience = Audience{"other"} })), wantErr: ErrInvalidAudience},
+		{name: "no subject", token: hs256(with(func(c *Claims) { c.Subject = "" })), wantErr: ErrMissingSubject},
+		{name: "no client", token: hs256(with(func(c *Claims) { c.ClientID, c.AuthorizedParty = "", "" })), wantErr: ErrMissingClient},
+		{name: "HS256 with another secret", token: func() string {
+			token, _ := SignHS256(valid(), []byte("another secret of at least 32 bytes"))
+			return token
+		}(), wantErr: ErrInvalidSignature},
+		{name: "RS256 with an unknown kid", token: signRS256(t, valid(), jwksKey, "key-2"), wantErr: ErrUnknownKey},
+		{name: "RS256 with another key", token: signRS256(t, valid(), otherKey, "key-1"), wantErr: ErrInvalidSignature},
+		{
+			name:    "HS256 signed with the RSA public key",
+			token:   hs256Header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(hmacSHA256(der, hs256Header+"."+payload)),
+			wantErr: ErrInvalidSignature,
+		},
+		{name: "alg none", token: noneHeader + "." + payload + ".", wantErr: ErrUnsupportedAlgorithm},
+		{name: "not a JWT", token: "abc", wantErr: ErrMalformedToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Verify(tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	if _, err := NewVerifier(&Config{}); err == nil {
+		t.Error("NewVerifier() without keys succeeded, want an error")
+	}
+	if _, err := NewVerifier(&Config{HMACSecretFile: writeFile(t, "secret", []byte("short"))}); err == nil {
+		t.Error("NewVerifier() with a short secret succeeded, want an error")
+	}
+	if _, err := NewVerifier(DefaultConfig()); err == nil {
+		t.Error("NewVerifier(DefaultConfig()) succeeded, want an error: no key ships with the wallet")
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	secretFile := writeFile(t, "secret", testSecret)
+	t.Setenv(EnvHMACSecretFile, secretFile)
+	t.Setenv(EnvIssuer, "https://id.example.com")
+	t.Setenv(EnvLeeway, "5s")
+
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Config{HMACSecretFile: secretFile, Issuer: "https://id.example.com", Audience: "wallet", Leeway: 5 * time.Second}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("ConfigFromEnv() = %+v, want %+v", cfg, want)
+	}
+	if _, err = NewVerifier(cfg); err != nil {
+		t.Errorf("NewVerifier() error = %v", err)
+	}
+
+	t.Setenv(EnvLeeway, "soon")
+	if _, err = ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv() with an invalid leeway succeeded, want an error")
+	}
+}
+
+func TestPrincipal_UserID(t *testing.T) {
+	tests := []struct {
+		name      string
+		principal *Principal
+		want      string
+	}{
+		{name: "user of an app", principal: &Principal{Subject: "user-1", ClientID: "mobile-app"}, want: "user-1"},
+		{name: "user without a client", principal: &Principal{Subject: "user-1"}, want: "user-1"},
+		{name: "service acting for itself", principal: &Principal{Subject: "payroll", ClientID: "payroll"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.principal.UserID(); got != tt.want {
+				t.Errorf("UserID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"wallet/auth"
+	"wallet/logic/apikey"
+	"wallet/server"
+	"wallet/storage"
+)
+
+const usage = `usage: apikey <command> [flags]
+
+Secrets are encrypted under the master key in the file named by $WALLET_API_KEY_MASTER_KEY_FILE.
+
+commands:
+  genkey  -out FILE                                   write a new random master key
+  issue   -client ID [-scope "transfers:write ..."]   issue a key to a partner; prints its secret once
+  rotate  -key KEY_ID                                 issue a replacement; the old key works for the grace period
+  revoke  -key KEY_ID                                 reject the key straight away
+  list    -client ID                                  list the keys issued to a partner
+  sign    -key KEY_ID -secret SECRET -method M -uri U [-body FILE]
+                                                      print the headers of a signed request, for trying the API`
+
+// apikey manages the API keys partners' backends sign requests with, and prints its results as JSON
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal(usage)
+	}
+	cmd, args := os.Args[1], os.Args[2:]
+	fs := flag.NewFlagSet(cmd, flag.ExitOnError)
+	clientID := fs.String("client", "", "client ID of the partner")
+	keyID := fs.String("key", "", "key ID")
+	scopes := fs.String("scope", auth.ScopeTransfersWrite, "space-separated scopes granted to the key")
+	secret := fs.String("secret", "", "secret of the key, to sign with")
+	method := fs.String("method", "POST", "method of the request to sign")
+	uri := fs.String("uri", "", "path and query of the request to sign")
+	bodyFile := fs.String("body", "", "file holding the body of the request to sign")
+	out := fs.String("out", "", "file to write the master key to")
+	_ = fs.Parse(args)
+
+	switch cmd {
+	case "sign":
+		sign(*keyID, *secret, *method, *uri, *bodyFile)
+		return
+	case "genkey":
+		requireFlag("out", *out)
+		writeMasterKey(*out)
+		return
+	}
+
+	cfg, err := apikey.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if !cfg.Enabled() && (cmd == "issue" || cmd == "rotate") {
+		log.Fatalf("$%s is required to %s keys", apikey.EnvMasterKeyFile, cmd)
+	}
+	logic := apikey.NewAPIKeyLogic(storage.NewAPIKeyDAO(server.OpenDB()), cfg)
+	ctx := context.Background()
+	var res any
+	switch cmd {
+	case "issue":
+		res, err = logic.IssueKey(ctx, *clientID, strings.Fields(*scopes))
+	case "rotate":
+		requireFlag("key", *keyID)
+		issued, rotated, rotateErr := logic.RotateKey(ctx, *keyID)
+		res, err = map[string]any{"issued": issued, "rotated": rotated}, rotateErr
+	case "revoke":
+		requireFlag("key", *keyID)
+		res, err = logic.RevokeKey(ctx, *keyID)
+	case "list":
+		requireFlag("client", *clientID)
+		res, err = logic.ListKeys(ctx, *clientID)
+	default:
+		log.Fatal(usage)
+	}
+	if err != nil {
+		log.Fatalf("%s failed: %v", cmd, err)
+	}
+	printJSON(res)
+}
+
+func sign(keyID, secret, method, uri, bodyFile string) {
+	requireFlag("key", keyID)
+	requireFlag("secret", secret)
+	requireFlag("uri", uri)
+	var body []byte
+	if bodyFile != "" {
+		var err error
+		if body, err = os.ReadFile(bodyFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		log.Fatal(err)
+	}
+	nonce := hex.EncodeToString(raw)
+	timestamp := time.Now().Unix()
+	printJSON(map[string]string{
+		auth.HeaderKeyID:     keyID,
+		auth.HeaderTimestamp: strconv.FormatInt(timestamp, 10),
+		auth.HeaderNonce:     nonce,
+		auth.HeaderSignature: auth.SignRequest([]byte(secret), method, uri, timestamp, nonce, body),
+	})
+}
+
+// writeMasterKey writes a random AES-256 key, hex encoded, to a file only its owner can read. It never
+// overwrites an existing key: the secrets sealed under it could not be opened any more.
+func writeMasterKey(file string) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	if _, err = fmt.Fprintln(f, hex.EncodeToString(raw)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func requireFlag(name, value string) {
+	if value == "" {
+		log.Fatal(fmt.Sprintf("-%s is required\n\n%s", name, usage))
+	}
+}
+
+func printJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
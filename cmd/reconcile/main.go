@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"wallet/logic/reconcile"
+	"wallet/server"
+	"wallet/storage"
+)
+
+// reconcile compares every account balance with the sum of its ledger legs and prints the report as JSON.
+// It exits with status 2 when drifts were found and left uncorrected, so it can gate deployments or alerts.
+func main() {
+	fix := flag.Bool("fix", false, "write a correction journal for every drift found")
+	reason := flag.String("reason", "", "audit reason recorded on correction journals (required with -fix)")
+	batchSize := flag.Int("batch", 500, "number of accounts checked per query")
+	flag.Parse()
+
+	db := server.OpenDB()
+	logic := reconcile.NewReconcileLogic(
+		storage.NewAccountDAO(db),
+		storage.NewTransactionDAO(db),
+		storage.NewTransferDAO(db),
+	)
+
+	report, err := logic.Reconcile(context.Background(), &reconcile.Options{
+		Fix:       *fix,
+		Reason:    *reason,
+		BatchSize: *batchSize,
+	})
+	if report != nil {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	}
+	if err != nil {
+		log.Fatalf("reconciliation failed: %v", err)
+	}
+	if len(report.Drifts) > 0 && !report.Corrected {
+		os.Exit(2)
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"wallet/auth"
+)
+
+// token mints an HS256 access token, for trying the API locally. Real tokens come from an identity
+// provider. With -init it writes a fresh demo secret to sign with instead; no secret ships with the wallet.
+func main() {
+	cfg, err := auth.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	secretFile := flag.String("secret", cfg.HMACSecretFile, "file holding the HMAC secret to sign with (default $"+auth.EnvHMACSecretFile+")")
+	initSecret := flag.Bool("init", false, "write a new random secret to the -secret file and exit")
+	subject := flag.String("sub", "", "subject: a user ID, or the client ID for a service acting for itself")
+	clientID := flag.String("client", "demo-client", "client ID the token is issued to")
+	scopes := flag.String("scope", strings.Join([]string{
+		auth.ScopeAccountsRead, auth.ScopeAccountsWrite,
+		auth.ScopeTransfersRead, auth.ScopeTransfersWrite,
+		auth.ScopeUsersRead, auth.ScopeUsersWrite,
+	}, " "), "space-separated scopes")
+	ttl := flag.Duration("ttl", time.Hour, "how long the token is valid")
+	flag.Parse()
+	if *secretFile == "" {
+		log.Fatal("-secret or $" + auth.EnvHMACSecretFile + " is required")
+	}
+	if *initSecret {
+		writeSecret(*secretFile)
+		return
+	}
+	if *subject == "" {
+		log.Fatal("-sub is required")
+	}
+
+	secret, err := os.ReadFile(*secretFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	now := time.Now()
+	claims := &auth.Claims{
+		Subject:   *subject,
+		Issuer:    cfg.Issuer,
+		ExpiresAt: now.Add(*ttl).Unix(),
+		IssuedAt:  now.Unix(),
+		Scope:     *scopes,
+		ClientID:  *clientID,
+	}
+	if cfg.Audience != "" {
+		claims.Audience = auth.Audience{cfg.Audience}
+	}
+	token, err := auth.SignHS256(claims, bytes.TrimSpace(secret))
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(token)
+}
+
+// writeSecret writes a random 32-byte secret, hex encoded, to a file only its owner can read. It never
+// overwrites an existing secret.
+func writeSecret(file string) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	if _, err = fmt.Fprintln(f, hex.EncodeToString(raw)); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,103 @@
+package currency
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency describes an ISO 4217 currency. Amounts are always handled in minor units; Exponent is the
+// number of minor-unit digits, e.g. 2 for MYR (100 sen = RM 1.00) and 0 for JPY.
+type Currency struct {
+	Code     string  `json:"code"`     // ISO 4217 alphabetic code
+	Numeric  string  `json:"numeric"`  // ISO 4217 numeric code
+	Name     string  `json:"name"`     // English name
+	Exponent int     `json:"exponent"` // minor-unit digits
+	Display  Display `json:"display"`
+}
+
+// Display holds the rules used to show amounts of a currency to people
+type Display struct {
+	Symbol            string `json:"symbol"`
+	SymbolAfterAmount bool   `json:"symbolAfterAmount"`
+	DecimalSeparator  string `json:"decimalSeparator"`
+	GroupSeparator    string `json:"groupSeparator"`
+}
+
+var registry = map[string]Currency{}
+
+func register(c Currency) {
+	registry[c.Code] = c
+}
+
+func init() {
+	dotDecimal := func(symbol string) Display {
+		return Display{Symbol: symbol, DecimalSeparator: ".", GroupSeparator: ","}
+	}
+	register(Currency{Code: "MYR", Numeric: "458", Name: "Malaysian Ringgit", Exponent: 2, Display: dotDecimal("RM")})
+	register(Currency{Code: "SGD", Numeric: "702", Name: "Singapore Dollar", Exponent: 2, Display: dotDecimal("S$")})
+	register(Currency{Code: "USD", Numeric: "840", Name: "US Dollar", Exponent: 2, Display: dotDecimal("US$")})
+	register(Currency{Code: "JPY", Numeric: "392", Name: "Japanese Yen", Exponent: 0, Display: dotDecimal("¥")})
+	register(Currency{Code: "IDR", Numeric: "360", Name: "Indonesian Rupiah", Exponent: 2, Display: Display{Symbol: "Rp", DecimalSeparator: ",", GroupSeparator: "."}})
+	register(Currency{Code: "EUR", Numeric: "978", Name: "Euro", Exponent: 2, Display: Display{Symbol: "€", SymbolAfterAmount: true, DecimalSeparator: ",", GroupSeparator: "."}})
+	register(Currency{Code: "KWD", Numeric: "414", Name: "Kuwaiti Dinar", Exponent: 3, Display: dotDecimal("KD")})
+}
+
+// Lookup returns the registered currency with ISO 4217 code code
+func Lookup(code string) (Currency, bool) {
+	c, ok := registry[code]
+	return c, ok
+}
+
+// IsRegistered reports whether code is a registered currency
+func IsRegistered(code string) bool {
+	_, ok := registry[code]
+	return ok
+}
+
+// All returns every registered currency ordered by code
+func All() []Currency {
+	all := make([]Currency, 0, len(registry))
+	for _, c := range registry {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Code < all[j].Code })
+	return all
+}
+
+// Format renders an amount in minor units following the display rules of c, e.g. "RM 1,234.50"
+func (c Currency) Format(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	digits := strconv.FormatUint(absUint(amount), 10)
+	if len(digits) <= c.Exponent {
+		digits = strings.Repeat("0", c.Exponent-len(digits)+1) + digits
+	}
+	whole, fraction := digits[:len(digits)-c.Exponent], digits[len(digits)-c.Exponent:]
+
+	var grouped strings.Builder
+	for i, d := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteString(c.Display.GroupSeparator)
+		}
+		grouped.WriteRune(d)
+	}
+	number := grouped.String()
+	if c.Exponent > 0 {
+		number += c.Display.DecimalSeparator + fraction
+	}
+
+	if c.Display.SymbolAfterAmount {
+		return sign + number + " " + c.Display.Symbol
+	}
+	return sign + c.Display.Symbol + " " + number
+}
+
+func absUint(n int64) uint64 {
+	if n < 0 {
+		return uint64(-(n + 1)) + 1
+	}
+	return uint64(n)
+}
@@ -0,0 +1,51 @@
+package currency
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCurrency_Format(t *testing.T) {
+	tests := []struct {
+		code   string
+		amount int64
+		want   string
+	}{
+		{code: "MYR", amount: 123450, want: "RM 1,234.50"},
+		{code: "MYR", amount: 5, want: "RM 0.05"},
+		{code: "MYR", amount: 0, want: "RM 0.00"},
+		{code: "MYR", amount: -100000, want: "-RM 1,000.00"},
+		{code: "JPY", amount: 1234567, want: "¥ 1,234,567"},
+		{code: "KWD", amount: 1500, want: "KD 1.500"},
+		{code: "IDR", amount: 1500000, want: "Rp 15.000,00"},
+		{code: "EUR", amount: 123456, want: "1.234,56 €"},
+		{code: "USD", amount: math.MinInt64, want: "-US$ 92,233,720,368,547,758.08"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			c, ok := Lookup(tt.code)
+			if !ok {
+				t.Fatalf("Lookup(%s) not found", tt.code)
+			}
+			if got := c.Format(tt.amount); got != tt.want {
+				t.Errorf("Format(%d) = %q, want %q", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRegistered(t *testing.T) {
+	for _, c := range All() {
+		if len(c.Code) != 3 || len(c.Numeric) != 3 {
+			t.Errorf("%+v is not a valid ISO 4217 entry", c)
+		}
+		if !IsRegistered(c.Code) {
+			t.Errorf("IsRegistered(%s) = false", c.Code)
+		}
+	}
+	for _, code := range []string{"", "myr", "XXX", "MYRR"} {
+		if IsRegistered(code) {
+			t.Errorf("IsRegistered(%q) = true, want false", code)
+		}
+	}
+}
@@ -3,13 +3,48 @@ CREATE TABLE account
     id         SERIAL PRIMARY KEY,                    -- Auto-incrementing internal DB ID
     account_id VARCHAR(64) NOT NULL UNIQUE,           -- App-level public ID (e.g., 'acct_xxx'), must be unique
     name       TEXT        NOT NULL,                  -- Display name (e.g., "Main Wallet")
-    type       VARCHAR(20) NOT NULL DEFAULT 'WALLET', -- Account type: WALLET, CASA
+    type       VARCHAR(20) NOT NULL DEFAULT 'WALLET', -- Account type: WALLET, CASA, HOLDING, SYSTEM, FX_POSITION, FEE_INCOME
     currency   CHAR(3)     NOT NULL DEFAULT 'MYR',    -- ISO 4217 currency code (USD, MYR, etc.)
-    balance    BIGINT      NOT NULL DEFAULT 0,        -- Current balance in minor units (e.g., cents)
+    balance    BIGINT      NOT NULL DEFAULT 0,        -- Current (ledger) balance in minor units (e.g., cents)
+    held_balance BIGINT    NOT NULL DEFAULT 0,        -- Reserved by active holds; available = balance - held_balance
+    tier       VARCHAR(20) NOT NULL DEFAULT '',       -- KYC tier selecting the account's limits, empty for basic
+    parent_account_id VARCHAR(64),                    -- Set on shard sub-accounts, the logical account they back
+    status     VARCHAR(16) NOT NULL DEFAULT 'ACTIVE', -- ACTIVE, FROZEN or CLOSED
+    freeze     VARCHAR(8)  NOT NULL DEFAULT '',       -- DEBIT, CREDIT or ALL while FROZEN
+    closed_at  TIMESTAMPTZ,                           -- When the account was closed
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),    -- Creation time
     updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()     -- Last updated time
 );
 
+CREATE INDEX idx_account_parent_account_id ON account (parent_account_id);
+
+CREATE TABLE users
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    VARCHAR(36)  NOT NULL,                  -- Public user ID
+    name       TEXT         NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    phone      VARCHAR(32)  NOT NULL DEFAULT '',
+    status     VARCHAR(16)  NOT NULL DEFAULT 'ACTIVE', -- ACTIVE, SUSPENDED or CLOSED
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_user_id UNIQUE (user_id),
+    CONSTRAINT uk_user_email UNIQUE (email)
+);
+
+CREATE TABLE user_account
+(
+    id         BIGSERIAL PRIMARY KEY,
+    user_id    VARCHAR(36) NOT NULL,
+    account_id VARCHAR(64) NOT NULL,
+    role       VARCHAR(16) NOT NULL,               -- OWNER, JOINT or VIEWER
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_user_account UNIQUE (user_id, account_id)
+);
+
+CREATE INDEX idx_user_account_account_id ON user_account (account_id);
+
 CREATE TABLE transfer
 (
     id                        BIGSERIAL PRIMARY KEY,              -- Auto-incrementing internal DB ID
@@ -17,10 +52,20 @@ CREATE TABLE transfer
     tx_type                   VARCHAR(36)  NOT NULL DEFAULT '',   -- Purpose type
     user_id                   VARCHAR(36),                        -- Customer who initiates the transfer
     transaction_id            VARCHAR(36)  NOT NULL DEFAULT '',   -- Internal transaction tracking ID
-    reference_id              VARCHAR(36)  NOT NULL DEFAULT '',   -- Idempotency key
+    reference_id              VARCHAR(36)  NOT NULL DEFAULT '',   -- Idempotency key, cleared once its retention window ends
+    client_id                 VARCHAR(64)  NOT NULL DEFAULT '',   -- Client the idempotency key is scoped to
+    request_hash              VARCHAR(64)  NOT NULL DEFAULT '',   -- Fingerprint of the request that created the transfer
     status                    VARCHAR(36)  NOT NULL DEFAULT '',   -- Status of the transaction
     amount                    BIGINT       NOT NULL,              -- Amount in minor unit
     currency                  VARCHAR(3)   NOT NULL DEFAULT '',   -- ISO currency code
+    fx_quote_id               VARCHAR(36)  NOT NULL DEFAULT '',   -- FX quote of a cross-currency transfer
+    hold_id                   VARCHAR(36)  NOT NULL DEFAULT '',   -- Hold a capture settles
+    parent_transaction_id     VARCHAR(36)  NOT NULL DEFAULT '',   -- Transfer a reversal refunds
+    refunded_amount           BIGINT       NOT NULL DEFAULT 0,    -- Refunded by reversals so far, in minor unit
+    destination_amount        BIGINT       NOT NULL DEFAULT 0,    -- Amount credited in minor unit of destination_currency, cross-currency only
+    destination_currency      VARCHAR(3)   NOT NULL DEFAULT '',   -- Currency credited, cross-currency only
+    fee_amount                BIGINT       NOT NULL DEFAULT 0,    -- Total fees in minor unit of currency
+    fees                      JSONB,                              -- Fee breakdown
     source_account_id         VARCHAR(36),                        -- Source account ID
     source_account            JSONB        NOT NULL DEFAULT '{}', -- Source account details
     destination_account_id    VARCHAR(36),                        -- Destination account ID
@@ -30,15 +75,26 @@ CREATE TABLE transfer
     note                      VARCHAR(255) NOT NULL DEFAULT '',   -- Transfer note/remark
     properties                JSONB                 DEFAULT '{}', -- Metadata per transfer type
     created_at                TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
-    valued_at                 TIMESTAMPTZ,
+    execute_at                TIMESTAMPTZ,                        -- When a scheduled transfer is due
+    claimed_until             TIMESTAMPTZ,                        -- While processing, until when the executing instance holds it
+    valued_at                 TIMESTAMPTZ,                        -- When the transfer took effect
     updated_at                TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
-    CONSTRAINT uk_transaction_id UNIQUE (transaction_id),
-    CONSTRAINT uk_reference_id UNIQUE (reference_id)
+    CONSTRAINT uk_transaction_id UNIQUE (transaction_id)
 );
 
+CREATE UNIQUE INDEX uk_client_reference_id ON transfer (client_id, reference_id) WHERE reference_id <> '';
+-- Limit usage lookups
+CREATE INDEX idx_transfer_source_usage ON transfer (source_account_id, tx_type, COALESCE(valued_at, created_at)) WHERE status IN ('COMPLETED', 'PARTIALLY_REFUNDED', 'REVERSED');
+CREATE INDEX idx_transfer_destination_usage ON transfer (destination_account_id, tx_type, COALESCE(valued_at, created_at)) WHERE status IN ('COMPLETED', 'PARTIALLY_REFUNDED', 'REVERSED');
+CREATE INDEX idx_transfer_scheduled ON transfer (execute_at) WHERE status = 'SCHEDULED';
+CREATE INDEX idx_transfer_processing ON transfer (claimed_until) WHERE status = 'PROCESSING';
+CREATE INDEX idx_transfer_parent_transaction_id ON transfer (parent_transaction_id) WHERE parent_transaction_id <> '';
+
 CREATE TABLE transaction
 (
     id         SERIAL PRIMARY KEY,                                       -- Auto-incrementing ID
+    journal_id VARCHAR(36) NOT NULL,                                     -- transaction_id of the owning transfer
+    leg_no     INT         NOT NULL DEFAULT 0,                           -- Position of the leg within its journal
     account_id VARCHAR(64) NOT NULL,                                     -- Account this transaction belongs to
     type       VARCHAR(10) NOT NULL CHECK (type IN ('credit', 'debit')), -- 'credit' or 'debit'
     amount     BIGINT      NOT NULL CHECK (amount >= 0),                 -- Minor units (e.g., cents)
@@ -51,6 +107,198 @@ CREATE TABLE transaction
     properties JSONB                DEFAULT '{}'                         -- Metadata, tags, channel info, etc.
 );
 
+CREATE INDEX idx_transaction_journal_id ON transaction (journal_id);
+
+CREATE TABLE hold
+(
+    id                     BIGSERIAL PRIMARY KEY,
+    hold_id                VARCHAR(36)  NOT NULL,             -- Public hold ID
+    client_id              VARCHAR(64)  NOT NULL DEFAULT '',  -- Client that placed the hold
+    reference_id           VARCHAR(255) NOT NULL,             -- Idempotency key of the request that placed the hold
+    account_id             VARCHAR(64)  NOT NULL,             -- Account the funds are held on
+    destination_account_id VARCHAR(64)  NOT NULL,             -- Account captures are credited to
+    currency               CHAR(3)      NOT NULL,
+    amount                 BIGINT       NOT NULL,             -- Minor unit
+    captured_amount        BIGINT       NOT NULL DEFAULT 0,   -- Minor unit
+    released_amount        BIGINT       NOT NULL DEFAULT 0,   -- Minor unit, set once released or expired
+    status                 VARCHAR(16)  NOT NULL,             -- ACTIVE, CAPTURED, RELEASED, EXPIRED
+    reason                 VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at             TIMESTAMPTZ  NOT NULL,
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_hold_id UNIQUE (hold_id),
+    CONSTRAINT uk_hold_client_reference_id UNIQUE (client_id, reference_id)
+);
+
+CREATE INDEX idx_hold_account_id ON hold (account_id);
+CREATE INDEX idx_hold_active_expires_at ON hold (expires_at) WHERE status = 'ACTIVE';
+
+CREATE TABLE standing_instruction
+(
+    id                     BIGSERIAL PRIMARY KEY,
+    instruction_id         VARCHAR(36)  NOT NULL,             -- Public instruction ID
+    client_id              VARCHAR(64)  NOT NULL DEFAULT '',  -- Client that created the instruction
+    reference_id           VARCHAR(255) NOT NULL,             -- Idempotency key of the request that created the instruction
+    user_id                VARCHAR(36),                       -- User the transfers are made for
+    source_account_id      VARCHAR(64)  NOT NULL,
+    destination_account_id VARCHAR(64)  NOT NULL,
+    currency               CHAR(3)      NOT NULL,
+    amount                 BIGINT       NOT NULL,             -- Minor unit, per occurrence
+    note                   VARCHAR(255) NOT NULL DEFAULT '',
+    schedule               VARCHAR(255) NOT NULL,             -- RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+    start_at               TIMESTAMPTZ  NOT NULL,
+    end_at                 TIMESTAMPTZ,
+    max_occurrences        INT          NOT NULL DEFAULT 0,   -- 0 for no maximum
+    failure_policy         VARCHAR(16)  NOT NULL,             -- SKIP, RETRY, SUSPEND
+    max_retries            INT          NOT NULL DEFAULT 0,
+    status                 VARCHAR(16)  NOT NULL,             -- ACTIVE, SUSPENDED, CANCELLED, COMPLETED
+    next_occurrence_at     TIMESTAMPTZ,                       -- Date of the next occurrence
+    next_run_at            TIMESTAMPTZ,                       -- When the next occurrence is run, later while retrying
+    occurrence             INT          NOT NULL DEFAULT 0,   -- Occurrences run so far, paid or not
+    attempt                INT          NOT NULL DEFAULT 0,   -- Failed attempts of the next occurrence
+    failed_count           INT          NOT NULL DEFAULT 0,
+    last_transaction_id    VARCHAR(36)  NOT NULL DEFAULT '',
+    last_error             VARCHAR(64)  NOT NULL DEFAULT '',
+    locked_until           TIMESTAMPTZ,                       -- Claim of the instance running the next occurrence
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_standing_instruction_id UNIQUE (instruction_id),
+    CONSTRAINT uk_standing_instruction_client_reference_id UNIQUE (client_id, reference_id)
+);
+
+CREATE INDEX idx_standing_instruction_source_account_id ON standing_instruction (source_account_id);
+CREATE INDEX idx_standing_instruction_due ON standing_instruction (next_run_at) WHERE status = 'ACTIVE';
+
+CREATE TABLE transfer_batch
+(
+    id              BIGSERIAL PRIMARY KEY,
+    batch_id        VARCHAR(36)  NOT NULL,             -- Public batch ID
+    client_id       VARCHAR(64)  NOT NULL DEFAULT '',  -- Client that submitted the batch
+    reference_id    VARCHAR(255) NOT NULL,             -- Idempotency key of the request that submitted the batch
+    request_hash    VARCHAR(64)  NOT NULL,             -- Fingerprint of the submitted items
+    user_id         VARCHAR(36),                       -- User the transfers are made for
+    mode            VARCHAR(16)  NOT NULL,             -- INDEPENDENT or ATOMIC
+    status          VARCHAR(24)  NOT NULL,             -- PENDING, PROCESSING, COMPLETED, PARTIALLY_COMPLETED, FAILED
+    item_count      INT          NOT NULL,
+    completed_count INT          NOT NULL DEFAULT 0,
+    failed_count    INT          NOT NULL DEFAULT 0,
+    locked_until    TIMESTAMPTZ,                       -- Claim of the instance processing the batch
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    finished_at     TIMESTAMPTZ,
+    CONSTRAINT uk_transfer_batch_id UNIQUE (batch_id),
+    CONSTRAINT uk_transfer_batch_client_reference_id UNIQUE (client_id, reference_id)
+);
+
+CREATE INDEX idx_transfer_batch_pending ON transfer_batch (created_at) WHERE status IN ('PENDING', 'PROCESSING');
+
+CREATE TABLE transfer_batch_item
+(
+    id                     BIGSERIAL PRIMARY KEY,
+    batch_id               VARCHAR(36)  NOT NULL,
+    item_index             INT          NOT NULL,             -- Position in the submitted list, from 0
+    reference              VARCHAR(255) NOT NULL DEFAULT '',  -- Client's own reference, e.g. an employee ID
+    source_account_id      VARCHAR(64)  NOT NULL,
+    destination_account_id VARCHAR(64)  NOT NULL,
+    currency               CHAR(3)      NOT NULL,
+    amount                 BIGINT       NOT NULL,             -- Minor unit
+    note                   VARCHAR(255) NOT NULL DEFAULT '',
+    status                 VARCHAR(16)  NOT NULL,             -- PENDING, COMPLETED, FAILED
+    status_reason          VARCHAR(64)  NOT NULL DEFAULT '',  -- Error code of a failed item
+    transaction_id         VARCHAR(36)  NOT NULL DEFAULT '',  -- Transfer made for the item
+    created_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at             TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_transfer_batch_item UNIQUE (batch_id, item_index)
+);
+
+CREATE INDEX idx_transfer_batch_item_status ON transfer_batch_item (batch_id, status);
+
+CREATE TABLE fx_quote
+(
+    id                   BIGSERIAL PRIMARY KEY,
+    quote_id             VARCHAR(36) NOT NULL,             -- Public quote ID
+    client_id            VARCHAR(64) NOT NULL DEFAULT '',  -- Client the quote was issued to
+    source_currency      CHAR(3)     NOT NULL,
+    destination_currency CHAR(3)     NOT NULL,
+    rate                 VARCHAR(32) NOT NULL,             -- Destination units per source unit
+    source_amount        BIGINT      NOT NULL,             -- Minor unit of source_currency
+    destination_amount   BIGINT      NOT NULL,             -- Minor unit of destination_currency
+    expires_at           TIMESTAMPTZ NOT NULL,
+    transaction_id       VARCHAR(36),                      -- Transfer that used the quote
+    used_at              TIMESTAMPTZ,
+    created_at           TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_fx_quote_id UNIQUE (quote_id)
+);
+
+CREATE TABLE idempotency_record
+(
+    id              BIGSERIAL PRIMARY KEY,
+    client_id       VARCHAR(64)  NOT NULL DEFAULT '', -- Client the key is scoped to
+    idempotency_key VARCHAR(255) NOT NULL,            -- Key sent by the client
+    request_hash    VARCHAR(64)  NOT NULL DEFAULT '', -- Fingerprint of the request first made with the key
+    status          VARCHAR(16)  NOT NULL,            -- IN_FLIGHT or COMPLETED
+    response_code   INT          NOT NULL DEFAULT 0,  -- HTTP status of the recorded response
+    content_type    VARCHAR(255) NOT NULL DEFAULT '', -- Content-Type of the recorded response
+    response_body   BYTEA,                            -- Body of the recorded response
+    created_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_idempotency_client_key UNIQUE (client_id, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_record_created_at ON idempotency_record (created_at);
+
+CREATE TABLE api_key
+(
+    id            BIGSERIAL PRIMARY KEY,
+    key_id        VARCHAR(36)  NOT NULL,             -- Public key ID sent with signed requests
+    client_id     VARCHAR(64)  NOT NULL,             -- Partner the key was issued to
+    sealed_secret VARCHAR(255) NOT NULL,             -- Secret encrypted with AES-256-GCM under the master key
+    scopes        VARCHAR(255) NOT NULL DEFAULT '',  -- Space-separated scopes
+    status        VARCHAR(16)  NOT NULL,             -- ACTIVE or REVOKED
+    expires_at    TIMESTAMPTZ,                       -- Set on a rotated key, which keeps working until then
+    revoked_at    TIMESTAMPTZ,
+    created_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at    TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    CONSTRAINT uk_api_key_id UNIQUE (key_id)
+);
+
+CREATE INDEX idx_api_key_client_id ON api_key (client_id);
+
+CREATE TABLE api_nonce
+(
+    id         BIGSERIAL PRIMARY KEY,
+    key_id     VARCHAR(36) NOT NULL,
+    nonce      VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL, -- When the signed request's timestamp falls out of the accepted window
+    CONSTRAINT uk_api_nonce UNIQUE (key_id, nonce)
+);
+
+CREATE INDEX idx_api_nonce_expires_at ON api_nonce (expires_at);
+
+-- Every journal must balance per currency once its database transaction commits.
+CREATE
+OR REPLACE FUNCTION check_journal_balanced()
+RETURNS TRIGGER AS $$
+BEGIN
+  IF EXISTS (SELECT 1
+             FROM transaction
+             WHERE journal_id = NEW.journal_id
+             GROUP BY currency
+             HAVING SUM(CASE WHEN type = 'credit' THEN amount ELSE -amount END) <> 0) THEN
+    RAISE EXCEPTION 'journal % is not balanced', NEW.journal_id;
+END IF;
+RETURN NULL;
+END;
+$$
+LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trigger_check_journal_balanced
+    AFTER INSERT OR UPDATE
+    ON transaction
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW
+    EXECUTE FUNCTION check_journal_balanced();
+
 CREATE
 OR REPLACE FUNCTION set_updated_at()
 RETURNS TRIGGER AS $$
@@ -9,13 +9,25 @@ INSERT INTO account (
 ) VALUES (
              '1000000001',
              'Holding Account',
-             'wallet',
+             'HOLDING',
              'MYR',
              100000000000,
              NOW(),
              NOW()
          );
 
+-- Shards backing the holding account; deposits and withdrawals post to one of them per transfer
+INSERT INTO account (account_id, name, type, currency, balance, parent_account_id)
+VALUES ('1000000001-01', 'Holding Account Shard 1', 'HOLDING', 'MYR', 0, '1000000001'),
+       ('1000000001-02', 'Holding Account Shard 2', 'HOLDING', 'MYR', 0, '1000000001'),
+       ('1000000001-03', 'Holding Account Shard 3', 'HOLDING', 'MYR', 0, '1000000001'),
+       ('1000000001-04', 'Holding Account Shard 4', 'HOLDING', 'MYR', 0, '1000000001');
+
+-- One holding account per currency
+INSERT INTO account (account_id, name, type, currency, balance)
+VALUES ('1000000002', 'Holding Account SGD', 'HOLDING', 'SGD', 0),
+       ('1000000003', 'Holding Account USD', 'HOLDING', 'USD', 0);
+
 INSERT INTO account (
     account_id,
     name,
@@ -27,7 +39,7 @@ INSERT INTO account (
 ) VALUES (
              '12345678',
              'Demo Wallet 1',
-             'wallet',
+             'WALLET',
              'MYR',
              100000,             -- RM 1,000.00 in minor unit (e.g., sen)
              NOW(),
@@ -45,9 +57,96 @@ INSERT INTO account (
 ) VALUES (
              '87654321',
              'Demo Wallet 2',
-             'wallet',
+             'WALLET',
              'MYR',
              100000,             -- RM 1,000.00 in minor unit (e.g., sen)
              NOW(),
              NOW()
-         );
\ No newline at end of file
+         );
+
+INSERT INTO account (account_id, name, type, currency, balance)
+VALUES ('11223344', 'Demo SGD Wallet', 'WALLET', 'SGD', 0);
+
+-- FX position per currency; cross-currency transfers pass through the positions of both currencies
+INSERT INTO account (account_id, name, type, currency, balance)
+VALUES ('8000000001', 'FX Position MYR', 'FX_POSITION', 'MYR', 0),
+       ('8000000002', 'FX Position SGD', 'FX_POSITION', 'SGD', 0),
+       ('8000000003', 'FX Position USD', 'FX_POSITION', 'USD', 0);
+
+-- Fee income per currency
+INSERT INTO account (account_id, name, type, currency, balance)
+VALUES ('7000000001', 'Fee Income MYR', 'FEE_INCOME', 'MYR', 0),
+       ('7000000002', 'Fee Income SGD', 'FEE_INCOME', 'SGD', 0),
+       ('7000000003', 'Fee Income USD', 'FEE_INCOME', 'USD', 0);
+
+INSERT INTO account (
+    account_id,
+    name,
+    type,
+    currency,
+    balance,
+    created_at,
+    updated_at
+) VALUES (
+             '9000000000',
+             'Opening Balance Equity',
+             'SYSTEM',
+             'MYR',
+             -100000200000,      -- contra side of the opening balances below
+             NOW(),
+             NOW()
+         ), (
+             '9000000001',
+             'Reconciliation Suspense',
+             'SYSTEM',
+             'MYR',
+             0,
+             NOW(),
+             NOW()
+         );
+
+-- Opening balance journal so every seeded balance is explained by ledger legs
+INSERT INTO transfer (
+    type,
+    tx_type,
+    transaction_id,
+    reference_id,
+    status,
+    amount,
+    currency,
+    source_account_id,
+    destination_account_id,
+    note,
+    created_at,
+    valued_at,
+    updated_at
+) VALUES (
+             'INTRA',
+             'OPENING',
+             '00000000-0000-0000-0000-000000000001',
+             '00000000-0000-0000-0000-000000000001',
+             'COMPLETED',
+             100000200000,
+             'MYR',
+             '9000000000',
+             NULL,
+             'Opening balances',
+             NOW(),
+             NOW(),
+             NOW()
+         );
+
+INSERT INTO transaction (journal_id, leg_no, account_id, type, amount, currency, note)
+VALUES ('00000000-0000-0000-0000-000000000001', 1, '9000000000', 'debit', 100000200000, 'MYR', 'Opening balances'),
+       ('00000000-0000-0000-0000-000000000001', 2, '1000000001', 'credit', 100000000000, 'MYR', 'Opening balance'),
+       ('00000000-0000-0000-0000-000000000001', 3, '12345678', 'credit', 100000, 'MYR', 'Opening balance'),
+       ('00000000-0000-0000-0000-000000000001', 4, '87654321', 'credit', 100000, 'MYR', 'Opening balance');
+
+-- Demo users, each owning one of the demo accounts
+INSERT INTO users (user_id, name, email, phone)
+VALUES ('00000000-0000-0000-0000-0000000000a1', 'Alice Tan', 'alice@example.com', '+60123456789'),
+       ('00000000-0000-0000-0000-0000000000b2', 'Bala Kumar', 'bala@example.com', '+60198765432');
+
+INSERT INTO user_account (user_id, account_id, role)
+VALUES ('00000000-0000-0000-0000-0000000000a1', '12345678', 'OWNER'),
+       ('00000000-0000-0000-0000-0000000000b2', '87654321', 'OWNER');
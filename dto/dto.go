@@ -1,19 +1,24 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type CreateTransferRequest struct {
-	Currency           string                             `json:"currency" binding:"required,oneof=MYR"`
+	Currency           string                             `json:"currency" binding:"required,currency"`
 	Amount             int64                              `json:"amount" binding:"required,gt=0"`        // must be positive, in minor unit
 	SourceAccount      CreateTransferRequestAccountDetail `json:"sourceAccount" binding:"required"`      // required nested struct
 	DestinationAccount CreateTransferRequestAccountDetail `json:"destinationAccount" binding:"required"` // required nested struct
 	Properties         map[string]interface{}             `json:"properties"`                            // flexible metadata
 	Note               string                             `json:"note"`                                  // optional
 	IdempotencyKey     string                             `json:"idempotencyKey" binding:"required"`     // must be present for idempotency
+	QuoteID            string                             `json:"quoteID"`                               // FX quote, required for cross-currency transfers
+	ExecuteAt          *time.Time                         `json:"executeAt"`                             // optional, schedules the transfer for later
 }
 
 type CreateTransferRequestAccountDetail struct {
-	Number string `json:"number" binding:"required"`
+	Number string `json:"number" binding:"required,accountnumber"`
 }
 
 type CreateTransferResponse struct {
@@ -22,13 +27,107 @@ type CreateTransferResponse struct {
 	Amount         int64  `json:"amount"`
 	Currency       string `json:"currency"`
 	Status         string `json:"status"`
+	StatusReason   string `json:"statusReason,omitempty"`
+
+	QuoteID             string `json:"quoteID,omitempty"`             // set on cross-currency transfers
+	DestinationAmount   int64  `json:"destinationAmount,omitempty"`   // amount credited, cross-currency only
+	DestinationCurrency string `json:"destinationCurrency,omitempty"` // currency credited, cross-currency only
+
+	ParentTransactionID string     `json:"parentTransactionID,omitempty"` // transfer a reversal refunds
+	RefundedAmount      int64      `json:"refundedAmount,omitempty"`      // refunded by reversals so far, in minor unit
+	ExecuteAt           *time.Time `json:"executeAt,omitempty"`           // set on scheduled transfers
+
+	Fees     []*FeeResponse `json:"fees,omitempty"`     // fee breakdown, in the transfer currency
+	TotalFee int64          `json:"totalFee,omitempty"` // charged on top of amount, in minor unit
+}
+
+// GetTransferRequest looks a transfer up by exactly one of its transaction ID and the idempotency key it
+// was created with
+type GetTransferRequest struct {
+	TransactionID  string `uri:"transactionID"`
+	IdempotencyKey string `form:"idempotencyKey"`
+}
+
+// TransferDetailResponse is a transfer with everything recorded about it
+type TransferDetailResponse struct {
+	TransactionID           string          `json:"transactionID"`
+	IdempotencyKey          string          `json:"idempotencyKey,omitempty"` // empty once released after its retention window
+	TxType                  string          `json:"txType"`
+	UserID                  string          `json:"userID,omitempty"` // user who initiated the transfer
+	Status                  string          `json:"status"`
+	StatusReason            string          `json:"statusReason,omitempty"`
+	StatusReasonDescription string          `json:"statusReasonDescription,omitempty"`
+	Amount                  int64           `json:"amount"`
+	Currency                string          `json:"currency"`
+	SourceAccountID         string          `json:"sourceAccountID"`
+	SourceAccount           json.RawMessage `json:"sourceAccount"` // snapshot taken when the transfer was executed
+	DestinationAccountID    string          `json:"destinationAccountID"`
+	DestinationAccount      json.RawMessage `json:"destinationAccount"` // snapshot taken when the transfer was executed
+	Note                    string          `json:"note"`
+	Properties              json.RawMessage `json:"properties,omitempty"`
+
+	QuoteID             string     `json:"quoteID,omitempty"`
+	DestinationAmount   int64      `json:"destinationAmount,omitempty"`
+	DestinationCurrency string     `json:"destinationCurrency,omitempty"`
+	HoldID              string     `json:"holdID,omitempty"`
+	ParentTransactionID string     `json:"parentTransactionID,omitempty"`
+	RefundedAmount      int64      `json:"refundedAmount,omitempty"`
+	ExecuteAt           *time.Time `json:"executeAt,omitempty"`
+
+	Fees     []*FeeResponse         `json:"fees,omitempty"`
+	TotalFee int64                  `json:"totalFee,omitempty"`
+	Legs     []*TransferLegResponse `json:"legs"` // ledger legs booked by the transfer, none if it failed
+
+	CreatedAt time.Time  `json:"createdAt"`
+	ValuedAt  *time.Time `json:"valuedAt,omitempty"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+type TransferLegResponse struct {
+	EntryID   int64  `json:"entryID"`
+	AccountID string `json:"accountID"`
+	Type      string `json:"type"`
+	Amount    int64  `json:"amount"`
+	Currency  string `json:"currency"`
+	Note      string `json:"note"`
+}
+
+type RescheduleTransferRequest struct {
+	ExecuteAt time.Time `json:"executeAt" binding:"required"`
+}
+
+// ReverseTransferRequest refunds all or part of a completed transfer
+type ReverseTransferRequest struct {
+	IdempotencyKey string `json:"idempotencyKey" binding:"required"`
+	Amount         int64  `json:"amount" binding:"omitempty,gt=0"` // in minor unit, defaults to everything not refunded yet
+	Reason         string `json:"reason"`                          // optional, kept as the note of the reversal
+}
+
+type FeeResponse struct {
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"` // in minor unit
+}
+
+// PreviewFeesRequest describes a transfer to price without creating it
+type PreviewFeesRequest struct {
+	TxType    string `json:"txType" binding:"required,oneof=TRANSFER DEPOSIT WITHDRAWAL"`
+	AccountID string `json:"accountID" binding:"required,accountnumber"` // account paying the fees: the source of transfers and withdrawals, the destination of deposits
+	Currency  string `json:"currency" binding:"required,currency"`
+	Amount    int64  `json:"amount" binding:"required,gt=0"` // in minor unit
+}
+
+type PreviewFeesResponse struct {
+	Currency string         `json:"currency"`
+	Amount   int64          `json:"amount"`
+	Fees     []*FeeResponse `json:"fees"`
+	TotalFee int64          `json:"totalFee"`
 }
 
 type CreateDepositRequest struct {
-	IdempotencyKey string `json:"idempotencyKey" binding:"required"`            // target account
-	AccountID      string `json:"accountID" binding:"required"`                 // target account
-	Amount         int64  `json:"amount" binding:"required,gt=0,lt=9999999999"` // must be positive, in minor units
-	Currency       string `json:"currency" binding:"required,oneof=MYR"`
+	IdempotencyKey string `json:"idempotencyKey" binding:"required"`          // target account
+	AccountID      string `json:"accountID" binding:"required,accountnumber"` // target account
+	Amount         int64  `json:"amount" binding:"required,gt=0"`             // must be positive, in minor units
+	Currency       string `json:"currency" binding:"required,currency"`
 	Note           string `json:"note"` // optional
 }
 
@@ -38,10 +137,10 @@ type CreateDepositResponse struct {
 }
 
 type CreateWithdrawalRequest struct {
-	IdempotencyKey string `json:"idempotencyKey" binding:"required"` // target account
-	AccountID      string `json:"accountID" binding:"required"`      // target account
-	Amount         int64  `json:"amount" binding:"required,gt=0"`    // must be positive, in minor units
-	Currency       string `json:"currency" binding:"required,oneof=MYR"`
+	IdempotencyKey string `json:"idempotencyKey" binding:"required"`          // target account
+	AccountID      string `json:"accountID" binding:"required,accountnumber"` // target account
+	Amount         int64  `json:"amount" binding:"required,gt=0"`             // must be positive, in minor units
+	Currency       string `json:"currency" binding:"required,currency"`
 	Note           string `json:"note"` // optional
 }
 
@@ -50,23 +149,361 @@ type CreateWithdrawalResponse struct {
 	Status        string `json:"status"`
 }
 
+// OpenAccountRequest opens a customer account. Its account number is generated.
+type OpenAccountRequest struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	Type     string `json:"type" binding:"omitempty,oneof=WALLET CASA"` // defaults to WALLET
+	Currency string `json:"currency" binding:"required,currency"`
+}
+
+// UpdateAccountRequest renames an account or changes its type. Fields left empty are not changed.
+type UpdateAccountRequest struct {
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+	Name      string `json:"name" binding:"omitempty,max=255"`
+	Type      string `json:"type" binding:"omitempty,oneof=WALLET CASA"`
+}
+
+// FreezeAccountRequest freezes or unfreezes debits, credits or both of an account
+type FreezeAccountRequest struct {
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+	Scope     string `json:"scope" binding:"required,oneof=DEBIT CREDIT ALL"`
+}
+
+type AccountRequest struct {
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+}
+
+type AccountResponse struct {
+	AccountID string     `json:"accountID"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	Currency  string     `json:"currency"`
+	Status    string     `json:"status"`           // ACTIVE, FROZEN or CLOSED
+	Freeze    string     `json:"freeze,omitempty"` // DEBIT, CREDIT or ALL while FROZEN
+	Balance   int64      `json:"balance"`          // in minor unit
+	CreatedAt time.Time  `json:"createdAt"`
+	ClosedAt  *time.Time `json:"closedAt,omitempty"`
+}
+
+// CreateUserRequest registers a user. Their user ID is generated.
+type CreateUserRequest struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Email string `json:"email" binding:"required,email,max=255"`
+	Phone string `json:"phone" binding:"omitempty,e164"`
+}
+
+// UpdateUserRequest changes the contact details of a user. Fields left empty are not changed.
+type UpdateUserRequest struct {
+	UserID string `json:"userID" binding:"required,uuid"`
+	Name   string `json:"name" binding:"omitempty,max=255"`
+	Email  string `json:"email" binding:"omitempty,email,max=255"`
+	Phone  string `json:"phone" binding:"omitempty,e164"`
+}
+
+// UserStatusRequest suspends, reactivates or closes a user
+type UserStatusRequest struct {
+	UserID string `json:"userID" binding:"required,uuid"`
+	Status string `json:"status" binding:"required,oneof=ACTIVE SUSPENDED CLOSED"`
+}
+
+type UserRequest struct {
+	UserID string `json:"userID" binding:"required,uuid"`
+}
+
+type UserResponse struct {
+	UserID    string                 `json:"userID"`
+	Name      string                 `json:"name"`
+	Email     string                 `json:"email"`
+	Phone     string                 `json:"phone,omitempty"`
+	Status    string                 `json:"status"` // ACTIVE, SUSPENDED or CLOSED
+	Accounts  []*UserAccountResponse `json:"accounts"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+// LinkAccountRequest gives a user a role on an account, or changes the role they have
+type LinkAccountRequest struct {
+	UserID    string `json:"userID" binding:"required,uuid"`
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+	Role      string `json:"role" binding:"required,oneof=OWNER JOINT VIEWER"`
+}
+
+type UnlinkAccountRequest struct {
+	UserID    string `json:"userID" binding:"required,uuid"`
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+}
+
+type UserAccountResponse struct {
+	AccountID string    `json:"accountID"`
+	Role      string    `json:"role"` // OWNER, JOINT or VIEWER
+	LinkedAt  time.Time `json:"linkedAt"`
+}
+
 type GetAccountTransactionsRequest struct {
-	AccountID string `json:"accountID" binding:"required"`
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
 	Limit     int    `json:"limit" binding:"omitempty,min=1,max=100"`
 	NextToken string `json:"nextToken" binding:"omitempty"`
 }
 
 type TransactionResponse struct {
-	TransactionID string    `json:"transactionID"`
-	TxType        string    `json:"txType"`
-	Status        string    `json:"status"`
-	Amount        int64     `json:"amount"`
-	Currency      string    `json:"currency"`
-	CreatedAt     time.Time `json:"createdAt"`
-	Note          string    `json:"note"`
+	TransactionID           string    `json:"transactionID"`
+	TxType                  string    `json:"txType"`
+	Status                  string    `json:"status"`
+	StatusReason            string    `json:"statusReason,omitempty"`            // set on failed transfers
+	StatusReasonDescription string    `json:"statusReasonDescription,omitempty"` // set on failed transfers
+	Fee                     int64     `json:"fee,omitempty"`                     // fees paid by the account, on top of amount
+	ParentTransactionID     string    `json:"parentTransactionID,omitempty"`     // set on reversals, the transfer they refund
+	RefundedAmount          int64     `json:"refundedAmount,omitempty"`          // refunded by reversals so far
+	Amount                  int64     `json:"amount"`
+	Currency                string    `json:"currency"`
+	CreatedAt               time.Time `json:"createdAt"`
+	Note                    string    `json:"note"`
 }
 
 type GetAccountTransactionsResponse struct {
 	Data      []*TransactionResponse `json:"data"`
 	NextToken string                 `json:"nextToken,omitempty"`
 }
+
+type GetAccountLedgerRequest struct {
+	AccountID string     `json:"accountID" binding:"required,accountnumber"`
+	Type      string     `json:"type" binding:"omitempty,oneof=credit debit"`
+	Currency  string     `json:"currency" binding:"omitempty,currency"`
+	From      *time.Time `json:"from" binding:"omitempty"` // inclusive
+	To        *time.Time `json:"to" binding:"omitempty"`   // exclusive
+	Limit     int        `json:"limit" binding:"omitempty,min=1,max=100"`
+	NextToken string     `json:"nextToken" binding:"omitempty"`
+}
+
+type LedgerEntryResponse struct {
+	EntryID        int64     `json:"entryID"`
+	TransactionID  string    `json:"transactionID"` // transfer the entry belongs to
+	Type           string    `json:"type"`
+	Amount         int64     `json:"amount"`
+	Currency       string    `json:"currency"`
+	RunningBalance int64     `json:"runningBalance"` // balance right after this entry, in minor unit
+	Note           string    `json:"note"`
+	ValuedAt       time.Time `json:"valuedAt"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type GetAccountLedgerResponse struct {
+	Data      []*LedgerEntryResponse `json:"data"`
+	NextToken string                 `json:"nextToken,omitempty"`
+}
+
+// CreateFxQuoteRequest fixes exactly one of SourceAmount and DestinationAmount; the other is quoted
+type CreateFxQuoteRequest struct {
+	SourceCurrency      string `json:"sourceCurrency" binding:"required,currency"`
+	DestinationCurrency string `json:"destinationCurrency" binding:"required,currency"`
+	SourceAmount        int64  `json:"sourceAmount" binding:"omitempty,gt=0"`      // in minor unit of the source currency
+	DestinationAmount   int64  `json:"destinationAmount" binding:"omitempty,gt=0"` // in minor unit of the destination currency
+}
+
+type FxQuoteResponse struct {
+	QuoteID             string    `json:"quoteID"`
+	SourceCurrency      string    `json:"sourceCurrency"`
+	DestinationCurrency string    `json:"destinationCurrency"`
+	Rate                string    `json:"rate"`              // destination units per source unit
+	SourceAmount        int64     `json:"sourceAmount"`      // debited, in minor unit
+	DestinationAmount   int64     `json:"destinationAmount"` // credited, in minor unit
+	ExpiresAt           time.Time `json:"expiresAt"`
+}
+
+type GetAccountLimitsRequest struct {
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
+}
+
+// AccountLimitsResponse lists the limits of an account. Limits that are not set are left out.
+type AccountLimitsResponse struct {
+	AccountID  string              `json:"accountID"`
+	Currency   string              `json:"currency"`
+	Tier       string              `json:"tier"`
+	MaxBalance *LimitUsageResponse `json:"maxBalance,omitempty"` // used is the current balance
+	Limits     []*TxLimitResponse  `json:"limits"`
+}
+
+type TxLimitResponse struct {
+	TxType    string                   `json:"txType"`
+	MinAmount int64                    `json:"minAmount,omitempty"` // per transaction
+	MaxAmount int64                    `json:"maxAmount,omitempty"` // per transaction
+	Daily     *LimitUsageResponse      `json:"daily,omitempty"`     // amount per calendar day
+	Monthly   *LimitUsageResponse      `json:"monthly,omitempty"`   // amount per calendar month
+	Velocity  []*VelocityUsageResponse `json:"velocity,omitempty"`  // number of transactions per rolling window
+}
+
+type LimitUsageResponse struct {
+	Limit     int64 `json:"limit"`
+	Used      int64 `json:"used"`
+	Remaining int64 `json:"remaining"`
+}
+
+type VelocityUsageResponse struct {
+	Window string `json:"window"` // e.g. 1m0s
+	LimitUsageResponse
+}
+
+// CreateTransferBatchRequest submits many transfers at once. It can also be uploaded as CSV, see
+// handler.CreateTransferBatch.
+type CreateTransferBatchRequest struct {
+	IdempotencyKey string                      `json:"idempotencyKey" binding:"required"`
+	Mode           string                      `json:"mode" binding:"omitempty,oneof=INDEPENDENT ATOMIC"` // defaults to INDEPENDENT
+	Items          []*TransferBatchItemRequest `json:"items" binding:"required,min=1,max=10000,dive"`
+}
+
+type TransferBatchItemRequest struct {
+	Reference            string `json:"reference"` // optional, the client's own reference, e.g. an employee ID
+	SourceAccountID      string `json:"sourceAccountID" binding:"required,accountnumber"`
+	DestinationAccountID string `json:"destinationAccountID" binding:"required,accountnumber"`
+	Amount               int64  `json:"amount" binding:"required,gt=0"` // in minor unit
+	Currency             string `json:"currency" binding:"required,currency"`
+	Note                 string `json:"note"` // optional
+}
+
+type GetTransferBatchRequest struct {
+	BatchID   string `uri:"batchID" binding:"required"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=1000"` // items per page, defaults to 100
+	NextToken string `form:"nextToken"`
+}
+
+type TransferBatchResponse struct {
+	BatchID        string                       `json:"batchID"`
+	Mode           string                       `json:"mode"`
+	Status         string                       `json:"status"`
+	ItemCount      int                          `json:"itemCount"`
+	PendingCount   int                          `json:"pendingCount"`
+	CompletedCount int                          `json:"completedCount"`
+	FailedCount    int                          `json:"failedCount"`
+	CreatedAt      time.Time                    `json:"createdAt"`
+	FinishedAt     *time.Time                   `json:"finishedAt,omitempty"`
+	Items          []*TransferBatchItemResponse `json:"items,omitempty"`
+	NextToken      string                       `json:"nextToken,omitempty"` // set when there are more items
+}
+
+type TransferBatchItemResponse struct {
+	Index                int    `json:"index"` // position in the submitted list, from 0
+	Reference            string `json:"reference,omitempty"`
+	SourceAccountID      string `json:"sourceAccountID"`
+	DestinationAccountID string `json:"destinationAccountID"`
+	Amount               int64  `json:"amount"`
+	Currency             string `json:"currency"`
+	Status               string `json:"status"`
+	StatusReason         string `json:"statusReason,omitempty"`  // error code of a failed item
+	TransactionID        string `json:"transactionID,omitempty"` // transfer made for a completed item
+}
+
+type PlaceHoldRequest struct {
+	IdempotencyKey       string `json:"idempotencyKey" binding:"required"`
+	AccountID            string `json:"accountID" binding:"required,accountnumber"`            // account the funds are reserved on
+	DestinationAccountID string `json:"destinationAccountID" binding:"required,accountnumber"` // account captures are paid into
+	Amount               int64  `json:"amount" binding:"required,gt=0"`                        // in minor unit
+	Currency             string `json:"currency" binding:"required,currency"`
+	Reason               string `json:"reason"`                                    // optional
+	ExpiresInSeconds     int64  `json:"expiresInSeconds" binding:"omitempty,gt=0"` // optional, defaults to the configured hold duration
+}
+
+type CaptureHoldRequest struct {
+	IdempotencyKey string `json:"idempotencyKey" binding:"required"`
+	HoldID         string `json:"holdID" binding:"required"`
+	Amount         int64  `json:"amount" binding:"omitempty,gt=0"` // in minor unit, defaults to everything still held
+	Note           string `json:"note"`                            // optional
+}
+
+type ExtendHoldRequest struct {
+	HoldID           string `json:"holdID" binding:"required"`
+	ExpiresInSeconds int64  `json:"expiresInSeconds" binding:"required,gt=0"` // new expiry, counted from now
+}
+
+type HoldRequest struct {
+	HoldID string `json:"holdID" binding:"required"`
+}
+
+type HoldResponse struct {
+	HoldID               string    `json:"holdID"`
+	AccountID            string    `json:"accountID"`
+	DestinationAccountID string    `json:"destinationAccountID"`
+	Currency             string    `json:"currency"`
+	Amount               int64     `json:"amount"`          // originally held, in minor unit
+	CapturedAmount       int64     `json:"capturedAmount"`  // in minor unit
+	ReleasedAmount       int64     `json:"releasedAmount"`  // in minor unit
+	RemainingAmount      int64     `json:"remainingAmount"` // still held, in minor unit
+	Status               string    `json:"status"`
+	Reason               string    `json:"reason,omitempty"`
+	ExpiresAt            time.Time `json:"expiresAt"`
+	CreatedAt            time.Time `json:"createdAt"`
+}
+
+type CreateStandingInstructionRequest struct {
+	IdempotencyKey       string     `json:"idempotencyKey" binding:"required"`
+	SourceAccountID      string     `json:"sourceAccountID" binding:"required,accountnumber"`
+	DestinationAccountID string     `json:"destinationAccountID" binding:"required,accountnumber"`
+	Amount               int64      `json:"amount" binding:"required,gt=0"` // per occurrence, in minor unit
+	Currency             string     `json:"currency" binding:"required,currency"`
+	Note                 string     `json:"note"`                                    // optional
+	Schedule             string     `json:"schedule" binding:"required"`             // RRULE, e.g. FREQ=MONTHLY;BYMONTHDAY=1
+	StartAt              time.Time  `json:"startAt" binding:"required"`              // first possible occurrence, also sets the time of day
+	EndAt                *time.Time `json:"endAt"`                                   // optional, no occurrence after it
+	MaxOccurrences       int        `json:"maxOccurrences" binding:"omitempty,gt=0"` // optional
+	FailurePolicy        string     `json:"failurePolicy" binding:"required,oneof=SKIP RETRY SUSPEND"`
+	MaxRetries           int        `json:"maxRetries" binding:"required_if=FailurePolicy RETRY,min=0,max=10"` // RETRY only
+}
+
+type StandingInstructionRequest struct {
+	InstructionID string `json:"instructionID" binding:"required"`
+}
+
+type StandingInstructionResponse struct {
+	InstructionID        string     `json:"instructionID"`
+	SourceAccountID      string     `json:"sourceAccountID"`
+	DestinationAccountID string     `json:"destinationAccountID"`
+	Amount               int64      `json:"amount"`
+	Currency             string     `json:"currency"`
+	Note                 string     `json:"note,omitempty"`
+	Schedule             string     `json:"schedule"`
+	StartAt              time.Time  `json:"startAt"`
+	EndAt                *time.Time `json:"endAt,omitempty"`
+	MaxOccurrences       int        `json:"maxOccurrences,omitempty"`
+	FailurePolicy        string     `json:"failurePolicy"`
+	MaxRetries           int        `json:"maxRetries,omitempty"`
+	Status               string     `json:"status"`
+	NextOccurrenceAt     *time.Time `json:"nextOccurrenceAt,omitempty"`
+	Occurrences          int        `json:"occurrences"`       // run so far, paid or not
+	FailedOccurrences    int        `json:"failedOccurrences"` // skipped after failing
+	LastTransactionID    string     `json:"lastTransactionID,omitempty"`
+	LastError            string     `json:"lastError,omitempty"` // code of the last failure
+	CreatedAt            time.Time  `json:"createdAt"`
+}
+
+type CreateReconciliationRequest struct {
+	Fix       bool   `json:"fix"`                                   // write correction journals for drifts found
+	Reason    string `json:"reason" binding:"required_if=Fix true"` // audit reason, required when fix is true
+	BatchSize int    `json:"batchSize" binding:"omitempty,min=1,max=5000"`
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+type ErrorDetail struct {
+	Code    string             `json:"code"`    // stable, machine-readable
+	Message string             `json:"message"` // localized per Accept-Language
+	Fields  []FieldErrorDetail `json:"fields,omitempty"`
+}
+
+type FieldErrorDetail struct {
+	Field   string `json:"field"` // JSON path of the field, e.g. sourceAccount.number
+	Rule    string `json:"rule"`  // validation rule that failed, e.g. required
+	Message string `json:"message"`
+}
+
+// APIKeyResponse describes an API key. Secret is only set when the key is issued, it cannot be read again.
+type APIKeyResponse struct {
+	KeyID     string     `json:"keyID"`
+	ClientID  string     `json:"clientID"`
+	Secret    string     `json:"secret,omitempty"`
+	Scopes    []string   `json:"scopes"`
+	Status    string     `json:"status"`              // ACTIVE or REVOKED
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"` // set on a rotated key, which keeps working until then
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+)
+
+func (p *WalletService) OpenAccount(c *gin.Context) {
+	var req dto.OpenAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, openErr := p.accountLogic.OpenAccount(c.Request.Context(), &req)
+	if openErr != nil {
+		respondError(c, openErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) UpdateAccount(c *gin.Context) {
+	var req dto.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, updateErr := p.accountLogic.UpdateAccount(c.Request.Context(), &req)
+	if updateErr != nil {
+		respondError(c, updateErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) FreezeAccount(c *gin.Context) {
+	var req dto.FreezeAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, freezeErr := p.accountLogic.FreezeAccount(c.Request.Context(), &req)
+	if freezeErr != nil {
+		respondError(c, freezeErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) UnfreezeAccount(c *gin.Context) {
+	var req dto.FreezeAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, unfreezeErr := p.accountLogic.UnfreezeAccount(c.Request.Context(), &req)
+	if unfreezeErr != nil {
+		respondError(c, unfreezeErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) CloseAccount(c *gin.Context) {
+	var req dto.AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, closeErr := p.accountLogic.CloseAccount(c.Request.Context(), req.AccountID)
+	if closeErr != nil {
+		respondError(c, closeErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// checkAccountVisible responds ACCOUNT_NOT_FOUND and returns false unless the user the request is made for is
+// linked to accountID, with any role. Services acting for themselves see every account.
+func (p *WalletService) checkAccountVisible(c *gin.Context, accountID string) bool {
+	user := userID(c)
+	if user == "" {
+		return true
+	}
+	if err := p.userLogic.CheckViewAccess(c.Request.Context(), user, accountID); err != nil {
+		respondError(c, err)
+		return false
+	}
+	return true
+}
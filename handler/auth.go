@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strings"
+	"wallet/apperr"
+	"wallet/auth"
+	"wallet/logic/apikey"
+)
+
+// Authenticate requires a valid JWT bearer token, or an API key signature, on every request and puts the
+// principal the credentials were issued to in the request context, where handlers and logic find it with
+// auth.FromContext. Requests carrying an X-Api-Key header are checked as signed requests; keys may be nil
+// to accept bearer tokens only.
+func Authenticate(verifier *auth.Verifier, keys apikey.IAPIKeyLogic) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if keyID := c.GetHeader(auth.HeaderKeyID); keyID != "" && keys != nil {
+			authenticateSigned(c, keys, keyID)
+			return
+		}
+		token, ok := bearerToken(c.GetHeader("Authorization"))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="wallet"`)
+			respondError(c, apperr.New(apperr.CodeUnauthenticated))
+			return
+		}
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="wallet", error="invalid_token"`)
+			respondError(c, apperr.New(apperr.CodeUnauthenticated).Wrap(err))
+			return
+		}
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// maxBodySize caps the bodies read whole before a handler binds them, e.g. to check their signature. It
+// leaves room for the largest transfer batch.
+const maxBodySize = 8 << 20
+
+// readBody reads the body of the request, up to maxBodySize, and puts it back for the handlers after
+func readBody(c *gin.Context) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBodySize))
+	if err != nil {
+		return nil, err
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// authenticateSigned checks the signature of a request signed with an API key. The body is read to check
+// it and put back for the handler.
+func authenticateSigned(c *gin.Context, keys apikey.IAPIKeyLogic, keyID string) {
+	body, err := readBody(c)
+	if err != nil {
+		respondError(c, apperr.New(apperr.CodeInvalidRequest).Wrap(err))
+		return
+	}
+	principal, err := keys.Authenticate(c.Request.Context(), &apikey.SignedRequest{
+		KeyID:      keyID,
+		Timestamp:  c.GetHeader(auth.HeaderTimestamp),
+		Nonce:      c.GetHeader(auth.HeaderNonce),
+		Signature:  c.GetHeader(auth.HeaderSignature),
+		Method:     c.Request.Method,
+		RequestURI: c.Request.URL.RequestURI(),
+		Body:       body,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+	c.Next()
+}
+
+// RequireScope lets through requests whose principal was granted scope. It must come after Authenticate.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			respondError(c, apperr.New(apperr.CodeUnauthenticated))
+			return
+		}
+		if !principal.HasScope(scope) {
+			c.Header("WWW-Authenticate", `Bearer realm="wallet", error="insufficient_scope", scope="`+scope+`"`)
+			respondError(c, apperr.New(apperr.CodeInsufficientScope))
+			return
+		}
+		c.Next()
+	}
+}
+
+func bearerToken(authorization string) (string, bool) {
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
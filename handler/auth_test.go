@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"wallet/apperr"
+	"wallet/auth"
+	"wallet/logic/apikey"
+	apikeymock "wallet/logic/apikey/mocks"
+)
+
+func TestAuthenticate(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, secret, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := auth.NewVerifier(&auth.Config{HMACSecretFile: secretFile, Audience: "wallet"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := func(scope string) string {
+		signed, signErr := auth.SignHS256(&auth.Claims{
+			Subject:   "user-1",
+			Audience:  auth.Audience{"wallet"},
+			ExpiresAt: time.Now().Add(time.Hour).Unix(),
+			Scope:     scope,
+			ClientID:  "mobile-app",
+		}, secret)
+		if signErr != nil {
+			t.Fatal(signErr)
+		}
+		return signed
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/accounts", Authenticate(verifier, nil), RequireScope(auth.ScopeAccountsRead), func(c *gin.Context) {
+		c.String(http.StatusOK, userID(c)+"@"+clientID(c))
+	})
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:          "happy path - principal reaches the handler",
+			authorization: "Bearer " + token("accounts:read transfers:write"),
+			wantStatus:    http.StatusOK,
+			wantBody:      "user-1@mobile-app",
+		},
+		{
+			name:       "error - no token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "error - not a bearer token",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "error - invalid token",
+			authorization: "Bearer " + token("accounts:read") + "x",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "error - scope not granted",
+			authorization: "Bearer " + token("transfers:write"),
+			wantStatus:    http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/accounts", nil)
+			req.Header.Set("X-User-ID", "someone-else")
+			req.Header.Set("X-Client-ID", "another-app")
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if tt.wantBody != "" && w.Body.String() != tt.wantBody {
+				t.Errorf("body = %q, want %q", w.Body.String(), tt.wantBody)
+			}
+			if w.Code == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("WWW-Authenticate header missing on 401")
+			}
+		})
+	}
+}
+
+func TestAuthenticate_signedRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		authErr    error
+		wantStatus int
+	}{
+		{
+			name:       "happy path - body still reaches the handler",
+			body:       `{"amount":"10.00"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "error - signature rejected",
+			body:       `{"amount":"10.00"}`,
+			authErr:    apperr.New(apperr.CodeUnauthenticated).Wrap(auth.ErrInvalidSignature),
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "error - body too large to read",
+			body:       strings.Repeat(" ", maxBodySize+1),
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys := apikeymock.NewMockIAPIKeyLogic(t)
+			if tt.wantStatus != http.StatusBadRequest {
+				keys.EXPECT().Authenticate(mock.Anything, mock.MatchedBy(func(req *apikey.SignedRequest) bool {
+					return req.KeyID == "ak_1" && req.Method == http.MethodPost && req.RequestURI == "/transfers?dryRun=true" &&
+						req.Timestamp == "1772366400" && req.Nonce == "0123456789abcdef" && req.Signature == "abcd" &&
+						string(req.Body) == tt.body
+				})).Return(&auth.Principal{Subject: "payroll-acme", ClientID: "payroll-acme", Scopes: []string{auth.ScopeTransfersWrite}}, tt.authErr).Once()
+			}
+
+			gin.SetMode(gin.TestMode)
+			r := gin.New()
+			r.POST("/transfers", Authenticate(nil, keys), RequireScope(auth.ScopeTransfersWrite), func(c *gin.Context) {
+				got, _ := io.ReadAll(c.Request.Body)
+				c.String(http.StatusOK, userID(c)+"@"+clientID(c)+" "+string(got))
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/transfers?dryRun=true", strings.NewReader(tt.body))
+			req.Header.Set(auth.HeaderKeyID, "ak_1")
+			req.Header.Set(auth.HeaderTimestamp, "1772366400")
+			req.Header.Set(auth.HeaderNonce, "0123456789abcdef")
+			req.Header.Set(auth.HeaderSignature, "abcd")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if w.Code == http.StatusOK && w.Body.String() != "@payroll-acme "+tt.body {
+				t.Errorf("body = %q", w.Body.String())
+			}
+		})
+	}
+}
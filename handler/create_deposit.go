@@ -2,7 +2,6 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
-	"net/http"
 	"wallet/dto"
 	"wallet/logic/transfer"
 )
@@ -10,30 +9,26 @@ import (
 func (p *WalletService) CreateDeposit(c *gin.Context) {
 	var req dto.CreateDepositRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 	res, createErr := p.transferLogic.CreateTransfer(c.Request.Context(),
 		createDepositRequestToCreateTransferRequest(&req),
 		&transfer.CreateTransferOpts{
-			TxType: transfer.TxTypeDeposit,
+			TxType:   transfer.TxTypeDeposit,
+			ClientID: clientID(c),
+			UserID:   userID(c),
 		})
 	if createErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create transfer",
-			"details": createErr.Error(),
-		})
+		respondError(c, createErr)
 		return
 	}
-	c.JSON(http.StatusOK, res)
+	respondTransfer(c, res)
 }
 
 func createDepositRequestToCreateTransferRequest(req *dto.CreateDepositRequest) *dto.CreateTransferRequest {
 	return &dto.CreateTransferRequest{
-		Currency: "MYR",
+		Currency: req.Currency,
 		Amount:   req.Amount,
 		DestinationAccount: dto.CreateTransferRequestAccountDetail{
 			Number: req.AccountID,
@@ -88,7 +88,7 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 			args: func() args {
 				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/deposits", &dto.CreateDepositRequest{
 					IdempotencyKey: "idempotency-key",
-					AccountID:      "destination-account",
+					AccountID:      "87654321",
 					Amount:         1000,
 					Currency:       "MYR",
 					Note:           "Test deposit",
@@ -110,7 +110,7 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 							return req.IdempotencyKey == "idempotency-key" &&
 								req.Amount == 1000 &&
 								req.Currency == "MYR" &&
-								req.DestinationAccount.Number == "destination-account" &&
+								req.DestinationAccount.Number == "87654321" &&
 								req.Note == "Test deposit"
 						}),
 						mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -158,17 +158,14 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 				fields.transferLogic = newMockTransferLogic(t)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: gin.H{
-				"error":   "Invalid request body",
-				"details": mock.Anything,
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INVALID_REQUEST"}},
 		},
 		{
 			name: "error - transfer logic returns error",
 			args: func() args {
 				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/deposits", &dto.CreateDepositRequest{
 					IdempotencyKey: "idempotency-key",
-					AccountID:      "destination-account",
+					AccountID:      "87654321",
 					Amount:         1000,
 					Currency:       "MYR",
 					Note:           "Test deposit",
@@ -190,7 +187,7 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 							return req.IdempotencyKey == "idempotency-key" &&
 								req.Amount == 1000 &&
 								req.Currency == "MYR" &&
-								req.DestinationAccount.Number == "destination-account" &&
+								req.DestinationAccount.Number == "87654321" &&
 								req.Note == "Test deposit"
 						}),
 						mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -201,10 +198,7 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 					Once()
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: gin.H{
-				"error":   "Failed to create transfer",
-				"details": "transfer error",
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INTERNAL_ERROR"}},
 		},
 	}
 
@@ -230,6 +224,13 @@ func TestWalletService_CreateDeposit(t *testing.T) {
 			// Check response
 			require.Equal(t, tt.expectedStatus, tt.args.w.Code)
 
+			if expErr, ok := tt.expectedBody.(*dto.ErrorResponse); ok {
+				var actualErr dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(tt.args.w.Body.Bytes(), &actualErr))
+				require.Equal(t, expErr.Error.Code, actualErr.Error.Code)
+				require.NotEmpty(t, actualErr.Error.Message)
+			}
+
 			if tt.expectedStatus != http.StatusOK {
 				return
 			}
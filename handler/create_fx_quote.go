@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+	"wallet/logic/fx"
+)
+
+func (p *WalletService) CreateFxQuote(c *gin.Context) {
+	var req dto.CreateFxQuoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, quoteErr := p.fxLogic.CreateQuote(c.Request.Context(), &req, &fx.CreateQuoteOpts{
+		ClientID: clientID(c),
+	})
+	if quoteErr != nil {
+		respondError(c, quoteErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
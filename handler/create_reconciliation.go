@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+	"wallet/logic/reconcile"
+)
+
+func (p *WalletService) CreateReconciliation(c *gin.Context) {
+	var req dto.CreateReconciliationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	report, reconcileErr := p.reconcileLogic.Reconcile(c.Request.Context(), &reconcile.Options{
+		Fix:       req.Fix,
+		Reason:    req.Reason,
+		BatchSize: req.BatchSize,
+	})
+	if reconcileErr != nil {
+		respondError(c, reconcileErr)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	"net/http"
+	"wallet/auth"
 	"wallet/dto"
 	"wallet/logic/transfer"
 )
@@ -10,21 +11,52 @@ import (
 func (p *WalletService) CreateTransfer(c *gin.Context) {
 	var req dto.CreateTransferRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 	res, createErr := p.transferLogic.CreateTransfer(c.Request.Context(), &req, &transfer.CreateTransferOpts{
-		TxType: transfer.TxTypeP2PTransfer,
+		TxType:   transfer.TxTypeP2PTransfer,
+		ClientID: clientID(c),
+		UserID:   userID(c),
 	})
 	if createErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create transfer",
-			"details": createErr.Error(),
-		})
+		respondError(c, createErr)
 		return
 	}
+	respondTransfer(c, res)
+}
+
+// respondTransfer writes res. A transfer still PROCESSING or SCHEDULED may yet complete or fail, so the
+// response is not stored for idempotent replay; a retry is answered with the transfer's current status.
+func respondTransfer(c *gin.Context, res *dto.CreateTransferResponse) {
+	if res.Status == transfer.TxStatusPROCESSING || res.Status == transfer.TxStatusSCHEDULED {
+		markUnfinished(c)
+	}
 	c.JSON(http.StatusOK, res)
 }
+
+// clientHeader identifies the calling client when a request carries no credentials, e.g. in tests.
+// Idempotency keys are scoped to the client.
+const clientHeader = "X-Client-ID"
+
+// clientID returns the client the request's credentials were issued to. The X-Client-ID header is only read
+// for requests that are not authenticated, so a caller cannot claim another client.
+func clientID(c *gin.Context) string {
+	if principal, ok := auth.FromContext(c.Request.Context()); ok {
+		return principal.ClientID
+	}
+	return c.GetHeader(clientHeader)
+}
+
+// userHeader identifies the user a request is made for when it carries no credentials, e.g. in tests
+const userHeader = "X-User-ID"
+
+// userID returns the user the request is made for. It is the principal's user once the request is
+// authenticated, and empty for services acting for themselves, whose transfers are not checked against user
+// roles.
+func userID(c *gin.Context) string {
+	if principal, ok := auth.FromContext(c.Request.Context()); ok {
+		return principal.UserID()
+	}
+	return c.GetHeader(userHeader)
+}
@@ -45,10 +45,10 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 					Amount:         1000,
 					Currency:       "MYR",
 					SourceAccount: dto.CreateTransferRequestAccountDetail{
-						Number: "source-account",
+						Number: "12345678",
 					},
 					DestinationAccount: dto.CreateTransferRequestAccountDetail{
-						Number: "destination-account",
+						Number: "87654321",
 					},
 					Note: "Test transfer",
 				})
@@ -68,8 +68,8 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 						return req.IdempotencyKey == "idempotency-key" &&
 							req.Amount == 1000 &&
 							req.Currency == "MYR" &&
-							req.SourceAccount.Number == "source-account" &&
-							req.DestinationAccount.Number == "destination-account" &&
+							req.SourceAccount.Number == "12345678" &&
+							req.DestinationAccount.Number == "87654321" &&
 							req.Note == "Test transfer"
 					}),
 					mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -119,10 +119,7 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 				fields.transferLogic = transfermock.NewMockITransferLogic(t)
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: gin.H{
-				"error":   "Invalid request body",
-				"details": mock.Anything,
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INVALID_REQUEST"}},
 		},
 		{
 			name: "error - transfer logic returns error",
@@ -132,10 +129,10 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 					Amount:         1000,
 					Currency:       "MYR",
 					SourceAccount: dto.CreateTransferRequestAccountDetail{
-						Number: "source-account",
+						Number: "12345678",
 					},
 					DestinationAccount: dto.CreateTransferRequestAccountDetail{
-						Number: "destination-account",
+						Number: "87654321",
 					},
 					Note: "Test transfer",
 				})
@@ -155,8 +152,8 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 						return req.IdempotencyKey == "idempotency-key" &&
 							req.Amount == 1000 &&
 							req.Currency == "MYR" &&
-							req.SourceAccount.Number == "source-account" &&
-							req.DestinationAccount.Number == "destination-account" &&
+							req.SourceAccount.Number == "12345678" &&
+							req.DestinationAccount.Number == "87654321" &&
 							req.Note == "Test transfer"
 					}),
 					mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -169,10 +166,46 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 				fields.transferLogic = mockTransferLogic
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: gin.H{
-				"error":   "Failed to create transfer",
-				"details": "transfer error",
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INTERNAL_ERROR"}},
+		},
+		{
+			name: "error - idempotency key reused for a different request",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/payment/transfers", &dto.CreateTransferRequest{
+					IdempotencyKey: "idempotency-key",
+					Amount:         1000,
+					Currency:       "MYR",
+					SourceAccount: dto.CreateTransferRequestAccountDetail{
+						Number: "12345678",
+					},
+					DestinationAccount: dto.CreateTransferRequestAccountDetail{
+						Number: "87654321",
+					},
+				})
+				c.Request.Header.Set("X-Client-ID", "client-a")
+				return args{c: c, w: w}
+			}(),
+			setupMocks: func(fields *fields) {
+				fields.validator = newMockValidator()
+				fields.accountDAO = newMockAccountDAO(t)
+				fields.transferDAO = newMockTransferDAO(t)
+				fields.transactionDAO = newMockTransactionDAO(t)
+
+				mockTransferLogic := transfermock.NewMockITransferLogic(t)
+				mockTransferLogic.On("CreateTransfer",
+					mock.Anything,
+					mock.Anything,
+					mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
+						return opts.TxType == transfer.TxTypeP2PTransfer && opts.ClientID == "client-a"
+					}),
+				).
+					Return(nil, transfer.IdempotencyKeyReusedErr).
+					Once()
+
+				fields.transferLogic = mockTransferLogic
 			},
+			expectedStatus: http.StatusUnprocessableEntity,
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "IDEMPOTENCY_KEY_REUSED"}},
 		},
 	}
 
@@ -198,6 +231,13 @@ func TestWalletService_CreateTransfer(t *testing.T) {
 			// Check response
 			require.Equal(t, tt.expectedStatus, tt.args.w.Code)
 
+			if expErr, ok := tt.expectedBody.(*dto.ErrorResponse); ok {
+				var actualErr dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(tt.args.w.Body.Bytes(), &actualErr))
+				require.Equal(t, expErr.Error.Code, actualErr.Error.Code)
+				require.NotEmpty(t, actualErr.Error.Message)
+			}
+
 			if tt.expectedStatus != http.StatusOK {
 				return
 			}
@@ -2,7 +2,6 @@ package handler
 
 import (
 	"github.com/gin-gonic/gin"
-	"net/http"
 	"wallet/dto"
 	"wallet/logic/transfer"
 )
@@ -10,28 +9,24 @@ import (
 func (p *WalletService) CreateWithdrawal(c *gin.Context) {
 	var req dto.CreateWithdrawalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error":   "Invalid request body",
-			"details": err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
 	res, createErr := p.transferLogic.CreateTransfer(c.Request.Context(), createWithdrawalRequestToCreateTransferRequest(&req), &transfer.CreateTransferOpts{
-		TxType: transfer.TxTypeWithdrawal,
+		TxType:   transfer.TxTypeWithdrawal,
+		ClientID: clientID(c),
+		UserID:   userID(c),
 	})
 	if createErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error":   "Failed to create transfer",
-			"details": createErr.Error(),
-		})
+		respondError(c, createErr)
 		return
 	}
-	c.JSON(http.StatusOK, res)
+	respondTransfer(c, res)
 }
 
 func createWithdrawalRequestToCreateTransferRequest(req *dto.CreateWithdrawalRequest) *dto.CreateTransferRequest {
 	return &dto.CreateTransferRequest{
-		Currency: "MYR",
+		Currency: req.Currency,
 		Amount:   req.Amount,
 		SourceAccount: dto.CreateTransferRequestAccountDetail{
 			Number: req.AccountID,
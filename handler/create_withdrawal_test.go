@@ -63,7 +63,7 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 			args: func() args {
 				c, w := newMockGinContextForWithdrawal(t, http.MethodPost, "/v1/accounts/withdrawals", &dto.CreateWithdrawalRequest{
 					IdempotencyKey: "idempotency-key",
-					AccountID:      "source-account",
+					AccountID:      "12345678",
 					Amount:         1000,
 					Currency:       "MYR",
 					Note:           "Test withdrawal",
@@ -100,7 +100,7 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 							return req.IdempotencyKey == "idempotency-key" &&
 								req.Amount == 1000 &&
 								req.Currency == "MYR" &&
-								req.SourceAccount.Number == "source-account" &&
+								req.SourceAccount.Number == "12345678" &&
 								req.Note == "Test withdrawal"
 						}),
 						mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -148,10 +148,7 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 				fields.transferLogic = &transfermocks.MockITransferLogic{}
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: gin.H{
-				"error":   "Invalid request body",
-				"details": mock.Anything,
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INVALID_REQUEST"}},
 		},
 		{
 			name: "error - missing required fields",
@@ -171,17 +168,14 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 				fields.transferLogic = &transfermocks.MockITransferLogic{}
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody: gin.H{
-				"error":   "Invalid request body",
-				"details": mock.Anything,
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "VALIDATION_FAILED"}},
 		},
 		{
 			name: "error - transfer logic returns error",
 			args: func() args {
 				c, w := newMockGinContextForWithdrawal(t, http.MethodPost, "/v1/accounts/withdrawals", &dto.CreateWithdrawalRequest{
 					IdempotencyKey: "idempotency-key",
-					AccountID:      "source-account",
+					AccountID:      "12345678",
 					Amount:         1000,
 					Currency:       "MYR",
 					Note:           "Test withdrawal",
@@ -203,7 +197,7 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 							return req.IdempotencyKey == "idempotency-key" &&
 								req.Amount == 1000 &&
 								req.Currency == "MYR" &&
-								req.SourceAccount.Number == "source-account" &&
+								req.SourceAccount.Number == "12345678" &&
 								req.Note == "Test withdrawal"
 						}),
 						mock.MatchedBy(func(opts *transfer.CreateTransferOpts) bool {
@@ -214,10 +208,7 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 				}()
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody: gin.H{
-				"error":   "Failed to create transfer",
-				"details": "insufficient balance",
-			},
+			expectedBody:   &dto.ErrorResponse{Error: dto.ErrorDetail{Code: "INTERNAL_ERROR"}},
 		},
 	}
 
@@ -243,6 +234,13 @@ func TestWalletService_CreateWithdrawal(t *testing.T) {
 			// Check response
 			require.Equal(t, tt.expectedStatus, tt.args.w.Code)
 
+			if expErr, ok := tt.expectedBody.(*dto.ErrorResponse); ok {
+				var actualErr dto.ErrorResponse
+				require.NoError(t, json.Unmarshal(tt.args.w.Body.Bytes(), &actualErr))
+				require.Equal(t, expErr.Error.Code, actualErr.Error.Code)
+				require.NotEmpty(t, actualErr.Error.Message)
+			}
+
 			if tt.expectedStatus != http.StatusOK {
 				return
 			}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
+	"log"
+	"strings"
+	"wallet/apperr"
+	"wallet/dto"
+)
+
+// respondError writes err in the error envelope with the HTTP status of its code. Errors outside the
+// catalog are logged and answered with INTERNAL_ERROR, so their details never reach the client.
+func respondError(c *gin.Context, err error) {
+	e := apperr.From(err)
+	if e.Code == apperr.CodeInternal {
+		log.Printf("%s %s failed: %v", c.Request.Method, c.Request.URL.Path, err)
+	}
+	c.AbortWithStatusJSON(e.Status(), toErrorResponse(e, apperr.Negotiate(c.GetHeader("Accept-Language"))))
+}
+
+// respondBindError reports a request body that could not be bound, with per-field details for
+// validation failures
+func respondBindError(c *gin.Context, err error) {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		respondError(c, apperr.New(apperr.CodeInvalidRequest).Wrap(err))
+		return
+	}
+	fields := make([]apperr.FieldError, 0, len(validationErrs))
+	for _, fe := range validationErrs {
+		fields = append(fields, apperr.FieldError{
+			Field: fieldPath(fe.Namespace()),
+			Rule:  fe.Tag(),
+			Param: fe.Param(),
+		})
+	}
+	respondError(c, apperr.New(apperr.CodeValidationFailed).WithFields(fields...))
+}
+
+// fieldPath drops the struct name validator puts in front of the field path
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+func toErrorResponse(e *apperr.Error, lang language.Tag) *dto.ErrorResponse {
+	resp := &dto.ErrorResponse{Error: dto.ErrorDetail{
+		Code:    string(e.Code),
+		Message: apperr.Message(e.Code, lang),
+	}}
+	for _, f := range e.Fields {
+		resp.Error.Fields = append(resp.Error.Fields, dto.FieldErrorDetail{
+			Field:   f.Field,
+			Rule:    f.Rule,
+			Message: apperr.FieldMessage(f, lang),
+		})
+	}
+	return resp
+}
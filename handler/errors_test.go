@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"wallet/dto"
+	"wallet/logic/transfer"
+)
+
+func Test_respondError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		acceptLanguage string
+		wantStatus     int
+		wantCode       string
+		wantMessage    string
+	}{
+		{
+			name:        "insufficient balance",
+			err:         transfer.InsufficientBalanceErr,
+			wantStatus:  http.StatusUnprocessableEntity,
+			wantCode:    "INSUFFICIENT_BALANCE",
+			wantMessage: "The account balance is insufficient.",
+		},
+		{
+			name:        "invalid destination account",
+			err:         transfer.InvalidDestinationAccountErr,
+			wantStatus:  http.StatusNotFound,
+			wantCode:    "INVALID_DESTINATION_ACCOUNT",
+			wantMessage: "The destination account does not exist.",
+		},
+		{
+			name:        "invalid amount",
+			err:         transfer.InvalidAmountErr,
+			wantStatus:  http.StatusBadRequest,
+			wantCode:    "INVALID_AMOUNT",
+			wantMessage: "The amount is invalid.",
+		},
+		{
+			name:           "localized message",
+			err:            transfer.InsufficientBalanceErr,
+			acceptLanguage: "ms-MY,ms;q=0.9,en;q=0.8",
+			wantStatus:     http.StatusUnprocessableEntity,
+			wantCode:       "INSUFFICIENT_BALANCE",
+			wantMessage:    "Baki akaun tidak mencukupi.",
+		},
+		{
+			name:           "unsupported language falls back to English",
+			err:            transfer.InsufficientBalanceErr,
+			acceptLanguage: "fr-FR",
+			wantStatus:     http.StatusUnprocessableEntity,
+			wantCode:       "INSUFFICIENT_BALANCE",
+			wantMessage:    "The account balance is insufficient.",
+		},
+		{
+			name:        "unknown error does not leak details",
+			err:         errors.New("pq: connection refused"),
+			wantStatus:  http.StatusInternalServerError,
+			wantCode:    "INTERNAL_ERROR",
+			wantMessage: "Something went wrong. Please try again later.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := newMockGinContext(t, http.MethodPost, "/v1/payment/transfers", nil)
+			c.Request.Header.Set("Accept-Language", tt.acceptLanguage)
+
+			respondError(c, tt.err)
+
+			require.Equal(t, tt.wantStatus, w.Code)
+			var resp dto.ErrorResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			require.Equal(t, tt.wantCode, resp.Error.Code)
+			require.Equal(t, tt.wantMessage, resp.Error.Message)
+			require.NotContains(t, w.Body.String(), "pq:")
+		})
+	}
+}
+
+func Test_respondBindError(t *testing.T) {
+	c, w := newMockGinContext(t, http.MethodPost, "/v1/payment/transfers", gin.H{
+		"currency":      "MYR",
+		"amount":        -5,
+		"sourceAccount": gin.H{"number": "12345678"},
+	})
+
+	var req dto.CreateTransferRequest
+	respondBindError(c, c.ShouldBindJSON(&req))
+
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	var resp dto.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Equal(t, "VALIDATION_FAILED", resp.Error.Code)
+	require.Equal(t, []dto.FieldErrorDetail{
+		{Field: "amount", Rule: "gt", Message: "must be greater than 0"},
+		{Field: "destinationAccount.number", Rule: "required", Message: "is required"},
+		{Field: "idempotencyKey", Rule: "required", Message: "is required"},
+	}, resp.Error.Fields)
+}
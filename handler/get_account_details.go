@@ -5,11 +5,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
+	"wallet/apperr"
+	"wallet/currency"
 	"wallet/storage"
 )
 
 type GetAccountDetailRequest struct {
-	AccountID string `json:"accountID" binding:"required"`
+	AccountID string `json:"accountID" binding:"required,accountnumber"`
 }
 
 type GetAccountDetailResponse struct {
@@ -17,36 +19,66 @@ type GetAccountDetailResponse struct {
 	Name      string `json:"name"`
 	Type      string `json:"type"`
 	Currency  string `json:"currency"`
-	Balance   int64  `json:"balance"` // in minor unit (e.g. sen/cents)
+	Status    string `json:"status"`           // ACTIVE, FROZEN or CLOSED
+	Freeze    string `json:"freeze,omitempty"` // DEBIT, CREDIT or ALL while FROZEN
+	Balance   int64  `json:"balance"`          // in minor unit (e.g. sen/cents)
+	// HeldBalance is the part of the balance reserved by active holds
+	HeldBalance int64 `json:"heldBalance"`
+	// AvailableBalance is what can still be spent: the balance less the held balance
+	AvailableBalance int64 `json:"availableBalance"`
+	// DisplayBalance is the balance formatted with the display rules of the currency, e.g. "RM 1,000.00"
+	DisplayBalance string `json:"displayBalance,omitempty"`
 }
 
 func (p *WalletService) GetAccountDetails(c *gin.Context) {
 	var req GetAccountDetailRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		respondBindError(c, err)
+		return
+	}
+	if !p.checkAccountVisible(c, req.AccountID) {
 		return
 	}
 
 	account, err := p.accountDAO.FindByAccountID(c.Request.Context(), req.AccountID)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "account not found"})
-		} else {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to retrieve account"})
+			err = apperr.New(apperr.CodeAccountNotFound).Wrap(err)
 		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, toGetAccountDetailResponse(account))
+	// sharded accounts report the consolidated balance of all their shards
+	shards, shardErr := p.accountDAO.FindByParentAccountID(c.Request.Context(), account.AccountID)
+	if shardErr != nil {
+		respondError(c, shardErr)
+		return
+	}
+
+	c.JSON(http.StatusOK, toGetAccountDetailResponse(account, shards))
 }
 
-func toGetAccountDetailResponse(a *storage.Account) *GetAccountDetailResponse {
-	return &GetAccountDetailResponse{
-		AccountID: a.AccountID,
-		Name:      a.Name,
-		Type:      a.Type,
-		Currency:  a.Currency,
-		Balance:   a.Balance,
+func toGetAccountDetailResponse(a *storage.Account, shards []*storage.Account) *GetAccountDetailResponse {
+	balance, held := a.Balance, a.HeldBalance
+	for _, shard := range shards {
+		balance += shard.Balance
+		held += shard.HeldBalance
+	}
+	resp := &GetAccountDetailResponse{
+		AccountID:        a.AccountID,
+		Name:             a.Name,
+		Type:             a.Type,
+		Currency:         a.Currency,
+		Status:           a.Status,
+		Freeze:           a.Freeze,
+		Balance:          balance,
+		HeldBalance:      held,
+		AvailableBalance: balance - held,
+	}
+	if c, ok := currency.Lookup(a.Currency); ok {
+		resp.DisplayBalance = c.Format(balance)
 	}
+	return resp
 }
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+	"wallet/util"
+)
+
+func (p *WalletService) GetAccountLedger(c *gin.Context) {
+	var req dto.GetAccountLedgerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !p.checkAccountVisible(c, req.AccountID) {
+		return
+	}
+
+	// default limit if empty
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+
+	filter := &storage.LedgerFilter{
+		AccountID: req.AccountID,
+		Type:      req.Type,
+		Currency:  req.Currency,
+		From:      req.From,
+		To:        req.To,
+		Limit:     req.Limit,
+	}
+	if req.NextToken != "" {
+		cursor, decodeErr := util.DecodeNextToken(req.NextToken)
+		if decodeErr != nil {
+			respondError(c, apperr.New(apperr.CodeInvalidNextToken))
+			return
+		}
+		lastID, parseErr := strconv.ParseInt(cursor.LastID, 10, 64)
+		if parseErr != nil {
+			respondError(c, apperr.New(apperr.CodeInvalidNextToken))
+			return
+		}
+		filter.BeforeTimestamp = &cursor.LastTimestamp
+		filter.BeforeID = lastID
+	}
+
+	entries, listErr := p.transactionDAO.FindLedgerByAccountID(c.Request.Context(), filter)
+	if listErr != nil {
+		respondError(c, listErr)
+		return
+	}
+
+	resp := &dto.GetAccountLedgerResponse{}
+	for _, e := range entries {
+		resp.Data = append(resp.Data, &dto.LedgerEntryResponse{
+			EntryID:        e.ID,
+			TransactionID:  e.JournalID,
+			Type:           e.Type,
+			Amount:         e.Amount,
+			Currency:       e.Currency,
+			RunningBalance: e.RunningBalance,
+			Note:           e.Note,
+			ValuedAt:       e.ValuedAt,
+			CreatedAt:      e.CreatedAt,
+		})
+	}
+
+	// create new cursor if we still have more
+	if len(entries) == req.Limit {
+		last := entries[len(entries)-1]
+		resp.NextToken = util.EncodeNextToken(util.DataCursor{
+			LastTimestamp: last.CreatedAt,
+			LastID:        strconv.FormatInt(last.ID, 10),
+		})
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
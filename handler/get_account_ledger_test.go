@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/logic/user"
+	usermock "wallet/logic/user/mocks"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+	"wallet/util"
+)
+
+func TestWalletService_GetAccountLedger(t *testing.T) {
+	createdAt := time.Date(2025, 7, 15, 10, 0, 0, 0, time.UTC)
+
+	type args struct {
+		c *gin.Context
+		w *httptest.ResponseRecorder
+	}
+	tests := []struct {
+		name           string
+		args           args
+		userID         string
+		viewErr        error
+		setupMocks     func(transactionDAO *storagemock.MockITransactionDAO)
+		expectedStatus int
+		expectedBody   *dto.GetAccountLedgerResponse
+	}{
+		{
+			name: "happy path - entries with running balance and next token",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+					Type:      "debit",
+					Limit:     1,
+				})
+				return args{c: c, w: w}
+			}(),
+			setupMocks: func(transactionDAO *storagemock.MockITransactionDAO) {
+				transactionDAO.On("FindLedgerByAccountID", mock.Anything, mock.MatchedBy(func(f *storage.LedgerFilter) bool {
+					return f.AccountID == "12345678" && f.Type == "debit" && f.Limit == 1 && f.BeforeTimestamp == nil
+				})).Return([]*storage.LedgerEntry{
+					{
+						Transaction: storage.Transaction{
+							ID:        42,
+							JournalID: "tx-123",
+							AccountID: "12345678",
+							Type:      "debit",
+							Amount:    1000,
+							Currency:  "MYR",
+							ValuedAt:  createdAt,
+							CreatedAt: createdAt,
+							Note:      "Transfer to 87654321",
+						},
+						RunningBalance: 99000,
+					},
+				}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: &dto.GetAccountLedgerResponse{
+				Data: []*dto.LedgerEntryResponse{
+					{
+						EntryID:        42,
+						TransactionID:  "tx-123",
+						Type:           "debit",
+						Amount:         1000,
+						Currency:       "MYR",
+						RunningBalance: 99000,
+						Note:           "Transfer to 87654321",
+						ValuedAt:       createdAt,
+						CreatedAt:      createdAt,
+					},
+				},
+				NextToken: util.EncodeNextToken(util.DataCursor{LastTimestamp: createdAt, LastID: "42"}),
+			},
+		},
+		{
+			name: "happy path - next token becomes keyset cursor",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+					NextToken: util.EncodeNextToken(util.DataCursor{LastTimestamp: createdAt, LastID: "42"}),
+				})
+				return args{c: c, w: w}
+			}(),
+			setupMocks: func(transactionDAO *storagemock.MockITransactionDAO) {
+				transactionDAO.On("FindLedgerByAccountID", mock.Anything, mock.MatchedBy(func(f *storage.LedgerFilter) bool {
+					return f.Limit == 20 && f.BeforeTimestamp != nil && f.BeforeTimestamp.Equal(createdAt) && f.BeforeID == 42
+				})).Return([]*storage.LedgerEntry{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &dto.GetAccountLedgerResponse{},
+		},
+		{
+			name: "happy path - user linked to the account",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+				})
+				return args{c: c, w: w}
+			}(),
+			userID: "user-1",
+			setupMocks: func(transactionDAO *storagemock.MockITransactionDAO) {
+				transactionDAO.On("FindLedgerByAccountID", mock.Anything, mock.Anything).Return([]*storage.LedgerEntry{}, nil).Once()
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   &dto.GetAccountLedgerResponse{},
+		},
+		{
+			name: "error - account not linked to the user",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+				})
+				return args{c: c, w: w}
+			}(),
+			userID:         "user-1",
+			viewErr:        user.AccountNotFoundErr,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: "error - invalid type filter",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+					Type:      "refund",
+				})
+				return args{c: c, w: w}
+			}(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - invalid next token",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+					NextToken: util.EncodeNextToken(util.DataCursor{LastTimestamp: createdAt, LastID: "not-a-number"}),
+				})
+				return args{c: c, w: w}
+			}(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "error - DAO returns error",
+			args: func() args {
+				c, w := newMockGinContext(t, http.MethodPost, "/v1/accounts/ledger/query", &dto.GetAccountLedgerRequest{
+					AccountID: "12345678",
+				})
+				return args{c: c, w: w}
+			}(),
+			setupMocks: func(transactionDAO *storagemock.MockITransactionDAO) {
+				transactionDAO.On("FindLedgerByAccountID", mock.Anything, mock.Anything).
+					Return(nil, errors.New("database error")).Once()
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactionDAO := newMockTransactionDAO(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(transactionDAO)
+			}
+
+			userLogic := usermock.NewMockIUserLogic(t)
+			if tt.userID != "" {
+				tt.args.c.Request.Header.Set("X-User-ID", tt.userID)
+				userLogic.EXPECT().CheckViewAccess(mock.Anything, tt.userID, "12345678").Return(tt.viewErr).Once()
+			}
+
+			p := &WalletService{
+				validator:      newMockValidator(),
+				accountDAO:     newMockAccountDAO(t),
+				transferDAO:    newMockTransferDAO(t),
+				transactionDAO: transactionDAO,
+				transferLogic:  newMockTransferLogic(t),
+				userLogic:      userLogic,
+			}
+
+			p.GetAccountLedger(tt.args.c)
+
+			require.Equal(t, tt.expectedStatus, tt.args.w.Code)
+			if tt.expectedBody == nil {
+				return
+			}
+
+			var actual dto.GetAccountLedgerResponse
+			require.NoError(t, json.Unmarshal(tt.args.w.Body.Bytes(), &actual))
+			require.Equal(t, *tt.expectedBody, actual)
+		})
+	}
+}
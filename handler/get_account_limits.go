@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+)
+
+func (p *WalletService) GetAccountLimits(c *gin.Context) {
+	var req dto.GetAccountLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !p.checkAccountVisible(c, req.AccountID) {
+		return
+	}
+	res, limitsErr := p.limitLogic.GetLimits(c.Request.Context(), req.AccountID)
+	if limitsErr != nil {
+		respondError(c, limitsErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
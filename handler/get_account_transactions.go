@@ -3,12 +3,12 @@ package handler
 import (
 	"context"
 	"errors"
-	"fmt"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 	"net/http"
 	"slices"
 	"time"
+	"wallet/apperr"
 	"wallet/dto"
 	"wallet/logic/transfer"
 	"wallet/storage"
@@ -18,7 +18,10 @@ import (
 func (p *WalletService) GetAccountTransactions(c *gin.Context) {
 	var req dto.GetAccountTransactionsRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err)
+		return
+	}
+	if !p.checkAccountVisible(c, req.AccountID) {
 		return
 	}
 
@@ -33,26 +36,41 @@ func (p *WalletService) GetAccountTransactions(c *gin.Context) {
 		return
 	}
 	if listErr != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to fetch transactions"})
+		respondError(c, listErr)
 		return
 	}
 
 	var resp []*dto.TransactionResponse
 	for _, tx := range transfersData {
-		amt := tx.Amount
+		amt, txCurrency := tx.Amount, tx.Currency
 		if tx.SourceAccountID == req.AccountID && slices.Contains([]string{
 			string(transfer.TxTypeWithdrawal),
-			string(transfer.TxTypeP2PTransfer)}, tx.TxType) {
+			string(transfer.TxTypeP2PTransfer),
+			string(transfer.TxTypeCapture),
+			string(transfer.TxTypeReversal)}, tx.TxType) {
 			amt = -amt
+		} else if tx.DestinationCurrency != "" {
+			// the destination of a cross-currency transfer was credited in its own currency
+			amt, txCurrency = tx.DestinationAmount, tx.DestinationCurrency
+		}
+		// fees are paid by the source account, or by the destination account of a deposit
+		var fee int64
+		if (tx.TxType == string(transfer.TxTypeDeposit)) == (tx.DestinationAccountID == req.AccountID) {
+			fee = tx.FeeAmount
 		}
 		resp = append(resp, &dto.TransactionResponse{
-			TransactionID: tx.TransactionID,
-			TxType:        tx.TxType,
-			Amount:        amt,
-			Currency:      tx.Currency,
-			CreatedAt:     tx.CreatedAt,
-			Note:          tx.Note,
-			Status:        tx.Status,
+			TransactionID:           tx.TransactionID,
+			TxType:                  tx.TxType,
+			Amount:                  amt,
+			Currency:                txCurrency,
+			CreatedAt:               tx.CreatedAt,
+			Note:                    tx.Note,
+			Status:                  tx.Status,
+			StatusReason:            tx.StatusReason,
+			StatusReasonDescription: tx.StatusReasonDescription,
+			Fee:                     fee,
+			ParentTransactionID:     tx.ParentTransactionID,
+			RefundedAmount:          tx.RefundedAmount,
 		})
 	}
 
@@ -74,7 +92,7 @@ func (p *WalletService) listAccountTransfers(
 	if nextToken != "" {
 		cursor, err := util.DecodeNextToken(nextToken)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid nextToken: %w", err)
+			return nil, "", apperr.New(apperr.CodeInvalidNextToken).Wrap(err)
 		}
 		beforeTimestamp = &cursor.LastTimestamp
 	}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/transfer"
+)
+
+// GetTransfer serves GET /v1/payment/transfers/{transactionID}
+func (p *WalletService) GetTransfer(c *gin.Context) {
+	var req dto.GetTransferRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	p.getTransfer(c, &req)
+}
+
+// FindTransfer serves GET /v1/payment/transfers?idempotencyKey=...
+func (p *WalletService) FindTransfer(c *gin.Context) {
+	var req dto.GetTransferRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if req.IdempotencyKey == "" {
+		respondError(c, apperr.New(apperr.CodeInvalidRequest))
+		return
+	}
+	p.getTransfer(c, &req)
+}
+
+func (p *WalletService) getTransfer(c *gin.Context, req *dto.GetTransferRequest) {
+	res, getErr := p.transferLogic.GetTransfer(c.Request.Context(), req, &transfer.GetTransferOpts{
+		ClientID: clientID(c),
+		UserID:   userID(c),
+	})
+	if getErr != nil {
+		respondError(c, getErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
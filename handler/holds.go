@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+	"wallet/logic/transfer"
+)
+
+func (p *WalletService) PlaceHold(c *gin.Context) {
+	var req dto.PlaceHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, placeErr := p.transferLogic.PlaceHold(c.Request.Context(), &req, &transfer.HoldOpts{ClientID: clientID(c), UserID: userID(c)})
+	if placeErr != nil {
+		respondError(c, placeErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) CaptureHold(c *gin.Context) {
+	var req dto.CaptureHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, captureErr := p.transferLogic.CaptureHold(c.Request.Context(), &req, &transfer.HoldOpts{ClientID: clientID(c)})
+	if captureErr != nil {
+		respondError(c, captureErr)
+		return
+	}
+	respondTransfer(c, res)
+}
+
+func (p *WalletService) ExtendHold(c *gin.Context) {
+	var req dto.ExtendHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, extendErr := p.transferLogic.ExtendHold(c.Request.Context(), &req, &transfer.HoldOpts{ClientID: clientID(c)})
+	if extendErr != nil {
+		respondError(c, extendErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) ReleaseHold(c *gin.Context) {
+	var req dto.HoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, releaseErr := p.transferLogic.ReleaseHold(c.Request.Context(), req.HoldID, &transfer.HoldOpts{ClientID: clientID(c)})
+	if releaseErr != nil {
+		respondError(c, releaseErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) GetHold(c *gin.Context) {
+	var req dto.HoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, getErr := p.transferLogic.GetHold(c.Request.Context(), req.HoldID, &transfer.HoldOpts{ClientID: clientID(c)})
+	if getErr != nil {
+		respondError(c, getErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"time"
+	"wallet/apperr"
+	"wallet/storage"
+)
+
+const (
+	// idempotencyKeyHeader carries the idempotency key. Requests without it fall back to the
+	// idempotencyKey field of their JSON body.
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader is set on responses replayed from the idempotency store
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+
+	// DefaultIdempotencyInFlightTimeout is how long an unfinished request holds its key before a retry may
+	// take it over, e.g. after the instance serving it crashed.
+	DefaultIdempotencyInFlightTimeout = time.Minute
+
+	// idempotencyUnfinishedKey is set on the context of a request whose outcome is not final yet
+	idempotencyUnfinishedKey = "idempotencyUnfinished"
+)
+
+// Idempotent makes the handlers after it idempotent. The first request made with a key is recorded as in
+// flight and its response stored once it finishes; retries with the same key and request are answered
+// with the stored status code and body, whether it was a success or an error. A retry arriving while the
+// first request is still in flight gets 409, and reusing a key for a different request gets 422.
+// 5xx responses are not terminal: their record is dropped so the request can be retried. Neither are
+// responses the handler marked with markUnfinished.
+func Idempotent(dao storage.IIdempotencyRecordDAO, inFlightTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := readBody(c)
+		if err != nil {
+			respondError(c, apperr.New(apperr.CodeInvalidRequest).Wrap(err))
+			return
+		}
+
+		key := idempotencyKey(c, body)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		record := &storage.IdempotencyRecord{
+			ClientID:       clientID(c),
+			IdempotencyKey: key,
+			RequestHash:    idempotencyRequestHash(c, body),
+		}
+		claimed, err := dao.Claim(ctx, record)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		if !claimed {
+			existing, findErr := dao.Find(ctx, record.ClientID, record.IdempotencyKey)
+			if findErr != nil {
+				respondError(c, findErr)
+				return
+			}
+			switch {
+			case existing.RequestHash != record.RequestHash:
+				respondError(c, apperr.New(apperr.CodeIdempotencyKeyReused))
+				return
+			case existing.Status == storage.IdempotencyStatusCompleted:
+				c.Header(idempotencyReplayedHeader, "true")
+				c.Data(existing.ResponseCode, existing.ContentType, existing.ResponseBody)
+				c.Abort()
+				return
+			}
+			record.ID = existing.ID
+			if claimed, err = dao.ClaimStale(ctx, record, time.Now().Add(-inFlightTimeout)); err != nil {
+				respondError(c, err)
+				return
+			}
+			if !claimed {
+				respondError(c, apperr.New(apperr.CodeIdempotencyRequestInProgress))
+				return
+			}
+		}
+
+		// the outcome is stored even if the client went away in the meantime
+		storeCtx := context.WithoutCancel(ctx)
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		finished := false
+		defer func() {
+			// a panicking handler leaves no terminal outcome behind
+			if !finished {
+				if releaseErr := dao.Release(storeCtx, record); releaseErr != nil {
+					log.Printf("failed to release idempotency key %q: %v", record.IdempotencyKey, releaseErr)
+				}
+			}
+		}()
+
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError || c.GetBool(idempotencyUnfinishedKey) {
+			return
+		}
+		record.ResponseCode = recorder.Status()
+		record.ContentType = recorder.Header().Get("Content-Type")
+		record.ResponseBody = recorder.body.Bytes()
+		if completeErr := dao.Complete(storeCtx, record); completeErr != nil {
+			log.Printf("failed to store response for idempotency key %q: %v", record.IdempotencyKey, completeErr)
+			return
+		}
+		finished = true
+	}
+}
+
+// markUnfinished keeps the response to c out of the idempotency store, as the outcome it reports may still
+// change. The key is released instead, so a retry runs the handler again and gets the current outcome.
+func markUnfinished(c *gin.Context) {
+	c.Set(idempotencyUnfinishedKey, true)
+}
+
+func idempotencyKey(c *gin.Context, body []byte) string {
+	if key := c.GetHeader(idempotencyKeyHeader); key != "" {
+		return key
+	}
+	var req struct {
+		IdempotencyKey string `json:"idempotencyKey"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return ""
+	}
+	return req.IdempotencyKey
+}
+
+// idempotencyRequestHash fingerprints the path and body of a request, so a key reused on another path, such
+// as the reversal of another transfer, counts as a different request. JSON bodies are re-encoded first so
+// that whitespace and key order do not matter.
+func idempotencyRequestHash(c *gin.Context, body []byte) string {
+	canonical := body
+	var decoded interface{}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err == nil {
+		if encoded, encodeErr := json.Marshal(decoded); encodeErr == nil {
+			canonical = encoded
+		}
+	}
+	h := sha256.New()
+	h.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+	h.Write(canonical)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// responseRecorder keeps a copy of the response body written through it
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/logic/transfer"
+	transfermock "wallet/logic/transfer/mocks"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+)
+
+func TestIdempotent(t *testing.T) {
+	const body = `{"idempotencyKey":"idempotency-key","amount":1000}`
+
+	tests := []struct {
+		name            string
+		body            string
+		handlerStatus   int
+		setupMocks      func(dao *storagemock.MockIIdempotencyRecordDAO)
+		wantHandlerRuns int
+		wantStatus      int
+		wantBody        string
+		wantReplayed    bool
+	}{
+		{
+			name:            "no idempotency key - passes through",
+			body:            `{"amount":1000}`,
+			handlerStatus:   http.StatusOK,
+			setupMocks:      func(dao *storagemock.MockIIdempotencyRecordDAO) {},
+			wantHandlerRuns: 1,
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"status":"done"}`,
+		},
+		{
+			name:          "first request - stores the response",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				dao.EXPECT().Claim(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+					return r.ClientID == "client-a" && r.IdempotencyKey == "idempotency-key" && r.RequestHash != ""
+				})).Return(true, nil).Once()
+				dao.EXPECT().Complete(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+					return r.ResponseCode == http.StatusOK && string(r.ResponseBody) == `{"status":"done"}`
+				})).Return(nil).Once()
+			},
+			wantHandlerRuns: 1,
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"status":"done"}`,
+		},
+		{
+			name:          "first request fails with a client error - stores the error",
+			body:          body,
+			handlerStatus: http.StatusUnprocessableEntity,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).Return(true, nil).Once()
+				dao.EXPECT().Complete(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+					return r.ResponseCode == http.StatusUnprocessableEntity
+				})).Return(nil).Once()
+			},
+			wantHandlerRuns: 1,
+			wantStatus:      http.StatusUnprocessableEntity,
+			wantBody:        `{"status":"done"}`,
+		},
+		{
+			name:          "first request fails with a server error - releases the key",
+			body:          body,
+			handlerStatus: http.StatusInternalServerError,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).Return(true, nil).Once()
+				dao.EXPECT().Release(mock.Anything, mock.Anything).Return(nil).Once()
+			},
+			wantHandlerRuns: 1,
+			wantStatus:      http.StatusInternalServerError,
+			wantBody:        `{"status":"done"}`,
+		},
+		{
+			name:          "retry of a completed request - replays the response",
+			body:          " {\"amount\": 1000, \"idempotencyKey\": \"idempotency-key\"}\n",
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				var hash string
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, r *storage.IdempotencyRecord) (bool, error) {
+					hash = r.RequestHash
+					return false, nil
+				}).Once()
+				dao.EXPECT().Find(mock.Anything, "client-a", "idempotency-key").RunAndReturn(func(context.Context, string, string) (*storage.IdempotencyRecord, error) {
+					return &storage.IdempotencyRecord{
+						RequestHash:  hash,
+						Status:       storage.IdempotencyStatusCompleted,
+						ResponseCode: http.StatusBadRequest,
+						ContentType:  "application/json; charset=utf-8",
+						ResponseBody: []byte(`{"error":{"code":"INSUFFICIENT_BALANCE","message":"The account balance is insufficient."}}`),
+					}, nil
+				}).Once()
+			},
+			wantStatus:   http.StatusBadRequest,
+			wantBody:     `{"error":{"code":"INSUFFICIENT_BALANCE","message":"The account balance is insufficient."}}`,
+			wantReplayed: true,
+		},
+		{
+			name:          "retry with a different request - 422",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).Return(false, nil).Once()
+				dao.EXPECT().Find(mock.Anything, "client-a", "idempotency-key").Return(&storage.IdempotencyRecord{
+					RequestHash: "other-request",
+					Status:      storage.IdempotencyStatusCompleted,
+				}, nil).Once()
+			},
+			wantStatus: http.StatusUnprocessableEntity,
+			wantBody:   `{"error":{"code":"IDEMPOTENCY_KEY_REUSED","message":"The idempotency key was already used for a different request."}}`,
+		},
+		{
+			name:          "retry while the first request is in flight - 409",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				var hash string
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, r *storage.IdempotencyRecord) (bool, error) {
+					hash = r.RequestHash
+					return false, nil
+				}).Once()
+				dao.EXPECT().Find(mock.Anything, "client-a", "idempotency-key").RunAndReturn(func(context.Context, string, string) (*storage.IdempotencyRecord, error) {
+					return &storage.IdempotencyRecord{ID: 7, RequestHash: hash, Status: storage.IdempotencyStatusInFlight}, nil
+				}).Once()
+				dao.EXPECT().ClaimStale(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+					return r.ID == 7
+				}), mock.Anything).Return(false, nil).Once()
+			},
+			wantStatus: http.StatusConflict,
+			wantBody:   `{"error":{"code":"IDEMPOTENCY_REQUEST_IN_PROGRESS","message":"A request with this idempotency key is still being processed."}}`,
+		},
+		{
+			name:          "retry of an abandoned in-flight request - takes it over",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				var hash string
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, r *storage.IdempotencyRecord) (bool, error) {
+					hash = r.RequestHash
+					return false, nil
+				}).Once()
+				dao.EXPECT().Find(mock.Anything, "client-a", "idempotency-key").RunAndReturn(func(context.Context, string, string) (*storage.IdempotencyRecord, error) {
+					return &storage.IdempotencyRecord{ID: 7, RequestHash: hash, Status: storage.IdempotencyStatusInFlight}, nil
+				}).Once()
+				dao.EXPECT().ClaimStale(mock.Anything, mock.Anything, mock.Anything).Return(true, nil).Once()
+				dao.EXPECT().Complete(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+					return r.ID == 7 && r.ResponseCode == http.StatusOK
+				})).Return(nil).Once()
+			},
+			wantHandlerRuns: 1,
+			wantStatus:      http.StatusOK,
+			wantBody:        `{"status":"done"}`,
+		},
+		{
+			name:       "body too large - rejected before the key is claimed",
+			body:       `{"idempotencyKey":"idempotency-key","note":"` + strings.Repeat("x", maxBodySize) + `"}`,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   `{"error":{"code":"INVALID_REQUEST","message":"The request body is malformed."}}`,
+		},
+		{
+			name:          "idempotency store unavailable - 500",
+			body:          body,
+			handlerStatus: http.StatusOK,
+			setupMocks: func(dao *storagemock.MockIIdempotencyRecordDAO) {
+				dao.EXPECT().Claim(mock.Anything, mock.Anything).Return(false, errors.New("database error")).Once()
+			},
+			wantStatus: http.StatusInternalServerError,
+			wantBody:   `{"error":{"code":"INTERNAL_ERROR","message":"Something went wrong. Please try again later."}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			dao := storagemock.NewMockIIdempotencyRecordDAO(t)
+			tt.setupMocks(dao)
+
+			handlerRuns := 0
+			r := gin.New()
+			r.POST("/v1/payment/transfers", Idempotent(dao, time.Minute), func(c *gin.Context) {
+				handlerRuns++
+				c.JSON(tt.handlerStatus, gin.H{"status": "done"})
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/payment/transfers", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Client-ID", "client-a")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			require.Equal(t, tt.wantHandlerRuns, handlerRuns)
+			require.Equal(t, tt.wantStatus, w.Code)
+			require.Equal(t, tt.wantBody, w.Body.String())
+			require.Equal(t, tt.wantReplayed, w.Header().Get("Idempotent-Replayed") == "true")
+		})
+	}
+}
+
+// A transfer left PROCESSING by a server error may still complete, so retries must not be answered with a
+// stored PROCESSING response once the executor has finished it.
+func TestIdempotent_transferRetriedAfterServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dao := storagemock.NewMockIIdempotencyRecordDAO(t)
+	transferLogic := transfermock.NewMockITransferLogic(t)
+	transferred := func(status string) *dto.CreateTransferResponse {
+		return &dto.CreateTransferResponse{IdempotencyKey: "idempotency-key", TransactionID: "tx-123", Amount: 1000, Currency: "MYR", Status: status}
+	}
+
+	// the first attempt fails with the transfer left PROCESSING, and the retry reports it still PROCESSING:
+	// neither is stored, so the last retry sees it COMPLETED
+	dao.EXPECT().Claim(mock.Anything, mock.Anything).Return(true, nil).Times(3)
+	transferLogic.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection reset")).Once()
+	dao.EXPECT().Release(mock.Anything, mock.Anything).Return(nil).Once()
+	transferLogic.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).Return(transferred(transfer.TxStatusPROCESSING), nil).Once()
+	dao.EXPECT().Release(mock.Anything, mock.Anything).Return(nil).Once()
+	transferLogic.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).Return(transferred(transfer.TxStatusCOMPLETED), nil).Once()
+	dao.EXPECT().Complete(mock.Anything, mock.MatchedBy(func(r *storage.IdempotencyRecord) bool {
+		return r.ResponseCode == http.StatusOK && strings.Contains(string(r.ResponseBody), `"status":"COMPLETED"`)
+	})).Return(nil).Once()
+
+	p := &WalletService{transferLogic: transferLogic}
+	r := gin.New()
+	r.POST("/v1/payment/transfers", Idempotent(dao, time.Minute), p.CreateTransfer)
+
+	body := `{"idempotencyKey":"idempotency-key","amount":1000,"currency":"MYR","sourceAccount":{"number":"12345678"},"destinationAccount":{"number":"87654321"}}`
+	for _, want := range []struct {
+		status int
+		body   string
+	}{
+		{status: http.StatusInternalServerError, body: `"INTERNAL_ERROR"`},
+		{status: http.StatusOK, body: `"status":"PROCESSING"`},
+		{status: http.StatusOK, body: `"status":"COMPLETED"`},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/transfers", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Client-ID", "client-a")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		require.Equal(t, want.status, w.Code)
+		require.Contains(t, w.Body.String(), want.body)
+	}
+}
+
+func Test_idempotencyRequestHash(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hashes := map[string]string{}
+	r := gin.New()
+	r.POST("/v1/payment/transfers/:transactionID/reversals", func(c *gin.Context) {
+		hashes[c.Param("transactionID")] = idempotencyRequestHash(c, []byte(`{"idempotencyKey":"idempotency-key"}`))
+	})
+	for _, transactionID := range []string{"tx-1", "tx-2"} {
+		req := httptest.NewRequest(http.MethodPost, "/v1/payment/transfers/"+transactionID+"/reversals", nil)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	require.NotEqual(t, hashes["tx-1"], hashes["tx-2"])
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+)
+
+func (p *WalletService) PreviewFees(c *gin.Context) {
+	var req dto.PreviewFeesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, previewErr := p.transferLogic.PreviewFees(c.Request.Context(), &req)
+	if previewErr != nil {
+		respondError(c, previewErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
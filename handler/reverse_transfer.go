@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"wallet/dto"
+	"wallet/logic/transfer"
+)
+
+func (p *WalletService) ReverseTransfer(c *gin.Context) {
+	var req dto.ReverseTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, reverseErr := p.transferLogic.ReverseTransfer(c.Request.Context(), c.Param("transactionID"), &req, &transfer.ReverseTransferOpts{
+		ClientID: clientID(c),
+		UserID:   userID(c),
+	})
+	if reverseErr != nil {
+		respondError(c, reverseErr)
+		return
+	}
+	respondTransfer(c, res)
+}
@@ -3,47 +3,164 @@ package handler
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
+	"wallet/accountnumber"
+	"wallet/auth"
+	"wallet/logic/account"
+	"wallet/logic/apikey"
+	"wallet/logic/batch"
+	"wallet/logic/fx"
+	"wallet/logic/limit"
+	"wallet/logic/reconcile"
+	"wallet/logic/standing"
 	"wallet/logic/transfer"
+	"wallet/logic/user"
 	"wallet/storage"
 )
 
 type WalletService struct {
 	validator *validator.Validate
+	verifier  *auth.Verifier
 
 	accountDAO     storage.IAccountDAO
 	transferDAO    storage.ITransferDAO
 	transactionDAO storage.ITransactionDAO
+	fxQuoteDAO     storage.IFxQuoteDAO
+	holdDAO        storage.IHoldDAO
 
-	transferLogic transfer.ITransferLogic
+	idempotencyRecordDAO storage.IIdempotencyRecordDAO
+
+	accountLogic   account.IAccountLogic
+	transferLogic  transfer.ITransferLogic
+	reconcileLogic reconcile.IReconcileLogic
+	fxLogic        fx.IFxLogic
+	limitLogic     limit.ILimitLogic
+	standingLogic  standing.IStandingLogic
+	batchLogic     batch.IBatchLogic
+	userLogic      user.IUserLogic
+	apiKeyLogic    apikey.IAPIKeyLogic
 }
 
 func NewWalletService(
 	AccountDAO storage.IAccountDAO,
 	TransactionDAO storage.ITransactionDAO,
 	TransferDAO storage.ITransferDAO,
+	IdempotencyRecordDAO storage.IIdempotencyRecordDAO,
+	FxQuoteDAO storage.IFxQuoteDAO,
+	HoldDAO storage.IHoldDAO,
+	StandingInstructionDAO storage.IStandingInstructionDAO,
+	TransferBatchDAO storage.ITransferBatchDAO,
+	UserDAO storage.IUserDAO,
+	APIKeyDAO storage.IAPIKeyDAO,
+	TransferConfig *transfer.Config,
+	FxConfig *fx.Config,
+	StandingConfig *standing.Config,
+	BatchConfig *batch.Config,
+	AccountNumberScheme *accountnumber.Scheme,
+	APIKeyConfig *apikey.Config,
+	Verifier *auth.Verifier,
 ) *WalletService {
+	transferLogic := transfer.NewTransferLogic(TransferDAO, AccountDAO, TransactionDAO, FxQuoteDAO, HoldDAO, UserDAO, TransferConfig)
+	// signed requests are only accepted with a master key to decrypt the secrets of API keys
+	var apiKeyLogic apikey.IAPIKeyLogic
+	if APIKeyConfig.Enabled() {
+		apiKeyLogic = apikey.NewAPIKeyLogic(APIKeyDAO, APIKeyConfig)
+	}
 	return &WalletService{
 		validator:      validator.New(),
+		verifier:       Verifier,
 		accountDAO:     AccountDAO,
 		transferDAO:    TransferDAO,
 		transactionDAO: TransactionDAO,
-		transferLogic:  transfer.NewTransferLogic(TransferDAO, AccountDAO, TransactionDAO),
+		fxQuoteDAO:     FxQuoteDAO,
+		holdDAO:        HoldDAO,
+
+		idempotencyRecordDAO: IdempotencyRecordDAO,
+
+		accountLogic:   account.NewAccountLogic(AccountDAO, AccountNumberScheme),
+		transferLogic:  transferLogic,
+		reconcileLogic: reconcile.NewReconcileLogic(AccountDAO, TransactionDAO, TransferDAO),
+		fxLogic:        fx.NewFxLogic(FxQuoteDAO, FxConfig),
+		limitLogic:     limit.NewLimitLogic(AccountDAO, TransferDAO, TransferConfig.Limits),
+		standingLogic:  standing.NewStandingLogic(StandingInstructionDAO, AccountDAO, transferLogic, StandingConfig),
+		batchLogic:     batch.NewBatchLogic(TransferBatchDAO, AccountDAO, transferLogic, BatchConfig),
+		userLogic:      user.NewUserLogic(UserDAO, AccountDAO),
+		apiKeyLogic:    apiKeyLogic,
 	}
 }
 
 func (p *WalletService) RegisterRoutes(ge *gin.Engine) {
-	v1 := ge.Group("/v1")
+	v1 := ge.Group("/v1", Authenticate(p.verifier, p.apiKeyLogic))
+	idempotent := Idempotent(p.idempotencyRecordDAO, DefaultIdempotencyInFlightTimeout)
+	accountsRead := RequireScope(auth.ScopeAccountsRead)
+	accountsWrite := RequireScope(auth.ScopeAccountsWrite)
+	transfersRead := RequireScope(auth.ScopeTransfersRead)
+	transfersWrite := RequireScope(auth.ScopeTransfersWrite)
+	usersRead := RequireScope(auth.ScopeUsersRead)
+	usersWrite := RequireScope(auth.ScopeUsersWrite)
 
 	v1accounts := v1.Group("/accounts")
 	{
-		v1accounts.POST("/transactions/query", p.GetAccountTransactions)
-		v1accounts.POST("/query", p.GetAccountDetails)
-		v1accounts.POST("/withdrawals", p.CreateWithdrawal)
-		v1accounts.POST("/deposits", p.CreateDeposit)
+		v1accounts.POST("/transactions/query", accountsRead, p.GetAccountTransactions)
+		v1accounts.POST("/ledger/query", accountsRead, p.GetAccountLedger)
+		v1accounts.POST("/limits/query", accountsRead, p.GetAccountLimits)
+		v1accounts.POST("/query", accountsRead, p.GetAccountDetails)
+		v1accounts.POST("/withdrawals", transfersWrite, idempotent, p.CreateWithdrawal)
+		v1accounts.POST("/deposits", transfersWrite, idempotent, p.CreateDeposit)
+		v1accounts.POST("", accountsWrite, idempotent, p.OpenAccount)
+		v1accounts.POST("/update", accountsWrite, p.UpdateAccount)
+		v1accounts.POST("/freeze", accountsWrite, p.FreezeAccount)
+		v1accounts.POST("/unfreeze", accountsWrite, p.UnfreezeAccount)
+		v1accounts.POST("/close", accountsWrite, p.CloseAccount)
+	}
+
+	v1users := v1.Group("/users")
+	{
+		v1users.POST("", usersWrite, idempotent, p.CreateUser)
+		v1users.POST("/query", usersRead, p.GetUser)
+		v1users.POST("/update", usersWrite, p.UpdateUser)
+		v1users.POST("/status", usersWrite, p.ChangeUserStatus)
+		v1users.POST("/accounts/link", usersWrite, p.LinkUserAccount)
+		v1users.POST("/accounts/unlink", usersWrite, p.UnlinkUserAccount)
 	}
 
 	v1transfers := v1.Group("/payment")
 	{
-		v1transfers.POST("/transfers", p.CreateTransfer)
+		v1transfers.POST("/transfers", transfersWrite, idempotent, p.CreateTransfer)
+		v1transfers.POST("/transfers/:transactionID/reversals", transfersWrite, idempotent, p.ReverseTransfer)
+		v1transfers.POST("/transfers/:transactionID/cancel", transfersWrite, p.CancelTransfer)
+		v1transfers.POST("/transfers/:transactionID/reschedule", transfersWrite, p.RescheduleTransfer)
+		v1transfers.GET("/transfers", transfersRead, p.FindTransfer)
+		v1transfers.GET("/transfers/:transactionID", transfersRead, p.GetTransfer)
+		v1transfers.POST("/fees/preview", transfersRead, p.PreviewFees)
+		v1transfers.POST("/batches", transfersWrite, idempotent, p.CreateTransferBatch)
+		v1transfers.GET("/batches/:batchID", transfersRead, p.GetTransferBatch)
+	}
+
+	v1holds := v1.Group("/holds")
+	{
+		v1holds.POST("", transfersWrite, idempotent, p.PlaceHold)
+		v1holds.POST("/capture", transfersWrite, idempotent, p.CaptureHold)
+		v1holds.POST("/extend", transfersWrite, p.ExtendHold)
+		v1holds.POST("/release", transfersWrite, p.ReleaseHold)
+		v1holds.POST("/query", transfersRead, p.GetHold)
+	}
+
+	v1standing := v1.Group("/standing-instructions")
+	{
+		v1standing.POST("", transfersWrite, idempotent, p.CreateStandingInstruction)
+		v1standing.POST("/query", transfersRead, p.GetStandingInstruction)
+		v1standing.POST("/suspend", transfersWrite, p.SuspendStandingInstruction)
+		v1standing.POST("/resume", transfersWrite, p.ResumeStandingInstruction)
+		v1standing.POST("/cancel", transfersWrite, p.CancelStandingInstruction)
+	}
+
+	v1fx := v1.Group("/fx")
+	{
+		v1fx.POST("/quotes", transfersWrite, p.CreateFxQuote)
+	}
+
+	v1admin := v1.Group("/admin", RequireScope(auth.ScopeAdmin))
+	{
+		v1admin.POST("/reconciliations", p.CreateReconciliation)
 	}
 }
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+	"wallet/logic/transfer"
+)
+
+func (p *WalletService) CancelTransfer(c *gin.Context) {
+	res, cancelErr := p.transferLogic.CancelTransfer(c.Request.Context(), c.Param("transactionID"), &transfer.ScheduledTransferOpts{
+		ClientID: clientID(c),
+	})
+	if cancelErr != nil {
+		respondError(c, cancelErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) RescheduleTransfer(c *gin.Context) {
+	var req dto.RescheduleTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, rescheduleErr := p.transferLogic.RescheduleTransfer(c.Request.Context(), c.Param("transactionID"), &req, &transfer.ScheduledTransferOpts{
+		ClientID: clientID(c),
+	})
+	if rescheduleErr != nil {
+		respondError(c, rescheduleErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+	"wallet/logic/standing"
+)
+
+func (p *WalletService) CreateStandingInstruction(c *gin.Context) {
+	var req dto.CreateStandingInstructionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, createErr := p.standingLogic.CreateInstruction(c.Request.Context(), &req, &standing.InstructionOpts{ClientID: clientID(c), UserID: userID(c)})
+	if createErr != nil {
+		respondError(c, createErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) GetStandingInstruction(c *gin.Context) {
+	var req dto.StandingInstructionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, getErr := p.standingLogic.GetInstruction(c.Request.Context(), req.InstructionID, &standing.InstructionOpts{ClientID: clientID(c)})
+	if getErr != nil {
+		respondError(c, getErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) SuspendStandingInstruction(c *gin.Context) {
+	var req dto.StandingInstructionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, suspendErr := p.standingLogic.SuspendInstruction(c.Request.Context(), req.InstructionID, &standing.InstructionOpts{ClientID: clientID(c)})
+	if suspendErr != nil {
+		respondError(c, suspendErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) ResumeStandingInstruction(c *gin.Context) {
+	var req dto.StandingInstructionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, resumeErr := p.standingLogic.ResumeInstruction(c.Request.Context(), req.InstructionID, &standing.InstructionOpts{ClientID: clientID(c)})
+	if resumeErr != nil {
+		respondError(c, resumeErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) CancelStandingInstruction(c *gin.Context) {
+	var req dto.StandingInstructionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, cancelErr := p.standingLogic.CancelInstruction(c.Request.Context(), req.InstructionID, &standing.InstructionOpts{ClientID: clientID(c)})
+	if cancelErr != nil {
+		respondError(c, cancelErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/batch"
+)
+
+// batchCSVColumns are the columns of a CSV batch upload, named as the JSON fields of an item
+var batchCSVColumns = []string{"reference", "sourceAccountID", "destinationAccountID", "amount", "currency", "note"}
+
+var requiredBatchCSVColumns = []string{"sourceAccountID", "destinationAccountID", "amount", "currency"}
+
+// CreateTransferBatch serves POST /v1/payment/batches. The batch is accepted with 202 and processed in the
+// background; its progress is read with GetTransferBatch.
+//
+// Besides JSON, the items can be uploaded as CSV, either as the request body (Content-Type: text/csv) or as
+// the "file" part of a multipart form. The first row names the columns: reference, sourceAccountID,
+// destinationAccountID, amount, currency and note, in any order; reference and note may be left out. The
+// idempotency key is then taken from the Idempotency-Key header, and the mode from the "mode" form field or
+// query parameter.
+func (p *WalletService) CreateTransferBatch(c *gin.Context) {
+	var req dto.CreateTransferBatchRequest
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	switch mediaType {
+	case "text/csv", "multipart/form-data":
+		if err := bindBatchCSV(c, mediaType, &req); err != nil {
+			respondError(c, err)
+			return
+		}
+		if err := binding.Validator.ValidateStruct(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	default:
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	res, createErr := p.batchLogic.CreateBatch(c.Request.Context(), &req, &batch.BatchOpts{ClientID: clientID(c), UserID: userID(c)})
+	if createErr != nil {
+		respondError(c, createErr)
+		return
+	}
+	c.JSON(http.StatusAccepted, res)
+}
+
+// GetTransferBatch serves GET /v1/payment/batches/{batchID}?limit=...&nextToken=...
+func (p *WalletService) GetTransferBatch(c *gin.Context) {
+	var req dto.GetTransferBatchRequest
+	if err := c.ShouldBindUri(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, getErr := p.batchLogic.GetBatch(c.Request.Context(), &req, &batch.BatchOpts{ClientID: clientID(c)})
+	if getErr != nil {
+		respondError(c, getErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// bindBatchCSV fills req from a CSV upload
+func bindBatchCSV(c *gin.Context, mediaType string, req *dto.CreateTransferBatchRequest) error {
+	req.IdempotencyKey = c.GetHeader(idempotencyKeyHeader)
+	req.Mode = c.Query("mode")
+	body := c.Request.Body
+	if mediaType == "multipart/form-data" {
+		file, err := c.FormFile("file")
+		if err != nil {
+			return apperr.New(apperr.CodeInvalidRequest).Wrap(err)
+		}
+		f, err := file.Open()
+		if err != nil {
+			return apperr.New(apperr.CodeInvalidRequest).Wrap(err)
+		}
+		defer f.Close()
+		body = f
+		if mode := c.PostForm("mode"); mode != "" {
+			req.Mode = mode
+		}
+	}
+	items, err := parseBatchCSV(body)
+	if err != nil {
+		return err
+	}
+	req.Items = items
+	return nil
+}
+
+// parseBatchCSV reads the items of a CSV upload. Amounts that are not whole numbers are reported as field
+// errors; everything else is left to the validation of the request.
+func parseBatchCSV(r io.Reader) ([]*dto.TransferBatchItemRequest, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperr.New(apperr.CodeInvalidRequest).Wrap(err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		for _, column := range batchCSVColumns {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				columns[column] = i
+			}
+		}
+	}
+	var fields []apperr.FieldError
+	for _, column := range requiredBatchCSVColumns {
+		if _, ok := columns[column]; !ok {
+			fields = append(fields, apperr.FieldError{Field: column, Rule: "required"})
+		}
+	}
+	if len(fields) > 0 {
+		return nil, apperr.New(apperr.CodeValidationFailed).WithFields(fields...)
+	}
+
+	var items []*dto.TransferBatchItemRequest
+	for {
+		record, readErr := reader.Read()
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return nil, apperr.New(apperr.CodeInvalidRequest).Wrap(readErr)
+		}
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		item := &dto.TransferBatchItemRequest{
+			Reference:            value("reference"),
+			SourceAccountID:      value("sourceAccountID"),
+			DestinationAccountID: value("destinationAccountID"),
+			Currency:             value("currency"),
+			Note:                 value("note"),
+		}
+		if amount := value("amount"); amount != "" {
+			if item.Amount, err = strconv.ParseInt(amount, 10, 64); err != nil {
+				fields = append(fields, apperr.FieldError{Field: fmt.Sprintf("items[%d].amount", len(items)), Rule: "numeric"})
+			}
+		}
+		items = append(items, item)
+	}
+	if len(fields) > 0 {
+		return nil, apperr.New(apperr.CodeValidationFailed).WithFields(fields...)
+	}
+	return items, nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"wallet/dto"
+)
+
+func (p *WalletService) CreateUser(c *gin.Context) {
+	var req dto.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, createErr := p.userLogic.CreateUser(c.Request.Context(), &req)
+	if createErr != nil {
+		respondError(c, createErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) GetUser(c *gin.Context) {
+	var req dto.UserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, getErr := p.userLogic.GetUser(c.Request.Context(), req.UserID)
+	if getErr != nil {
+		respondError(c, getErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) UpdateUser(c *gin.Context) {
+	var req dto.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, updateErr := p.userLogic.UpdateUser(c.Request.Context(), &req)
+	if updateErr != nil {
+		respondError(c, updateErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) ChangeUserStatus(c *gin.Context) {
+	var req dto.UserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, statusErr := p.userLogic.ChangeUserStatus(c.Request.Context(), &req)
+	if statusErr != nil {
+		respondError(c, statusErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) LinkUserAccount(c *gin.Context) {
+	var req dto.LinkAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, linkErr := p.userLogic.LinkAccount(c.Request.Context(), &req)
+	if linkErr != nil {
+		respondError(c, linkErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (p *WalletService) UnlinkUserAccount(c *gin.Context) {
+	var req dto.UnlinkAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	res, unlinkErr := p.userLogic.UnlinkAccount(c.Request.Context(), &req)
+	if unlinkErr != nil {
+		respondError(c, unlinkErr)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
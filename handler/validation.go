@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"reflect"
+	"strings"
+	"wallet/accountnumber"
+	"wallet/currency"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		registerValidations(v)
+	}
+}
+
+// registerValidations adds the custom validation tags used by the DTOs to v
+func registerValidations(v *validator.Validate) {
+	// report validation failures under the JSON names of the fields
+	v.RegisterTagNameFunc(jsonFieldName)
+	_ = v.RegisterValidation("currency", func(fl validator.FieldLevel) bool {
+		return currency.IsRegistered(fl.Field().String())
+	})
+	// rejects malformed account numbers, such as ones with a typo, before they are looked up
+	_ = v.RegisterValidation("accountnumber", func(fl validator.FieldLevel) bool {
+		return accountnumber.Valid(fl.Field().String())
+	})
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name := strings.SplitN(f.Tag.Get("json"), ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
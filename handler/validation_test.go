@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"wallet/dto"
+)
+
+func Test_currencyValidation(t *testing.T) {
+	tests := []struct {
+		currency string
+		wantErr  bool
+	}{
+		{currency: "MYR"},
+		{currency: "SGD"},
+		{currency: "JPY"},
+		{currency: "XYZ", wantErr: true},
+		{currency: "myr", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			c, _ := newMockGinContext(t, http.MethodPost, "/v1/accounts/deposits", gin.H{
+				"idempotencyKey": "idempotency-key",
+				"accountID":      "12345678",
+				"amount":         1000,
+				"currency":       tt.currency,
+			})
+			var req dto.CreateDepositRequest
+			err := c.ShouldBindJSON(&req)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			var validationErrs validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			require.Len(t, validationErrs, 1)
+			require.Equal(t, "currency", validationErrs[0].Tag())
+			require.Equal(t, "CreateDepositRequest.currency", validationErrs[0].Namespace())
+		})
+	}
+}
+
+func Test_accountNumberValidation(t *testing.T) {
+	tests := []struct {
+		number  string
+		wantErr bool
+	}{
+		{number: "800000000011"},
+		{number: "12345678"}, // issued before the account number scheme
+		{number: "800000000018", wantErr: true},
+		{number: "12345678x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.number, func(t *testing.T) {
+			c, _ := newMockGinContext(t, http.MethodPost, "/v1/accounts/deposits", gin.H{
+				"idempotencyKey": "idempotency-key",
+				"accountID":      tt.number,
+				"amount":         1000,
+				"currency":       "MYR",
+			})
+			var req dto.CreateDepositRequest
+			err := c.ShouldBindJSON(&req)
+			if !tt.wantErr {
+				require.NoError(t, err)
+				return
+			}
+			var validationErrs validator.ValidationErrors
+			require.ErrorAs(t, err, &validationErrs)
+			require.Len(t, validationErrs, 1)
+			require.Equal(t, "accountnumber", validationErrs[0].Tag())
+			require.Equal(t, "CreateDepositRequest.accountID", validationErrs[0].Namespace())
+		})
+	}
+}
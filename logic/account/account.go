@@ -0,0 +1,206 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"wallet/accountnumber"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+// maxNumberAttempts bounds how many account numbers are drawn before giving up on finding a free one
+const maxNumberAttempts = 5
+
+var (
+	AccountNotFoundErr      = apperr.New(apperr.CodeAccountNotFound)
+	AccountNotEmptyErr      = apperr.New(apperr.CodeAccountNotEmpty)
+	InvalidAccountStatusErr = apperr.New(apperr.CodeInvalidAccountStatus)
+)
+
+// IAccountLogic manages the lifecycle of customer accounts, the WALLET and CASA accounts. Internal accounts
+// such as holding accounts are set up with the database and cannot be changed through it.
+type IAccountLogic interface {
+	OpenAccount(context.Context, *dto.OpenAccountRequest) (*dto.AccountResponse, error)
+	UpdateAccount(context.Context, *dto.UpdateAccountRequest) (*dto.AccountResponse, error)
+	FreezeAccount(context.Context, *dto.FreezeAccountRequest) (*dto.AccountResponse, error)
+	UnfreezeAccount(context.Context, *dto.FreezeAccountRequest) (*dto.AccountResponse, error)
+	CloseAccount(ctx context.Context, accountID string) (*dto.AccountResponse, error)
+}
+
+type logicImpl struct {
+	AccountDAO storage.IAccountDAO
+
+	numbers *accountnumber.Scheme
+}
+
+func NewAccountLogic(ad storage.IAccountDAO, numbers *accountnumber.Scheme) IAccountLogic {
+	return &logicImpl{AccountDAO: ad, numbers: numbers}
+}
+
+// OpenAccount opens an ACTIVE account with a newly generated account number
+func (l *logicImpl) OpenAccount(ctx context.Context, req *dto.OpenAccountRequest) (*dto.AccountResponse, error) {
+	accountType := req.Type
+	if accountType == "" {
+		accountType = storage.AccountTypeWallet
+	}
+	accountID, err := l.newAccountNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	acc, err := l.AccountDAO.Create(ctx, &storage.Account{
+		AccountID: accountID,
+		Name:      req.Name,
+		Type:      accountType,
+		Currency:  req.Currency,
+		Status:    storage.AccountStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapAccountStorageToResponse(acc), nil
+}
+
+// newAccountNumber draws account numbers from the scheme until it finds one not taken yet
+func (l *logicImpl) newAccountNumber(ctx context.Context) (string, error) {
+	for attempt := 0; attempt < maxNumberAttempts; attempt++ {
+		number, err := l.numbers.Generate()
+		if err != nil {
+			return "", err
+		}
+		_, findErr := l.AccountDAO.FindByAccountID(ctx, number)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return number, nil
+		}
+		if findErr != nil {
+			return "", findErr
+		}
+	}
+	return "", errors.New("no free account number found")
+}
+
+// UpdateAccount renames the account or changes its type. Closed accounts cannot be changed.
+func (l *logicImpl) UpdateAccount(ctx context.Context, req *dto.UpdateAccountRequest) (*dto.AccountResponse, error) {
+	return l.updateAccount(ctx, req.AccountID, func(acc *storage.Account) error {
+		if acc.Closed() {
+			return InvalidAccountStatusErr
+		}
+		if req.Name != "" {
+			acc.Name = req.Name
+		}
+		if req.Type != "" {
+			acc.Type = req.Type
+		}
+		return nil
+	})
+}
+
+// FreezeAccount freezes debits, credits or both of the account, on top of what is frozen already
+func (l *logicImpl) FreezeAccount(ctx context.Context, req *dto.FreezeAccountRequest) (*dto.AccountResponse, error) {
+	return l.updateAccount(ctx, req.AccountID, func(acc *storage.Account) error {
+		if acc.Closed() {
+			return InvalidAccountStatusErr
+		}
+		debit, credit := freezeScope(req.Scope)
+		setFreeze(acc, acc.DebitFrozen() || debit, acc.CreditFrozen() || credit)
+		return nil
+	})
+}
+
+// UnfreezeAccount lifts the freeze of debits, credits or both of the account. The account is ACTIVE again
+// once nothing is frozen.
+func (l *logicImpl) UnfreezeAccount(ctx context.Context, req *dto.FreezeAccountRequest) (*dto.AccountResponse, error) {
+	return l.updateAccount(ctx, req.AccountID, func(acc *storage.Account) error {
+		if acc.Closed() {
+			return InvalidAccountStatusErr
+		}
+		debit, credit := freezeScope(req.Scope)
+		setFreeze(acc, acc.DebitFrozen() && !debit, acc.CreditFrozen() && !credit)
+		return nil
+	})
+}
+
+// CloseAccount closes the account for good. Only accounts with no balance and no active holds can be
+// closed.
+func (l *logicImpl) CloseAccount(ctx context.Context, accountID string) (*dto.AccountResponse, error) {
+	return l.updateAccount(ctx, accountID, func(acc *storage.Account) error {
+		if acc.Closed() {
+			return InvalidAccountStatusErr
+		}
+		if acc.Balance != 0 || acc.HeldBalance != 0 {
+			return AccountNotEmptyErr
+		}
+		now := time.Now()
+		acc.Status = storage.AccountStatusClosed
+		acc.Freeze = ""
+		acc.ClosedAt = &now
+		return nil
+	})
+}
+
+// updateAccount applies change to the customer account accountID and saves it, with the account locked
+func (l *logicImpl) updateAccount(ctx context.Context, accountID string, change func(acc *storage.Account) error) (*dto.AccountResponse, error) {
+	var updated *storage.Account
+	err := l.AccountDAO.RunInTransaction(func(tx *gorm.DB) error {
+		accountDAO := l.AccountDAO.WithTx(tx)
+		acc, findErr := accountDAO.FindByAccountIDForUpdate(ctx, accountID)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) || (acc != nil && !isCustomerAccount(acc)) {
+			return AccountNotFoundErr
+		}
+		if findErr != nil {
+			return findErr
+		}
+		if changeErr := change(acc); changeErr != nil {
+			return changeErr
+		}
+		updated = acc
+		return accountDAO.UpdateDetails(ctx, acc)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapAccountStorageToResponse(updated), nil
+}
+
+func isCustomerAccount(acc *storage.Account) bool {
+	return acc.Type == storage.AccountTypeWallet || acc.Type == storage.AccountTypeCASA
+}
+
+// freezeScope returns whether scope covers debits and credits
+func freezeScope(scope string) (debit bool, credit bool) {
+	return scope == storage.FreezeDebit || scope == storage.FreezeAll, scope == storage.FreezeCredit || scope == storage.FreezeAll
+}
+
+// setFreeze sets the freeze and status of acc from what is frozen
+func setFreeze(acc *storage.Account, debit bool, credit bool) {
+	switch {
+	case debit && credit:
+		acc.Freeze = storage.FreezeAll
+	case debit:
+		acc.Freeze = storage.FreezeDebit
+	case credit:
+		acc.Freeze = storage.FreezeCredit
+	default:
+		acc.Freeze = ""
+	}
+	acc.Status = storage.AccountStatusActive
+	if acc.Freeze != "" {
+		acc.Status = storage.AccountStatusFrozen
+	}
+}
+
+func mapAccountStorageToResponse(acc *storage.Account) *dto.AccountResponse {
+	return &dto.AccountResponse{
+		AccountID: acc.AccountID,
+		Name:      acc.Name,
+		Type:      acc.Type,
+		Currency:  acc.Currency,
+		Status:    acc.Status,
+		Freeze:    acc.Freeze,
+		Balance:   acc.Balance,
+		CreatedAt: acc.CreatedAt,
+		ClosedAt:  acc.ClosedAt,
+	}
+}
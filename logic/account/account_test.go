@@ -0,0 +1,174 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wallet/accountnumber"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// inTransaction runs the next transaction of ad straight away on ad itself
+func inTransaction(ad *storagemock.MockIAccountDAO) {
+	ad.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn) error {
+		return fn(nil)
+	}).Once()
+	ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+}
+
+func Test_logicImpl_OpenAccount(t *testing.T) {
+	ad := storagemock.NewMockIAccountDAO(t)
+	ad.EXPECT().FindByAccountID(mock.Anything, mock.Anything).Return(&storage.Account{}, nil).Once()
+	ad.EXPECT().FindByAccountID(mock.Anything, mock.Anything).Return(nil, gorm.ErrRecordNotFound).Once()
+	ad.EXPECT().Create(mock.Anything, mock.MatchedBy(func(acc *storage.Account) bool {
+		return len(acc.AccountID) == 12 && accountnumber.DefaultScheme().Valid(acc.AccountID) &&
+			acc.Type == storage.AccountTypeWallet && acc.Status == storage.AccountStatusActive
+	})).RunAndReturn(func(_ context.Context, acc *storage.Account) (*storage.Account, error) {
+		return acc, nil
+	}).Once()
+
+	l := &logicImpl{AccountDAO: ad, numbers: accountnumber.DefaultScheme()}
+	got, err := l.OpenAccount(context.Background(), &dto.OpenAccountRequest{Name: "Main Wallet", Currency: "MYR"})
+	if err != nil {
+		t.Fatalf("OpenAccount() error = %v", err)
+	}
+	if got.AccountID == "" || got.Status != storage.AccountStatusActive || got.Currency != "MYR" {
+		t.Errorf("OpenAccount() = %+v, want an active MYR account", got)
+	}
+}
+
+func Test_logicImpl_FreezeAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		account    *storage.Account
+		freeze     bool
+		scope      string
+		wantStatus string
+		wantFreeze string
+		wantErr    error
+	}{
+		{
+			name:       "happy path - freeze debits of an active account",
+			account:    &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusActive},
+			freeze:     true,
+			scope:      storage.FreezeDebit,
+			wantStatus: storage.AccountStatusFrozen,
+			wantFreeze: storage.FreezeDebit,
+		},
+		{
+			name:       "happy path - freezing credits on top of debits freezes both",
+			account:    &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusFrozen, Freeze: storage.FreezeDebit},
+			freeze:     true,
+			scope:      storage.FreezeCredit,
+			wantStatus: storage.AccountStatusFrozen,
+			wantFreeze: storage.FreezeAll,
+		},
+		{
+			name:       "happy path - unfreezing debits of a fully frozen account leaves credits frozen",
+			account:    &storage.Account{Type: storage.AccountTypeCASA, Status: storage.AccountStatusFrozen, Freeze: storage.FreezeAll},
+			scope:      storage.FreezeDebit,
+			wantStatus: storage.AccountStatusFrozen,
+			wantFreeze: storage.FreezeCredit,
+		},
+		{
+			name:       "happy path - unfreezing everything makes the account active",
+			account:    &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusFrozen, Freeze: storage.FreezeCredit},
+			scope:      storage.FreezeAll,
+			wantStatus: storage.AccountStatusActive,
+		},
+		{
+			name:    "error - closed account",
+			account: &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusClosed},
+			freeze:  true,
+			scope:   storage.FreezeAll,
+			wantErr: InvalidAccountStatusErr,
+		},
+		{
+			name:    "error - internal account",
+			account: &storage.Account{Type: storage.AccountTypeHolding, Status: storage.AccountStatusActive},
+			freeze:  true,
+			scope:   storage.FreezeAll,
+			wantErr: AccountNotFoundErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			inTransaction(ad)
+			tt.account.AccountID = "12345678"
+			ad.EXPECT().FindByAccountIDForUpdate(mock.Anything, "12345678").Return(tt.account, nil).Once()
+			if tt.wantErr == nil {
+				ad.EXPECT().UpdateDetails(mock.Anything, tt.account).Return(nil).Once()
+			}
+
+			l := &logicImpl{AccountDAO: ad}
+			req := &dto.FreezeAccountRequest{AccountID: "12345678", Scope: tt.scope}
+			var got *dto.AccountResponse
+			var err error
+			if tt.freeze {
+				got, err = l.FreezeAccount(context.Background(), req)
+			} else {
+				got, err = l.UnfreezeAccount(context.Background(), req)
+			}
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("FreezeAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Status != tt.wantStatus || got.Freeze != tt.wantFreeze) {
+				t.Errorf("FreezeAccount() = %s/%s, want %s/%s", got.Status, got.Freeze, tt.wantStatus, tt.wantFreeze)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_CloseAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		account *storage.Account
+		wantErr error
+	}{
+		{
+			name:    "happy path - empty frozen account is closed",
+			account: &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusFrozen, Freeze: storage.FreezeAll},
+		},
+		{
+			name:    "error - account with a balance",
+			account: &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusActive, Balance: 100},
+			wantErr: AccountNotEmptyErr,
+		},
+		{
+			name:    "error - account with active holds",
+			account: &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusActive, HeldBalance: 100},
+			wantErr: AccountNotEmptyErr,
+		},
+		{
+			name:    "error - already closed",
+			account: &storage.Account{Type: storage.AccountTypeWallet, Status: storage.AccountStatusClosed},
+			wantErr: InvalidAccountStatusErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			inTransaction(ad)
+			tt.account.AccountID = "12345678"
+			ad.EXPECT().FindByAccountIDForUpdate(mock.Anything, "12345678").Return(tt.account, nil).Once()
+			if tt.wantErr == nil {
+				ad.EXPECT().UpdateDetails(mock.Anything, tt.account).Return(nil).Once()
+			}
+
+			l := &logicImpl{AccountDAO: ad}
+			got, err := l.CloseAccount(context.Background(), "12345678")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CloseAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Status != storage.AccountStatusClosed || got.Freeze != "" || got.ClosedAt == nil) {
+				t.Errorf("CloseAccount() = %+v, want a closed account", got)
+			}
+		})
+	}
+}
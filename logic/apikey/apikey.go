@@ -0,0 +1,289 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"gorm.io/gorm"
+	"strconv"
+	"strings"
+	"time"
+	"wallet/apperr"
+	"wallet/auth"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+const (
+	keyIDPrefix  = "ak_"
+	secretPrefix = "sk_"
+	minNonceLen  = 16
+	maxNonceLen  = 64
+)
+
+var (
+	APIKeyNotFoundErr  = apperr.New(apperr.CodeAPIKeyNotFound)
+	APIKeyNotActiveErr = apperr.New(apperr.CodeAPIKeyNotActive)
+	UnauthenticatedErr = apperr.New(apperr.CodeUnauthenticated)
+)
+
+// Why a signed request was rejected. Callers only see UnauthenticatedErr, which wraps one of these.
+var (
+	errKeyNotUsable   = errors.New("api key revoked or expired")
+	errStaleTimestamp = errors.New("timestamp outside the accepted window")
+	errInvalidNonce   = errors.New("nonce must be 16 to 64 characters")
+	errReplayedNonce  = errors.New("nonce already used")
+)
+
+// SignedRequest is a request signed with an API key, as received
+type SignedRequest struct {
+	KeyID      string
+	Timestamp  string
+	Nonce      string
+	Signature  string
+	Method     string
+	RequestURI string
+	Body       []byte
+}
+
+// IAPIKeyLogic issues the API keys partners' backends sign requests with, and authenticates those requests.
+// A key's secret is only returned when it is issued. The wallet keeps it encrypted under the master key and
+// decrypts it to check signatures, so the api_key table alone is not enough to sign requests.
+type IAPIKeyLogic interface {
+	IssueKey(ctx context.Context, clientID string, scopes []string) (*dto.APIKeyResponse, error)
+	// RotateKey issues a replacement for the key, which keeps working for the rotation grace period
+	RotateKey(ctx context.Context, keyID string) (issued *dto.APIKeyResponse, rotated *dto.APIKeyResponse, err error)
+	RevokeKey(ctx context.Context, keyID string) (*dto.APIKeyResponse, error)
+	ListKeys(ctx context.Context, clientID string) ([]*dto.APIKeyResponse, error)
+	Authenticate(context.Context, *SignedRequest) (*auth.Principal, error)
+	// PurgeNonces forgets the nonces of requests too old to be accepted anyway
+	PurgeNonces(ctx context.Context) (int64, error)
+}
+
+type logicImpl struct {
+	APIKeyDAO storage.IAPIKeyDAO
+	cfg       *Config
+	now       func() time.Time
+}
+
+func NewAPIKeyLogic(dao storage.IAPIKeyDAO, cfg *Config) IAPIKeyLogic {
+	if cfg == nil {
+		cfg = DefaultConfig()
+	}
+	return &logicImpl{APIKeyDAO: dao, cfg: cfg, now: time.Now}
+}
+
+// IssueKey issues an ACTIVE key to the client, granted scopes
+func (l *logicImpl) IssueKey(ctx context.Context, clientID string, scopes []string) (*dto.APIKeyResponse, error) {
+	if err := validateKey(clientID, scopes); err != nil {
+		return nil, err
+	}
+	key, secret, err := l.newKey(clientID, strings.Join(scopes, " "))
+	if err != nil {
+		return nil, err
+	}
+	if err = l.APIKeyDAO.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	resp := mapAPIKeyStorageToResponse(key)
+	resp.Secret = secret
+	return resp, nil
+}
+
+func (l *logicImpl) RotateKey(ctx context.Context, keyID string) (*dto.APIKeyResponse, *dto.APIKeyResponse, error) {
+	var issued, old *storage.APIKey
+	var secret string
+	err := l.APIKeyDAO.RunInTransaction(func(tx *gorm.DB) error {
+		dao := l.APIKeyDAO.WithTx(tx)
+		var err error
+		old, err = dao.FindByKeyIDForUpdate(ctx, keyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return APIKeyNotFoundErr
+		}
+		if err != nil {
+			return err
+		}
+		now := l.now()
+		if !old.Usable(now) {
+			return APIKeyNotActiveErr
+		}
+		issued, secret, err = l.newKey(old.ClientID, old.Scopes)
+		if err != nil {
+			return err
+		}
+		if err = dao.Create(ctx, issued); err != nil {
+			return err
+		}
+		// a key rotated twice keeps its earlier expiry
+		expiresAt := now.Add(l.cfg.RotationGrace)
+		if old.ExpiresAt == nil || expiresAt.Before(*old.ExpiresAt) {
+			old.ExpiresAt = &expiresAt
+		}
+		old.UpdatedAt = now
+		return dao.Save(ctx, old)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	resp := mapAPIKeyStorageToResponse(issued)
+	resp.Secret = secret
+	return resp, mapAPIKeyStorageToResponse(old), nil
+}
+
+// RevokeKey rejects requests signed with the key from now on. Revoking a revoked key changes nothing.
+func (l *logicImpl) RevokeKey(ctx context.Context, keyID string) (*dto.APIKeyResponse, error) {
+	var key *storage.APIKey
+	err := l.APIKeyDAO.RunInTransaction(func(tx *gorm.DB) error {
+		dao := l.APIKeyDAO.WithTx(tx)
+		var err error
+		key, err = dao.FindByKeyIDForUpdate(ctx, keyID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return APIKeyNotFoundErr
+		}
+		if err != nil {
+			return err
+		}
+		if key.Status == storage.APIKeyStatusRevoked {
+			return nil
+		}
+		now := l.now()
+		key.Status = storage.APIKeyStatusRevoked
+		key.RevokedAt = &now
+		key.UpdatedAt = now
+		return dao.Save(ctx, key)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapAPIKeyStorageToResponse(key), nil
+}
+
+// ListKeys returns the keys issued to the client, revoked and expired ones included, oldest first
+func (l *logicImpl) ListKeys(ctx context.Context, clientID string) ([]*dto.APIKeyResponse, error) {
+	keys, err := l.APIKeyDAO.FindByClientID(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	resp := make([]*dto.APIKeyResponse, 0, len(keys))
+	for _, key := range keys {
+		resp = append(resp, mapAPIKeyStorageToResponse(key))
+	}
+	return resp, nil
+}
+
+// Authenticate checks the signature of the request and returns the client the key was issued to, acting for
+// itself. A request is accepted once: its timestamp must be within the timestamp window of now, and its
+// nonce is remembered for as long.
+func (l *logicImpl) Authenticate(ctx context.Context, req *SignedRequest) (*auth.Principal, error) {
+	timestamp, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, UnauthenticatedErr.Wrap(errStaleTimestamp)
+	}
+	now := l.now()
+	signedAt := time.Unix(timestamp, 0)
+	if signedAt.Before(now.Add(-l.cfg.TimestampWindow)) || signedAt.After(now.Add(l.cfg.TimestampWindow)) {
+		return nil, UnauthenticatedErr.Wrap(errStaleTimestamp)
+	}
+	if len(req.Nonce) < minNonceLen || len(req.Nonce) > maxNonceLen {
+		return nil, UnauthenticatedErr.Wrap(errInvalidNonce)
+	}
+
+	key, err := l.APIKeyDAO.FindByKeyID(ctx, req.KeyID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, UnauthenticatedErr.Wrap(auth.ErrUnknownKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !key.Usable(now) {
+		return nil, UnauthenticatedErr.Wrap(errKeyNotUsable)
+	}
+	secret, err := openSecret(l.cfg.MasterKey, key.KeyID, key.SealedSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !auth.ValidSignature(secret, req.Signature, req.Method, req.RequestURI, timestamp, req.Nonce, req.Body) {
+		return nil, UnauthenticatedErr.Wrap(auth.ErrInvalidSignature)
+	}
+
+	// claimed only once the signature is valid, so nobody can burn the nonces of a partner
+	claimed, err := l.APIKeyDAO.ClaimNonce(ctx, &storage.APINonce{
+		KeyID:     key.KeyID,
+		Nonce:     req.Nonce,
+		ExpiresAt: signedAt.Add(l.cfg.TimestampWindow),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, UnauthenticatedErr.Wrap(errReplayedNonce)
+	}
+	return &auth.Principal{
+		Subject:  key.ClientID,
+		ClientID: key.ClientID,
+		Scopes:   strings.Fields(key.Scopes),
+	}, nil
+}
+
+func (l *logicImpl) PurgeNonces(ctx context.Context) (int64, error) {
+	return l.APIKeyDAO.DeleteExpiredNonces(ctx, l.now())
+}
+
+func (l *logicImpl) newKey(clientID string, scopes string) (*storage.APIKey, string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, "", err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, "", err
+	}
+	keyID := keyIDPrefix + hex.EncodeToString(id)
+	secret := secretPrefix + base64.RawURLEncoding.EncodeToString(raw)
+	sealed, err := sealSecret(l.cfg.MasterKey, keyID, secret)
+	if err != nil {
+		return nil, "", err
+	}
+	now := l.now()
+	return &storage.APIKey{
+		KeyID:        keyID,
+		ClientID:     clientID,
+		SealedSecret: sealed,
+		Scopes:       scopes,
+		Status:       storage.APIKeyStatusActive,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}, secret, nil
+}
+
+func validateKey(clientID string, scopes []string) error {
+	if clientID == "" {
+		return apperr.New(apperr.CodeValidationFailed).WithFields(apperr.FieldError{Field: "clientID", Rule: "required"})
+	}
+	if len(clientID) > 64 {
+		return apperr.New(apperr.CodeValidationFailed).WithFields(apperr.FieldError{Field: "clientID", Rule: "max", Param: "64"})
+	}
+	if len(scopes) == 0 {
+		return apperr.New(apperr.CodeValidationFailed).WithFields(apperr.FieldError{Field: "scopes", Rule: "required"})
+	}
+	for _, scope := range scopes {
+		if !auth.KnownScope(scope) {
+			return apperr.New(apperr.CodeValidationFailed).WithFields(apperr.FieldError{Field: "scopes", Rule: "scope", Param: scope})
+		}
+	}
+	return nil
+}
+
+func mapAPIKeyStorageToResponse(key *storage.APIKey) *dto.APIKeyResponse {
+	return &dto.APIKeyResponse{
+		KeyID:     key.KeyID,
+		ClientID:  key.ClientID,
+		Scopes:    strings.Fields(key.Scopes),
+		Status:    key.Status,
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
@@ -0,0 +1,283 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"wallet/apperr"
+	"wallet/auth"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testMasterKey = []byte("0123456789abcdef0123456789abcdef")
+
+func testConfig() *Config {
+	cfg := DefaultConfig()
+	cfg.MasterKey = testMasterKey
+	return cfg
+}
+
+// inTransaction runs the next transaction of dao straight away on dao itself
+func inTransaction(dao *storagemock.MockIAPIKeyDAO) {
+	dao.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn) error {
+		return fn(nil)
+	}).Once()
+	dao.EXPECT().WithTx(mock.Anything).Return(dao).Once()
+}
+
+func Test_logicImpl_IssueKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		clientID string
+		scopes   []string
+		wantErr  error
+	}{
+		{
+			name:     "happy path",
+			clientID: "payroll-acme",
+			scopes:   []string{auth.ScopeTransfersWrite, auth.ScopeTransfersRead},
+		},
+		{
+			name:    "error - no client",
+			scopes:  []string{auth.ScopeTransfersWrite},
+			wantErr: apperr.New(apperr.CodeValidationFailed),
+		},
+		{
+			name:     "error - unknown scope",
+			clientID: "payroll-acme",
+			scopes:   []string{"transfers:*"},
+			wantErr:  apperr.New(apperr.CodeValidationFailed),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := storagemock.NewMockIAPIKeyDAO(t)
+			var created *storage.APIKey
+			if tt.wantErr == nil {
+				dao.EXPECT().Create(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, key *storage.APIKey) error {
+					created = key
+					return nil
+				}).Once()
+			}
+
+			l := NewAPIKeyLogic(dao, testConfig())
+			got, err := l.IssueKey(context.Background(), tt.clientID, tt.scopes)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("IssueKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Secret == "" || got.KeyID != created.KeyID || created.Scopes != "transfers:write transfers:read" {
+				t.Errorf("IssueKey() = %+v, stored %+v", got, created)
+			}
+			if strings.Contains(created.SealedSecret, strings.TrimPrefix(got.Secret, secretPrefix)) {
+				t.Error("IssueKey() stored the secret in the clear")
+			}
+			if opened, openErr := openSecret(testMasterKey, created.KeyID, created.SealedSecret); openErr != nil || string(opened) != got.Secret {
+				t.Errorf("IssueKey() stored a sealed secret that does not open to the secret: %v", openErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_RotateKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	earlier := now.Add(time.Hour)
+	revokedAt := now.Add(-time.Hour)
+	tests := []struct {
+		name          string
+		key           *storage.APIKey
+		findErr       error
+		wantExpiresAt time.Time
+		wantErr       error
+	}{
+		{
+			name:          "happy path - old key works for the grace period",
+			key:           &storage.APIKey{KeyID: "ak_old", ClientID: "payroll-acme", Scopes: "transfers:write", Status: storage.APIKeyStatusActive},
+			wantExpiresAt: now.Add(24 * time.Hour),
+		},
+		{
+			name:          "happy path - rotated twice keeps the earlier expiry",
+			key:           &storage.APIKey{KeyID: "ak_old", ClientID: "payroll-acme", Scopes: "transfers:write", Status: storage.APIKeyStatusActive, ExpiresAt: &earlier},
+			wantExpiresAt: earlier,
+		},
+		{
+			name:    "error - revoked key",
+			key:     &storage.APIKey{KeyID: "ak_old", Status: storage.APIKeyStatusRevoked, RevokedAt: &revokedAt},
+			wantErr: APIKeyNotActiveErr,
+		},
+		{
+			name:    "error - no such key",
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: APIKeyNotFoundErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := storagemock.NewMockIAPIKeyDAO(t)
+			inTransaction(dao)
+			dao.EXPECT().FindByKeyIDForUpdate(mock.Anything, "ak_old").Return(tt.key, tt.findErr).Once()
+			if tt.wantErr == nil {
+				dao.EXPECT().Create(mock.Anything, mock.MatchedBy(func(k *storage.APIKey) bool {
+					return k.KeyID != "ak_old" && k.ClientID == "payroll-acme" && k.Scopes == "transfers:write"
+				})).Return(nil).Once()
+				dao.EXPECT().Save(mock.Anything, mock.MatchedBy(func(k *storage.APIKey) bool {
+					return k.KeyID == "ak_old" && k.ExpiresAt.Equal(tt.wantExpiresAt)
+				})).Return(nil).Once()
+			}
+
+			l := &logicImpl{APIKeyDAO: dao, cfg: testConfig(), now: func() time.Time { return now }}
+			issued, rotated, err := l.RotateKey(context.Background(), "ak_old")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("RotateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (issued.Secret == "" || rotated.Secret != "" || rotated.ExpiresAt == nil) {
+				t.Errorf("RotateKey() = %+v, %+v", issued, rotated)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_Authenticate(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	secret := "sk_test-secret"
+	sealed, err := sealSecret(testMasterKey, "ak_1", secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"amount":"10.00"}`)
+	nonce := "0123456789abcdef"
+	expired := now.Add(-time.Second)
+	active := &storage.APIKey{
+		KeyID:        "ak_1",
+		ClientID:     "payroll-acme",
+		SealedSecret: sealed,
+		Scopes:       "transfers:write transfers:read",
+		Status:       storage.APIKeyStatusActive,
+	}
+	signed := func(signedAt time.Time, nonce string) *SignedRequest {
+		return &SignedRequest{
+			KeyID:      "ak_1",
+			Timestamp:  strconv.FormatInt(signedAt.Unix(), 10),
+			Nonce:      nonce,
+			Signature:  auth.SignRequest([]byte(secret), "POST", "/v1/transfers", signedAt.Unix(), nonce, body),
+			Method:     "POST",
+			RequestURI: "/v1/transfers",
+			Body:       body,
+		}
+	}
+
+	tests := []struct {
+		name      string
+		req       *SignedRequest
+		key       *storage.APIKey
+		findErr   error
+		claimed   bool
+		wantCause error
+	}{
+		{
+			name:    "happy path",
+			req:     signed(now.Add(-time.Minute), nonce),
+			key:     active,
+			claimed: true,
+		},
+		{
+			name:      "error - timestamp too old",
+			req:       signed(now.Add(-6*time.Minute), nonce),
+			wantCause: errStaleTimestamp,
+		},
+		{
+			name:      "error - timestamp in the future",
+			req:       signed(now.Add(6*time.Minute), nonce),
+			wantCause: errStaleTimestamp,
+		},
+		{
+			name:      "error - nonce too short",
+			req:       signed(now, "abc"),
+			wantCause: errInvalidNonce,
+		},
+		{
+			name:      "error - unknown key",
+			req:       signed(now, nonce),
+			findErr:   gorm.ErrRecordNotFound,
+			wantCause: auth.ErrUnknownKey,
+		},
+		{
+			name: "error - rotated key past its grace period",
+			req:  signed(now, nonce),
+			key: &storage.APIKey{
+				KeyID: "ak_1", SealedSecret: sealed, Status: storage.APIKeyStatusActive, ExpiresAt: &expired,
+			},
+			wantCause: errKeyNotUsable,
+		},
+		{
+			name: "error - body tampered with",
+			req: func() *SignedRequest {
+				req := signed(now, nonce)
+				req.Body = []byte(`{"amount":"1000.00"}`)
+				return req
+			}(),
+			key:       active,
+			wantCause: auth.ErrInvalidSignature,
+		},
+		{
+			name:      "error - replayed",
+			req:       signed(now, nonce),
+			key:       active,
+			claimed:   false,
+			wantCause: errReplayedNonce,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dao := storagemock.NewMockIAPIKeyDAO(t)
+			if tt.key != nil || tt.findErr != nil {
+				dao.EXPECT().FindByKeyID(mock.Anything, "ak_1").Return(tt.key, tt.findErr).Once()
+			}
+			if tt.claimed || errors.Is(tt.wantCause, errReplayedNonce) {
+				dao.EXPECT().ClaimNonce(mock.Anything, mock.MatchedBy(func(n *storage.APINonce) bool {
+					return n.KeyID == "ak_1" && n.Nonce == nonce
+				})).Return(tt.claimed, nil).Once()
+			}
+
+			l := &logicImpl{APIKeyDAO: dao, cfg: testConfig(), now: func() time.Time { return now }}
+			got, err := l.Authenticate(context.Background(), tt.req)
+			if !errors.Is(err, tt.wantCause) || (err == nil) != (tt.wantCause == nil) {
+				t.Fatalf("Authenticate() error = %v, want cause %v", err, tt.wantCause)
+			}
+			if err != nil {
+				if !errors.Is(err, UnauthenticatedErr) {
+					t.Errorf("Authenticate() error = %v, want %v", err, UnauthenticatedErr)
+				}
+				return
+			}
+			if got.UserID() != "" || got.ClientID != "payroll-acme" || !got.HasScope(auth.ScopeTransfersWrite) {
+				t.Errorf("Authenticate() = %+v", got)
+			}
+		})
+	}
+}
+
+func Test_openSecret(t *testing.T) {
+	sealed, err := sealSecret(testMasterKey, "ak_1", "sk_test-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, openErr := openSecret(testMasterKey, "ak_1", sealed); openErr != nil || string(got) != "sk_test-secret" {
+		t.Errorf("openSecret() = %q, %v, want the secret", got, openErr)
+	}
+	if _, openErr := openSecret(testMasterKey, "ak_2", sealed); openErr == nil {
+		t.Error("openSecret() of a secret sealed for another key succeeded, want an error")
+	}
+	if _, openErr := openSecret([]byte("fedcba9876543210fedcba9876543210"), "ak_1", sealed); openErr == nil {
+		t.Error("openSecret() under another master key succeeded, want an error")
+	}
+}
@@ -0,0 +1,69 @@
+package apikey
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvMasterKeyFile names the file holding the master key, for ConfigFromEnv
+const EnvMasterKeyFile = "WALLET_API_KEY_MASTER_KEY_FILE"
+
+// masterKeyLen is the length of an AES-256 key
+const masterKeyLen = 32
+
+type Config struct {
+	// MasterKey encrypts the secrets of API keys at rest, with AES-256-GCM. Nil disables signed requests.
+	MasterKey []byte
+	// TimestampWindow is how far the timestamp of a signed request may be from the server's clock, either
+	// way. Nonces are remembered for as long, so a captured request cannot be replayed.
+	TimestampWindow time.Duration
+	// RotationGrace is how long a rotated key keeps working next to its replacement, so partners can switch
+	// without downtime
+	RotationGrace time.Duration
+	// NoncePurgeInterval is how often nonces that cannot be replayed any more are purged. Zero disables the
+	// purge.
+	NoncePurgeInterval time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		TimestampWindow:    5 * time.Minute,
+		RotationGrace:      24 * time.Hour,
+		NoncePurgeInterval: time.Minute,
+	}
+}
+
+// ConfigFromEnv returns DefaultConfig with the master key read from the file named by
+// WALLET_API_KEY_MASTER_KEY_FILE, hex encoded. Signed requests stay disabled if it is not set.
+func ConfigFromEnv() (*Config, error) {
+	cfg := DefaultConfig()
+	file := os.Getenv(EnvMasterKeyFile)
+	if file == "" {
+		return cfg, nil
+	}
+	raw, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	cfg.MasterKey, err = hex.DecodeString(string(bytes.TrimSpace(raw)))
+	if err != nil {
+		return nil, fmt.Errorf("master key in %s is not hex: %w", file, err)
+	}
+	return cfg, cfg.Check()
+}
+
+// Enabled reports whether signed requests are accepted, which needs a master key
+func (c *Config) Enabled() bool {
+	return c != nil && len(c.MasterKey) > 0
+}
+
+// Check reports a master key of the wrong length
+func (c *Config) Check() error {
+	if len(c.MasterKey) > 0 && len(c.MasterKey) != masterKeyLen {
+		return fmt.Errorf("master key is %d bytes, want %d", len(c.MasterKey), masterKeyLen)
+	}
+	return nil
+}
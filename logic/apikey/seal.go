@@ -0,0 +1,53 @@
+package apikey
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+var errNoMasterKey = errors.New("no API key master key configured")
+
+// sealSecret encrypts secret under masterKey with AES-256-GCM. The key ID is authenticated with it, so a
+// sealed secret copied to another key's row does not open.
+func sealSecret(masterKey []byte, keyID string, secret string) (string, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(secret), []byte(keyID))
+	return base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// openSecret decrypts a secret sealed by sealSecret
+func openSecret(masterKey []byte, keyID string, sealed string) ([]byte, error) {
+	aead, err := newAEAD(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(sealed)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) < aead.NonceSize() {
+		return nil, errors.New("sealed secret too short")
+	}
+	return aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(keyID))
+}
+
+func newAEAD(masterKey []byte) (cipher.AEAD, error) {
+	if len(masterKey) == 0 {
+		return nil, errNoMasterKey
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
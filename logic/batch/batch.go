@@ -0,0 +1,498 @@
+package batch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strconv"
+	"sync"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/transfer"
+	"wallet/storage"
+	"wallet/util"
+)
+
+const (
+	claimBatchSize  = 10
+	itemPageSize    = 500
+	defaultPageSize = 100
+	// maxFieldErrors caps how many invalid items are reported, so a bad upload does not produce a huge error
+	maxFieldErrors = 100
+)
+
+// itemNamespace derives the idempotency keys of the transfers made for batch items
+var itemNamespace = uuid.MustParse("9d3f2a61-7c4e-4b8a-a1f5-6e0d8c2b4f17")
+
+var (
+	BatchNotFoundErr   = apperr.New(apperr.CodeBatchNotFound)
+	BatchRolledBackErr = apperr.New(apperr.CodeBatchRolledBack)
+)
+
+type BatchOpts struct {
+	// ClientID is the client acting on the batch. Batches are only visible to the client that submitted
+	// them, and their transfers are made on its behalf.
+	ClientID string
+	// UserID is the user submitting the batch. When set, the user must be allowed to debit the source
+	// account of every item, when the batch is submitted and when each transfer is made.
+	UserID string
+}
+
+type IBatchLogic interface {
+	CreateBatch(ctx context.Context, req *dto.CreateTransferBatchRequest, opts *BatchOpts) (*dto.TransferBatchResponse, error)
+	GetBatch(ctx context.Context, req *dto.GetTransferBatchRequest, opts *BatchOpts) (*dto.TransferBatchResponse, error)
+	ProcessPendingBatches(ctx context.Context) (int64, error)
+}
+
+type logicImpl struct {
+	TransferBatchDAO storage.ITransferBatchDAO
+	AccountDAO       storage.IAccountDAO
+	TransferLogic    transfer.ITransferLogic
+
+	cfg *Config
+}
+
+func NewBatchLogic(bd storage.ITransferBatchDAO, ad storage.IAccountDAO, tl transfer.ITransferLogic, cfg *Config) IBatchLogic {
+	return &logicImpl{
+		TransferBatchDAO: bd,
+		AccountDAO:       ad,
+		TransferLogic:    tl,
+		cfg:              cfg,
+	}
+}
+
+// CreateBatch validates every item of req and stores the batch as PENDING. Nothing is stored if any item is
+// invalid; the invalid items are reported as field errors instead. The transfers are made later by
+// ProcessPendingBatches.
+func (l *logicImpl) CreateBatch(ctx context.Context, req *dto.CreateTransferBatchRequest, opts *BatchOpts) (*dto.TransferBatchResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	mode := req.Mode
+	if mode == "" {
+		mode = storage.BatchModeIndependent
+	}
+	fingerprint := batchFingerprint(mode, req.Items)
+	if existing, _ := l.TransferBatchDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+		return replayBatch(existing, fingerprint)
+	}
+
+	if mode == storage.BatchModeAtomic && l.cfg.MaxAtomicItems > 0 && len(req.Items) > l.cfg.MaxAtomicItems {
+		return nil, apperr.New(apperr.CodeValidationFailed).WithFields(apperr.FieldError{
+			Field: "items",
+			Rule:  "max",
+			Param: strconv.Itoa(l.cfg.MaxAtomicItems),
+		})
+	}
+	if err := l.checkAccounts(ctx, req.Items); err != nil {
+		return nil, err
+	}
+	if err := l.checkDebitAccess(ctx, opts.UserID, req.Items); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	batch := &storage.TransferBatch{
+		BatchID:     uuid.New().String(),
+		ClientID:    opts.ClientID,
+		ReferenceID: req.IdempotencyKey,
+		RequestHash: fingerprint,
+		UserID:      opts.UserID,
+		Mode:        mode,
+		Status:      storage.BatchStatusPending,
+		ItemCount:   len(req.Items),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	items := make([]*storage.TransferBatchItem, 0, len(req.Items))
+	for i, item := range req.Items {
+		items = append(items, &storage.TransferBatchItem{
+			BatchID:              batch.BatchID,
+			ItemIndex:            i,
+			Reference:            item.Reference,
+			SourceAccountID:      item.SourceAccountID,
+			DestinationAccountID: item.DestinationAccountID,
+			Currency:             item.Currency,
+			Amount:               item.Amount,
+			Note:                 item.Note,
+			Status:               storage.BatchItemStatusPending,
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		})
+	}
+	if createErr := l.TransferBatchDAO.Create(ctx, batch, items); createErr != nil {
+		// a concurrent request with the same key got there first
+		if existing, _ := l.TransferBatchDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+			return replayBatch(existing, fingerprint)
+		}
+		return nil, createErr
+	}
+	res := mapBatchStorageToResponse(batch, nil)
+	res.PendingCount = batch.ItemCount
+	return res, nil
+}
+
+// checkAccounts checks the accounts of every item exist and are held in the item currency
+func (l *logicImpl) checkAccounts(ctx context.Context, items []*dto.TransferBatchItemRequest) error {
+	ids := map[string]bool{}
+	for _, item := range items {
+		ids[item.SourceAccountID] = true
+		ids[item.DestinationAccountID] = true
+	}
+	accountIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		accountIDs = append(accountIDs, id)
+	}
+	accounts, err := l.AccountDAO.FindByAccountIDs(ctx, accountIDs)
+	if err != nil {
+		return err
+	}
+	currencies := make(map[string]string, len(accounts))
+	for _, acc := range accounts {
+		currencies[acc.AccountID] = acc.Currency
+	}
+
+	var fields []apperr.FieldError
+	for i, item := range items {
+		if currencies[item.SourceAccountID] != item.Currency {
+			fields = append(fields, apperr.FieldError{Field: fmt.Sprintf("items[%d].sourceAccountID", i), Rule: "account"})
+		}
+		if currencies[item.DestinationAccountID] != item.Currency {
+			fields = append(fields, apperr.FieldError{Field: fmt.Sprintf("items[%d].destinationAccountID", i), Rule: "account"})
+		}
+		if len(fields) >= maxFieldErrors {
+			break
+		}
+	}
+	if len(fields) > 0 {
+		return apperr.New(apperr.CodeValidationFailed).WithFields(fields...)
+	}
+	return nil
+}
+
+// checkDebitAccess checks userID may debit the source account of every item. Batches submitted without a
+// user, by partners, are not checked.
+func (l *logicImpl) checkDebitAccess(ctx context.Context, userID string, items []*dto.TransferBatchItemRequest) error {
+	if userID == "" {
+		return nil
+	}
+	checked := map[string]bool{}
+	for _, item := range items {
+		if checked[item.SourceAccountID] {
+			continue
+		}
+		checked[item.SourceAccountID] = true
+		if err := l.TransferLogic.CheckDebitAccess(ctx, userID, item.SourceAccountID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// batchFingerprint returns the SHA-256 of the canonical items and mode of a batch request
+func batchFingerprint(mode string, items []*dto.TransferBatchItemRequest) string {
+	canonical, _ := json.Marshal(struct {
+		Mode  string                          `json:"mode"`
+		Items []*dto.TransferBatchItemRequest `json:"items"`
+	}{Mode: mode, Items: items})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// replayBatch answers a repeated create request with the batch submitted by the first one
+func replayBatch(existing *storage.TransferBatch, fingerprint string) (*dto.TransferBatchResponse, error) {
+	if existing.RequestHash != fingerprint {
+		return nil, transfer.IdempotencyKeyReusedErr
+	}
+	return mapBatchStorageToResponse(existing, nil), nil
+}
+
+// GetBatch returns a batch with its progress and a page of its items
+func (l *logicImpl) GetBatch(ctx context.Context, req *dto.GetTransferBatchRequest, opts *BatchOpts) (*dto.TransferBatchResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	batch, err := l.TransferBatchDAO.FindByBatchID(ctx, req.BatchID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (batch != nil && batch.ClientID != opts.ClientID) {
+		return nil, BatchNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &storage.BatchItemFilter{BatchID: batch.BatchID, AfterIndex: -1, Limit: req.Limit}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if req.NextToken != "" {
+		cursor, decodeErr := util.DecodeNextToken(req.NextToken)
+		if decodeErr != nil {
+			return nil, apperr.New(apperr.CodeInvalidNextToken)
+		}
+		if filter.AfterIndex, decodeErr = strconv.Atoi(cursor.LastID); decodeErr != nil {
+			return nil, apperr.New(apperr.CodeInvalidNextToken)
+		}
+	}
+	items, err := l.TransferBatchDAO.FindItems(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := l.TransferBatchDAO.CountItemsByStatus(ctx, batch.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapBatchStorageToResponse(batch, items)
+	res.PendingCount = counts[storage.BatchItemStatusPending]
+	res.CompletedCount = counts[storage.BatchItemStatusCompleted]
+	res.FailedCount = counts[storage.BatchItemStatusFailed]
+	if len(items) == filter.Limit {
+		res.NextToken = util.EncodeNextToken(util.DataCursor{LastID: strconv.Itoa(items[len(items)-1].ItemIndex)})
+	}
+	return res, nil
+}
+
+// ProcessPendingBatches makes the transfers of every submitted batch and returns how many batches it
+// finished. Batches are claimed before they are processed, so any number of instances may run it at the
+// same time. An instance that crashes part way through a batch leaves it to be picked up again once its
+// claim has expired: the transfer of an item always has the same idempotency key, so items already paid
+// are only replayed.
+func (l *logicImpl) ProcessPendingBatches(ctx context.Context) (int64, error) {
+	var processed int64
+	var errs []error
+	for {
+		claimed, err := l.TransferBatchDAO.ClaimPending(ctx, time.Now(), l.cfg.Lease, claimBatchSize)
+		if err != nil {
+			return processed, errors.Join(append(errs, err)...)
+		}
+		for _, batch := range claimed {
+			if processErr := l.processBatch(ctx, batch); processErr != nil {
+				errs = append(errs, fmt.Errorf("batch %s: %w", batch.BatchID, processErr))
+				continue
+			}
+			processed++
+		}
+		if len(claimed) < claimBatchSize {
+			return processed, errors.Join(errs...)
+		}
+	}
+}
+
+func (l *logicImpl) processBatch(ctx context.Context, batch *storage.TransferBatch) error {
+	var err error
+	if batch.Mode == storage.BatchModeAtomic {
+		err = l.processAtomically(ctx, batch)
+	} else {
+		err = l.processIndependently(ctx, batch)
+	}
+	if err != nil {
+		return err
+	}
+
+	counts, err := l.TransferBatchDAO.CountItemsByStatus(ctx, batch.BatchID)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	batch.CompletedCount = counts[storage.BatchItemStatusCompleted]
+	batch.FailedCount = counts[storage.BatchItemStatusFailed]
+	switch batch.CompletedCount {
+	case batch.ItemCount:
+		batch.Status = storage.BatchStatusCompleted
+	case 0:
+		batch.Status = storage.BatchStatusFailed
+	default:
+		batch.Status = storage.BatchStatusPartiallyCompleted
+	}
+	batch.LockedUntil = nil
+	batch.FinishedAt = &now
+	batch.UpdatedAt = now
+	return l.TransferBatchDAO.Save(ctx, batch)
+}
+
+// processIndependently makes the transfers of the pending items of batch, a page at a time, each page with
+// a pool of workers. Each item completes or fails on its own.
+func (l *logicImpl) processIndependently(ctx context.Context, batch *storage.TransferBatch) error {
+	for {
+		items, err := l.TransferBatchDAO.FindItems(ctx, &storage.BatchItemFilter{
+			BatchID:    batch.BatchID,
+			Status:     storage.BatchItemStatusPending,
+			AfterIndex: -1,
+			Limit:      itemPageSize,
+		})
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		if runErr := l.runItems(ctx, batch, items); runErr != nil {
+			return runErr
+		}
+		if renewErr := l.TransferBatchDAO.RenewLease(ctx, batch.BatchID, time.Now().Add(l.cfg.Lease)); renewErr != nil {
+			return renewErr
+		}
+	}
+}
+
+func (l *logicImpl) runItems(ctx context.Context, batch *storage.TransferBatch, items []*storage.TransferBatchItem) error {
+	workers := l.cfg.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	work := make(chan *storage.TransferBatchItem)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				if err := l.runItem(ctx, batch, item); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("item %d: %w", item.ItemIndex, err))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, item := range items {
+		work <- item
+	}
+	close(work)
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// runItem makes the transfer of item and records its outcome. A failed transfer fails the item: it was
+// recorded as failed under its idempotency key, so making it again would only replay the failure. Any other
+// error leaves the item pending, to be made again with the same key.
+func (l *logicImpl) runItem(ctx context.Context, batch *storage.TransferBatch, item *storage.TransferBatchItem) error {
+	res, err := l.TransferLogic.CreateTransfer(ctx, itemTransferRequest(batch, item), &transfer.CreateTransferOpts{
+		TxType:   transfer.TxTypeP2PTransfer,
+		ClientID: batch.ClientID,
+		UserID:   batch.UserID,
+	})
+	if err == nil && res.Status == transfer.TxStatusPROCESSING {
+		// another instance is still making the transfer, or left it to the transfer executor
+		return errors.New("item transfer still processing")
+	}
+	if err != nil && !transfer.IsOneOfTransferErrors(err) {
+		return err
+	}
+	if err != nil {
+		item.Status = storage.BatchItemStatusFailed
+		item.StatusReason = string(apperr.From(err).Code)
+	} else {
+		item.Status = storage.BatchItemStatusCompleted
+		item.TransactionID = res.TransactionID
+	}
+	item.UpdatedAt = time.Now()
+	return l.TransferBatchDAO.SaveItem(ctx, item)
+}
+
+// processAtomically makes the transfers of every item of batch in one database transaction. If one fails,
+// it fails with its reason and every other item fails with BATCH_ROLLED_BACK.
+func (l *logicImpl) processAtomically(ctx context.Context, batch *storage.TransferBatch) error {
+	items, err := l.TransferBatchDAO.FindItems(ctx, &storage.BatchItemFilter{
+		BatchID:    batch.BatchID,
+		Status:     storage.BatchItemStatusPending,
+		AfterIndex: -1,
+		Limit:      batch.ItemCount,
+	})
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	reqs := make([]*dto.CreateTransferRequest, 0, len(items))
+	for _, item := range items {
+		reqs = append(reqs, itemTransferRequest(batch, item))
+	}
+
+	responses, err := l.TransferLogic.CreateTransfersAtomically(ctx, reqs, &transfer.CreateTransferOpts{
+		TxType:   transfer.TxTypeP2PTransfer,
+		ClientID: batch.ClientID,
+		UserID:   batch.UserID,
+	})
+	var itemErr *transfer.ItemError
+	if err != nil && (!errors.As(err, &itemErr) || !transfer.IsOneOfTransferErrors(itemErr.Err)) {
+		// nothing was made; the batch is processed again once the claim expires
+		return err
+	}
+	now := time.Now()
+	for i, item := range items {
+		switch {
+		case itemErr == nil:
+			item.Status = storage.BatchItemStatusCompleted
+			item.TransactionID = responses[i].TransactionID
+		case itemErr.Index == i:
+			item.Status = storage.BatchItemStatusFailed
+			item.StatusReason = string(apperr.From(itemErr.Err).Code)
+		default:
+			item.Status = storage.BatchItemStatusFailed
+			item.StatusReason = string(BatchRolledBackErr.Code)
+		}
+		item.UpdatedAt = now
+		if saveErr := l.TransferBatchDAO.SaveItem(ctx, item); saveErr != nil {
+			return saveErr
+		}
+	}
+	return nil
+}
+
+func itemTransferRequest(batch *storage.TransferBatch, item *storage.TransferBatchItem) *dto.CreateTransferRequest {
+	properties := map[string]interface{}{
+		"batchID":   batch.BatchID,
+		"batchItem": item.ItemIndex,
+	}
+	if item.Reference != "" {
+		properties["reference"] = item.Reference
+	}
+	return &dto.CreateTransferRequest{
+		Currency:           item.Currency,
+		Amount:             item.Amount,
+		SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: item.SourceAccountID},
+		DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: item.DestinationAccountID},
+		Properties:         properties,
+		Note:               item.Note,
+		IdempotencyKey:     itemKey(batch.BatchID, item.ItemIndex),
+	}
+}
+
+// itemKey returns the idempotency key of the transfer made for item index of a batch
+func itemKey(batchID string, index int) string {
+	return uuid.NewSHA1(itemNamespace, []byte(fmt.Sprintf("%s/%d", batchID, index))).String()
+}
+
+func mapBatchStorageToResponse(batch *storage.TransferBatch, items []*storage.TransferBatchItem) *dto.TransferBatchResponse {
+	res := &dto.TransferBatchResponse{
+		BatchID:        batch.BatchID,
+		Mode:           batch.Mode,
+		Status:         batch.Status,
+		ItemCount:      batch.ItemCount,
+		CompletedCount: batch.CompletedCount,
+		FailedCount:    batch.FailedCount,
+		CreatedAt:      batch.CreatedAt,
+		FinishedAt:     batch.FinishedAt,
+	}
+	for _, item := range items {
+		res.Items = append(res.Items, &dto.TransferBatchItemResponse{
+			Index:                item.ItemIndex,
+			Reference:            item.Reference,
+			SourceAccountID:      item.SourceAccountID,
+			DestinationAccountID: item.DestinationAccountID,
+			Amount:               item.Amount,
+			Currency:             item.Currency,
+			Status:               item.Status,
+			StatusReason:         item.StatusReason,
+			TransactionID:        item.TransactionID,
+		})
+	}
+	return res
+}
@@ -0,0 +1,305 @@
+package batch
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/transfer"
+	transfermock "wallet/logic/transfer/mocks"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newItems() []*dto.TransferBatchItemRequest {
+	return []*dto.TransferBatchItemRequest{
+		{Reference: "EMP-1", SourceAccountID: "payroll", DestinationAccountID: "employee-1", Amount: 350000, Currency: "MYR"},
+		{Reference: "EMP-2", SourceAccountID: "payroll", DestinationAccountID: "employee-2", Amount: 420000, Currency: "MYR"},
+	}
+}
+
+func Test_logicImpl_CreateBatch(t *testing.T) {
+	accounts := []*storage.Account{
+		{AccountID: "payroll", Currency: "MYR"},
+		{AccountID: "employee-1", Currency: "MYR"},
+		{AccountID: "employee-2", Currency: "SGD"},
+	}
+
+	tests := []struct {
+		name          string
+		req           *dto.CreateTransferBatchRequest
+		userID        string
+		setupMocks    func(bd *storagemock.MockITransferBatchDAO, ad *storagemock.MockIAccountDAO)
+		setupTransfer func(tl *transfermock.MockITransferLogic)
+		wantErr       error
+		wantFields    []string
+	}{
+		{
+			name: "happy path - stored as pending",
+			req: func() *dto.CreateTransferBatchRequest {
+				req := &dto.CreateTransferBatchRequest{IdempotencyKey: "idempotency-key", Items: newItems()}
+				req.Items[1].DestinationAccountID = "employee-1"
+				return req
+			}(),
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, ad *storagemock.MockIAccountDAO) {
+				bd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+				ad.EXPECT().FindByAccountIDs(mock.Anything, mock.Anything).Return(accounts, nil).Once()
+				bd.EXPECT().Create(mock.Anything, mock.MatchedBy(func(b *storage.TransferBatch) bool {
+					return b.Status == storage.BatchStatusPending && b.Mode == storage.BatchModeIndependent && b.ItemCount == 2
+				}), mock.MatchedBy(func(items []*storage.TransferBatchItem) bool {
+					return len(items) == 2 && items[1].ItemIndex == 1 && items[1].Status == storage.BatchItemStatusPending
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "error - user cannot debit a source account",
+			req: func() *dto.CreateTransferBatchRequest {
+				req := &dto.CreateTransferBatchRequest{IdempotencyKey: "idempotency-key", Items: newItems()}
+				req.Items[1].DestinationAccountID = "employee-1"
+				return req
+			}(),
+			userID: "user-1",
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, ad *storagemock.MockIAccountDAO) {
+				bd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+				ad.EXPECT().FindByAccountIDs(mock.Anything, mock.Anything).Return(accounts, nil).Once()
+			},
+			setupTransfer: func(tl *transfermock.MockITransferLogic) {
+				// both items debit the same account, which is checked once
+				tl.EXPECT().CheckDebitAccess(mock.Anything, "user-1", "payroll").Return(transfer.AccountAccessDeniedErr).Once()
+			},
+			wantErr: transfer.AccountAccessDeniedErr,
+		},
+		{
+			name: "error - items with unknown accounts or another currency",
+			req: func() *dto.CreateTransferBatchRequest {
+				req := &dto.CreateTransferBatchRequest{IdempotencyKey: "idempotency-key", Items: newItems()}
+				req.Items[0].SourceAccountID = "unknown"
+				return req
+			}(),
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, ad *storagemock.MockIAccountDAO) {
+				bd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+				ad.EXPECT().FindByAccountIDs(mock.Anything, mock.Anything).Return(accounts, nil).Once()
+			},
+			wantErr:    apperr.New(apperr.CodeValidationFailed),
+			wantFields: []string{"items[0].sourceAccountID", "items[1].destinationAccountID"},
+		},
+		{
+			name: "error - atomic batch over the maximum",
+			req: &dto.CreateTransferBatchRequest{
+				IdempotencyKey: "idempotency-key",
+				Mode:           storage.BatchModeAtomic,
+				Items:          append(newItems(), newItems()...),
+			},
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, _ *storagemock.MockIAccountDAO) {
+				bd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr:    apperr.New(apperr.CodeValidationFailed),
+			wantFields: []string{"items"},
+		},
+		{
+			name: "error - key reused for other items",
+			req:  &dto.CreateTransferBatchRequest{IdempotencyKey: "idempotency-key", Items: newItems()},
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, _ *storagemock.MockIAccountDAO) {
+				bd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").
+					Return(&storage.TransferBatch{BatchID: "batch-1", RequestHash: "other"}, nil).Once()
+			},
+			wantErr: transfer.IdempotencyKeyReusedErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := storagemock.NewMockITransferBatchDAO(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			tl := transfermock.NewMockITransferLogic(t)
+			tt.setupMocks(bd, ad)
+			if tt.setupTransfer != nil {
+				tt.setupTransfer(tl)
+			}
+
+			l := &logicImpl{TransferBatchDAO: bd, AccountDAO: ad, TransferLogic: tl, cfg: &Config{MaxAtomicItems: 3}}
+			got, err := l.CreateBatch(context.Background(), tt.req, &BatchOpts{ClientID: "client-a", UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CreateBatch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				fields := apperr.From(err).Fields
+				if len(fields) != len(tt.wantFields) {
+					t.Fatalf("CreateBatch() fields = %+v, want %v", fields, tt.wantFields)
+				}
+				for i, f := range fields {
+					if f.Field != tt.wantFields[i] {
+						t.Errorf("CreateBatch() field %d = %s, want %s", i, f.Field, tt.wantFields[i])
+					}
+				}
+				return
+			}
+			if got.BatchID == "" || got.Status != storage.BatchStatusPending || got.PendingCount != 2 {
+				t.Errorf("CreateBatch() = %+v, want a pending batch of 2 items", got)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_ProcessPendingBatches(t *testing.T) {
+	newItemRecords := func() []*storage.TransferBatchItem {
+		return []*storage.TransferBatchItem{
+			{BatchID: "batch-1", ItemIndex: 0, SourceAccountID: "payroll", DestinationAccountID: "employee-1", Amount: 350000, Currency: "MYR", Status: storage.BatchItemStatusPending},
+			{BatchID: "batch-1", ItemIndex: 1, SourceAccountID: "payroll", DestinationAccountID: "employee-2", Amount: 420000, Currency: "MYR", Status: storage.BatchItemStatusPending},
+		}
+	}
+	pendingItems := func(f *storage.BatchItemFilter) bool {
+		return f.BatchID == "batch-1" && f.Status == storage.BatchItemStatusPending
+	}
+
+	tests := []struct {
+		name         string
+		mode         string
+		setupMocks   func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic)
+		wantStatus   string
+		wantReasons  []string
+		wantErr      bool
+		wantFinished int64
+	}{
+		{
+			name: "independent - one item fails on its own",
+			mode: storage.BatchModeIndependent,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()
+				bd.EXPECT().FindItems(mock.Anything, mock.MatchedBy(pendingItems)).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfer(mock.Anything, mock.MatchedBy(func(req *dto.CreateTransferRequest) bool {
+					return req.DestinationAccount.Number == "employee-1" && req.IdempotencyKey == itemKey("batch-1", 0)
+				}), mock.Anything).Return(&dto.CreateTransferResponse{TransactionID: "tx-1", Status: transfer.TxStatusCOMPLETED}, nil).Once()
+				tl.EXPECT().CreateTransfer(mock.Anything, mock.MatchedBy(func(req *dto.CreateTransferRequest) bool {
+					return req.DestinationAccount.Number == "employee-2"
+				}), mock.Anything).Return(nil, transfer.InsufficientBalanceErr).Once()
+				bd.EXPECT().RenewLease(mock.Anything, "batch-1", mock.Anything).Return(nil).Once()
+				bd.EXPECT().FindItems(mock.Anything, mock.MatchedBy(pendingItems)).Return(nil, nil).Once()
+				bd.EXPECT().CountItemsByStatus(mock.Anything, "batch-1").Return(map[string]int{
+					storage.BatchItemStatusCompleted: 1,
+					storage.BatchItemStatusFailed:    1,
+				}, nil).Once()
+			},
+			wantStatus:   storage.BatchStatusPartiallyCompleted,
+			wantReasons:  []string{"", string(apperr.CodeInsufficientBalance)},
+			wantFinished: 1,
+		},
+		{
+			name: "independent - item still processing elsewhere leaves the batch claimed",
+			mode: storage.BatchModeIndependent,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()[:1]
+				bd.EXPECT().FindItems(mock.Anything, mock.Anything).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).
+					Return(&dto.CreateTransferResponse{TransactionID: "tx-1", Status: transfer.TxStatusPROCESSING}, nil).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "independent - unexpected failure leaves the item pending",
+			mode: storage.BatchModeIndependent,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()[:1]
+				bd.EXPECT().FindItems(mock.Anything, mock.Anything).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "atomic - one failure rolls back every item",
+			mode: storage.BatchModeAtomic,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()
+				bd.EXPECT().FindItems(mock.Anything, mock.Anything).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfersAtomically(mock.Anything, mock.MatchedBy(func(reqs []*dto.CreateTransferRequest) bool {
+					return len(reqs) == 2
+				}), mock.Anything).Return(nil, &transfer.ItemError{Index: 1, Err: transfer.InsufficientBalanceErr}).Once()
+				bd.EXPECT().CountItemsByStatus(mock.Anything, "batch-1").Return(map[string]int{
+					storage.BatchItemStatusFailed: 2,
+				}, nil).Once()
+			},
+			wantStatus:   storage.BatchStatusFailed,
+			wantReasons:  []string{string(apperr.CodeBatchRolledBack), string(apperr.CodeInsufficientBalance)},
+			wantFinished: 1,
+		},
+		{
+			name: "atomic - unexpected failure leaves every item pending",
+			mode: storage.BatchModeAtomic,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()
+				bd.EXPECT().FindItems(mock.Anything, mock.Anything).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfersAtomically(mock.Anything, mock.Anything, mock.Anything).
+					Return(nil, &transfer.ItemError{Index: 1, Err: errors.New("connection refused")}).Once()
+			},
+			wantErr: true,
+		},
+		{
+			name: "atomic - all items completed",
+			mode: storage.BatchModeAtomic,
+			setupMocks: func(bd *storagemock.MockITransferBatchDAO, tl *transfermock.MockITransferLogic) {
+				items := newItemRecords()
+				bd.EXPECT().FindItems(mock.Anything, mock.Anything).Return(items, nil).Once()
+				tl.EXPECT().CreateTransfersAtomically(mock.Anything, mock.Anything, mock.Anything).Return([]*dto.CreateTransferResponse{
+					{TransactionID: "tx-1"}, {TransactionID: "tx-2"},
+				}, nil).Once()
+				bd.EXPECT().CountItemsByStatus(mock.Anything, "batch-1").Return(map[string]int{
+					storage.BatchItemStatusCompleted: 2,
+				}, nil).Once()
+			},
+			wantStatus:   storage.BatchStatusCompleted,
+			wantReasons:  []string{"", ""},
+			wantFinished: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bd := storagemock.NewMockITransferBatchDAO(t)
+			tl := transfermock.NewMockITransferLogic(t)
+			batch := &storage.TransferBatch{BatchID: "batch-1", ClientID: "client-a", Mode: tt.mode, Status: storage.BatchStatusProcessing, ItemCount: 2}
+			bd.EXPECT().ClaimPending(mock.Anything, mock.Anything, mock.Anything, claimBatchSize).Return([]*storage.TransferBatch{batch}, nil).Once()
+			tt.setupMocks(bd, tl)
+			// items are saved by concurrent workers
+			var mu sync.Mutex
+			var saved []*storage.TransferBatchItem
+			bd.EXPECT().SaveItem(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, item *storage.TransferBatchItem) error {
+				mu.Lock()
+				defer mu.Unlock()
+				saved = append(saved, item)
+				return nil
+			}).Maybe()
+			if !tt.wantErr {
+				bd.EXPECT().Save(mock.Anything, batch).Return(nil).Once()
+			}
+
+			l := &logicImpl{TransferBatchDAO: bd, TransferLogic: tl, cfg: &Config{Workers: 2}}
+			got, err := l.ProcessPendingBatches(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ProcessPendingBatches() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantFinished {
+				t.Errorf("ProcessPendingBatches() = %d, want %d", got, tt.wantFinished)
+			}
+			if tt.wantErr {
+				if len(saved) != 0 {
+					t.Errorf("saved %d items, want them left pending", len(saved))
+				}
+				return
+			}
+			if batch.Status != tt.wantStatus || batch.FinishedAt == nil || batch.LockedUntil != nil {
+				t.Errorf("batch = %+v, want finished with status %s", batch, tt.wantStatus)
+			}
+			if len(saved) != len(tt.wantReasons) {
+				t.Fatalf("saved %d items, want %d", len(saved), len(tt.wantReasons))
+			}
+			for _, item := range saved {
+				if item.StatusReason != tt.wantReasons[item.ItemIndex] {
+					t.Errorf("item %d reason = %q, want %q", item.ItemIndex, item.StatusReason, tt.wantReasons[item.ItemIndex])
+				}
+			}
+		})
+	}
+}
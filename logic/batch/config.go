@@ -0,0 +1,26 @@
+package batch
+
+import "time"
+
+type Config struct {
+	// Workers is how many items of an INDEPENDENT batch are processed at the same time, per instance
+	Workers int
+	// MaxAtomicItems caps the number of items of an ATOMIC batch, since they are all made in one database
+	// transaction
+	MaxAtomicItems int
+	// Lease is how long an instance may go without progress on a batch it claimed. A batch claimed by an
+	// instance that crashed is picked up again once its lease has passed.
+	Lease time.Duration
+	// ProcessInterval is how often submitted batches are picked up. Zero disables processing, e.g. on
+	// instances that should only serve requests.
+	ProcessInterval time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		Workers:         8,
+		MaxAtomicItems:  1000,
+		Lease:           5 * time.Minute,
+		ProcessInterval: time.Second,
+	}
+}
@@ -0,0 +1,99 @@
+package fee
+
+// Charge is one line of a fee breakdown
+type Charge struct {
+	Name   string `json:"name"`
+	Amount int64  `json:"amount"` // in minor unit of the transfer currency
+}
+
+// Tier prices the transfer amounts up to and including UpTo
+type Tier struct {
+	UpTo        int64 // inclusive upper bound in minor units; zero for no bound
+	Flat        int64 // in minor units
+	BasisPoints int64 // of the transfer amount, 1 = 0.01%
+}
+
+// Component is one named fee. Its amount is Flat plus BasisPoints of the transfer amount or, when Tiers are
+// set, that of the first tier covering the transfer amount; a non-zero result is then kept within Min and
+// Max.
+type Component struct {
+	Name        string
+	Flat        int64 // in minor units
+	BasisPoints int64 // of the transfer amount, 1 = 0.01%
+	Tiers       []Tier
+	Min         int64 // zero for no minimum
+	Max         int64 // zero for no maximum
+}
+
+// Rule prices the transfers matching TxType, AccountType and Currency. The account type is that of the
+// account paying the fee.
+type Rule struct {
+	TxType      string
+	AccountType string // empty matches every account type
+	Currency    string // empty matches every currency
+	Components  []Component
+}
+
+// Schedule holds the fee rules. The first matching rule applies, so more specific rules go first.
+type Schedule struct {
+	Rules []Rule
+}
+
+// Compute returns the fees due on a transfer of amount, leaving out components that come to zero. A nil
+// schedule charges nothing.
+func (s *Schedule) Compute(txType string, accountType string, currency string, amount int64) []Charge {
+	if s == nil {
+		return nil
+	}
+	for _, rule := range s.Rules {
+		if !rule.matches(txType, accountType, currency) {
+			continue
+		}
+		var charges []Charge
+		for _, component := range rule.Components {
+			if due := component.amount(amount); due > 0 {
+				charges = append(charges, Charge{Name: component.Name, Amount: due})
+			}
+		}
+		return charges
+	}
+	return nil
+}
+
+func (r Rule) matches(txType string, accountType string, currency string) bool {
+	return r.TxType == txType &&
+		(r.AccountType == "" || r.AccountType == accountType) &&
+		(r.Currency == "" || r.Currency == currency)
+}
+
+func (c Component) amount(transferAmount int64) int64 {
+	flat, basisPoints := c.Flat, c.BasisPoints
+	for _, tier := range c.Tiers {
+		if tier.UpTo == 0 || transferAmount <= tier.UpTo {
+			flat, basisPoints = tier.Flat, tier.BasisPoints
+			break
+		}
+	}
+	// percentages are rounded half up to the minor unit
+	due := flat + (transferAmount*basisPoints+5000)/10000
+	if due == 0 {
+		// a free tier stays free regardless of Min
+		return 0
+	}
+	if c.Min > 0 && due < c.Min {
+		due = c.Min
+	}
+	if c.Max > 0 && due > c.Max {
+		due = c.Max
+	}
+	return due
+}
+
+// Total returns the sum of charges
+func Total(charges []Charge) int64 {
+	var total int64
+	for _, charge := range charges {
+		total += charge.Amount
+	}
+	return total
+}
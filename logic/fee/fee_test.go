@@ -0,0 +1,107 @@
+package fee
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchedule_Compute(t *testing.T) {
+	schedule := &Schedule{Rules: []Rule{
+		{
+			TxType:      "WITHDRAWAL",
+			AccountType: "CASA",
+		},
+		{
+			TxType:     "WITHDRAWAL",
+			Currency:   "MYR",
+			Components: []Component{{Name: "WITHDRAWAL_FEE", Flat: 100}},
+		},
+		{
+			TxType:   "TRANSFER",
+			Currency: "MYR",
+			Components: []Component{
+				{
+					Name: "TRANSFER_FEE",
+					Tiers: []Tier{
+						{UpTo: 500000},
+						{BasisPoints: 10},
+					},
+					Min: 100,
+					Max: 1000,
+				},
+				{Name: "PROCESSING_FEE", Flat: 20, BasisPoints: 5},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name        string
+		txType      string
+		accountType string
+		currency    string
+		amount      int64
+		want        []Charge
+	}{
+		{
+			name:        "flat fee",
+			txType:      "WITHDRAWAL",
+			accountType: "WALLET",
+			currency:    "MYR",
+			amount:      5000,
+			want:        []Charge{{Name: "WITHDRAWAL_FEE", Amount: 100}},
+		},
+		{
+			name:        "earlier rule without components waives the fee",
+			txType:      "WITHDRAWAL",
+			accountType: "CASA",
+			currency:    "MYR",
+			amount:      5000,
+		},
+		{
+			name:        "free tier leaves the component out",
+			txType:      "TRANSFER",
+			accountType: "WALLET",
+			currency:    "MYR",
+			amount:      500000,
+			want:        []Charge{{Name: "PROCESSING_FEE", Amount: 270}},
+		},
+		{
+			name:        "percentage raised to the minimum",
+			txType:      "TRANSFER",
+			accountType: "WALLET",
+			currency:    "MYR",
+			amount:      600000,
+			want:        []Charge{{Name: "TRANSFER_FEE", Amount: 600}, {Name: "PROCESSING_FEE", Amount: 320}},
+		},
+		{
+			name:        "percentage capped at the maximum",
+			txType:      "TRANSFER",
+			accountType: "WALLET",
+			currency:    "MYR",
+			amount:      2000000,
+			want:        []Charge{{Name: "TRANSFER_FEE", Amount: 1000}, {Name: "PROCESSING_FEE", Amount: 1020}},
+		},
+		{
+			name:        "percentage rounded half up",
+			txType:      "TRANSFER",
+			accountType: "WALLET",
+			currency:    "MYR",
+			amount:      1010,
+			want:        []Charge{{Name: "PROCESSING_FEE", Amount: 21}},
+		},
+		{
+			name:        "no matching rule",
+			txType:      "TRANSFER",
+			accountType: "WALLET",
+			currency:    "SGD",
+			amount:      600000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schedule.Compute(tt.txType, tt.accountType, tt.currency, tt.amount); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Compute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
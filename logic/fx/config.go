@@ -0,0 +1,28 @@
+package fx
+
+import "time"
+
+type Config struct {
+	// QuoteTTL is how long a quoted rate stays valid for a transfer
+	QuoteTTL time.Duration
+	// RateSource provides the rates quotes are priced at
+	RateSource RateSource
+	// RatesFile, if set, replaces RateSource with rates loaded from this JSON file at startup
+	RatesFile string
+}
+
+// DefaultConfig quotes from a fixed set of demo rates
+func DefaultConfig() *Config {
+	rates, _ := NewStaticRateSource(map[string]string{
+		"USD/MYR": "4.7125",
+		"SGD/MYR": "3.4950",
+		"USD/SGD": "1.3480",
+		"USD/JPY": "149.50",
+		"EUR/USD": "1.0850",
+		"EUR/MYR": "5.1130",
+	})
+	return &Config{
+		QuoteTTL:   time.Minute,
+		RateSource: rates,
+	}
+}
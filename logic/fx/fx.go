@@ -0,0 +1,138 @@
+package fx
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"math/big"
+	"time"
+	"wallet/apperr"
+	"wallet/currency"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+// rateScale is the number of decimal places a quoted rate is rounded to. Amounts are converted at the
+// rounded rate, so the rate shown to the client is exactly the one applied.
+const rateScale = 8
+
+var (
+	RateUnavailableErr = apperr.New(apperr.CodeFxRateUnavailable)
+	InvalidAmountErr   = apperr.New(apperr.CodeInvalidAmount)
+	InvalidCurrencyErr = apperr.New(apperr.CodeInvalidCurrency)
+)
+
+type CreateQuoteOpts struct {
+	// ClientID is the client the quote is issued to. Only that client can use it.
+	ClientID string
+}
+
+type IFxLogic interface {
+	CreateQuote(ctx context.Context, req *dto.CreateFxQuoteRequest, opts *CreateQuoteOpts) (*dto.FxQuoteResponse, error)
+}
+
+type logicImpl struct {
+	FxQuoteDAO storage.IFxQuoteDAO
+
+	rateSource RateSource
+	quoteTTL   time.Duration
+}
+
+func NewFxLogic(qd storage.IFxQuoteDAO, cfg *Config) IFxLogic {
+	return &logicImpl{
+		FxQuoteDAO: qd,
+		rateSource: cfg.RateSource,
+		quoteTTL:   cfg.QuoteTTL,
+	}
+}
+
+// CreateQuote locks the current rate between two currencies for the fixed side of req. With a source
+// amount the destination amount is rounded down, with a destination amount the source amount is rounded
+// up, so a conversion never pays out more than the rate allows.
+func (l *logicImpl) CreateQuote(ctx context.Context, req *dto.CreateFxQuoteRequest, opts *CreateQuoteOpts) (*dto.FxQuoteResponse, error) {
+	if opts == nil {
+		opts = &CreateQuoteOpts{}
+	}
+	source, sourceOk := currency.Lookup(req.SourceCurrency)
+	destination, destinationOk := currency.Lookup(req.DestinationCurrency)
+	if !sourceOk || !destinationOk || source.Code == destination.Code {
+		return nil, InvalidCurrencyErr
+	}
+	if (req.SourceAmount > 0) == (req.DestinationAmount > 0) {
+		return nil, InvalidAmountErr
+	}
+
+	rate, err := l.rateSource.Rate(ctx, source.Code, destination.Code)
+	if err != nil {
+		return nil, err
+	}
+	rateText := rate.FloatString(rateScale)
+	rate.SetString(rateText)
+
+	sourceAmount, destinationAmount := req.SourceAmount, req.DestinationAmount
+	if sourceAmount > 0 {
+		destinationAmount = convert(sourceAmount, rate, source.Exponent, destination.Exponent, false)
+	} else {
+		inverse := new(big.Rat).Inv(rate)
+		sourceAmount = convert(destinationAmount, inverse, destination.Exponent, source.Exponent, true)
+	}
+	if sourceAmount <= 0 || destinationAmount <= 0 {
+		return nil, InvalidAmountErr
+	}
+
+	now := time.Now()
+	quote := &storage.FxQuote{
+		QuoteID:             uuid.New().String(),
+		ClientID:            opts.ClientID,
+		SourceCurrency:      source.Code,
+		DestinationCurrency: destination.Code,
+		Rate:                rateText,
+		SourceAmount:        sourceAmount,
+		DestinationAmount:   destinationAmount,
+		ExpiresAt:           now.Add(l.quoteTTL),
+		CreatedAt:           now,
+	}
+	if err = l.FxQuoteDAO.Create(ctx, quote); err != nil {
+		return nil, err
+	}
+	return mapQuoteStorageToResponse(quote), nil
+}
+
+// convert applies rate to amount, given in minor units of a currency with fromExponent digits, and returns
+// the result in minor units of a currency with toExponent digits, rounded up or down.
+func convert(amount int64, rate *big.Rat, fromExponent int, toExponent int, roundUp bool) int64 {
+	result := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(toExponent-fromExponent))), nil))
+	if toExponent >= fromExponent {
+		result.Mul(result, scale)
+	} else {
+		result.Quo(result, scale)
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(result.Num(), result.Denom(), new(big.Int))
+	if roundUp && remainder.Sign() > 0 {
+		quotient.Add(quotient, big.NewInt(1))
+	}
+	if !quotient.IsInt64() {
+		return 0
+	}
+	return quotient.Int64()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func mapQuoteStorageToResponse(quote *storage.FxQuote) *dto.FxQuoteResponse {
+	return &dto.FxQuoteResponse{
+		QuoteID:             quote.QuoteID,
+		SourceCurrency:      quote.SourceCurrency,
+		DestinationCurrency: quote.DestinationCurrency,
+		Rate:                quote.Rate,
+		SourceAmount:        quote.SourceAmount,
+		DestinationAmount:   quote.DestinationAmount,
+		ExpiresAt:           quote.ExpiresAt,
+	}
+}
@@ -0,0 +1,107 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_logicImpl_CreateQuote(t *testing.T) {
+	rates, _ := NewStaticRateSource(map[string]string{
+		"USD/MYR": "4.7125",
+		"USD/JPY": "149.5",
+		"KWD/MYR": "15.3333",
+	})
+
+	tests := []struct {
+		name                  string
+		req                   *dto.CreateFxQuoteRequest
+		wantRate              string
+		wantSourceAmount      int64
+		wantDestinationAmount int64
+		wantErr               error
+	}{
+		{
+			name:                  "source amount fixed, destination rounded down",
+			req:                   &dto.CreateFxQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "MYR", SourceAmount: 1001},
+			wantRate:              "4.71250000",
+			wantSourceAmount:      1001,
+			wantDestinationAmount: 4717, // 47.1716
+		},
+		{
+			name:                  "destination amount fixed, source rounded up",
+			req:                   &dto.CreateFxQuoteRequest{SourceCurrency: "MYR", DestinationCurrency: "USD", DestinationAmount: 1000},
+			wantRate:              "0.21220159",
+			wantSourceAmount:      4713, // 47.12500016
+			wantDestinationAmount: 1000,
+		},
+		{
+			name:                  "into a currency without minor units",
+			req:                   &dto.CreateFxQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "JPY", SourceAmount: 1099},
+			wantRate:              "149.50000000",
+			wantSourceAmount:      1099,
+			wantDestinationAmount: 1643, // 1643.005
+		},
+		{
+			name:                  "from a currency with three minor digits",
+			req:                   &dto.CreateFxQuoteRequest{SourceCurrency: "KWD", DestinationCurrency: "MYR", SourceAmount: 1001},
+			wantRate:              "15.33330000",
+			wantSourceAmount:      1001,
+			wantDestinationAmount: 1534, // 15.3486333
+		},
+		{
+			name:    "error - both amounts set",
+			req:     &dto.CreateFxQuoteRequest{SourceCurrency: "USD", DestinationCurrency: "MYR", SourceAmount: 100, DestinationAmount: 470},
+			wantErr: InvalidAmountErr,
+		},
+		{
+			name:    "error - converts to nothing",
+			req:     &dto.CreateFxQuoteRequest{SourceCurrency: "MYR", DestinationCurrency: "USD", SourceAmount: 1},
+			wantErr: InvalidAmountErr,
+		},
+		{
+			name:    "error - same currency",
+			req:     &dto.CreateFxQuoteRequest{SourceCurrency: "MYR", DestinationCurrency: "MYR", SourceAmount: 100},
+			wantErr: InvalidCurrencyErr,
+		},
+		{
+			name:    "error - no rate for the pair",
+			req:     &dto.CreateFxQuoteRequest{SourceCurrency: "SGD", DestinationCurrency: "JPY", SourceAmount: 100},
+			wantErr: RateUnavailableErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qd := storagemock.NewMockIFxQuoteDAO(t)
+			if tt.wantErr == nil {
+				qd.EXPECT().Create(mock.Anything, mock.AnythingOfType("*storage.FxQuote")).Return(nil).Once()
+			}
+			l := NewFxLogic(qd, &Config{QuoteTTL: time.Minute, RateSource: rates})
+
+			got, err := l.CreateQuote(context.Background(), tt.req, &CreateQuoteOpts{ClientID: "client-a"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CreateQuote() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Rate != tt.wantRate || got.SourceAmount != tt.wantSourceAmount || got.DestinationAmount != tt.wantDestinationAmount {
+				t.Errorf("CreateQuote() = %s %d -> %d, want %s %d -> %d",
+					got.Rate, got.SourceAmount, got.DestinationAmount, tt.wantRate, tt.wantSourceAmount, tt.wantDestinationAmount)
+			}
+			if got.QuoteID == "" || time.Until(got.ExpiresAt) <= 0 {
+				t.Errorf("CreateQuote() quote ID %q expires at %v, want an unexpired quote", got.QuoteID, got.ExpiresAt)
+			}
+			quote := qd.Calls[0].Arguments.Get(1).(*storage.FxQuote)
+			if quote.ClientID != "client-a" {
+				t.Errorf("stored quote client = %q, want client-a", quote.ClientID)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package fx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+)
+
+// RateSource provides exchange rates. A rate is the number of destination currency units one source
+// currency unit buys, in major units, e.g. 4.7125 for USD to MYR.
+type RateSource interface {
+	Rate(ctx context.Context, sourceCurrency string, destinationCurrency string) (*big.Rat, error)
+}
+
+// staticRateSource serves a fixed set of rates, suited to local development and tests. Rates are keyed by
+// pair, e.g. "USD/MYR"; the inverse of every listed pair is derived unless it is listed too.
+type staticRateSource struct {
+	rates map[string]*big.Rat
+}
+
+// NewStaticRateSource returns a RateSource serving rates, keyed "SOURCE/DESTINATION" with decimal string
+// values such as "4.7125"
+func NewStaticRateSource(rates map[string]string) (RateSource, error) {
+	parsed := map[string]*big.Rat{}
+	for pair, value := range rates {
+		from, to, ok := strings.Cut(pair, "/")
+		if !ok || len(from) != 3 || len(to) != 3 {
+			return nil, fmt.Errorf("invalid currency pair %q", pair)
+		}
+		rate, ok := new(big.Rat).SetString(value)
+		if !ok || rate.Sign() <= 0 {
+			return nil, fmt.Errorf("invalid rate %q for %s", value, pair)
+		}
+		parsed[pair] = rate
+	}
+	for pair, rate := range parsed {
+		from, to, _ := strings.Cut(pair, "/")
+		if _, listed := parsed[to+"/"+from]; !listed {
+			parsed[to+"/"+from] = new(big.Rat).Inv(rate)
+		}
+	}
+	return &staticRateSource{rates: parsed}, nil
+}
+
+// NewFileRateSource returns a static RateSource loaded from a JSON file holding an object of pair to rate,
+// e.g. {"USD/MYR": "4.7125"}
+func NewFileRateSource(path string) (RateSource, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rates map[string]string
+	if err = json.Unmarshal(content, &rates); err != nil {
+		return nil, fmt.Errorf("rates file %s: %w", path, err)
+	}
+	return NewStaticRateSource(rates)
+}
+
+func (s *staticRateSource) Rate(_ context.Context, sourceCurrency string, destinationCurrency string) (*big.Rat, error) {
+	rate, ok := s.rates[sourceCurrency+"/"+destinationCurrency]
+	if !ok {
+		return nil, RateUnavailableErr
+	}
+	return new(big.Rat).Set(rate), nil
+}
@@ -0,0 +1,61 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestStaticRateSource_Rate(t *testing.T) {
+	source, err := NewStaticRateSource(map[string]string{"USD/MYR": "4.0000", "MYR/SGD": "0.2900", "SGD/MYR": "3.5000"})
+	if err != nil {
+		t.Fatalf("NewStaticRateSource() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		want    string
+		wantErr error
+	}{
+		{name: "listed pair", from: "USD", to: "MYR", want: "4.00000000"},
+		{name: "inverse is derived", from: "MYR", to: "USD", want: "0.25000000"},
+		{name: "listed inverse is not overridden", from: "MYR", to: "SGD", want: "0.29000000"},
+		{name: "unknown pair", from: "USD", to: "JPY", wantErr: RateUnavailableErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := source.Rate(context.Background(), tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Rate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.FloatString(8) != tt.want {
+				t.Errorf("Rate() = %s, want %s", got.FloatString(8), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewStaticRateSource_invalid(t *testing.T) {
+	for _, rates := range []map[string]string{
+		{"USDMYR": "4.7"},
+		{"USD/MYR": "abc"},
+		{"USD/MYR": "0"},
+	} {
+		if _, err := NewStaticRateSource(rates); err == nil {
+			t.Errorf("NewStaticRateSource(%v) error = nil, want error", rates)
+		}
+	}
+}
+
+func TestNewFileRateSource(t *testing.T) {
+	source, err := NewFileRateSource("testdata/rates.json")
+	if err != nil {
+		t.Fatalf("NewFileRateSource() error = %v", err)
+	}
+	got, err := source.Rate(context.Background(), "MYR", "JPY")
+	if err != nil || got.FloatString(2) != "31.72" {
+		t.Errorf("Rate(MYR, JPY) = %v, %v, want 31.72", got, err)
+	}
+}
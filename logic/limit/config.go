@@ -0,0 +1,101 @@
+package limit
+
+import (
+	"time"
+	"wallet/storage"
+)
+
+// Velocity caps the number of transfers within a rolling window
+type Velocity struct {
+	Count  int64
+	Window time.Duration
+}
+
+// TxLimit holds the limits on one tx type. Zero values mean no limit.
+type TxLimit struct {
+	TxType        string
+	MinAmount     int64 // per transfer, in minor units
+	MaxAmount     int64 // per transfer, in minor units
+	DailyAmount   int64 // cumulative per calendar day, in minor units
+	MonthlyAmount int64 // cumulative per calendar month, in minor units
+	Velocity      []Velocity
+}
+
+// Policy holds the limits of the accounts matching AccountType, Tier and Currency
+type Policy struct {
+	AccountType string
+	Tier        string // empty matches every tier
+	Currency    string // empty matches every currency
+	MaxBalance  int64  // in minor units, zero for no maximum
+	TxLimits    []TxLimit
+}
+
+type Config struct {
+	// Policies are matched in order and the first match applies, so more specific policies go first.
+	// Accounts without a matching policy are not limited.
+	Policies []Policy
+	// Location sets where calendar days and months start. Nil uses the server's local time zone.
+	Location *time.Location
+}
+
+// DefaultConfig limits MYR wallets along the lines of e-money regulation: basic wallets may hold up to
+// RM 5,000 and premium (fully verified) wallets up to RM 20,000.
+func DefaultConfig() *Config {
+	return &Config{Policies: []Policy{
+		{
+			AccountType: storage.AccountTypeWallet,
+			Tier:        "PREMIUM",
+			Currency:    "MYR",
+			MaxBalance:  2000000,
+			TxLimits: []TxLimit{
+				{TxType: "DEPOSIT", MinAmount: 100, MaxAmount: 2000000},
+				{TxType: "WITHDRAWAL", MinAmount: 1000, MaxAmount: 1000000, DailyAmount: 1000000, MonthlyAmount: 5000000},
+				{TxType: "TRANSFER", MaxAmount: 1000000, DailyAmount: 2000000, MonthlyAmount: 10000000, Velocity: []Velocity{{Count: 20, Window: time.Minute}}},
+			},
+		},
+		{
+			AccountType: storage.AccountTypeWallet,
+			Currency:    "MYR",
+			MaxBalance:  500000,
+			TxLimits: []TxLimit{
+				{TxType: "DEPOSIT", MinAmount: 100, MaxAmount: 500000},
+				{TxType: "WITHDRAWAL", MinAmount: 1000, MaxAmount: 300000, DailyAmount: 500000, MonthlyAmount: 1500000, Velocity: []Velocity{{Count: 5, Window: time.Minute}}},
+				{TxType: "TRANSFER", MaxAmount: 300000, DailyAmount: 500000, MonthlyAmount: 3000000, Velocity: []Velocity{{Count: 10, Window: time.Minute}, {Count: 100, Window: 24 * time.Hour}}},
+			},
+		},
+	}}
+}
+
+// PolicyFor returns the policy acc falls under, or nil if it is not limited
+func (c *Config) PolicyFor(acc *storage.Account) *Policy {
+	if c == nil {
+		return nil
+	}
+	for i := range c.Policies {
+		p := &c.Policies[i]
+		if p.AccountType == acc.Type && (p.Tier == "" || p.Tier == acc.Tier) && (p.Currency == "" || p.Currency == acc.Currency) {
+			return p
+		}
+	}
+	return nil
+}
+
+// TxLimitFor returns the limits on txType, or nil if txType is not limited
+func (p *Policy) TxLimitFor(txType string) *TxLimit {
+	if p == nil {
+		return nil
+	}
+	for i := range p.TxLimits {
+		if p.TxLimits[i].TxType == txType {
+			return &p.TxLimits[i]
+		}
+	}
+	return nil
+}
+
+func (c *Config) location() *time.Location {
+	if c == nil || c.Location == nil {
+		return time.Local
+	}
+	return c.Location
+}
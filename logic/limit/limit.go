@@ -0,0 +1,192 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+// depositTxType is counted against the account a transfer credits; every other tx type is counted
+// against the account it debits
+const depositTxType = "DEPOSIT"
+
+var (
+	AmountBelowMinimumErr      = apperr.New(apperr.CodeAmountBelowMinimum)
+	AmountAboveMaximumErr      = apperr.New(apperr.CodeAmountAboveMaximum)
+	CumulativeLimitExceededErr = apperr.New(apperr.CodeCumulativeLimitExceeded)
+	VelocityLimitExceededErr   = apperr.New(apperr.CodeVelocityLimitExceeded)
+	BalanceLimitExceededErr    = apperr.New(apperr.CodeBalanceLimitExceeded)
+)
+
+type ILimitLogic interface {
+	CheckAmount(acc *storage.Account, txType string, amount int64) error
+	Enforce(ctx context.Context, tx *gorm.DB, txType string, payer *storage.Account, credited *storage.Account) error
+	GetLimits(ctx context.Context, accountID string) (*dto.AccountLimitsResponse, error)
+}
+
+type logicImpl struct {
+	AccountDAO  storage.IAccountDAO
+	TransferDAO storage.ITransferDAO
+
+	cfg *Config
+}
+
+func NewLimitLogic(ad storage.IAccountDAO, td storage.ITransferDAO, cfg *Config) ILimitLogic {
+	return &logicImpl{
+		AccountDAO:  ad,
+		TransferDAO: td,
+		cfg:         cfg,
+	}
+}
+
+// CheckAmount checks amount against the per-transfer limits of acc, the account paying for the transfer
+func (l *logicImpl) CheckAmount(acc *storage.Account, txType string, amount int64) error {
+	txLimit := l.cfg.PolicyFor(acc).TxLimitFor(txType)
+	if txLimit == nil {
+		return nil
+	}
+	if txLimit.MinAmount > 0 && amount < txLimit.MinAmount {
+		return AmountBelowMinimumErr
+	}
+	if txLimit.MaxAmount > 0 && amount > txLimit.MaxAmount {
+		return AmountAboveMaximumErr
+	}
+	return nil
+}
+
+// Enforce checks the cumulative and velocity limits of payer and the maximum balance of credited within tx.
+// It must run after the transfer was posted and saved as completed in tx: the usage it reads then includes
+// the transfer itself, and the balance updates hold the account rows until tx ends, so concurrent transfers
+// of the same account are checked one after the other.
+func (l *logicImpl) Enforce(ctx context.Context, tx *gorm.DB, txType string, payer *storage.Account, credited *storage.Account) error {
+	if payer != nil {
+		if txLimit := l.cfg.PolicyFor(payer).TxLimitFor(txType); txLimit != nil {
+			if err := l.enforceTxLimit(ctx, l.TransferDAO.WithTx(tx), txLimit, payer.AccountID, time.Now()); err != nil {
+				return err
+			}
+		}
+	}
+	if credited != nil {
+		if policy := l.cfg.PolicyFor(credited); policy != nil && policy.MaxBalance > 0 {
+			acc, err := l.AccountDAO.WithTx(tx).FindByAccountID(ctx, credited.AccountID)
+			if err != nil {
+				return err
+			}
+			if acc.Balance > policy.MaxBalance {
+				return BalanceLimitExceededErr
+			}
+		}
+	}
+	return nil
+}
+
+func (l *logicImpl) enforceTxLimit(ctx context.Context, td storage.ITransferDAO, txLimit *TxLimit, accountID string, now time.Time) error {
+	daily, monthly, err := l.calendarUsage(ctx, td, txLimit, accountID, now)
+	if err != nil {
+		return err
+	}
+	if (txLimit.DailyAmount > 0 && daily.Amount > txLimit.DailyAmount) || (txLimit.MonthlyAmount > 0 && monthly.Amount > txLimit.MonthlyAmount) {
+		return CumulativeLimitExceededErr
+	}
+	for _, velocity := range txLimit.Velocity {
+		usage, usageErr := usageSince(ctx, td, txLimit.TxType, accountID, now.Add(-velocity.Window))
+		if usageErr != nil {
+			return usageErr
+		}
+		if usage.Count > velocity.Count {
+			return VelocityLimitExceededErr
+		}
+	}
+	return nil
+}
+
+// calendarUsage returns the usage of the current day and month, skipping the queries for limits not set
+func (l *logicImpl) calendarUsage(ctx context.Context, td storage.ITransferDAO, txLimit *TxLimit, accountID string, now time.Time) (daily *storage.TransferUsage, monthly *storage.TransferUsage, err error) {
+	local := now.In(l.cfg.location())
+	daily, monthly = &storage.TransferUsage{}, &storage.TransferUsage{}
+	if txLimit.DailyAmount > 0 {
+		startOfDay := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, local.Location())
+		if daily, err = usageSince(ctx, td, txLimit.TxType, accountID, startOfDay); err != nil {
+			return nil, nil, err
+		}
+	}
+	if txLimit.MonthlyAmount > 0 {
+		startOfMonth := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, local.Location())
+		if monthly, err = usageSince(ctx, td, txLimit.TxType, accountID, startOfMonth); err != nil {
+			return nil, nil, err
+		}
+	}
+	return daily, monthly, nil
+}
+
+func usageSince(ctx context.Context, td storage.ITransferDAO, txType string, accountID string, since time.Time) (*storage.TransferUsage, error) {
+	if txType == depositTxType {
+		return td.SumCompletedByDestinationAccount(ctx, accountID, txType, since)
+	}
+	return td.SumCompletedBySourceAccount(ctx, accountID, txType, since)
+}
+
+// GetLimits returns the limits of an account together with how much of each is used and left
+func (l *logicImpl) GetLimits(ctx context.Context, accountID string) (*dto.AccountLimitsResponse, error) {
+	acc, err := l.AccountDAO.FindByAccountID(ctx, accountID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.New(apperr.CodeAccountNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.AccountLimitsResponse{
+		AccountID: acc.AccountID,
+		Currency:  acc.Currency,
+		Tier:      acc.Tier,
+		Limits:    []*dto.TxLimitResponse{},
+	}
+	policy := l.cfg.PolicyFor(acc)
+	if policy == nil {
+		return res, nil
+	}
+	if policy.MaxBalance > 0 {
+		res.MaxBalance = newLimitUsage(policy.MaxBalance, acc.Balance)
+	}
+
+	now := time.Now()
+	for i := range policy.TxLimits {
+		txLimit := &policy.TxLimits[i]
+		daily, monthly, usageErr := l.calendarUsage(ctx, l.TransferDAO, txLimit, acc.AccountID, now)
+		if usageErr != nil {
+			return nil, usageErr
+		}
+		limitRes := &dto.TxLimitResponse{
+			TxType:    txLimit.TxType,
+			MinAmount: txLimit.MinAmount,
+			MaxAmount: txLimit.MaxAmount,
+		}
+		if txLimit.DailyAmount > 0 {
+			limitRes.Daily = newLimitUsage(txLimit.DailyAmount, daily.Amount)
+		}
+		if txLimit.MonthlyAmount > 0 {
+			limitRes.Monthly = newLimitUsage(txLimit.MonthlyAmount, monthly.Amount)
+		}
+		for _, velocity := range txLimit.Velocity {
+			usage, velocityErr := usageSince(ctx, l.TransferDAO, txLimit.TxType, acc.AccountID, now.Add(-velocity.Window))
+			if velocityErr != nil {
+				return nil, velocityErr
+			}
+			limitRes.Velocity = append(limitRes.Velocity, &dto.VelocityUsageResponse{
+				Window:             velocity.Window.String(),
+				LimitUsageResponse: *newLimitUsage(velocity.Count, usage.Count),
+			})
+		}
+		res.Limits = append(res.Limits, limitRes)
+	}
+	return res, nil
+}
+
+func newLimitUsage(limit int64, used int64) *dto.LimitUsageResponse {
+	return &dto.LimitUsageResponse{Limit: limit, Used: used, Remaining: max(limit-used, 0)}
+}
@@ -0,0 +1,173 @@
+package limit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testConfig = &Config{Policies: []Policy{
+	{
+		AccountType: storage.AccountTypeWallet,
+		Tier:        "PREMIUM",
+		TxLimits:    []TxLimit{{TxType: "TRANSFER", MaxAmount: 100000}},
+	},
+	{
+		AccountType: storage.AccountTypeWallet,
+		Currency:    "MYR",
+		MaxBalance:  500000,
+		TxLimits: []TxLimit{
+			{TxType: "DEPOSIT", MinAmount: 100, DailyAmount: 200000},
+			{TxType: "TRANSFER", MaxAmount: 50000, DailyAmount: 80000, MonthlyAmount: 300000, Velocity: []Velocity{{Count: 3, Window: time.Minute}}},
+		},
+	},
+}}
+
+func TestLogicImpl_CheckAmount(t *testing.T) {
+	basic := &storage.Account{Type: storage.AccountTypeWallet, Currency: "MYR"}
+	premium := &storage.Account{Type: storage.AccountTypeWallet, Currency: "MYR", Tier: "PREMIUM"}
+
+	tests := []struct {
+		name    string
+		acc     *storage.Account
+		txType  string
+		amount  int64
+		wantErr error
+	}{
+		{name: "within limits", acc: basic, txType: "TRANSFER", amount: 50000},
+		{name: "above maximum", acc: basic, txType: "TRANSFER", amount: 50001, wantErr: AmountAboveMaximumErr},
+		{name: "below minimum", acc: basic, txType: "DEPOSIT", amount: 99, wantErr: AmountBelowMinimumErr},
+		{name: "tier policy applies before the basic one", acc: premium, txType: "TRANSFER", amount: 100000},
+		{name: "tx type without limits", acc: basic, txType: "WITHDRAWAL", amount: 99999999},
+		{name: "account type without policy", acc: &storage.Account{Type: storage.AccountTypeCASA, Currency: "MYR"}, txType: "TRANSFER", amount: 99999999},
+		{name: "currency without policy", acc: &storage.Account{Type: storage.AccountTypeWallet, Currency: "SGD"}, txType: "TRANSFER", amount: 99999999},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimitLogic(nil, nil, testConfig)
+			if err := l.CheckAmount(tt.acc, tt.txType, tt.amount); !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("CheckAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogicImpl_Enforce(t *testing.T) {
+	wallet := &storage.Account{AccountID: "wallet", Type: storage.AccountTypeWallet, Currency: "MYR"}
+	holding := &storage.Account{AccountID: "holding", Type: storage.AccountTypeHolding, Currency: "MYR"}
+
+	tests := []struct {
+		name       string
+		txType     string
+		payer      *storage.Account
+		credited   *storage.Account
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO)
+		wantErr    error
+	}{
+		{
+			name:     "happy path - transfer within every limit",
+			txType:   "TRANSFER",
+			payer:    wallet,
+			credited: holding,
+			setupMocks: func(_ *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				td.EXPECT().SumCompletedBySourceAccount(mock.Anything, "wallet", "TRANSFER", mock.Anything).Return(&storage.TransferUsage{Count: 3, Amount: 80000}, nil).Times(3)
+			},
+		},
+		{
+			name:   "error - daily amount exceeded",
+			txType: "TRANSFER",
+			payer:  wallet,
+			setupMocks: func(_ *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				td.EXPECT().SumCompletedBySourceAccount(mock.Anything, "wallet", "TRANSFER", mock.Anything).Return(&storage.TransferUsage{Count: 2, Amount: 80001}, nil).Twice()
+			},
+			wantErr: CumulativeLimitExceededErr,
+		},
+		{
+			name:   "error - too many transfers in the window",
+			txType: "TRANSFER",
+			payer:  wallet,
+			setupMocks: func(_ *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				td.EXPECT().SumCompletedBySourceAccount(mock.Anything, "wallet", "TRANSFER", mock.Anything).Return(&storage.TransferUsage{Count: 4, Amount: 4000}, nil).Times(3)
+			},
+			wantErr: VelocityLimitExceededErr,
+		},
+		{
+			name:     "error - deposit takes the wallet above its maximum balance",
+			txType:   "DEPOSIT",
+			payer:    wallet,
+			credited: wallet,
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				// deposits are counted against the account they credit
+				td.EXPECT().SumCompletedByDestinationAccount(mock.Anything, "wallet", "DEPOSIT", mock.Anything).Return(&storage.TransferUsage{Count: 1, Amount: 1000}, nil).Once()
+				ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "wallet").Return(&storage.Account{AccountID: "wallet", Balance: 500001}, nil).Once()
+			},
+			wantErr: BalanceLimitExceededErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			td.EXPECT().WithTx(mock.Anything).Return(td).Maybe()
+			tt.setupMocks(ad, td)
+
+			l := NewLimitLogic(ad, td, testConfig)
+			err := l.Enforce(context.Background(), nil, tt.txType, tt.payer, tt.credited)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("Enforce() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLogicImpl_GetLimits(t *testing.T) {
+	ad := storagemock.NewMockIAccountDAO(t)
+	td := storagemock.NewMockITransferDAO(t)
+	ad.EXPECT().FindByAccountID(mock.Anything, "wallet").Return(&storage.Account{AccountID: "wallet", Type: storage.AccountTypeWallet, Currency: "MYR", Balance: 120000}, nil).Once()
+	ad.EXPECT().FindByAccountID(mock.Anything, "unknown").Return(nil, gorm.ErrRecordNotFound).Once()
+	td.EXPECT().SumCompletedByDestinationAccount(mock.Anything, "wallet", "DEPOSIT", mock.Anything).Return(&storage.TransferUsage{Count: 1, Amount: 250000}, nil).Once()
+	td.EXPECT().SumCompletedBySourceAccount(mock.Anything, "wallet", "TRANSFER", mock.Anything).Return(&storage.TransferUsage{Count: 2, Amount: 30000}, nil).Times(3)
+
+	l := NewLimitLogic(ad, td, testConfig)
+	got, err := l.GetLimits(context.Background(), "wallet")
+	if err != nil {
+		t.Fatalf("GetLimits() error = %v", err)
+	}
+	want := &dto.AccountLimitsResponse{
+		AccountID:  "wallet",
+		Currency:   "MYR",
+		MaxBalance: &dto.LimitUsageResponse{Limit: 500000, Used: 120000, Remaining: 380000},
+		Limits: []*dto.TxLimitResponse{
+			{
+				TxType:    "DEPOSIT",
+				MinAmount: 100,
+				Daily:     &dto.LimitUsageResponse{Limit: 200000, Used: 250000, Remaining: 0},
+			},
+			{
+				TxType:    "TRANSFER",
+				MaxAmount: 50000,
+				Daily:     &dto.LimitUsageResponse{Limit: 80000, Used: 30000, Remaining: 50000},
+				Monthly:   &dto.LimitUsageResponse{Limit: 300000, Used: 30000, Remaining: 270000},
+				Velocity: []*dto.VelocityUsageResponse{
+					{Window: "1m0s", LimitUsageResponse: dto.LimitUsageResponse{Limit: 3, Used: 2, Remaining: 1}},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetLimits() = %+v, want %+v", got, want)
+	}
+
+	if _, err = l.GetLimits(context.Background(), "unknown"); err == nil {
+		t.Errorf("GetLimits() of an unknown account error = nil, want error")
+	}
+}
@@ -0,0 +1,211 @@
+package reconcile
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"wallet/apperr"
+	"wallet/storage"
+)
+
+const (
+	TxTypeAdjustment = "ADJUSTMENT"
+	defaultBatchSize = 500
+)
+
+var (
+	MissingReasonErr   = apperr.New(apperr.CodeMissingReason)
+	SuspenseAccountErr = errors.New("suspense account not found")
+)
+
+// Drift is an account whose stored balance disagrees with the sum of its ledger legs.
+type Drift struct {
+	AccountID     string `json:"accountID"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	LedgerBalance int64  `json:"ledgerBalance"`
+	Difference    int64  `json:"difference"`             // Balance - LedgerBalance, in minor unit
+	CorrectionID  string `json:"correctionID,omitempty"` // transaction ID of the correction journal, if one was written
+}
+
+type Report struct {
+	CheckedAccounts int       `json:"checkedAccounts"`
+	Drifts          []*Drift  `json:"drifts"`
+	Corrected       bool      `json:"corrected"`
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+}
+
+type Options struct {
+	// Fix writes a correction journal for every drift found, bringing the ledger in line with the
+	// stored balance and parking the difference in the suspense account.
+	Fix bool
+	// Reason is recorded on every correction journal for audit. Required when Fix is set.
+	Reason    string
+	BatchSize int
+}
+
+type IReconcileLogic interface {
+	Reconcile(context.Context, *Options) (*Report, error)
+}
+
+type logicImpl struct {
+	AccountDAO     storage.IAccountDAO
+	TransactionDAO storage.ITransactionDAO
+	TransferDAO    storage.ITransferDAO
+
+	suspenseAccountID string
+}
+
+func NewReconcileLogic(
+	ad storage.IAccountDAO,
+	txd storage.ITransactionDAO,
+	td storage.ITransferDAO) IReconcileLogic {
+	return &logicImpl{
+		AccountDAO:        ad,
+		TransactionDAO:    txd,
+		TransferDAO:       td,
+		suspenseAccountID: "9000000001",
+	}
+}
+
+// Reconcile recomputes every account balance from its ledger legs and reports the accounts that drifted.
+func (l *logicImpl) Reconcile(ctx context.Context, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if opts.Fix && opts.Reason == "" {
+		return nil, MissingReasonErr
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	report := &Report{StartedAt: time.Now(), Drifts: []*Drift{}}
+	var afterID uint64
+	for {
+		balances, err := l.TransactionDAO.SumLedgerByAccount(ctx, afterID, batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range balances {
+			report.CheckedAccounts++
+			if b.Balance != b.LedgerBalance {
+				report.Drifts = append(report.Drifts, &Drift{
+					AccountID:     b.AccountID,
+					Currency:      b.Currency,
+					Balance:       b.Balance,
+					LedgerBalance: b.LedgerBalance,
+					Difference:    b.Balance - b.LedgerBalance,
+				})
+			}
+		}
+		if len(balances) < batchSize {
+			break
+		}
+		afterID = balances[len(balances)-1].ID
+	}
+
+	if opts.Fix {
+		for _, d := range report.Drifts {
+			// the suspense account cannot be corrected against itself, it is left for manual review
+			if d.AccountID == l.suspenseAccountID {
+				continue
+			}
+			correctionID, err := l.writeCorrection(ctx, d, opts.Reason)
+			if err != nil {
+				return report, fmt.Errorf("correction for account %s failed: %w", d.AccountID, err)
+			}
+			d.CorrectionID = correctionID
+		}
+		report.Corrected = true
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// writeCorrection books the missing ledger movement of a drifted account against the suspense account.
+// Only the suspense balance moves: the drifted account already holds the balance the legs now explain.
+func (l *logicImpl) writeCorrection(ctx context.Context, d *Drift, reason string) (string, error) {
+	suspense, err := l.AccountDAO.FindByAccountID(ctx, l.suspenseAccountID)
+	if err != nil {
+		return "", SuspenseAccountErr
+	}
+
+	now := time.Now()
+	transactionID := uuid.New().String()
+	amount := d.Difference
+	accountLegType, suspenseLegType := storage.TransactionTypeCredit, storage.TransactionTypeDebit
+	sourceID, destinationID := suspense.AccountID, d.AccountID
+	if amount < 0 {
+		amount = -amount
+		accountLegType, suspenseLegType = storage.TransactionTypeDebit, storage.TransactionTypeCredit
+		sourceID, destinationID = d.AccountID, suspense.AccountID
+	}
+
+	properties, _ := json.Marshal(map[string]interface{}{
+		"reason":        reason,
+		"balance":       d.Balance,
+		"ledgerBalance": d.LedgerBalance,
+	})
+	correction := &storage.Transfer{
+		Type:                 "INTRA",
+		TxType:               TxTypeAdjustment,
+		TransactionID:        transactionID,
+		ReferenceID:          transactionID,
+		Status:               "COMPLETED",
+		Amount:               amount,
+		Currency:             d.Currency,
+		SourceAccountID:      sourceID,
+		DestinationAccountID: destinationID,
+		Note:                 reason,
+		Properties:           properties,
+		CreatedAt:            now,
+		ValuedAt:             &now,
+		UpdatedAt:            now,
+	}
+	legs := []*storage.Transaction{
+		correctionLeg(correction, d.AccountID, accountLegType),
+		correctionLeg(correction, suspense.AccountID, suspenseLegType),
+	}
+	suspenseDelta := amount
+	if suspenseLegType == storage.TransactionTypeDebit {
+		suspenseDelta = -amount
+	}
+
+	txErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		if journalErr := l.TransactionDAO.WithTx(tx).CreateJournal(ctx, legs); journalErr != nil {
+			return journalErr
+		}
+		if updateErr := l.AccountDAO.WithTx(tx).UpdateBalance(ctx, suspense, suspenseDelta); updateErr != nil {
+			return fmt.Errorf("suspense account update failed: %w", updateErr)
+		}
+		return l.TransferDAO.WithTx(tx).Create(ctx, correction)
+	})
+	if txErr != nil {
+		return "", txErr
+	}
+	return transactionID, nil
+}
+
+func correctionLeg(correction *storage.Transfer, accountID string, entryType string) *storage.Transaction {
+	return &storage.Transaction{
+		JournalID:  correction.TransactionID,
+		AccountID:  accountID,
+		Type:       entryType,
+		Amount:     correction.Amount,
+		Currency:   correction.Currency,
+		Note:       fmt.Sprintf("Reconciliation correction: %s", correction.Note),
+		Timestamp:  correction.CreatedAt,
+		ValuedAt:   correction.CreatedAt,
+		CreatedAt:  correction.CreatedAt,
+		UpdatedAt:  correction.CreatedAt,
+		Properties: correction.Properties,
+	}
+}
@@ -0,0 +1,141 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_logicImpl_Reconcile(t *testing.T) {
+	type fields struct {
+		AccountDAO     storage.IAccountDAO
+		TransactionDAO storage.ITransactionDAO
+		TransferDAO    storage.ITransferDAO
+	}
+	tests := []struct {
+		name       string
+		fields     fields
+		opts       *Options
+		wantDrifts []*Drift
+		wantCount  int
+		wantErr    error
+	}{
+		{
+			name: "happy path - all accounts reconcile",
+			fields: fields{
+				TransactionDAO: func() storage.ITransactionDAO {
+					mc := &storagemock.MockITransactionDAO{}
+					mc.On("SumLedgerByAccount", context.Background(), uint64(0), 500).Return([]*storage.AccountLedgerBalance{
+						{ID: 1, AccountID: "1000000001", Currency: "MYR", Balance: 10000, LedgerBalance: 10000},
+						{ID: 2, AccountID: "12345678", Currency: "MYR", Balance: 1000, LedgerBalance: 1000},
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			wantDrifts: []*Drift{},
+			wantCount:  2,
+		},
+		{
+			name: "happy path - drift reported across batches",
+			fields: fields{
+				TransactionDAO: func() storage.ITransactionDAO {
+					mc := &storagemock.MockITransactionDAO{}
+					mc.On("SumLedgerByAccount", context.Background(), uint64(0), 2).Return([]*storage.AccountLedgerBalance{
+						{ID: 1, AccountID: "1000000001", Currency: "MYR", Balance: 10000, LedgerBalance: 10000},
+						{ID: 2, AccountID: "12345678", Currency: "MYR", Balance: 1500, LedgerBalance: 1000},
+					}, nil).Once()
+					mc.On("SumLedgerByAccount", context.Background(), uint64(2), 2).Return([]*storage.AccountLedgerBalance{
+						{ID: 3, AccountID: "87654321", Currency: "MYR", Balance: 700, LedgerBalance: 1000},
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			opts: &Options{BatchSize: 2},
+			wantDrifts: []*Drift{
+				{AccountID: "12345678", Currency: "MYR", Balance: 1500, LedgerBalance: 1000, Difference: 500},
+				{AccountID: "87654321", Currency: "MYR", Balance: 700, LedgerBalance: 1000, Difference: -300},
+			},
+			wantCount: 3,
+		},
+		{
+			name: "happy path - fix writes correction journal",
+			fields: fields{
+				TransactionDAO: func() storage.ITransactionDAO {
+					mc := &storagemock.MockITransactionDAO{}
+					mc.On("SumLedgerByAccount", context.Background(), uint64(0), 500).Return([]*storage.AccountLedgerBalance{
+						{ID: 2, AccountID: "12345678", Currency: "MYR", Balance: 1500, LedgerBalance: 1000},
+					}, nil).Once()
+					return mc
+				}(),
+				AccountDAO: func() storage.IAccountDAO {
+					mc := &storagemock.MockIAccountDAO{}
+					mc.On("FindByAccountID", context.Background(), "9000000001").Return(&storage.Account{
+						AccountID: "9000000001",
+						Currency:  "MYR",
+					}, nil).Once()
+					return mc
+				}(),
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("RunInTransaction", mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+					return mc
+				}(),
+			},
+			opts: &Options{Fix: true, Reason: "balance update committed without legs"},
+			wantDrifts: []*Drift{
+				{AccountID: "12345678", Currency: "MYR", Balance: 1500, LedgerBalance: 1000, Difference: 500},
+			},
+			wantCount: 1,
+		},
+		{
+			name:    "error - fix without reason",
+			opts:    &Options{Fix: true},
+			wantErr: MissingReasonErr,
+		},
+		{
+			name: "error - SumLedgerByAccount returns error",
+			fields: fields{
+				TransactionDAO: func() storage.ITransactionDAO {
+					mc := &storagemock.MockITransactionDAO{}
+					mc.On("SumLedgerByAccount", context.Background(), uint64(0), 500).Return(nil, errors.New("database error")).Once()
+					return mc
+				}(),
+			},
+			wantErr: errors.New("database error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &logicImpl{
+				AccountDAO:        tt.fields.AccountDAO,
+				TransactionDAO:    tt.fields.TransactionDAO,
+				TransferDAO:       tt.fields.TransferDAO,
+				suspenseAccountID: "9000000001",
+			}
+			got, err := l.Reconcile(context.Background(), tt.opts)
+			if (err != nil) != (tt.wantErr != nil) || (err != nil && err.Error() != tt.wantErr.Error()) {
+				t.Fatalf("Reconcile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.CheckedAccounts != tt.wantCount {
+				t.Errorf("Reconcile() checked = %v, want %v", got.CheckedAccounts, tt.wantCount)
+			}
+			for _, d := range got.Drifts {
+				if tt.opts != nil && tt.opts.Fix && d.CorrectionID == "" {
+					t.Errorf("Reconcile() drift %s has no correction", d.AccountID)
+				}
+				d.CorrectionID = ""
+			}
+			if !reflect.DeepEqual(got.Drifts, tt.wantDrifts) {
+				t.Errorf("Reconcile() drifts = %v, want %v", got.Drifts, tt.wantDrifts)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package standing
+
+import "time"
+
+type Config struct {
+	// Location sets the time zone occurrences are computed in, so a monthly instruction keeps paying on the
+	// same local day and time. Nil uses the server's local time zone.
+	Location *time.Location
+	// RetryDelay is how long a failed occurrence waits before it is retried, under the RETRY policy
+	RetryDelay time.Duration
+	// Lease is how long an instance has to run an occurrence it claimed. An instruction claimed by an
+	// instance that crashed is picked up again once its lease has passed.
+	Lease time.Duration
+	// RunInterval is how often due instructions are run. Zero disables the runner.
+	RunInterval time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		RetryDelay:  time.Hour,
+		Lease:       5 * time.Minute,
+		RunInterval: time.Minute,
+	}
+}
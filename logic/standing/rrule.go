@@ -0,0 +1,144 @@
+package standing
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequencies supported in the FREQ part of a rule
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// maxOccurrenceScan bounds how many periods Next looks at, so a rule that can never match again does not
+// loop forever
+const maxOccurrenceScan = 100000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a recurrence rule, parsed from the subset of RFC 5545 RRULE made of FREQ (DAILY, WEEKLY or
+// MONTHLY), INTERVAL, BYDAY (weekly rules only, e.g. MO,TH) and BYMONTHDAY (monthly rules only, 1 to 31 or
+// -1 for the last day of the month), e.g. FREQ=MONTHLY;BYMONTHDAY=1. Occurrences happen at the time of
+// day of the start they are computed from.
+type Rule struct {
+	Freq       string
+	Interval   int
+	ByDay      []time.Weekday // empty falls on the weekday of the start
+	ByMonthDay int            // zero falls on the day of month of the start
+}
+
+// ParseRule parses an RRULE such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR. A leading "RRULE:" is accepted.
+func ParseRule(s string) (*Rule, error) {
+	r := &Rule{Interval: 1}
+	for _, part := range strings.Split(strings.TrimPrefix(strings.TrimSpace(s), "RRULE:"), ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid interval %q", value)
+			}
+			r.Interval = interval
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := weekdays[strings.ToUpper(day)]
+				if !ok {
+					return nil, fmt.Errorf("invalid day %q", day)
+				}
+				r.ByDay = append(r.ByDay, weekday)
+			}
+		case "BYMONTHDAY":
+			day, err := strconv.Atoi(value)
+			if err != nil || day == 0 || day < -1 || day > 31 {
+				return nil, fmt.Errorf("invalid month day %q", value)
+			}
+			r.ByMonthDay = day
+		default:
+			return nil, fmt.Errorf("unsupported rule part %q", name)
+		}
+	}
+	switch {
+	case r.Freq != FreqDaily && r.Freq != FreqWeekly && r.Freq != FreqMonthly:
+		return nil, fmt.Errorf("unsupported frequency %q", r.Freq)
+	case len(r.ByDay) > 0 && r.Freq != FreqWeekly:
+		return nil, errors.New("BYDAY is only supported on weekly rules")
+	case r.ByMonthDay != 0 && r.Freq != FreqMonthly:
+		return nil, errors.New("BYMONTHDAY is only supported on monthly rules")
+	}
+	// weekly occurrences are generated in weekday order, starting the week on Monday
+	sort.Slice(r.ByDay, func(i, j int) bool { return mondayOffset(r.ByDay[i]) < mondayOffset(r.ByDay[j]) })
+	return r, nil
+}
+
+// Next returns the first occurrence of the rule started at start that is after after, or the zero time if
+// there is none. The first occurrence is never before start.
+func (r *Rule) Next(start time.Time, after time.Time) time.Time {
+	for period := 0; period < maxOccurrenceScan; period += r.Interval {
+		for _, occurrence := range r.occurrencesIn(start, period) {
+			if !occurrence.Before(start) && occurrence.After(after) {
+				return occurrence
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// occurrencesIn returns the occurrences within the period-th day, week or month after the one of start, in
+// order
+func (r *Rule) occurrencesIn(start time.Time, period int) []time.Time {
+	y, m, d := start.Date()
+	hh, mm, ss := start.Clock()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, hh, mm, ss, start.Nanosecond(), start.Location())
+	}
+	switch r.Freq {
+	case FreqDaily:
+		return []time.Time{at(y, m, d+period)}
+	case FreqWeekly:
+		monday := d - mondayOffset(start.Weekday()) + 7*period
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(y, m, monday+mondayOffset(start.Weekday()))}
+		}
+		occurrences := make([]time.Time, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			occurrences = append(occurrences, at(y, m, monday+mondayOffset(weekday)))
+		}
+		return occurrences
+	default:
+		// time.Date normalises the month, and day 0 of the next month is the last day of this one
+		first := time.Date(y, m+time.Month(period), 1, 0, 0, 0, 0, start.Location())
+		last := time.Date(first.Year(), first.Month()+1, 0, 0, 0, 0, 0, start.Location()).Day()
+		day := r.ByMonthDay
+		if day == 0 {
+			day = d
+		}
+		// months too short for the day, and BYMONTHDAY=-1, fall on the last day of the month
+		if day == -1 || day > last {
+			day = last
+		}
+		return []time.Time{at(first.Year(), first.Month(), day)}
+	}
+}
+
+func mondayOffset(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
@@ -0,0 +1,111 @@
+package standing
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRule_invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"FREQ=YEARLY",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-2",
+		"FREQ=DAILY;BYDAY=MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=DAILY;COUNT=3",
+		"FREQ",
+	} {
+		if _, err := ParseRule(s); err == nil {
+			t.Errorf("ParseRule(%q) error = nil, want an error", s)
+		}
+	}
+}
+
+func TestRule_Next(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+	// a Wednesday
+	start := date(2025, time.January, 1)
+
+	tests := []struct {
+		name  string
+		rule  string
+		after time.Time
+		want  []time.Time
+	}{
+		{
+			name:  "daily starts on the start date",
+			rule:  "FREQ=DAILY",
+			after: start.Add(-time.Hour),
+			want:  []time.Time{date(2025, time.January, 1), date(2025, time.January, 2), date(2025, time.January, 3)},
+		},
+		{
+			name:  "every other day",
+			rule:  "RRULE:FREQ=DAILY;INTERVAL=2",
+			after: start,
+			want:  []time.Time{date(2025, time.January, 3), date(2025, time.January, 5)},
+		},
+		{
+			name:  "weekly on the start weekday",
+			rule:  "FREQ=WEEKLY",
+			after: start,
+			want:  []time.Time{date(2025, time.January, 8), date(2025, time.January, 15)},
+		},
+		{
+			name:  "weekly by day skips days before the start",
+			rule:  "FREQ=WEEKLY;BYDAY=FR,MO",
+			after: start.Add(-time.Hour),
+			want:  []time.Time{date(2025, time.January, 3), date(2025, time.January, 6), date(2025, time.January, 10)},
+		},
+		{
+			name:  "fortnightly by day",
+			rule:  "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO",
+			after: start,
+			want:  []time.Time{date(2025, time.January, 13), date(2025, time.January, 27)},
+		},
+		{
+			name:  "monthly on the first",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=1",
+			after: date(2025, time.March, 15),
+			want:  []time.Time{date(2025, time.April, 1), date(2025, time.May, 1)},
+		},
+		{
+			name:  "monthly on the last day",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=-1",
+			after: start,
+			want:  []time.Time{date(2025, time.January, 31), date(2025, time.February, 28), date(2025, time.March, 31)},
+		},
+		{
+			name:  "day 31 falls on the last day of shorter months",
+			rule:  "FREQ=MONTHLY;BYMONTHDAY=31",
+			after: date(2025, time.January, 31),
+			want:  []time.Time{date(2025, time.February, 28), date(2025, time.March, 31), date(2025, time.April, 30)},
+		},
+		{
+			name:  "quarterly across a year",
+			rule:  "FREQ=MONTHLY;INTERVAL=3",
+			after: date(2025, time.November, 1),
+			want:  []time.Time{date(2026, time.January, 1), date(2026, time.April, 1)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.rule)
+			if err != nil {
+				t.Fatalf("ParseRule() error = %v", err)
+			}
+			after := tt.after
+			for i, want := range tt.want {
+				got := rule.Next(start, after)
+				if !got.Equal(want) {
+					t.Fatalf("occurrence %d = %v, want %v", i, got, want)
+				}
+				after = got
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package standing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/transfer"
+	"wallet/storage"
+)
+
+// Failure policies, deciding what happens to an occurrence whose transfer fails
+const (
+	// FailureSkip gives up on the occurrence and waits for the next one
+	FailureSkip = "SKIP"
+	// FailureRetry retries the occurrence up to MaxRetries times, then gives up on it like FailureSkip
+	FailureRetry = "RETRY"
+	// FailureSuspend suspends the instruction until it is resumed
+	FailureSuspend = "SUSPEND"
+)
+
+const runBatchSize = 100
+
+// occurrenceNamespace derives the idempotency keys of the transfers paying occurrences
+var occurrenceNamespace = uuid.MustParse("5b0c7a5e-3f1d-4d7e-9a39-2f8e6c1b7d40")
+
+var (
+	InvalidScheduleErr          = apperr.New(apperr.CodeInvalidSchedule)
+	InstructionNotFoundErr      = apperr.New(apperr.CodeStandingInstructionNotFound)
+	InvalidInstructionStatusErr = apperr.New(apperr.CodeInvalidInstructionStatus)
+)
+
+type InstructionOpts struct {
+	// ClientID is the client acting on the instruction. Instructions are only visible to the client that
+	// created them, and their transfers are made on its behalf.
+	ClientID string
+	// UserID is the user creating the instruction. When set, the user must be allowed to debit the source
+	// account, when the instruction is created and on every occurrence.
+	UserID string
+}
+
+type IStandingLogic interface {
+	CreateInstruction(ctx context.Context, req *dto.CreateStandingInstructionRequest, opts *InstructionOpts) (*dto.StandingInstructionResponse, error)
+	GetInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error)
+	SuspendInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error)
+	ResumeInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error)
+	CancelInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error)
+	RunDueInstructions(ctx context.Context) (int64, error)
+}
+
+type logicImpl struct {
+	StandingInstructionDAO storage.IStandingInstructionDAO
+	AccountDAO             storage.IAccountDAO
+	TransferLogic          transfer.ITransferLogic
+
+	cfg *Config
+}
+
+func NewStandingLogic(sd storage.IStandingInstructionDAO, ad storage.IAccountDAO, tl transfer.ITransferLogic, cfg *Config) IStandingLogic {
+	return &logicImpl{
+		StandingInstructionDAO: sd,
+		AccountDAO:             ad,
+		TransferLogic:          tl,
+		cfg:                    cfg,
+	}
+}
+
+// CreateInstruction sets up a transfer repeating on the occurrences of req.Schedule. Occurrences before now
+// are skipped, so the first transfer is made on the first occurrence from now on.
+func (l *logicImpl) CreateInstruction(ctx context.Context, req *dto.CreateStandingInstructionRequest, opts *InstructionOpts) (*dto.StandingInstructionResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	if existing, _ := l.StandingInstructionDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+		return replayInstruction(existing, req)
+	}
+
+	rule, parseErr := ParseRule(req.Schedule)
+	if parseErr != nil {
+		return nil, InvalidScheduleErr.Wrap(parseErr)
+	}
+	if req.EndAt != nil && req.EndAt.Before(req.StartAt) {
+		return nil, InvalidScheduleErr
+	}
+	sourceAcc, findErr := l.AccountDAO.FindByAccountID(ctx, req.SourceAccountID)
+	if findErr != nil {
+		return nil, transfer.InvalidSourceAccountErr
+	}
+	destAcc, findErr := l.AccountDAO.FindByAccountID(ctx, req.DestinationAccountID)
+	if findErr != nil {
+		return nil, transfer.InvalidDestinationAccountErr
+	}
+	if sourceAcc.Currency != req.Currency || destAcc.Currency != req.Currency {
+		return nil, transfer.InvalidCurrencyErr
+	}
+	if opts.UserID != "" {
+		if accessErr := l.TransferLogic.CheckDebitAccess(ctx, opts.UserID, sourceAcc.AccountID); accessErr != nil {
+			return nil, accessErr
+		}
+	}
+
+	now := time.Now()
+	si := &storage.StandingInstruction{
+		InstructionID:        uuid.New().String(),
+		ClientID:             opts.ClientID,
+		ReferenceID:          req.IdempotencyKey,
+		UserID:               opts.UserID,
+		SourceAccountID:      sourceAcc.AccountID,
+		DestinationAccountID: destAcc.AccountID,
+		Currency:             req.Currency,
+		Amount:               req.Amount,
+		Note:                 req.Note,
+		Schedule:             req.Schedule,
+		StartAt:              req.StartAt,
+		EndAt:                req.EndAt,
+		MaxOccurrences:       req.MaxOccurrences,
+		FailurePolicy:        req.FailurePolicy,
+		MaxRetries:           req.MaxRetries,
+		Status:               storage.StandingInstructionStatusActive,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	l.scheduleNext(si, rule, now)
+	if si.Status != storage.StandingInstructionStatusActive {
+		return nil, InvalidScheduleErr
+	}
+	if createErr := l.StandingInstructionDAO.Create(ctx, si); createErr != nil {
+		// a concurrent request with the same key got there first
+		if existing, _ := l.StandingInstructionDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+			return replayInstruction(existing, req)
+		}
+		return nil, createErr
+	}
+	return mapInstructionStorageToResponse(si), nil
+}
+
+// replayInstruction answers a repeated create request with the instruction created by the first one
+func replayInstruction(existing *storage.StandingInstruction, req *dto.CreateStandingInstructionRequest) (*dto.StandingInstructionResponse, error) {
+	if existing.SourceAccountID != req.SourceAccountID || existing.DestinationAccountID != req.DestinationAccountID ||
+		existing.Currency != req.Currency || existing.Amount != req.Amount || existing.Note != req.Note ||
+		existing.Schedule != req.Schedule || !existing.StartAt.Equal(req.StartAt) ||
+		existing.FailurePolicy != req.FailurePolicy || existing.MaxRetries != req.MaxRetries ||
+		existing.MaxOccurrences != req.MaxOccurrences {
+		return nil, transfer.IdempotencyKeyReusedErr
+	}
+	return mapInstructionStorageToResponse(existing), nil
+}
+
+func (l *logicImpl) GetInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	si, err := l.StandingInstructionDAO.FindByInstructionID(ctx, instructionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (si != nil && si.ClientID != opts.ClientID) {
+		return nil, InstructionNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapInstructionStorageToResponse(si), nil
+}
+
+// SuspendInstruction stops an active instruction from running until it is resumed
+func (l *logicImpl) SuspendInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error) {
+	return l.updateInstruction(ctx, instructionID, opts, func(si *storage.StandingInstruction) error {
+		if si.Status != storage.StandingInstructionStatusActive {
+			return InvalidInstructionStatusErr
+		}
+		si.Status = storage.StandingInstructionStatusSuspended
+		return nil
+	})
+}
+
+// ResumeInstruction reactivates a suspended instruction. Occurrences missed while it was suspended are not
+// made up for: it carries on with the first occurrence from now on, retrying nothing.
+func (l *logicImpl) ResumeInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error) {
+	return l.updateInstruction(ctx, instructionID, opts, func(si *storage.StandingInstruction) error {
+		if si.Status != storage.StandingInstructionStatusSuspended {
+			return InvalidInstructionStatusErr
+		}
+		rule, err := ParseRule(si.Schedule)
+		if err != nil {
+			return err
+		}
+		si.Status = storage.StandingInstructionStatusActive
+		if si.Attempt > 0 {
+			// the occurrence being retried is given up on, like one that ran out of retries
+			si.Occurrence++
+			si.FailedCount++
+			si.Attempt = 0
+		}
+		l.scheduleNext(si, rule, time.Now())
+		return nil
+	})
+}
+
+// CancelInstruction stops an instruction for good. An occurrence already being run still completes.
+func (l *logicImpl) CancelInstruction(ctx context.Context, instructionID string, opts *InstructionOpts) (*dto.StandingInstructionResponse, error) {
+	return l.updateInstruction(ctx, instructionID, opts, func(si *storage.StandingInstruction) error {
+		if si.Status != storage.StandingInstructionStatusActive && si.Status != storage.StandingInstructionStatusSuspended {
+			return InvalidInstructionStatusErr
+		}
+		si.Status = storage.StandingInstructionStatusCancelled
+		si.NextOccurrenceAt = nil
+		si.NextRunAt = nil
+		return nil
+	})
+}
+
+// updateInstruction applies update to the instruction under lock, so it cannot race the runner recording
+// an occurrence
+func (l *logicImpl) updateInstruction(
+	ctx context.Context,
+	instructionID string,
+	opts *InstructionOpts,
+	update func(si *storage.StandingInstruction) error,
+) (*dto.StandingInstructionResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	var updated *storage.StandingInstruction
+	err := l.StandingInstructionDAO.RunInTransaction(func(tx *gorm.DB) error {
+		sd := l.StandingInstructionDAO.WithTx(tx)
+		si, findErr := sd.FindByInstructionIDForUpdate(ctx, instructionID)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) || (si != nil && si.ClientID != opts.ClientID) {
+			return InstructionNotFoundErr
+		}
+		if findErr != nil {
+			return findErr
+		}
+		if updateErr := update(si); updateErr != nil {
+			return updateErr
+		}
+		si.UpdatedAt = time.Now()
+		updated = si
+		return sd.Save(ctx, si)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapInstructionStorageToResponse(updated), nil
+}
+
+// RunDueInstructions runs the next occurrence of every instruction that is due and returns how many it ran,
+// whether they were paid or not. Instructions are claimed before they are run, so any number of instances
+// may run it at the same time. An instance that crashes half way through an occurrence leaves it to be
+// run again once its claim has expired: the transfer of an occurrence attempt always has the same
+// idempotency key, so the second run gets the outcome of the first instead of paying again.
+func (l *logicImpl) RunDueInstructions(ctx context.Context) (int64, error) {
+	var ran int64
+	var errs []error
+	for {
+		due, err := l.StandingInstructionDAO.ClaimDue(ctx, time.Now(), l.cfg.Lease, runBatchSize)
+		if err != nil {
+			return ran, errors.Join(append(errs, err)...)
+		}
+		for _, si := range due {
+			if runErr := l.runOccurrence(ctx, si); runErr != nil {
+				errs = append(errs, fmt.Errorf("standing instruction %s: %w", si.InstructionID, runErr))
+				continue
+			}
+			ran++
+		}
+		if len(due) < runBatchSize {
+			return ran, errors.Join(errs...)
+		}
+	}
+}
+
+// runOccurrence pays the next occurrence of si and records the outcome. A failed transfer is handled by the
+// instruction's failure policy: it was recorded as failed under its idempotency key, so running the same
+// attempt again would only replay the failure. Any other error leaves the transfer unfinished, and the
+// occurrence is run again with the same key once the claim expires, as it is when the outcome cannot be
+// recorded.
+func (l *logicImpl) runOccurrence(ctx context.Context, si *storage.StandingInstruction) error {
+	rule, err := ParseRule(si.Schedule)
+	if err != nil {
+		return err
+	}
+	occurrence, occurredAt := si.Occurrence+1, *si.NextOccurrenceAt
+	res, trfErr := l.TransferLogic.CreateTransfer(ctx, &dto.CreateTransferRequest{
+		Currency:           si.Currency,
+		Amount:             si.Amount,
+		SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: si.SourceAccountID},
+		DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: si.DestinationAccountID},
+		Properties: map[string]interface{}{
+			"standingInstructionID": si.InstructionID,
+			"occurrence":            occurrence,
+		},
+		Note:           si.Note,
+		IdempotencyKey: occurrenceKey(si.InstructionID, occurrence, si.Attempt),
+	}, &transfer.CreateTransferOpts{
+		TxType:   transfer.TxTypeP2PTransfer,
+		ClientID: si.ClientID,
+		UserID:   si.UserID,
+	})
+	if trfErr == nil && res.Status == transfer.TxStatusPROCESSING {
+		// another instance is still making the transfer, or left it to the transfer executor
+		return errors.New("occurrence transfer still processing")
+	}
+	if trfErr != nil && !transfer.IsOneOfTransferErrors(trfErr) {
+		return trfErr
+	}
+
+	return l.StandingInstructionDAO.RunInTransaction(func(tx *gorm.DB) error {
+		sd := l.StandingInstructionDAO.WithTx(tx)
+		// the instruction is read again, as it may have been suspended or cancelled while the transfer was made
+		locked, findErr := sd.FindByInstructionIDForUpdate(ctx, si.InstructionID)
+		if findErr != nil {
+			return findErr
+		}
+		if locked.Occurrence != si.Occurrence || locked.Attempt != si.Attempt {
+			// recorded by an instance whose claim expired in the meantime
+			return nil
+		}
+		now := time.Now()
+		status := locked.Status
+		if trfErr == nil {
+			locked.LastTransactionID = res.TransactionID
+			locked.LastError = ""
+			l.advance(locked, rule, occurredAt)
+		} else {
+			l.recordFailure(locked, rule, occurredAt, trfErr, now)
+		}
+		if status == storage.StandingInstructionStatusSuspended || status == storage.StandingInstructionStatusCancelled {
+			// the occurrence counts, but the instruction stays as it was put
+			locked.Status = status
+			if status == storage.StandingInstructionStatusCancelled {
+				locked.NextOccurrenceAt = nil
+				locked.NextRunAt = nil
+			}
+		}
+		locked.LockedUntil = nil
+		locked.UpdatedAt = now
+		return sd.Save(ctx, locked)
+	})
+}
+
+// recordFailure applies the failure policy of si to its occurrence of occurredAt failing with err
+func (l *logicImpl) recordFailure(si *storage.StandingInstruction, rule *Rule, occurredAt time.Time, err error, now time.Time) {
+	si.LastError = string(apperr.From(err).Code)
+	switch {
+	case si.FailurePolicy == FailureRetry && si.Attempt < si.MaxRetries:
+		si.Attempt++
+		retryAt := now.Add(l.cfg.RetryDelay)
+		si.NextRunAt = &retryAt
+	case si.FailurePolicy == FailureSuspend:
+		si.FailedCount++
+		l.advance(si, rule, occurredAt)
+		if si.Status == storage.StandingInstructionStatusActive {
+			si.Status = storage.StandingInstructionStatusSuspended
+		}
+	default:
+		si.FailedCount++
+		l.advance(si, rule, occurredAt)
+	}
+}
+
+// advance moves si past its occurrence of occurredAt
+func (l *logicImpl) advance(si *storage.StandingInstruction, rule *Rule, occurredAt time.Time) {
+	si.Occurrence++
+	si.Attempt = 0
+	l.scheduleNext(si, rule, occurredAt)
+}
+
+// scheduleNext sets the next occurrence of si to the first one after after, or completes si if it has none
+// left. Occurrences missed while the runner was down are not skipped: they are due straight away.
+func (l *logicImpl) scheduleNext(si *storage.StandingInstruction, rule *Rule, after time.Time) {
+	next := rule.Next(si.StartAt.In(l.location()), after)
+	if next.IsZero() || (si.EndAt != nil && next.After(*si.EndAt)) ||
+		(si.MaxOccurrences > 0 && si.Occurrence >= si.MaxOccurrences) {
+		si.Status = storage.StandingInstructionStatusCompleted
+		si.NextOccurrenceAt = nil
+		si.NextRunAt = nil
+		return
+	}
+	si.NextOccurrenceAt = &next
+	si.NextRunAt = &next
+}
+
+func (l *logicImpl) location() *time.Location {
+	if l.cfg.Location != nil {
+		return l.cfg.Location
+	}
+	return time.Local
+}
+
+// occurrenceKey returns the idempotency key of the transfer paying attempt attempt of occurrence occurrence
+func occurrenceKey(instructionID string, occurrence int, attempt int) string {
+	return uuid.NewSHA1(occurrenceNamespace, []byte(fmt.Sprintf("%s/%d/%d", instructionID, occurrence, attempt))).String()
+}
+
+func mapInstructionStorageToResponse(si *storage.StandingInstruction) *dto.StandingInstructionResponse {
+	return &dto.StandingInstructionResponse{
+		InstructionID:        si.InstructionID,
+		SourceAccountID:      si.SourceAccountID,
+		DestinationAccountID: si.DestinationAccountID,
+		Amount:               si.Amount,
+		Currency:             si.Currency,
+		Note:                 si.Note,
+		Schedule:             si.Schedule,
+		StartAt:              si.StartAt,
+		EndAt:                si.EndAt,
+		MaxOccurrences:       si.MaxOccurrences,
+		FailurePolicy:        si.FailurePolicy,
+		MaxRetries:           si.MaxRetries,
+		Status:               si.Status,
+		NextOccurrenceAt:     si.NextOccurrenceAt,
+		Occurrences:          si.Occurrence,
+		FailedOccurrences:    si.FailedCount,
+		LastTransactionID:    si.LastTransactionID,
+		LastError:            si.LastError,
+		CreatedAt:            si.CreatedAt,
+	}
+}
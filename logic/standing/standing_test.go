@@ -0,0 +1,398 @@
+package standing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/transfer"
+	transfermock "wallet/logic/transfer/mocks"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// inTransaction runs the next transaction of sd straight away on sd itself
+func inTransaction(sd *storagemock.MockIStandingInstructionDAO) {
+	sd.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn) error {
+		return fn(nil)
+	}).Once()
+	sd.EXPECT().WithTx(mock.Anything).Return(sd).Once()
+}
+
+func Test_logicImpl_CreateInstruction(t *testing.T) {
+	tomorrow := time.Now().Add(24 * time.Hour)
+	newReq := func() *dto.CreateStandingInstructionRequest {
+		return &dto.CreateStandingInstructionRequest{
+			IdempotencyKey:       "idempotency-key",
+			SourceAccountID:      "source-account",
+			DestinationAccountID: "destination-account",
+			Amount:               150000,
+			Currency:             "MYR",
+			Note:                 "Rent",
+			Schedule:             "FREQ=MONTHLY",
+			StartAt:              tomorrow,
+			FailurePolicy:        FailureSkip,
+		}
+	}
+	accounts := func(ad *storagemock.MockIAccountDAO) {
+		ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR"}, nil).Once()
+		ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "MYR"}, nil).Once()
+	}
+
+	tests := []struct {
+		name          string
+		req           *dto.CreateStandingInstructionRequest
+		userID        string
+		setupMocks    func(sd *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO)
+		setupTransfer func(tl *transfermock.MockITransferLogic)
+		wantErr       error
+	}{
+		{
+			name: "happy path - first occurrence on the start date",
+			req:  newReq(),
+			setupMocks: func(sd *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO) {
+				accounts(ad)
+				sd.EXPECT().Create(mock.Anything, mock.MatchedBy(func(si *storage.StandingInstruction) bool {
+					return si.Status == storage.StandingInstructionStatusActive && si.ClientID == "client-a" &&
+						si.NextOccurrenceAt.Equal(tomorrow) && si.NextRunAt.Equal(tomorrow)
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:   "happy path - made for the user who created it",
+			req:    newReq(),
+			userID: "user-1",
+			setupMocks: func(sd *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO) {
+				accounts(ad)
+				sd.EXPECT().Create(mock.Anything, mock.MatchedBy(func(si *storage.StandingInstruction) bool {
+					return si.UserID == "user-1"
+				})).Return(nil).Once()
+			},
+			setupTransfer: func(tl *transfermock.MockITransferLogic) {
+				tl.EXPECT().CheckDebitAccess(mock.Anything, "user-1", "source-account").Return(nil).Once()
+			},
+		},
+		{
+			name:   "error - user cannot debit the source account",
+			req:    newReq(),
+			userID: "user-1",
+			setupMocks: func(_ *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO) {
+				accounts(ad)
+			},
+			setupTransfer: func(tl *transfermock.MockITransferLogic) {
+				tl.EXPECT().CheckDebitAccess(mock.Anything, "user-1", "source-account").Return(transfer.AccountAccessDeniedErr).Once()
+			},
+			wantErr: transfer.AccountAccessDeniedErr,
+		},
+		{
+			name: "error - unsupported schedule",
+			req: func() *dto.CreateStandingInstructionRequest {
+				req := newReq()
+				req.Schedule = "FREQ=HOURLY"
+				return req
+			}(),
+			wantErr: InvalidScheduleErr,
+		},
+		{
+			name: "error - no occurrence before the end date",
+			req: func() *dto.CreateStandingInstructionRequest {
+				req := newReq()
+				req.Schedule = "FREQ=MONTHLY;BYMONTHDAY=1"
+				req.StartAt = time.Date(2030, time.January, 2, 0, 0, 0, 0, time.UTC)
+				endAt := time.Date(2030, time.January, 31, 0, 0, 0, 0, time.UTC)
+				req.EndAt = &endAt
+				return req
+			}(),
+			setupMocks: func(_ *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO) {
+				accounts(ad)
+			},
+			wantErr: InvalidScheduleErr,
+		},
+		{
+			name: "error - currency mismatch",
+			req: func() *dto.CreateStandingInstructionRequest {
+				req := newReq()
+				req.Currency = "SGD"
+				return req
+			}(),
+			setupMocks: func(_ *storagemock.MockIStandingInstructionDAO, ad *storagemock.MockIAccountDAO) {
+				accounts(ad)
+			},
+			wantErr: transfer.InvalidCurrencyErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd := storagemock.NewMockIStandingInstructionDAO(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			sd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+			tl := transfermock.NewMockITransferLogic(t)
+			if tt.setupMocks != nil {
+				tt.setupMocks(sd, ad)
+			}
+			if tt.setupTransfer != nil {
+				tt.setupTransfer(tl)
+			}
+
+			l := &logicImpl{StandingInstructionDAO: sd, AccountDAO: ad, TransferLogic: tl, cfg: &Config{}}
+			got, err := l.CreateInstruction(context.Background(), tt.req, &InstructionOpts{ClientID: "client-a", UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CreateInstruction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.InstructionID == "" || got.Status != storage.StandingInstructionStatusActive) {
+				t.Errorf("CreateInstruction() = %+v, want an active instruction", got)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_RunDueInstructions(t *testing.T) {
+	firstOfMonth := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(2025, time.April, 1, 9, 0, 0, 0, time.UTC)
+	newInstruction := func(policy string, occurrence int, attempt int) *storage.StandingInstruction {
+		return &storage.StandingInstruction{
+			InstructionID:        "instruction-1",
+			ClientID:             "client-a",
+			SourceAccountID:      "source-account",
+			DestinationAccountID: "destination-account",
+			Currency:             "MYR",
+			Amount:               150000,
+			Schedule:             "FREQ=MONTHLY;BYMONTHDAY=1",
+			StartAt:              time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC),
+			FailurePolicy:        policy,
+			MaxRetries:           2,
+			Status:               storage.StandingInstructionStatusActive,
+			NextOccurrenceAt:     &firstOfMonth,
+			NextRunAt:            &firstOfMonth,
+			Occurrence:           occurrence,
+			Attempt:              attempt,
+		}
+	}
+
+	tests := []struct {
+		name           string
+		si             *storage.StandingInstruction
+		transferRes    *dto.CreateTransferResponse
+		transferErr    error
+		wantRan        int64
+		wantErr        bool
+		wantSaved      bool
+		wantStatus     string
+		wantOccurrence int
+		wantAttempt    int
+		wantFailed     int
+		wantNextRun    func(got *time.Time) bool
+	}{
+		{
+			name:           "paid - moves on to the next occurrence",
+			si:             newInstruction(FailureSkip, 2, 0),
+			transferRes:    &dto.CreateTransferResponse{TransactionID: "tx-123", Status: transfer.TxStatusCOMPLETED},
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusActive,
+			wantOccurrence: 3,
+			wantNextRun:    func(got *time.Time) bool { return got != nil && got.Equal(nextMonth) },
+		},
+		{
+			name: "paid - last occurrence completes the instruction",
+			si: func() *storage.StandingInstruction {
+				si := newInstruction(FailureSkip, 2, 0)
+				si.MaxOccurrences = 3
+				return si
+			}(),
+			transferRes:    &dto.CreateTransferResponse{TransactionID: "tx-123", Status: transfer.TxStatusCOMPLETED},
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusCompleted,
+			wantOccurrence: 3,
+			wantNextRun:    func(got *time.Time) bool { return got == nil },
+		},
+		{
+			name:           "failed under SKIP - moves on to the next occurrence",
+			si:             newInstruction(FailureSkip, 2, 0),
+			transferErr:    transfer.InsufficientBalanceErr,
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusActive,
+			wantOccurrence: 3,
+			wantFailed:     1,
+			wantNextRun:    func(got *time.Time) bool { return got != nil && got.Equal(nextMonth) },
+		},
+		{
+			name:           "failed under RETRY - retried later",
+			si:             newInstruction(FailureRetry, 2, 1),
+			transferErr:    transfer.InsufficientBalanceErr,
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusActive,
+			wantOccurrence: 2,
+			wantAttempt:    2,
+			wantNextRun:    func(got *time.Time) bool { return got != nil && got.After(time.Now().Add(59*time.Minute)) },
+		},
+		{
+			name:           "failed under RETRY - out of retries",
+			si:             newInstruction(FailureRetry, 2, 2),
+			transferErr:    transfer.InsufficientBalanceErr,
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusActive,
+			wantOccurrence: 3,
+			wantFailed:     1,
+			wantNextRun:    func(got *time.Time) bool { return got != nil && got.Equal(nextMonth) },
+		},
+		{
+			name:           "failed under SUSPEND - suspended",
+			si:             newInstruction(FailureSuspend, 2, 0),
+			transferErr:    transfer.InsufficientBalanceErr,
+			wantRan:        1,
+			wantSaved:      true,
+			wantStatus:     storage.StandingInstructionStatusSuspended,
+			wantOccurrence: 3,
+			wantFailed:     1,
+			wantNextRun:    func(got *time.Time) bool { return got != nil },
+		},
+		{
+			name:        "unexpected failure - left for the next run with the same key",
+			si:          newInstruction(FailureSkip, 2, 0),
+			transferErr: errors.New("connection refused"),
+			wantErr:     true,
+		},
+		{
+			name:        "still processing - left for the next run",
+			si:          newInstruction(FailureSkip, 2, 0),
+			transferRes: &dto.CreateTransferResponse{TransactionID: "tx-123", Status: transfer.TxStatusPROCESSING},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sd := storagemock.NewMockIStandingInstructionDAO(t)
+			tl := transfermock.NewMockITransferLogic(t)
+			sd.EXPECT().ClaimDue(mock.Anything, mock.Anything, 5*time.Minute, runBatchSize).Return([]*storage.StandingInstruction{tt.si}, nil).Once()
+			tl.EXPECT().CreateTransfer(mock.Anything, mock.MatchedBy(func(req *dto.CreateTransferRequest) bool {
+				return req.IdempotencyKey == occurrenceKey("instruction-1", tt.si.Occurrence+1, tt.si.Attempt) &&
+					req.Amount == 150000 && req.SourceAccount.Number == "source-account"
+			}), &transfer.CreateTransferOpts{TxType: transfer.TxTypeP2PTransfer, ClientID: "client-a"}).Return(tt.transferRes, tt.transferErr).Once()
+
+			var saved *storage.StandingInstruction
+			if tt.wantSaved {
+				inTransaction(sd)
+				locked := *tt.si
+				sd.EXPECT().FindByInstructionIDForUpdate(mock.Anything, "instruction-1").Return(&locked, nil).Once()
+				sd.EXPECT().Save(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, si *storage.StandingInstruction) error {
+					saved = si
+					return nil
+				}).Once()
+			}
+
+			l := &logicImpl{StandingInstructionDAO: sd, TransferLogic: tl, cfg: &Config{Location: time.UTC, RetryDelay: time.Hour, Lease: 5 * time.Minute}}
+			ran, err := l.RunDueInstructions(context.Background())
+			if (err != nil) != tt.wantErr || ran != tt.wantRan {
+				t.Fatalf("RunDueInstructions() = %d, %v, want %d, wantErr %v", ran, err, tt.wantRan, tt.wantErr)
+			}
+			if !tt.wantSaved {
+				return
+			}
+			if saved.Status != tt.wantStatus || saved.Occurrence != tt.wantOccurrence || saved.Attempt != tt.wantAttempt ||
+				saved.FailedCount != tt.wantFailed || saved.LockedUntil != nil {
+				t.Errorf("saved %s occurrence %d attempt %d failed %d, want %s occurrence %d attempt %d failed %d",
+					saved.Status, saved.Occurrence, saved.Attempt, saved.FailedCount, tt.wantStatus, tt.wantOccurrence, tt.wantAttempt, tt.wantFailed)
+			}
+			if !tt.wantNextRun(saved.NextRunAt) {
+				t.Errorf("saved next run %v", saved.NextRunAt)
+			}
+			if tt.transferErr != nil && saved.LastError != string(apperr.From(tt.transferErr).Code) {
+				t.Errorf("saved last error %q, want %q", saved.LastError, apperr.From(tt.transferErr).Code)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_RunDueInstructions_cancelledWhileRunning(t *testing.T) {
+	due := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	si := &storage.StandingInstruction{
+		InstructionID:    "instruction-1",
+		ClientID:         "client-a",
+		Schedule:         "FREQ=MONTHLY",
+		StartAt:          time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC),
+		FailurePolicy:    FailureSkip,
+		Status:           storage.StandingInstructionStatusActive,
+		NextOccurrenceAt: &due,
+		NextRunAt:        &due,
+	}
+	sd := storagemock.NewMockIStandingInstructionDAO(t)
+	tl := transfermock.NewMockITransferLogic(t)
+	sd.EXPECT().ClaimDue(mock.Anything, mock.Anything, time.Minute, runBatchSize).Return([]*storage.StandingInstruction{si}, nil).Once()
+	tl.EXPECT().CreateTransfer(mock.Anything, mock.Anything, mock.Anything).Return(&dto.CreateTransferResponse{TransactionID: "tx-123", Status: transfer.TxStatusCOMPLETED}, nil).Once()
+	inTransaction(sd)
+	cancelled := *si
+	cancelled.Status = storage.StandingInstructionStatusCancelled
+	cancelled.NextOccurrenceAt, cancelled.NextRunAt = nil, nil
+	sd.EXPECT().FindByInstructionIDForUpdate(mock.Anything, "instruction-1").Return(&cancelled, nil).Once()
+	sd.EXPECT().Save(mock.Anything, mock.MatchedBy(func(saved *storage.StandingInstruction) bool {
+		// the transfer is recorded, but the instruction stays cancelled
+		return saved.Status == storage.StandingInstructionStatusCancelled && saved.Occurrence == 1 &&
+			saved.LastTransactionID == "tx-123" && saved.NextRunAt == nil
+	})).Return(nil).Once()
+
+	l := &logicImpl{StandingInstructionDAO: sd, TransferLogic: tl, cfg: &Config{Lease: time.Minute}}
+	if _, err := l.RunDueInstructions(context.Background()); err != nil {
+		t.Fatalf("RunDueInstructions() error = %v", err)
+	}
+}
+
+func Test_occurrenceKey(t *testing.T) {
+	key := occurrenceKey("instruction-1", 3, 0)
+	if occurrenceKey("instruction-1", 3, 0) != key {
+		t.Errorf("occurrenceKey() is not deterministic")
+	}
+	for _, other := range []string{
+		occurrenceKey("instruction-1", 4, 0),
+		occurrenceKey("instruction-1", 3, 1),
+		occurrenceKey("instruction-2", 3, 0),
+	} {
+		if other == key {
+			t.Errorf("occurrenceKey() = %s for different occurrences", key)
+		}
+	}
+}
+
+func Test_logicImpl_ResumeInstruction(t *testing.T) {
+	sd := storagemock.NewMockIStandingInstructionDAO(t)
+	inTransaction(sd)
+	due := time.Date(2025, time.March, 1, 9, 0, 0, 0, time.UTC)
+	sd.EXPECT().FindByInstructionIDForUpdate(mock.Anything, "instruction-1").Return(&storage.StandingInstruction{
+		InstructionID:    "instruction-1",
+		ClientID:         "client-a",
+		Schedule:         "FREQ=DAILY",
+		StartAt:          time.Date(2025, time.January, 1, 9, 0, 0, 0, time.UTC),
+		FailurePolicy:    FailureRetry,
+		Status:           storage.StandingInstructionStatusSuspended,
+		NextOccurrenceAt: &due,
+		NextRunAt:        &due,
+		Occurrence:       5,
+		Attempt:          1,
+	}, nil).Once()
+	sd.EXPECT().Save(mock.Anything, mock.MatchedBy(func(si *storage.StandingInstruction) bool {
+		// missed occurrences are not made up for, and the one being retried counts as failed
+		return si.Status == storage.StandingInstructionStatusActive && si.NextRunAt.After(time.Now()) &&
+			si.Occurrence == 6 && si.Attempt == 0 && si.FailedCount == 1
+	})).Return(nil).Once()
+
+	l := &logicImpl{StandingInstructionDAO: sd, cfg: &Config{Location: time.UTC}}
+	if _, err := l.ResumeInstruction(context.Background(), "instruction-1", &InstructionOpts{ClientID: "client-a"}); err != nil {
+		t.Fatalf("ResumeInstruction() error = %v", err)
+	}
+
+	// other clients cannot see the instruction
+	inTransaction(sd)
+	sd.EXPECT().FindByInstructionIDForUpdate(mock.Anything, "instruction-1").Return(&storage.StandingInstruction{ClientID: "client-a"}, nil).Once()
+	if _, err := l.CancelInstruction(context.Background(), "instruction-1", &InstructionOpts{ClientID: "client-b"}); !errors.Is(err, InstructionNotFoundErr) {
+		t.Errorf("CancelInstruction() error = %v, want %v", err, InstructionNotFoundErr)
+	}
+}
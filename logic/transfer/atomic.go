@@ -0,0 +1,115 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"wallet/dto"
+	"wallet/util"
+)
+
+// ItemError is the failure of one of the transfers made by CreateTransfersAtomically
+type ItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *ItemError) Error() string {
+	return fmt.Sprintf("transfer %d: %v", e.Index, e.Err)
+}
+
+func (e *ItemError) Unwrap() error {
+	return e.Err
+}
+
+// CreateTransfersAtomically makes the transfers of reqs, in order, in one database transaction: either all of
+// them complete or none does. Each transfer sees the balances left by the ones before it. If one fails,
+// nothing is kept, not even a failed transfer record, and an *ItemError with its index is returned.
+//
+// Idempotency keys are handled like CreateTransfer does. Since the transfers of a call commit together,
+// retrying a call that succeeded replays every one of them.
+func (l *logicImpl) CreateTransfersAtomically(ctx context.Context, reqs []*dto.CreateTransferRequest, opts *CreateTransferOpts) ([]*dto.CreateTransferResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	responses := make([]*dto.CreateTransferResponse, len(reqs))
+	err := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		bound := l.withTx(tx)
+		for i, req := range reqs {
+			res, createErr := bound.createInTransaction(ctx, req, opts)
+			if createErr != nil {
+				return &ItemError{Index: i, Err: createErr}
+			}
+			responses[i] = res
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return responses, nil
+}
+
+// createInTransaction makes one transfer of CreateTransfersAtomically. l is bound to the transaction of the
+// whole call, so the transactions of doTransfer become savepoints within it.
+func (l *logicImpl) createInTransaction(ctx context.Context, req *dto.CreateTransferRequest, opts *CreateTransferOpts) (*dto.CreateTransferResponse, error) {
+	fingerprint := requestFingerprint(req, opts)
+	existing, err := l.TransferDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey)
+	if existing != nil {
+		return replayTransfer(existing, fingerprint)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if req.ExecuteAt != nil {
+		return nil, InvalidExecutionDateErr
+	}
+
+	trf := mapCreateTransferRequestToTransfer(req, uuid.New().String(), opts)
+	trf.RequestHash = fingerprint
+	trf.ClaimedUntil = l.claimUntil()
+	if createErr := l.TransferDAO.Create(ctx, trf); createErr != nil {
+		return nil, createErr
+	}
+	if doErr := util.Retry(func() error {
+		return l.doTransfer(ctx, trf, opts)
+	}, l.maxRetries, l.retryDelay, PossibleErrors...); doErr != nil {
+		return nil, doErr
+	}
+	return mapTransferStorageToResponse(trf), nil
+}
+
+// withTx returns a copy of l whose DAOs are bound to tx, so everything it does is part of tx
+func (l *logicImpl) withTx(tx *gorm.DB) *logicImpl {
+	bound := &logicImpl{
+		TransferDAO:        l.TransferDAO.WithTx(tx),
+		AccountDAO:         l.AccountDAO.WithTx(tx),
+		TransactionDAO:     l.TransactionDAO.WithTx(tx),
+		holdingAccounts:    l.holdingAccounts,
+		fxPositionAccounts: l.fxPositionAccounts,
+		feeSchedule:        l.feeSchedule,
+		feeIncomeAccounts:  l.feeIncomeAccounts,
+		limits:             l.limits,
+		lockingStrategies:  l.lockingStrategies,
+		shardSelection:     l.shardSelection,
+		maxRetries:         l.maxRetries,
+		retryDelay:         l.retryDelay,
+		keyRetention:       l.keyRetention,
+		maxScheduleAhead:   l.maxScheduleAhead,
+		processingLease:    l.processingLease,
+		holdDefaultTTL:     l.holdDefaultTTL,
+		holdMaxTTL:         l.holdMaxTTL,
+	}
+	if l.FxQuoteDAO != nil {
+		bound.FxQuoteDAO = l.FxQuoteDAO.WithTx(tx)
+	}
+	if l.HoldDAO != nil {
+		bound.HoldDAO = l.HoldDAO.WithTx(tx)
+	}
+	if l.UserDAO != nil {
+		bound.UserDAO = l.UserDAO.WithTx(tx)
+	}
+	return bound
+}
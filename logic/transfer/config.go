@@ -0,0 +1,125 @@
+package transfer
+
+import (
+	"time"
+	"wallet/logic/fee"
+	"wallet/logic/limit"
+	"wallet/storage"
+)
+
+// LockingStrategy decides how concurrent balance updates on an account are serialised.
+type LockingStrategy string
+
+const (
+	// LockingOptimistic updates the balance only if the row's updated_at is unchanged since it was read,
+	// and retries the whole transfer on conflict. Cheap when accounts are rarely contended.
+	LockingOptimistic LockingStrategy = "OPTIMISTIC"
+	// LockingPessimistic locks the row with SELECT ... FOR UPDATE before updating it, so concurrent
+	// transfers queue up instead of failing. Suited to hot accounts such as the holding account.
+	LockingPessimistic LockingStrategy = "PESSIMISTIC"
+)
+
+type Config struct {
+	// HoldingAccountIDs is the holding account deposits and withdrawals go through, per currency.
+	// Deposits and withdrawals in a currency without a holding account are rejected.
+	HoldingAccountIDs map[string]string
+	// FxPositionAccountIDs is the FX position account per currency. A cross-currency transfer is credited
+	// to the position of its source currency and debited from the position of its destination currency.
+	FxPositionAccountIDs map[string]string
+	// FeeSchedule prices the fees charged on transfers. Nil charges nothing.
+	FeeSchedule *fee.Schedule
+	// FeeIncomeAccountIDs is the account fees are credited to, per currency
+	FeeIncomeAccountIDs map[string]string
+	// Limits holds the transaction limits and maximum balances of customer accounts. Nil limits nothing.
+	Limits *limit.Config
+	// LockingStrategies selects the locking strategy per account type. Types not listed are optimistic.
+	LockingStrategies map[string]LockingStrategy
+	// ShardSelection picks the shard of a sharded account, such as the holding account, per transfer.
+	ShardSelection ShardSelection
+	// ShardSweepInterval is how often shard balances are evened out. Zero disables the sweep.
+	ShardSweepInterval time.Duration
+	// MaxRetries is how many times a transfer is retried after a transient failure such as an
+	// optimistic locking conflict.
+	MaxRetries int
+	RetryDelay time.Duration
+	// IdempotencyKeyRetention is how long an idempotency key stays bound to its transfer. Once it has
+	// passed, the key is released and may be reused. Zero keeps keys forever.
+	IdempotencyKeyRetention time.Duration
+	// IdempotencyKeyPurgeInterval is how often expired idempotency keys are released.
+	IdempotencyKeyPurgeInterval time.Duration
+	// MaxScheduleAhead is how far in the future a transfer may be scheduled
+	MaxScheduleAhead time.Duration
+	// ScheduledExecutionInterval is how often due scheduled transfers, and transfers whose processing lease
+	// expired, are picked up. Zero disables the executor, e.g. on instances that should only serve requests.
+	ScheduledExecutionInterval time.Duration
+	// ProcessingLease is how long the instance executing a transfer holds it. A transfer still PROCESSING
+	// once its lease has expired, e.g. because the instance crashed, is executed again by the executor.
+	ProcessingLease time.Duration
+	// HoldDefaultTTL is how long a hold lasts when placed without an expiry
+	HoldDefaultTTL time.Duration
+	// HoldMaxTTL caps how long a hold may last from when it was placed, extensions included
+	HoldMaxTTL time.Duration
+	// HoldSweepInterval is how often expired holds are released. Zero disables the sweep.
+	HoldSweepInterval time.Duration
+}
+
+func DefaultConfig() *Config {
+	return &Config{
+		HoldingAccountIDs: map[string]string{
+			"MYR": "1000000001",
+			"SGD": "1000000002",
+			"USD": "1000000003",
+		},
+		FxPositionAccountIDs: map[string]string{
+			"MYR": "8000000001",
+			"SGD": "8000000002",
+			"USD": "8000000003",
+		},
+		FeeSchedule: &fee.Schedule{Rules: []fee.Rule{
+			{
+				TxType:      string(TxTypeWithdrawal),
+				AccountType: storage.AccountTypeWallet,
+				Currency:    "MYR",
+				Components:  []fee.Component{{Name: "WITHDRAWAL_FEE", Flat: 100}},
+			},
+			{
+				// transfers up to RM 5,000 are free, larger ones cost 0.1% between RM 1 and RM 10
+				TxType:      string(TxTypeP2PTransfer),
+				AccountType: storage.AccountTypeWallet,
+				Currency:    "MYR",
+				Components: []fee.Component{{
+					Name:  "TRANSFER_FEE",
+					Tiers: []fee.Tier{{UpTo: 500000}, {BasisPoints: 10}},
+					Min:   100,
+					Max:   1000,
+				}},
+			},
+		}},
+		FeeIncomeAccountIDs: map[string]string{
+			"MYR": "7000000001",
+			"SGD": "7000000002",
+			"USD": "7000000003",
+		},
+		Limits: limit.DefaultConfig(),
+		LockingStrategies: map[string]LockingStrategy{
+			storage.AccountTypeHolding:    LockingPessimistic,
+			storage.AccountTypeFxPosition: LockingPessimistic,
+			storage.AccountTypeFeeIncome:  LockingPessimistic,
+		},
+		ShardSelection:     ShardByHash,
+		ShardSweepInterval: 5 * time.Minute,
+		MaxRetries:         3,
+		RetryDelay:         100 * time.Millisecond,
+
+		IdempotencyKeyRetention:     30 * 24 * time.Hour,
+		IdempotencyKeyPurgeInterval: time.Hour,
+
+		MaxScheduleAhead:           365 * 24 * time.Hour,
+		ScheduledExecutionInterval: 10 * time.Second,
+		ProcessingLease:            5 * time.Minute,
+
+		HoldDefaultTTL:    7 * 24 * time.Hour,
+		HoldMaxTTL:        30 * 24 * time.Hour,
+		HoldSweepInterval: time.Minute,
+	}
+}
@@ -0,0 +1,83 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/fee"
+	"wallet/storage"
+)
+
+// chargeFees prices req against the fee schedule for the account type of payer and returns the legs moving
+// the fees from payer to the fee income account of the transfer currency. The breakdown is recorded on req.
+func (l *logicImpl) chargeFees(ctx context.Context, req *storage.Transfer, payer *storage.Account, accounts map[string]*storage.Account) ([]*storage.Transaction, error) {
+	charges := l.feeSchedule.Compute(req.TxType, payer.Type, req.Currency, req.Amount)
+	req.FeeAmount = fee.Total(charges)
+	req.Fees = nil
+	if len(charges) == 0 {
+		return nil, nil
+	}
+	incomeAccountID, ok := l.feeIncomeAccounts[req.Currency]
+	if !ok {
+		return nil, fmt.Errorf("no fee income account for %s", req.Currency)
+	}
+	income, findErr := l.resolvePostingAccount(ctx, incomeAccountID, req.TransactionID)
+	if findErr != nil {
+		return nil, fmt.Errorf("fee income account %s lookup failed: %w", incomeAccountID, findErr)
+	}
+	accounts[income.AccountID] = income
+
+	req.Fees, _ = json.Marshal(charges)
+	legs := make([]*storage.Transaction, 0, 2*len(charges))
+	for _, charge := range charges {
+		legs = append(legs,
+			newLeg(req, payer.AccountID, TypeDebit, charge.Amount, fmt.Sprintf("Fee %s", charge.Name)),
+			newLeg(req, income.AccountID, TypeCredit, charge.Amount, fmt.Sprintf("Fee %s from %s", charge.Name, payer.AccountID)),
+		)
+	}
+	return legs, nil
+}
+
+// PreviewFees returns the fees a transfer would be charged, without creating it
+func (l *logicImpl) PreviewFees(ctx context.Context, req *dto.PreviewFeesRequest) (*dto.PreviewFeesResponse, error) {
+	payer, err := l.AccountDAO.FindByAccountID(ctx, req.AccountID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, apperr.New(apperr.CodeAccountNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if payer.Currency != req.Currency {
+		return nil, InvalidCurrencyErr
+	}
+	charges := l.feeSchedule.Compute(req.TxType, payer.Type, req.Currency, req.Amount)
+	fees := make([]*dto.FeeResponse, 0, len(charges))
+	for _, charge := range charges {
+		fees = append(fees, &dto.FeeResponse{Name: charge.Name, Amount: charge.Amount})
+	}
+	return &dto.PreviewFeesResponse{
+		Currency: req.Currency,
+		Amount:   req.Amount,
+		Fees:     fees,
+		TotalFee: fee.Total(charges),
+	}, nil
+}
+
+func mapFeesStorageToResponse(raw json.RawMessage) []*dto.FeeResponse {
+	if len(raw) == 0 {
+		return nil
+	}
+	var charges []fee.Charge
+	if err := json.Unmarshal(raw, &charges); err != nil {
+		return nil
+	}
+	fees := make([]*dto.FeeResponse, 0, len(charges))
+	for _, charge := range charges {
+		fees = append(fees, &dto.FeeResponse{Name: charge.Name, Amount: charge.Amount})
+	}
+	return fees
+}
@@ -0,0 +1,149 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/logic/fee"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var testFeeSchedule = &fee.Schedule{Rules: []fee.Rule{{
+	TxType:      string(TxTypeWithdrawal),
+	AccountType: storage.AccountTypeWallet,
+	Components: []fee.Component{
+		{Name: "WITHDRAWAL_FEE", Flat: 100},
+		{Name: "PROCESSING_FEE", BasisPoints: 50},
+	},
+}}}
+
+func Test_logicImpl_chargeFees(t *testing.T) {
+	tests := []struct {
+		name              string
+		txType            TxType
+		feeIncomeAccounts map[string]string
+		setupMocks        func(ad *storagemock.MockIAccountDAO)
+		wantFee           int64
+		wantLegs          int
+		wantErr           bool
+	}{
+		{
+			name:              "happy path - one pair of legs per fee",
+			txType:            TxTypeWithdrawal,
+			feeIncomeAccounts: map[string]string{"MYR": "7000000001"},
+			setupMocks: func(ad *storagemock.MockIAccountDAO) {
+				ad.EXPECT().FindByParentAccountID(mock.Anything, "7000000001").Return(nil, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "7000000001").Return(&storage.Account{AccountID: "7000000001", Type: storage.AccountTypeFeeIncome}, nil).Once()
+			},
+			wantFee:  150,
+			wantLegs: 4,
+		},
+		{
+			name:       "happy path - no fee due",
+			txType:     TxTypeP2PTransfer,
+			setupMocks: func(*storagemock.MockIAccountDAO) {},
+		},
+		{
+			name:              "error - no fee income account for the currency",
+			txType:            TxTypeWithdrawal,
+			feeIncomeAccounts: map[string]string{"SGD": "7000000002"},
+			setupMocks:        func(*storagemock.MockIAccountDAO) {},
+			wantFee:           150,
+			wantErr:           true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			tt.setupMocks(ad)
+
+			l := &logicImpl{AccountDAO: ad, feeSchedule: testFeeSchedule, feeIncomeAccounts: tt.feeIncomeAccounts}
+			req := &storage.Transfer{TransactionID: "tx-123", TxType: string(tt.txType), Amount: 10000, Currency: "MYR"}
+			payer := &storage.Account{AccountID: "source-account", Type: storage.AccountTypeWallet}
+			accounts := map[string]*storage.Account{payer.AccountID: payer}
+
+			legs, err := l.chargeFees(context.Background(), req, payer, accounts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("chargeFees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if req.FeeAmount != tt.wantFee || len(legs) != tt.wantLegs {
+				t.Errorf("chargeFees() fee = %d with %d legs, want %d with %d legs", req.FeeAmount, len(legs), tt.wantFee, tt.wantLegs)
+			}
+			if len(legs) == 0 {
+				return
+			}
+			if deltas := netBalanceDeltas(legs); deltas["source-account"] != -tt.wantFee || deltas["7000000001"] != tt.wantFee {
+				t.Errorf("chargeFees() deltas = %v, want %d moved to the fee income account", deltas, tt.wantFee)
+			}
+			if got := mapFeesStorageToResponse(req.Fees); !reflect.DeepEqual(got, []*dto.FeeResponse{
+				{Name: "WITHDRAWAL_FEE", Amount: 100},
+				{Name: "PROCESSING_FEE", Amount: 50},
+			}) {
+				t.Errorf("recorded fees = %v", got)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_PreviewFees(t *testing.T) {
+	tests := []struct {
+		name       string
+		setupMocks func(ad *storagemock.MockIAccountDAO)
+		want       *dto.PreviewFeesResponse
+		wantErr    error
+	}{
+		{
+			name: "happy path",
+			setupMocks: func(ad *storagemock.MockIAccountDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Type: storage.AccountTypeWallet, Currency: "MYR"}, nil).Once()
+			},
+			want: &dto.PreviewFeesResponse{
+				Currency: "MYR",
+				Amount:   10000,
+				Fees:     []*dto.FeeResponse{{Name: "WITHDRAWAL_FEE", Amount: 100}, {Name: "PROCESSING_FEE", Amount: 50}},
+				TotalFee: 150,
+			},
+		},
+		{
+			name: "error - account not found",
+			setupMocks: func(ad *storagemock.MockIAccountDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: apperr.New(apperr.CodeAccountNotFound),
+		},
+		{
+			name: "error - account held in another currency",
+			setupMocks: func(ad *storagemock.MockIAccountDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "SGD"}, nil).Once()
+			},
+			wantErr: InvalidCurrencyErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			tt.setupMocks(ad)
+
+			l := &logicImpl{AccountDAO: ad, feeSchedule: testFeeSchedule}
+			got, err := l.PreviewFees(context.Background(), &dto.PreviewFeesRequest{
+				TxType:    string(TxTypeWithdrawal),
+				AccountID: "source-account",
+				Currency:  "MYR",
+				Amount:    10000,
+			})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("PreviewFees() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PreviewFees() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	"wallet/storage"
+)
+
+// findUsableQuote loads the FX quote req refers to and checks it can pay for req. A quote issued to
+// another client is reported as not found.
+func (l *logicImpl) findUsableQuote(ctx context.Context, req *storage.Transfer, opts *CreateTransferOpts) (*storage.FxQuote, error) {
+	quote, err := l.FxQuoteDAO.FindByQuoteID(ctx, req.FxQuoteID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (quote != nil && quote.ClientID != opts.ClientID) {
+		return nil, FxQuoteNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if quote.TransactionID != nil || !time.Now().Before(quote.ExpiresAt) {
+		return nil, FxQuoteExpiredErr
+	}
+	if quote.SourceCurrency != req.Currency || quote.SourceAmount != req.Amount {
+		return nil, FxQuoteMismatchErr
+	}
+	return quote, nil
+}
+
+// doFxTransfer books a cross-currency transfer at the rate locked by quote. The source amount moves into
+// the FX position of the source currency and the destination amount comes out of the FX position of
+// the destination currency, so each currency's legs balance on their own.
+func (l *logicImpl) doFxTransfer(ctx context.Context, req *storage.Transfer, quote *storage.FxQuote, sourceAcc *storage.Account, destAcc *storage.Account) error {
+	if sourceAcc.Currency != quote.SourceCurrency {
+		return InvalidCurrencyErr
+	}
+	if destAcc.Currency != quote.DestinationCurrency {
+		return FxQuoteMismatchErr
+	}
+	sourcePositionID, hasSource := l.fxPositionAccounts[quote.SourceCurrency]
+	destinationPositionID, hasDestination := l.fxPositionAccounts[quote.DestinationCurrency]
+	if !hasSource || !hasDestination {
+		return InvalidCurrencyErr
+	}
+	sourcePosition, findErr := l.resolvePostingAccount(ctx, sourcePositionID, req.TransactionID)
+	if findErr != nil {
+		return fmt.Errorf("fx position %s lookup failed: %w", sourcePositionID, findErr)
+	}
+	destinationPosition, findErr := l.resolvePostingAccount(ctx, destinationPositionID, req.TransactionID)
+	if findErr != nil {
+		return fmt.Errorf("fx position %s lookup failed: %w", destinationPositionID, findErr)
+	}
+
+	req.DestinationAmount = quote.DestinationAmount
+	req.DestinationCurrency = quote.DestinationCurrency
+	accounts := map[string]*storage.Account{
+		sourceAcc.AccountID:           sourceAcc,
+		destAcc.AccountID:             destAcc,
+		sourcePosition.AccountID:      sourcePosition,
+		destinationPosition.AccountID: destinationPosition,
+	}
+	legs := buildFxJournal(req, sourceAcc.AccountID, sourcePosition.AccountID, destinationPosition.AccountID, destAcc.AccountID)
+	if limitErr := l.checkAmountLimits(sourceAcc, req); limitErr != nil {
+		return limitErr
+	}
+	feeLegs, feeErr := l.chargeFees(ctx, req, sourceAcc, accounts)
+	if feeErr != nil {
+		return feeErr
+	}
+	return l.commitTransfer(ctx, req, append(legs, feeLegs...), accounts, nil, l.enforceLimits(ctx, req, sourceAcc, destAcc), func(tx *gorm.DB) error {
+		used, markErr := l.FxQuoteDAO.WithTx(tx).MarkUsed(ctx, quote.QuoteID, req.TransactionID)
+		if markErr != nil {
+			return markErr
+		}
+		if !used {
+			return FxQuoteExpiredErr
+		}
+		return nil
+	})
+}
+
+// buildFxJournal returns the four legs of a cross-currency transfer: the source side in the transfer
+// currency and the destination side in its destination currency.
+func buildFxJournal(req *storage.Transfer, sourceAccountID string, sourcePositionID string, destinationPositionID string, destinationAccountID string) []*storage.Transaction {
+	legs := []*storage.Transaction{
+		newLeg(req, sourceAccountID, TypeDebit, req.Amount, fmt.Sprintf("Transfer to %s", req.DestinationAccountID)),
+		newLeg(req, sourcePositionID, TypeCredit, req.Amount, fmt.Sprintf("FX %s/%s %s", req.Currency, req.DestinationCurrency, req.TransactionID)),
+		newLeg(req, destinationPositionID, TypeDebit, req.DestinationAmount, fmt.Sprintf("FX %s/%s %s", req.Currency, req.DestinationCurrency, req.TransactionID)),
+		newLeg(req, destinationAccountID, TypeCredit, req.DestinationAmount, fmt.Sprintf("Transfer from %s", req.SourceAccountID)),
+	}
+	legs[2].Currency = req.DestinationCurrency
+	legs[3].Currency = req.DestinationCurrency
+	return legs
+}
@@ -0,0 +1,152 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_logicImpl_doTransfer_fx(t *testing.T) {
+	fxPositionAccounts := map[string]string{"MYR": "8000000001", "USD": "8000000003"}
+	validQuote := func() *storage.FxQuote {
+		return &storage.FxQuote{
+			QuoteID:             "quote-1",
+			ClientID:            "client-a",
+			SourceCurrency:      "MYR",
+			DestinationCurrency: "USD",
+			Rate:                "0.21220159",
+			SourceAmount:        1000,
+			DestinationAmount:   212,
+			ExpiresAt:           time.Now().Add(time.Minute),
+		}
+	}
+	usedBy := "tx-000"
+	source := &storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 2000}
+
+	tests := []struct {
+		name       string
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO)
+		wantErr    error
+	}{
+		{
+			name: "error - quote not found",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: FxQuoteNotFoundErr,
+		},
+		{
+			name: "error - quote issued to another client",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				quote := validQuote()
+				quote.ClientID = "client-b"
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(quote, nil).Once()
+			},
+			wantErr: FxQuoteNotFoundErr,
+		},
+		{
+			name: "error - quote expired",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				quote := validQuote()
+				quote.ExpiresAt = time.Now().Add(-time.Second)
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(quote, nil).Once()
+			},
+			wantErr: FxQuoteExpiredErr,
+		},
+		{
+			name: "error - quote already used",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				quote := validQuote()
+				quote.TransactionID = &usedBy
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(quote, nil).Once()
+			},
+			wantErr: FxQuoteExpiredErr,
+		},
+		{
+			name: "error - quote for another amount",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				quote := validQuote()
+				quote.SourceAmount = 500
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(quote, nil).Once()
+			},
+			wantErr: FxQuoteMismatchErr,
+		},
+		{
+			name: "error - destination account not held in the quoted currency",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(validQuote(), nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "SGD"}, nil).Once()
+			},
+			wantErr: FxQuoteMismatchErr,
+		},
+		{
+			name: "happy path - books through the FX positions",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, qd *storagemock.MockIFxQuoteDAO) {
+				qd.EXPECT().FindByQuoteID(mock.Anything, "quote-1").Return(validQuote(), nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "USD"}, nil).Once()
+				for _, id := range []string{"8000000001", "8000000003"} {
+					ad.EXPECT().FindByParentAccountID(mock.Anything, id).Return(nil, nil).Once()
+					ad.EXPECT().FindByAccountID(mock.Anything, id).Return(&storage.Account{AccountID: id, Type: storage.AccountTypeFxPosition}, nil).Once()
+				}
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			qd := storagemock.NewMockIFxQuoteDAO(t)
+			tt.setupMocks(ad, td, qd)
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, FxQuoteDAO: qd, fxPositionAccounts: fxPositionAccounts}
+			req := &storage.Transfer{
+				TransactionID:        "tx-123",
+				Amount:               1000,
+				Currency:             "MYR",
+				FxQuoteID:            "quote-1",
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}
+			err := l.doTransfer(context.Background(), req, &CreateTransferOpts{TxType: TxTypeP2PTransfer, ClientID: "client-a"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (req.DestinationAmount != 212 || req.DestinationCurrency != "USD") {
+				t.Errorf("destination = %d %s, want 212 USD", req.DestinationAmount, req.DestinationCurrency)
+			}
+		})
+	}
+}
+
+func Test_buildFxJournal(t *testing.T) {
+	req := &storage.Transfer{
+		TransactionID:        "tx-123",
+		Amount:               1000,
+		Currency:             "MYR",
+		DestinationAmount:    212,
+		DestinationCurrency:  "USD",
+		SourceAccountID:      "source-account",
+		DestinationAccountID: "destination-account",
+	}
+	legs := buildFxJournal(req, "source-account", "8000000001", "8000000003", "destination-account")
+	if err := storage.ValidateJournal(legs); err != nil {
+		t.Fatalf("ValidateJournal() error = %v", err)
+	}
+
+	deltas := netBalanceDeltas(legs)
+	want := map[string]int64{"source-account": -1000, "8000000001": 1000, "8000000003": -212, "destination-account": 212}
+	for accountID, delta := range want {
+		if deltas[accountID] != delta {
+			t.Errorf("delta of %s = %d, want %d", accountID, deltas[accountID], delta)
+		}
+	}
+}
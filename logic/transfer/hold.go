@@ -0,0 +1,324 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+const holdSweepBatchSize = 100
+
+var InvalidExpiryErr = apperr.New(apperr.CodeInvalidExpiry)
+
+type HoldOpts struct {
+	// ClientID is the client acting on the hold. Holds are only visible to the client that placed them.
+	ClientID string
+	// UserID is the user placing the hold. When set, the user must be allowed to debit the held account.
+	UserID string
+}
+
+// PlaceHold reserves funds on an account for a later capture. The funds stay in the account's balance but
+// no longer count towards its available balance.
+func (l *logicImpl) PlaceHold(ctx context.Context, req *dto.PlaceHoldRequest, opts *HoldOpts) (*dto.HoldResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	if existing, _ := l.HoldDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+		return replayHold(existing, req)
+	}
+
+	ttl := l.holdDefaultTTL
+	if req.ExpiresInSeconds > 0 {
+		ttl = time.Duration(req.ExpiresInSeconds) * time.Second
+	}
+	if l.holdMaxTTL > 0 && ttl > l.holdMaxTTL {
+		return nil, InvalidExpiryErr
+	}
+
+	acc, findErr := l.AccountDAO.FindByAccountID(ctx, req.AccountID)
+	if findErr != nil {
+		return nil, InvalidSourceAccountErr
+	}
+	destAcc, findErr := l.AccountDAO.FindByAccountID(ctx, req.DestinationAccountID)
+	if findErr != nil {
+		return nil, InvalidDestinationAccountErr
+	}
+	if acc.Currency != req.Currency || destAcc.Currency != req.Currency {
+		return nil, InvalidCurrencyErr
+	}
+	if accessErr := l.checkDebitAccess(ctx, opts.UserID, acc.AccountID); accessErr != nil {
+		return nil, accessErr
+	}
+	if statusErr := checkPostable(acc, destAcc); statusErr != nil {
+		return nil, statusErr
+	}
+
+	now := time.Now()
+	hold := &storage.Hold{
+		HoldID:               uuid.New().String(),
+		ClientID:             opts.ClientID,
+		ReferenceID:          req.IdempotencyKey,
+		AccountID:            acc.AccountID,
+		DestinationAccountID: destAcc.AccountID,
+		Currency:             req.Currency,
+		Amount:               req.Amount,
+		Status:               storage.HoldStatusActive,
+		Reason:               req.Reason,
+		ExpiresAt:            now.Add(ttl),
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	placeErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		reserved, reserveErr := l.AccountDAO.WithTx(tx).ReserveBalance(ctx, acc.AccountID, req.Amount)
+		if reserveErr != nil {
+			return reserveErr
+		}
+		if !reserved {
+			return InsufficientBalanceErr
+		}
+		return l.HoldDAO.WithTx(tx).Create(ctx, hold)
+	})
+	if placeErr != nil {
+		// a concurrent request with the same key got there first
+		if existing, _ := l.HoldDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+			return replayHold(existing, req)
+		}
+		return nil, placeErr
+	}
+	return mapHoldStorageToResponse(hold), nil
+}
+
+// replayHold answers a repeated place request with the hold placed by the first one
+func replayHold(existing *storage.Hold, req *dto.PlaceHoldRequest) (*dto.HoldResponse, error) {
+	if existing.AccountID != req.AccountID || existing.DestinationAccountID != req.DestinationAccountID ||
+		existing.Currency != req.Currency || existing.Amount != req.Amount || existing.Reason != req.Reason {
+		return nil, IdempotencyKeyReusedErr
+	}
+	return mapHoldStorageToResponse(existing), nil
+}
+
+// CaptureHold settles all or part of a hold by transferring it to the hold's destination account. Without
+// an amount, everything still held is captured. A partially captured hold stays active for further
+// captures until it is fully captured, released or expires.
+func (l *logicImpl) CaptureHold(ctx context.Context, req *dto.CaptureHoldRequest, opts *HoldOpts) (*dto.CreateTransferResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	hold, err := l.findHold(ctx, req.HoldID, opts.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return l.CreateTransfer(ctx, &dto.CreateTransferRequest{
+		Currency:           hold.Currency,
+		Amount:             req.Amount,
+		SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: hold.AccountID},
+		DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: hold.DestinationAccountID},
+		Note:               req.Note,
+		IdempotencyKey:     req.IdempotencyKey,
+	}, &CreateTransferOpts{
+		TxType:   TxTypeCapture,
+		ClientID: opts.ClientID,
+		HoldID:   hold.HoldID,
+	})
+}
+
+// findCapturableHold loads the hold a capture settles and resolves the captured amount. It is checked again
+// under lock when the capture is posted.
+func (l *logicImpl) findCapturableHold(ctx context.Context, req *storage.Transfer) (*storage.Hold, error) {
+	hold, err := l.HoldDAO.FindByHoldID(ctx, req.HoldID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, HoldNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hold.Remaining() == 0 || !time.Now().Before(hold.ExpiresAt) {
+		return nil, HoldNotActiveErr
+	}
+	if req.Amount == 0 {
+		req.Amount = hold.Remaining()
+	}
+	if req.Amount > hold.Remaining() {
+		return nil, HoldAmountExceededErr
+	}
+	req.SourceAccountID = hold.AccountID
+	req.DestinationAccountID = hold.DestinationAccountID
+	return hold, nil
+}
+
+// consumeHold returns the step taking a capture's amount off its hold, to run within the transaction posting
+// the capture before its journal. Releasing the held funds touches the account row, so the account is
+// re-read for posting.
+func (l *logicImpl) consumeHold(ctx context.Context, req *storage.Transfer, accounts map[string]*storage.Account) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		holdDAO, accountDAO := l.HoldDAO.WithTx(tx), l.AccountDAO.WithTx(tx)
+		hold, err := holdDAO.FindByHoldIDForUpdate(ctx, req.HoldID)
+		if err != nil {
+			return err
+		}
+		if hold.Remaining() == 0 || !time.Now().Before(hold.ExpiresAt) {
+			return HoldNotActiveErr
+		}
+		if req.Amount > hold.Remaining() {
+			return HoldAmountExceededErr
+		}
+		if err = accountDAO.ReleaseBalance(ctx, hold.AccountID, req.Amount); err != nil {
+			return err
+		}
+		hold.CapturedAmount += req.Amount
+		if hold.CapturedAmount == hold.Amount {
+			hold.Status = storage.HoldStatusCaptured
+		}
+		hold.UpdatedAt = time.Now()
+		if err = holdDAO.Save(ctx, hold); err != nil {
+			return err
+		}
+		acc, err := accountDAO.FindByAccountID(ctx, hold.AccountID)
+		if err != nil {
+			return err
+		}
+		accounts[acc.AccountID] = acc
+		return nil
+	}
+}
+
+// ExtendHold moves the expiry of an active hold to the given duration from now, within the maximum hold
+// duration counted from when it was placed
+func (l *logicImpl) ExtendHold(ctx context.Context, req *dto.ExtendHoldRequest, opts *HoldOpts) (*dto.HoldResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	if _, err := l.findHold(ctx, req.HoldID, opts.ClientID); err != nil {
+		return nil, err
+	}
+	var hold *storage.Hold
+	extendErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		var err error
+		holdDAO := l.HoldDAO.WithTx(tx)
+		if hold, err = holdDAO.FindByHoldIDForUpdate(ctx, req.HoldID); err != nil {
+			return err
+		}
+		now := time.Now()
+		if hold.Status != storage.HoldStatusActive || !now.Before(hold.ExpiresAt) {
+			return HoldNotActiveErr
+		}
+		expiresAt := now.Add(time.Duration(req.ExpiresInSeconds) * time.Second)
+		if l.holdMaxTTL > 0 && expiresAt.After(hold.CreatedAt.Add(l.holdMaxTTL)) {
+			return InvalidExpiryErr
+		}
+		hold.ExpiresAt = expiresAt
+		hold.UpdatedAt = now
+		return holdDAO.Save(ctx, hold)
+	})
+	if extendErr != nil {
+		return nil, extendErr
+	}
+	return mapHoldStorageToResponse(hold), nil
+}
+
+// ReleaseHold gives whatever is still held back to the account's available balance
+func (l *logicImpl) ReleaseHold(ctx context.Context, holdID string, opts *HoldOpts) (*dto.HoldResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	if _, err := l.findHold(ctx, holdID, opts.ClientID); err != nil {
+		return nil, err
+	}
+	hold, err := l.releaseHold(ctx, holdID, storage.HoldStatusReleased)
+	if err != nil {
+		return nil, err
+	}
+	return mapHoldStorageToResponse(hold), nil
+}
+
+func (l *logicImpl) GetHold(ctx context.Context, holdID string, opts *HoldOpts) (*dto.HoldResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	hold, err := l.findHold(ctx, holdID, opts.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return mapHoldStorageToResponse(hold), nil
+}
+
+// ReleaseExpiredHolds releases every active hold past its expiry and returns how many it released
+func (l *logicImpl) ReleaseExpiredHolds(ctx context.Context) (int64, error) {
+	var released int64
+	for {
+		holds, err := l.HoldDAO.FindExpired(ctx, time.Now(), holdSweepBatchSize)
+		if err != nil {
+			return released, err
+		}
+		for _, hold := range holds {
+			if _, err = l.releaseHold(ctx, hold.HoldID, storage.HoldStatusExpired); err != nil {
+				return released, err
+			}
+			released++
+		}
+		if len(holds) < holdSweepBatchSize {
+			return released, nil
+		}
+	}
+}
+
+// releaseHold ends an active hold with status, releasing what is still held
+func (l *logicImpl) releaseHold(ctx context.Context, holdID string, status string) (*storage.Hold, error) {
+	var hold *storage.Hold
+	releaseErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		var err error
+		holdDAO := l.HoldDAO.WithTx(tx)
+		if hold, err = holdDAO.FindByHoldIDForUpdate(ctx, holdID); err != nil {
+			return err
+		}
+		remaining := hold.Remaining()
+		if remaining == 0 {
+			return HoldNotActiveErr
+		}
+		if err = l.AccountDAO.WithTx(tx).ReleaseBalance(ctx, hold.AccountID, remaining); err != nil {
+			return err
+		}
+		hold.ReleasedAmount = remaining
+		hold.Status = status
+		hold.UpdatedAt = time.Now()
+		return holdDAO.Save(ctx, hold)
+	})
+	if releaseErr != nil {
+		return nil, releaseErr
+	}
+	return hold, nil
+}
+
+// findHold loads a hold of clientID. Holds of other clients are reported as not found.
+func (l *logicImpl) findHold(ctx context.Context, holdID string, clientID string) (*storage.Hold, error) {
+	hold, err := l.HoldDAO.FindByHoldID(ctx, holdID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (hold != nil && hold.ClientID != clientID) {
+		return nil, HoldNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return hold, nil
+}
+
+func mapHoldStorageToResponse(hold *storage.Hold) *dto.HoldResponse {
+	return &dto.HoldResponse{
+		HoldID:               hold.HoldID,
+		AccountID:            hold.AccountID,
+		DestinationAccountID: hold.DestinationAccountID,
+		Currency:             hold.Currency,
+		Amount:               hold.Amount,
+		CapturedAmount:       hold.CapturedAmount,
+		ReleasedAmount:       hold.ReleasedAmount,
+		RemainingAmount:      hold.Remaining(),
+		Status:               hold.Status,
+		Reason:               hold.Reason,
+		ExpiresAt:            hold.ExpiresAt,
+		CreatedAt:            hold.CreatedAt,
+	}
+}
@@ -0,0 +1,285 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func inTransaction(td *storagemock.MockITransferDAO) {
+	td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn, _ ...*sql.TxOptions) error {
+		return fn(nil)
+	}).Once()
+}
+
+func Test_logicImpl_PlaceHold(t *testing.T) {
+	source := &storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 2000}
+	merchant := &storage.Account{AccountID: "merchant-account", Currency: "MYR"}
+	newReq := func() *dto.PlaceHoldRequest {
+		return &dto.PlaceHoldRequest{
+			IdempotencyKey:       "idempotency-key",
+			AccountID:            "source-account",
+			DestinationAccountID: "merchant-account",
+			Amount:               1500,
+			Currency:             "MYR",
+		}
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(req *dto.PlaceHoldRequest)
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO)
+		wantErr    error
+	}{
+		{
+			name: "happy path - reserves the amount",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "merchant-account").Return(merchant, nil).Once()
+				inTransaction(td)
+				ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+				ad.EXPECT().ReserveBalance(mock.Anything, "source-account", int64(1500)).Return(true, nil).Once()
+				hd.EXPECT().WithTx(mock.Anything).Return(hd).Once()
+				hd.EXPECT().Create(mock.Anything, mock.MatchedBy(func(h *storage.Hold) bool {
+					ttl := time.Until(h.ExpiresAt)
+					return h.Status == storage.HoldStatusActive && h.Amount == 1500 && ttl > 6*24*time.Hour && ttl <= 7*24*time.Hour
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name: "happy path - replays the hold placed with the same key",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(&storage.Hold{
+					HoldID:               "hold-1",
+					AccountID:            "source-account",
+					DestinationAccountID: "merchant-account",
+					Amount:               1500,
+					Currency:             "MYR",
+					Status:               storage.HoldStatusActive,
+				}, nil).Once()
+			},
+		},
+		{
+			name:   "error - key reused for another hold",
+			modify: func(req *dto.PlaceHoldRequest) { req.Amount = 1000 },
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(&storage.Hold{
+					AccountID:            "source-account",
+					DestinationAccountID: "merchant-account",
+					Amount:               1500,
+					Currency:             "MYR",
+				}, nil).Once()
+			},
+			wantErr: IdempotencyKeyReusedErr,
+		},
+		{
+			name:   "error - expiry beyond the maximum",
+			modify: func(req *dto.PlaceHoldRequest) { req.ExpiresInSeconds = 31 * 24 * 3600 },
+			setupMocks: func(_ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: InvalidExpiryErr,
+		},
+		{
+			name: "error - available balance too low",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Twice()
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "merchant-account").Return(merchant, nil).Once()
+				inTransaction(td)
+				ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+				ad.EXPECT().ReserveBalance(mock.Anything, "source-account", int64(1500)).Return(false, nil).Once()
+			},
+			wantErr: InsufficientBalanceErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			hd := storagemock.NewMockIHoldDAO(t)
+			tt.setupMocks(ad, td, hd)
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, HoldDAO: hd, holdDefaultTTL: 7 * 24 * time.Hour, holdMaxTTL: 30 * 24 * time.Hour}
+			req := newReq()
+			if tt.modify != nil {
+				tt.modify(req)
+			}
+			got, err := l.PlaceHold(context.Background(), req, &HoldOpts{ClientID: "client-a"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("PlaceHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.RemainingAmount != 1500 {
+				t.Errorf("PlaceHold() remaining = %d, want 1500", got.RemainingAmount)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_doTransfer_capture(t *testing.T) {
+	activeHold := func() *storage.Hold {
+		return &storage.Hold{
+			HoldID:               "hold-1",
+			AccountID:            "source-account",
+			DestinationAccountID: "merchant-account",
+			Currency:             "MYR",
+			Amount:               1500,
+			CapturedAmount:       500,
+			Status:               storage.HoldStatusActive,
+			ExpiresAt:            time.Now().Add(time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name       string
+		amount     int64
+		setupMocks func(ad *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO)
+		wantAmount int64
+		wantErr    error
+	}{
+		{
+			name: "error - hold not found",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByHoldID(mock.Anything, "hold-1").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: HoldNotFoundErr,
+		},
+		{
+			name: "error - hold released",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO) {
+				hold := activeHold()
+				hold.Status = storage.HoldStatusReleased
+				hd.EXPECT().FindByHoldID(mock.Anything, "hold-1").Return(hold, nil).Once()
+			},
+			wantErr: HoldNotActiveErr,
+		},
+		{
+			name: "error - hold expired",
+			setupMocks: func(_ *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO) {
+				hold := activeHold()
+				hold.ExpiresAt = time.Now().Add(-time.Second)
+				hd.EXPECT().FindByHoldID(mock.Anything, "hold-1").Return(hold, nil).Once()
+			},
+			wantErr: HoldNotActiveErr,
+		},
+		{
+			name:   "error - more than what is still held",
+			amount: 1001,
+			setupMocks: func(_ *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByHoldID(mock.Anything, "hold-1").Return(activeHold(), nil).Once()
+			},
+			wantErr: HoldAmountExceededErr,
+		},
+		{
+			name: "happy path - captures the remainder without an amount",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, hd *storagemock.MockIHoldDAO) {
+				hd.EXPECT().FindByHoldID(mock.Anything, "hold-1").Return(activeHold(), nil).Once()
+				// every bit of the balance is held, the capture spends the held funds
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 1000, HeldBalance: 1000}, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "merchant-account").Return(&storage.Account{AccountID: "merchant-account", Currency: "MYR"}, nil).Once()
+			},
+			wantAmount: 1000,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			hd := storagemock.NewMockIHoldDAO(t)
+			tt.setupMocks(ad, hd)
+			if tt.wantErr == nil {
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			}
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, HoldDAO: hd}
+			req := &storage.Transfer{TransactionID: "tx-123", Amount: tt.amount, Currency: "MYR", HoldID: "hold-1"}
+			err := l.doTransfer(context.Background(), req, &CreateTransferOpts{TxType: TxTypeCapture, HoldID: "hold-1"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (req.Amount != tt.wantAmount || req.SourceAccountID != "source-account" || req.DestinationAccountID != "merchant-account") {
+				t.Errorf("capture = %d from %s to %s, want %d from source-account to merchant-account",
+					req.Amount, req.SourceAccountID, req.DestinationAccountID, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_consumeHold(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     int64
+		wantStatus string
+	}{
+		{name: "partial capture keeps the hold active", amount: 400, wantStatus: storage.HoldStatusActive},
+		{name: "capturing the rest completes the hold", amount: 1000, wantStatus: storage.HoldStatusCaptured},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			hd := storagemock.NewMockIHoldDAO(t)
+			hd.EXPECT().WithTx(mock.Anything).Return(hd).Once()
+			ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+			hd.EXPECT().FindByHoldIDForUpdate(mock.Anything, "hold-1").Return(&storage.Hold{
+				HoldID:         "hold-1",
+				AccountID:      "source-account",
+				Amount:         1500,
+				CapturedAmount: 500,
+				Status:         storage.HoldStatusActive,
+				ExpiresAt:      time.Now().Add(time.Hour),
+			}, nil).Once()
+			ad.EXPECT().ReleaseBalance(mock.Anything, "source-account", tt.amount).Return(nil).Once()
+			hd.EXPECT().Save(mock.Anything, mock.MatchedBy(func(h *storage.Hold) bool {
+				return h.CapturedAmount == 500+tt.amount && h.Status == tt.wantStatus
+			})).Return(nil).Once()
+			reread := &storage.Account{AccountID: "source-account", Balance: 1000}
+			ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(reread, nil).Once()
+
+			l := &logicImpl{AccountDAO: ad, HoldDAO: hd}
+			accounts := map[string]*storage.Account{"source-account": {AccountID: "source-account", Balance: 1000, HeldBalance: 1000}}
+			req := &storage.Transfer{Amount: tt.amount, HoldID: "hold-1"}
+			if err := l.consumeHold(context.Background(), req, accounts)(nil); err != nil {
+				t.Fatalf("consumeHold() error = %v", err)
+			}
+			if accounts["source-account"] != reread {
+				t.Errorf("consumeHold() did not re-read the source account")
+			}
+		})
+	}
+}
+
+func Test_logicImpl_ReleaseExpiredHolds(t *testing.T) {
+	ad := storagemock.NewMockIAccountDAO(t)
+	td := storagemock.NewMockITransferDAO(t)
+	hd := storagemock.NewMockIHoldDAO(t)
+	hd.EXPECT().FindExpired(mock.Anything, mock.Anything, holdSweepBatchSize).Return([]*storage.Hold{{HoldID: "hold-1"}}, nil).Once()
+	inTransaction(td)
+	hd.EXPECT().WithTx(mock.Anything).Return(hd).Once()
+	hd.EXPECT().FindByHoldIDForUpdate(mock.Anything, "hold-1").Return(&storage.Hold{
+		HoldID:         "hold-1",
+		AccountID:      "source-account",
+		Amount:         1500,
+		CapturedAmount: 500,
+		Status:         storage.HoldStatusActive,
+	}, nil).Once()
+	ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+	ad.EXPECT().ReleaseBalance(mock.Anything, "source-account", int64(1000)).Return(nil).Once()
+	hd.EXPECT().Save(mock.Anything, mock.MatchedBy(func(h *storage.Hold) bool {
+		return h.Status == storage.HoldStatusExpired && h.ReleasedAmount == 1000
+	})).Return(nil).Once()
+
+	l := &logicImpl{TransferDAO: td, AccountDAO: ad, HoldDAO: hd}
+	got, err := l.ReleaseExpiredHolds(context.Background())
+	if err != nil || got != 1 {
+		t.Errorf("ReleaseExpiredHolds() = %v, %v, want 1, nil", got, err)
+	}
+}
@@ -0,0 +1,53 @@
+package transfer
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+	"wallet/dto"
+)
+
+// requestFingerprint hashes everything in req that affects the transfer, together with the tx type and the
+// hold it captures or transfer it refunds, so a replayed idempotency key can be told apart from a different request reusing it.
+// The key itself is left out. encoding/json writes struct fields in declaration order and map keys sorted,
+// which keeps the encoding canonical.
+func requestFingerprint(req *dto.CreateTransferRequest, opts *CreateTransferOpts) string {
+	canonical, _ := json.Marshal(struct {
+		TxType             TxType                 `json:"txType"`
+		Currency           string                 `json:"currency"`
+		Amount             int64                  `json:"amount"`
+		SourceAccount      string                 `json:"sourceAccount"`
+		DestinationAccount string                 `json:"destinationAccount"`
+		Properties         map[string]interface{} `json:"properties"`
+		Note               string                 `json:"note"`
+		QuoteID            string                 `json:"quoteID,omitempty"` // omitted when empty, keeping older fingerprints stable
+		HoldID             string                 `json:"holdID,omitempty"`
+		ParentTransaction  string                 `json:"parentTransactionID,omitempty"`
+		ExecuteAt          *time.Time             `json:"executeAt,omitempty"`
+	}{
+		TxType:             opts.TxType,
+		Currency:           req.Currency,
+		Amount:             req.Amount,
+		SourceAccount:      req.SourceAccount.Number,
+		DestinationAccount: req.DestinationAccount.Number,
+		Properties:         req.Properties,
+		Note:               req.Note,
+		QuoteID:            req.QuoteID,
+		HoldID:             opts.HoldID,
+		ParentTransaction:  opts.ParentTransactionID,
+		ExecuteAt:          req.ExecuteAt,
+	})
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// PurgeIdempotencyKeys releases the idempotency keys of finished transfers older than the configured retention
+// window, after which a client may reuse them for new transfers. It returns the number of keys released.
+func (l *logicImpl) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	if l.keyRetention <= 0 {
+		return 0, nil
+	}
+	return l.TransferDAO.ReleaseReferenceIDs(ctx, time.Now().Add(-l.keyRetention))
+}
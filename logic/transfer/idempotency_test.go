@@ -0,0 +1,77 @@
+package transfer
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wallet/dto"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_requestFingerprint(t *testing.T) {
+	base := func() *dto.CreateTransferRequest {
+		return &dto.CreateTransferRequest{
+			Currency:           "MYR",
+			Amount:             1000,
+			SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: "12345678"},
+			DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: "87654321"},
+			Properties:         map[string]interface{}{"channel": "app", "device": "ios"},
+			Note:               "lunch",
+			IdempotencyKey:     "idempotency-key",
+		}
+	}
+	want := requestFingerprint(base(), &CreateTransferOpts{TxType: TxTypeP2PTransfer})
+
+	tests := []struct {
+		name   string
+		modify func(req *dto.CreateTransferRequest)
+		txType TxType
+		holdID string
+		same   bool
+	}{
+		{name: "identical request", modify: func(*dto.CreateTransferRequest) {}, txType: TxTypeP2PTransfer, same: true},
+		{name: "different idempotency key", modify: func(r *dto.CreateTransferRequest) { r.IdempotencyKey = "other" }, txType: TxTypeP2PTransfer, same: true},
+		{name: "properties built in another order", modify: func(r *dto.CreateTransferRequest) {
+			r.Properties = map[string]interface{}{"device": "ios", "channel": "app"}
+		}, txType: TxTypeP2PTransfer, same: true},
+		{name: "different amount", modify: func(r *dto.CreateTransferRequest) { r.Amount = 1001 }, txType: TxTypeP2PTransfer},
+		{name: "different currency", modify: func(r *dto.CreateTransferRequest) { r.Currency = "SGD" }, txType: TxTypeP2PTransfer},
+		{name: "different destination", modify: func(r *dto.CreateTransferRequest) { r.DestinationAccount.Number = "11111111" }, txType: TxTypeP2PTransfer},
+		{name: "different tx type", modify: func(*dto.CreateTransferRequest) {}, txType: TxTypeWithdrawal},
+		{name: "capturing a hold", modify: func(*dto.CreateTransferRequest) {}, txType: TxTypeP2PTransfer, holdID: "hold-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base()
+			tt.modify(req)
+			if got := requestFingerprint(req, &CreateTransferOpts{TxType: tt.txType, HoldID: tt.holdID}); (got == want) != tt.same {
+				t.Errorf("requestFingerprint() = %v, base %v, want same %v", got, want, tt.same)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_PurgeIdempotencyKeys(t *testing.T) {
+	t.Run("releases keys older than the retention window", func(t *testing.T) {
+		td := storagemock.NewMockITransferDAO(t)
+		td.EXPECT().ReleaseReferenceIDs(mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+			return time.Since(before) >= time.Hour && time.Since(before) < time.Hour+time.Minute
+		})).Return(3, nil).Once()
+
+		l := &logicImpl{TransferDAO: td, keyRetention: time.Hour}
+		got, err := l.PurgeIdempotencyKeys(context.Background())
+		if err != nil || got != 3 {
+			t.Errorf("PurgeIdempotencyKeys() = %v, %v, want 3, nil", got, err)
+		}
+	})
+
+	t.Run("keeps keys forever without a retention window", func(t *testing.T) {
+		l := &logicImpl{TransferDAO: storagemock.NewMockITransferDAO(t)}
+		got, err := l.PurgeIdempotencyKeys(context.Background())
+		if err != nil || got != 0 {
+			t.Errorf("PurgeIdempotencyKeys() = %v, %v, want 0, nil", got, err)
+		}
+	})
+}
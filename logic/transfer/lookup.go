@@ -0,0 +1,129 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+type GetTransferOpts struct {
+	// ClientID is the client looking the transfer up. Transfers are only visible to the client that made them.
+	ClientID string
+	// UserID is the user looking the transfer up. When set, the user must be linked to its source or
+	// destination account. Service principals act for no user and see every transfer of their client.
+	UserID string
+}
+
+// GetTransfer returns a transfer with its ledger legs, looked up by transaction ID or by the idempotency
+// key it was created with. Transfers of other clients, and transfers the user has no account in, are
+// reported as not found.
+func (l *logicImpl) GetTransfer(ctx context.Context, req *dto.GetTransferRequest, opts *GetTransferOpts) (*dto.TransferDetailResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	var trf *storage.Transfer
+	var err error
+	switch {
+	case req.TransactionID != "":
+		trf, err = l.TransferDAO.FindByTransactionID(ctx, req.TransactionID)
+	case req.IdempotencyKey != "":
+		trf, err = l.TransferDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey)
+	default:
+		return nil, TransferNotFoundErr
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) || (trf != nil && trf.ClientID != opts.ClientID) {
+		return nil, TransferNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = l.checkViewAccess(ctx, opts.UserID, trf); err != nil {
+		return nil, err
+	}
+
+	legs, err := l.TransactionDAO.FindByJournalID(ctx, trf.TransactionID)
+	if err != nil {
+		return nil, err
+	}
+	return mapTransferStorageToDetailResponse(trf, legs), nil
+}
+
+// checkViewAccess checks userID may see trf, which needs a link to its source or destination account. An
+// empty userID is a service principal, which is not checked.
+func (l *logicImpl) checkViewAccess(ctx context.Context, userID string, trf *storage.Transfer) error {
+	if userID == "" {
+		return nil
+	}
+	user, err := l.UserDAO.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return TransferNotFoundErr
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active() {
+		return TransferNotFoundErr
+	}
+	for _, accountID := range []string{trf.SourceAccountID, trf.DestinationAccountID} {
+		if accountID == "" {
+			continue
+		}
+		_, err = l.UserDAO.FindUserAccount(ctx, userID, accountID)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	}
+	return TransferNotFoundErr
+}
+
+func mapTransferStorageToDetailResponse(trf *storage.Transfer, legs []*storage.Transaction) *dto.TransferDetailResponse {
+	resp := &dto.TransferDetailResponse{
+		TransactionID:           trf.TransactionID,
+		IdempotencyKey:          trf.ReferenceID,
+		TxType:                  trf.TxType,
+		UserID:                  trf.UserID,
+		Status:                  trf.Status,
+		StatusReason:            trf.StatusReason,
+		StatusReasonDescription: trf.StatusReasonDescription,
+		Amount:                  trf.Amount,
+		Currency:                trf.Currency,
+		SourceAccountID:         trf.SourceAccountID,
+		SourceAccount:           trf.SourceAccount,
+		DestinationAccountID:    trf.DestinationAccountID,
+		DestinationAccount:      trf.DestinationAccount,
+		Note:                    trf.Note,
+		Properties:              trf.Properties,
+
+		QuoteID:             trf.FxQuoteID,
+		DestinationAmount:   trf.DestinationAmount,
+		DestinationCurrency: trf.DestinationCurrency,
+		HoldID:              trf.HoldID,
+		ParentTransactionID: trf.ParentTransactionID,
+		RefundedAmount:      trf.RefundedAmount,
+		ExecuteAt:           trf.ExecuteAt,
+
+		Fees:     mapFeesStorageToResponse(trf.Fees),
+		TotalFee: trf.FeeAmount,
+		Legs:     []*dto.TransferLegResponse{},
+
+		CreatedAt: trf.CreatedAt,
+		ValuedAt:  trf.ValuedAt,
+		UpdatedAt: trf.UpdatedAt,
+	}
+	for _, leg := range legs {
+		resp.Legs = append(resp.Legs, &dto.TransferLegResponse{
+			EntryID:   leg.ID,
+			AccountID: leg.AccountID,
+			Type:      leg.Type,
+			Amount:    leg.Amount,
+			Currency:  leg.Currency,
+			Note:      leg.Note,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,110 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_logicImpl_GetTransfer(t *testing.T) {
+	found := &storage.Transfer{
+		TransactionID:        "tx-123",
+		ReferenceID:          "idempotency-key",
+		ClientID:             "client-a",
+		Status:               TxStatusCOMPLETED,
+		Amount:               1000,
+		SourceAccountID:      "source-account",
+		DestinationAccountID: "destination-account",
+	}
+	legs := []*storage.Transaction{
+		{ID: 1, AccountID: "source-account", Type: TypeDebit, Amount: 1000},
+		{ID: 2, AccountID: "destination-account", Type: TypeCredit, Amount: 1000},
+	}
+
+	tests := []struct {
+		name       string
+		req        *dto.GetTransferRequest
+		userID     string
+		setupMocks func(td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO, ud *storagemock.MockIUserDAO)
+		wantErr    error
+	}{
+		{
+			name: "happy path - by transaction ID",
+			req:  &dto.GetTransferRequest{TransactionID: "tx-123"},
+			setupMocks: func(td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO, _ *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-123").Return(found, nil).Once()
+				txd.EXPECT().FindByJournalID(mock.Anything, "tx-123").Return(legs, nil).Once()
+			},
+		},
+		{
+			name: "happy path - by idempotency key",
+			req:  &dto.GetTransferRequest{IdempotencyKey: "idempotency-key"},
+			setupMocks: func(td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO, _ *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(found, nil).Once()
+				txd.EXPECT().FindByJournalID(mock.Anything, "tx-123").Return(legs, nil).Once()
+			},
+		},
+		{
+			name:   "happy path - user linked to the destination account",
+			req:    &dto.GetTransferRequest{TransactionID: "tx-123"},
+			userID: "user-1",
+			setupMocks: func(td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO, ud *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-123").Return(found, nil).Once()
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(&storage.User{UserID: "user-1", Status: storage.UserStatusActive}, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "source-account").Return(nil, gorm.ErrRecordNotFound).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "destination-account").Return(&storage.UserAccount{}, nil).Once()
+				txd.EXPECT().FindByJournalID(mock.Anything, "tx-123").Return(legs, nil).Once()
+			},
+		},
+		{
+			name:   "error - user linked to neither account",
+			req:    &dto.GetTransferRequest{TransactionID: "tx-123"},
+			userID: "user-1",
+			setupMocks: func(td *storagemock.MockITransferDAO, _ *storagemock.MockITransactionDAO, ud *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-123").Return(found, nil).Once()
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(&storage.User{UserID: "user-1", Status: storage.UserStatusActive}, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", mock.Anything).Return(nil, gorm.ErrRecordNotFound).Twice()
+			},
+			wantErr: TransferNotFoundErr,
+		},
+		{
+			name: "error - unknown transaction ID",
+			req:  &dto.GetTransferRequest{TransactionID: "tx-123"},
+			setupMocks: func(td *storagemock.MockITransferDAO, _ *storagemock.MockITransactionDAO, _ *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-123").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: TransferNotFoundErr,
+		},
+		{
+			name: "error - transfer of another client",
+			req:  &dto.GetTransferRequest{TransactionID: "tx-456"},
+			setupMocks: func(td *storagemock.MockITransferDAO, _ *storagemock.MockITransactionDAO, _ *storagemock.MockIUserDAO) {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-456").Return(&storage.Transfer{TransactionID: "tx-456", ClientID: "client-b"}, nil).Once()
+			},
+			wantErr: TransferNotFoundErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := storagemock.NewMockITransferDAO(t)
+			txd := storagemock.NewMockITransactionDAO(t)
+			ud := storagemock.NewMockIUserDAO(t)
+			tt.setupMocks(td, txd, ud)
+
+			l := &logicImpl{TransferDAO: td, TransactionDAO: txd, UserDAO: ud}
+			got, err := l.GetTransfer(context.Background(), tt.req, &GetTransferOpts{ClientID: "client-a", UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("GetTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.TransactionID != "tx-123" || got.IdempotencyKey != "idempotency-key" || len(got.Legs) != 2) {
+				t.Errorf("GetTransfer() = %s/%s with %d legs, want tx-123/idempotency-key with 2 legs", got.TransactionID, got.IdempotencyKey, len(got.Legs))
+			}
+		})
+	}
+}
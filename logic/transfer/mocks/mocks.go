@@ -39,6 +39,217 @@ func (_m *MockITransferLogic) EXPECT() *MockITransferLogic_Expecter {
 	return &MockITransferLogic_Expecter{mock: &_m.Mock}
 }
 
+// CancelTransfer provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) CancelTransfer(ctx context.Context, transactionID string, opts *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error) {
+	ret := _mock.Called(ctx, transactionID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelTransfer")
+	}
+
+	var r0 *dto.TransferDetailResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error)); ok {
+		return returnFunc(ctx, transactionID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.ScheduledTransferOpts) *dto.TransferDetailResponse); ok {
+		r0 = returnFunc(ctx, transactionID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.TransferDetailResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *transfer.ScheduledTransferOpts) error); ok {
+		r1 = returnFunc(ctx, transactionID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_CancelTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CancelTransfer'
+type MockITransferLogic_CancelTransfer_Call struct {
+	*mock.Call
+}
+
+// CancelTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID string
+//   - opts *transfer.ScheduledTransferOpts
+func (_e *MockITransferLogic_Expecter) CancelTransfer(ctx interface{}, transactionID interface{}, opts interface{}) *MockITransferLogic_CancelTransfer_Call {
+	return &MockITransferLogic_CancelTransfer_Call{Call: _e.mock.On("CancelTransfer", ctx, transactionID, opts)}
+}
+
+func (_c *MockITransferLogic_CancelTransfer_Call) Run(run func(ctx context.Context, transactionID string, opts *transfer.ScheduledTransferOpts)) *MockITransferLogic_CancelTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *transfer.ScheduledTransferOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.ScheduledTransferOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_CancelTransfer_Call) Return(transferDetailResponse *dto.TransferDetailResponse, err error) *MockITransferLogic_CancelTransfer_Call {
+	_c.Call.Return(transferDetailResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_CancelTransfer_Call) RunAndReturn(run func(ctx context.Context, transactionID string, opts *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error)) *MockITransferLogic_CancelTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CaptureHold provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) CaptureHold(context1 context.Context, captureHoldRequest *dto.CaptureHoldRequest, holdOpts *transfer.HoldOpts) (*dto.CreateTransferResponse, error) {
+	ret := _mock.Called(context1, captureHoldRequest, holdOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CaptureHold")
+	}
+
+	var r0 *dto.CreateTransferResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.CaptureHoldRequest, *transfer.HoldOpts) (*dto.CreateTransferResponse, error)); ok {
+		return returnFunc(context1, captureHoldRequest, holdOpts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.CaptureHoldRequest, *transfer.HoldOpts) *dto.CreateTransferResponse); ok {
+		r0 = returnFunc(context1, captureHoldRequest, holdOpts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.CreateTransferResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dto.CaptureHoldRequest, *transfer.HoldOpts) error); ok {
+		r1 = returnFunc(context1, captureHoldRequest, holdOpts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_CaptureHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CaptureHold'
+type MockITransferLogic_CaptureHold_Call struct {
+	*mock.Call
+}
+
+// CaptureHold is a helper method to define mock.On call
+//   - context1 context.Context
+//   - captureHoldRequest *dto.CaptureHoldRequest
+//   - holdOpts *transfer.HoldOpts
+func (_e *MockITransferLogic_Expecter) CaptureHold(context1 interface{}, captureHoldRequest interface{}, holdOpts interface{}) *MockITransferLogic_CaptureHold_Call {
+	return &MockITransferLogic_CaptureHold_Call{Call: _e.mock.On("CaptureHold", context1, captureHoldRequest, holdOpts)}
+}
+
+func (_c *MockITransferLogic_CaptureHold_Call) Run(run func(context1 context.Context, captureHoldRequest *dto.CaptureHoldRequest, holdOpts *transfer.HoldOpts)) *MockITransferLogic_CaptureHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dto.CaptureHoldRequest
+		if args[1] != nil {
+			arg1 = args[1].(*dto.CaptureHoldRequest)
+		}
+		var arg2 *transfer.HoldOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.HoldOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_CaptureHold_Call) Return(createTransferResponse *dto.CreateTransferResponse, err error) *MockITransferLogic_CaptureHold_Call {
+	_c.Call.Return(createTransferResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_CaptureHold_Call) RunAndReturn(run func(context1 context.Context, captureHoldRequest *dto.CaptureHoldRequest, holdOpts *transfer.HoldOpts) (*dto.CreateTransferResponse, error)) *MockITransferLogic_CaptureHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CheckDebitAccess provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) CheckDebitAccess(ctx context.Context, userID string, accountID string) error {
+	ret := _mock.Called(ctx, userID, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CheckDebitAccess")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, userID, accountID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferLogic_CheckDebitAccess_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CheckDebitAccess'
+type MockITransferLogic_CheckDebitAccess_Call struct {
+	*mock.Call
+}
+
+// CheckDebitAccess is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - accountID string
+func (_e *MockITransferLogic_Expecter) CheckDebitAccess(ctx interface{}, userID interface{}, accountID interface{}) *MockITransferLogic_CheckDebitAccess_Call {
+	return &MockITransferLogic_CheckDebitAccess_Call{Call: _e.mock.On("CheckDebitAccess", ctx, userID, accountID)}
+}
+
+func (_c *MockITransferLogic_CheckDebitAccess_Call) Run(run func(ctx context.Context, userID string, accountID string)) *MockITransferLogic_CheckDebitAccess_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_CheckDebitAccess_Call) Return(err error) *MockITransferLogic_CheckDebitAccess_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferLogic_CheckDebitAccess_Call) RunAndReturn(run func(ctx context.Context, userID string, accountID string) error) *MockITransferLogic_CheckDebitAccess_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateTransfer provides a mock function for the type MockITransferLogic
 func (_mock *MockITransferLogic) CreateTransfer(context1 context.Context, createTransferRequest *dto.CreateTransferRequest, createTransferOpts *transfer.CreateTransferOpts) (*dto.CreateTransferResponse, error) {
 	ret := _mock.Called(context1, createTransferRequest, createTransferOpts)
@@ -112,3 +323,912 @@ func (_c *MockITransferLogic_CreateTransfer_Call) RunAndReturn(run func(context1
 	_c.Call.Return(run)
 	return _c
 }
+
+// CreateTransfersAtomically provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) CreateTransfersAtomically(ctx context.Context, reqs []*dto.CreateTransferRequest, opts *transfer.CreateTransferOpts) ([]*dto.CreateTransferResponse, error) {
+	ret := _mock.Called(ctx, reqs, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateTransfersAtomically")
+	}
+
+	var r0 []*dto.CreateTransferResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*dto.CreateTransferRequest, *transfer.CreateTransferOpts) ([]*dto.CreateTransferResponse, error)); ok {
+		return returnFunc(ctx, reqs, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*dto.CreateTransferRequest, *transfer.CreateTransferOpts) []*dto.CreateTransferResponse); ok {
+		r0 = returnFunc(ctx, reqs, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*dto.CreateTransferResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []*dto.CreateTransferRequest, *transfer.CreateTransferOpts) error); ok {
+		r1 = returnFunc(ctx, reqs, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_CreateTransfersAtomically_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateTransfersAtomically'
+type MockITransferLogic_CreateTransfersAtomically_Call struct {
+	*mock.Call
+}
+
+// CreateTransfersAtomically is a helper method to define mock.On call
+//   - ctx context.Context
+//   - reqs []*dto.CreateTransferRequest
+//   - opts *transfer.CreateTransferOpts
+func (_e *MockITransferLogic_Expecter) CreateTransfersAtomically(ctx interface{}, reqs interface{}, opts interface{}) *MockITransferLogic_CreateTransfersAtomically_Call {
+	return &MockITransferLogic_CreateTransfersAtomically_Call{Call: _e.mock.On("CreateTransfersAtomically", ctx, reqs, opts)}
+}
+
+func (_c *MockITransferLogic_CreateTransfersAtomically_Call) Run(run func(ctx context.Context, reqs []*dto.CreateTransferRequest, opts *transfer.CreateTransferOpts)) *MockITransferLogic_CreateTransfersAtomically_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*dto.CreateTransferRequest
+		if args[1] != nil {
+			arg1 = args[1].([]*dto.CreateTransferRequest)
+		}
+		var arg2 *transfer.CreateTransferOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.CreateTransferOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_CreateTransfersAtomically_Call) Return(createTransferResponses []*dto.CreateTransferResponse, err error) *MockITransferLogic_CreateTransfersAtomically_Call {
+	_c.Call.Return(createTransferResponses, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_CreateTransfersAtomically_Call) RunAndReturn(run func(ctx context.Context, reqs []*dto.CreateTransferRequest, opts *transfer.CreateTransferOpts) ([]*dto.CreateTransferResponse, error)) *MockITransferLogic_CreateTransfersAtomically_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExecuteDueTransfers provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) ExecuteDueTransfers(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExecuteDueTransfers")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_ExecuteDueTransfers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExecuteDueTransfers'
+type MockITransferLogic_ExecuteDueTransfers_Call struct {
+	*mock.Call
+}
+
+// ExecuteDueTransfers is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockITransferLogic_Expecter) ExecuteDueTransfers(ctx interface{}) *MockITransferLogic_ExecuteDueTransfers_Call {
+	return &MockITransferLogic_ExecuteDueTransfers_Call{Call: _e.mock.On("ExecuteDueTransfers", ctx)}
+}
+
+func (_c *MockITransferLogic_ExecuteDueTransfers_Call) Run(run func(ctx context.Context)) *MockITransferLogic_ExecuteDueTransfers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_ExecuteDueTransfers_Call) Return(n int64, err error) *MockITransferLogic_ExecuteDueTransfers_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_ExecuteDueTransfers_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *MockITransferLogic_ExecuteDueTransfers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ExtendHold provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) ExtendHold(context1 context.Context, extendHoldRequest *dto.ExtendHoldRequest, holdOpts *transfer.HoldOpts) (*dto.HoldResponse, error) {
+	ret := _mock.Called(context1, extendHoldRequest, holdOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExtendHold")
+	}
+
+	var r0 *dto.HoldResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.ExtendHoldRequest, *transfer.HoldOpts) (*dto.HoldResponse, error)); ok {
+		return returnFunc(context1, extendHoldRequest, holdOpts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.ExtendHoldRequest, *transfer.HoldOpts) *dto.HoldResponse); ok {
+		r0 = returnFunc(context1, extendHoldRequest, holdOpts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.HoldResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dto.ExtendHoldRequest, *transfer.HoldOpts) error); ok {
+		r1 = returnFunc(context1, extendHoldRequest, holdOpts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_ExtendHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ExtendHold'
+type MockITransferLogic_ExtendHold_Call struct {
+	*mock.Call
+}
+
+// ExtendHold is a helper method to define mock.On call
+//   - context1 context.Context
+//   - extendHoldRequest *dto.ExtendHoldRequest
+//   - holdOpts *transfer.HoldOpts
+func (_e *MockITransferLogic_Expecter) ExtendHold(context1 interface{}, extendHoldRequest interface{}, holdOpts interface{}) *MockITransferLogic_ExtendHold_Call {
+	return &MockITransferLogic_ExtendHold_Call{Call: _e.mock.On("ExtendHold", context1, extendHoldRequest, holdOpts)}
+}
+
+func (_c *MockITransferLogic_ExtendHold_Call) Run(run func(context1 context.Context, extendHoldRequest *dto.ExtendHoldRequest, holdOpts *transfer.HoldOpts)) *MockITransferLogic_ExtendHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dto.ExtendHoldRequest
+		if args[1] != nil {
+			arg1 = args[1].(*dto.ExtendHoldRequest)
+		}
+		var arg2 *transfer.HoldOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.HoldOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_ExtendHold_Call) Return(holdResponse *dto.HoldResponse, err error) *MockITransferLogic_ExtendHold_Call {
+	_c.Call.Return(holdResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_ExtendHold_Call) RunAndReturn(run func(context1 context.Context, extendHoldRequest *dto.ExtendHoldRequest, holdOpts *transfer.HoldOpts) (*dto.HoldResponse, error)) *MockITransferLogic_ExtendHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetHold provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) GetHold(ctx context.Context, holdID string, opts *transfer.HoldOpts) (*dto.HoldResponse, error) {
+	ret := _mock.Called(ctx, holdID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHold")
+	}
+
+	var r0 *dto.HoldResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.HoldOpts) (*dto.HoldResponse, error)); ok {
+		return returnFunc(ctx, holdID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.HoldOpts) *dto.HoldResponse); ok {
+		r0 = returnFunc(ctx, holdID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.HoldResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *transfer.HoldOpts) error); ok {
+		r1 = returnFunc(ctx, holdID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_GetHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetHold'
+type MockITransferLogic_GetHold_Call struct {
+	*mock.Call
+}
+
+// GetHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holdID string
+//   - opts *transfer.HoldOpts
+func (_e *MockITransferLogic_Expecter) GetHold(ctx interface{}, holdID interface{}, opts interface{}) *MockITransferLogic_GetHold_Call {
+	return &MockITransferLogic_GetHold_Call{Call: _e.mock.On("GetHold", ctx, holdID, opts)}
+}
+
+func (_c *MockITransferLogic_GetHold_Call) Run(run func(ctx context.Context, holdID string, opts *transfer.HoldOpts)) *MockITransferLogic_GetHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *transfer.HoldOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.HoldOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_GetHold_Call) Return(holdResponse *dto.HoldResponse, err error) *MockITransferLogic_GetHold_Call {
+	_c.Call.Return(holdResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_GetHold_Call) RunAndReturn(run func(ctx context.Context, holdID string, opts *transfer.HoldOpts) (*dto.HoldResponse, error)) *MockITransferLogic_GetHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTransfer provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) GetTransfer(context1 context.Context, getTransferRequest *dto.GetTransferRequest, getTransferOpts *transfer.GetTransferOpts) (*dto.TransferDetailResponse, error) {
+	ret := _mock.Called(context1, getTransferRequest, getTransferOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransfer")
+	}
+
+	var r0 *dto.TransferDetailResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.GetTransferRequest, *transfer.GetTransferOpts) (*dto.TransferDetailResponse, error)); ok {
+		return returnFunc(context1, getTransferRequest, getTransferOpts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.GetTransferRequest, *transfer.GetTransferOpts) *dto.TransferDetailResponse); ok {
+		r0 = returnFunc(context1, getTransferRequest, getTransferOpts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.TransferDetailResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dto.GetTransferRequest, *transfer.GetTransferOpts) error); ok {
+		r1 = returnFunc(context1, getTransferRequest, getTransferOpts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_GetTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTransfer'
+type MockITransferLogic_GetTransfer_Call struct {
+	*mock.Call
+}
+
+// GetTransfer is a helper method to define mock.On call
+//   - context1 context.Context
+//   - getTransferRequest *dto.GetTransferRequest
+//   - getTransferOpts *transfer.GetTransferOpts
+func (_e *MockITransferLogic_Expecter) GetTransfer(context1 interface{}, getTransferRequest interface{}, getTransferOpts interface{}) *MockITransferLogic_GetTransfer_Call {
+	return &MockITransferLogic_GetTransfer_Call{Call: _e.mock.On("GetTransfer", context1, getTransferRequest, getTransferOpts)}
+}
+
+func (_c *MockITransferLogic_GetTransfer_Call) Run(run func(context1 context.Context, getTransferRequest *dto.GetTransferRequest, getTransferOpts *transfer.GetTransferOpts)) *MockITransferLogic_GetTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dto.GetTransferRequest
+		if args[1] != nil {
+			arg1 = args[1].(*dto.GetTransferRequest)
+		}
+		var arg2 *transfer.GetTransferOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.GetTransferOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_GetTransfer_Call) Return(transferDetailResponse *dto.TransferDetailResponse, err error) *MockITransferLogic_GetTransfer_Call {
+	_c.Call.Return(transferDetailResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_GetTransfer_Call) RunAndReturn(run func(context1 context.Context, getTransferRequest *dto.GetTransferRequest, getTransferOpts *transfer.GetTransferOpts) (*dto.TransferDetailResponse, error)) *MockITransferLogic_GetTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PlaceHold provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) PlaceHold(context1 context.Context, placeHoldRequest *dto.PlaceHoldRequest, holdOpts *transfer.HoldOpts) (*dto.HoldResponse, error) {
+	ret := _mock.Called(context1, placeHoldRequest, holdOpts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PlaceHold")
+	}
+
+	var r0 *dto.HoldResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.PlaceHoldRequest, *transfer.HoldOpts) (*dto.HoldResponse, error)); ok {
+		return returnFunc(context1, placeHoldRequest, holdOpts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.PlaceHoldRequest, *transfer.HoldOpts) *dto.HoldResponse); ok {
+		r0 = returnFunc(context1, placeHoldRequest, holdOpts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.HoldResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dto.PlaceHoldRequest, *transfer.HoldOpts) error); ok {
+		r1 = returnFunc(context1, placeHoldRequest, holdOpts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_PlaceHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PlaceHold'
+type MockITransferLogic_PlaceHold_Call struct {
+	*mock.Call
+}
+
+// PlaceHold is a helper method to define mock.On call
+//   - context1 context.Context
+//   - placeHoldRequest *dto.PlaceHoldRequest
+//   - holdOpts *transfer.HoldOpts
+func (_e *MockITransferLogic_Expecter) PlaceHold(context1 interface{}, placeHoldRequest interface{}, holdOpts interface{}) *MockITransferLogic_PlaceHold_Call {
+	return &MockITransferLogic_PlaceHold_Call{Call: _e.mock.On("PlaceHold", context1, placeHoldRequest, holdOpts)}
+}
+
+func (_c *MockITransferLogic_PlaceHold_Call) Run(run func(context1 context.Context, placeHoldRequest *dto.PlaceHoldRequest, holdOpts *transfer.HoldOpts)) *MockITransferLogic_PlaceHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dto.PlaceHoldRequest
+		if args[1] != nil {
+			arg1 = args[1].(*dto.PlaceHoldRequest)
+		}
+		var arg2 *transfer.HoldOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.HoldOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_PlaceHold_Call) Return(holdResponse *dto.HoldResponse, err error) *MockITransferLogic_PlaceHold_Call {
+	_c.Call.Return(holdResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_PlaceHold_Call) RunAndReturn(run func(context1 context.Context, placeHoldRequest *dto.PlaceHoldRequest, holdOpts *transfer.HoldOpts) (*dto.HoldResponse, error)) *MockITransferLogic_PlaceHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PreviewFees provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) PreviewFees(ctx context.Context, req *dto.PreviewFeesRequest) (*dto.PreviewFeesResponse, error) {
+	ret := _mock.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PreviewFees")
+	}
+
+	var r0 *dto.PreviewFeesResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.PreviewFeesRequest) (*dto.PreviewFeesResponse, error)); ok {
+		return returnFunc(ctx, req)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *dto.PreviewFeesRequest) *dto.PreviewFeesResponse); ok {
+		r0 = returnFunc(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.PreviewFeesResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *dto.PreviewFeesRequest) error); ok {
+		r1 = returnFunc(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_PreviewFees_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PreviewFees'
+type MockITransferLogic_PreviewFees_Call struct {
+	*mock.Call
+}
+
+// PreviewFees is a helper method to define mock.On call
+//   - ctx context.Context
+//   - req *dto.PreviewFeesRequest
+func (_e *MockITransferLogic_Expecter) PreviewFees(ctx interface{}, req interface{}) *MockITransferLogic_PreviewFees_Call {
+	return &MockITransferLogic_PreviewFees_Call{Call: _e.mock.On("PreviewFees", ctx, req)}
+}
+
+func (_c *MockITransferLogic_PreviewFees_Call) Run(run func(ctx context.Context, req *dto.PreviewFeesRequest)) *MockITransferLogic_PreviewFees_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *dto.PreviewFeesRequest
+		if args[1] != nil {
+			arg1 = args[1].(*dto.PreviewFeesRequest)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_PreviewFees_Call) Return(previewFeesResponse *dto.PreviewFeesResponse, err error) *MockITransferLogic_PreviewFees_Call {
+	_c.Call.Return(previewFeesResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_PreviewFees_Call) RunAndReturn(run func(ctx context.Context, req *dto.PreviewFeesRequest) (*dto.PreviewFeesResponse, error)) *MockITransferLogic_PreviewFees_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeIdempotencyKeys provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) PurgeIdempotencyKeys(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeIdempotencyKeys")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_PurgeIdempotencyKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeIdempotencyKeys'
+type MockITransferLogic_PurgeIdempotencyKeys_Call struct {
+	*mock.Call
+}
+
+// PurgeIdempotencyKeys is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockITransferLogic_Expecter) PurgeIdempotencyKeys(ctx interface{}) *MockITransferLogic_PurgeIdempotencyKeys_Call {
+	return &MockITransferLogic_PurgeIdempotencyKeys_Call{Call: _e.mock.On("PurgeIdempotencyKeys", ctx)}
+}
+
+func (_c *MockITransferLogic_PurgeIdempotencyKeys_Call) Run(run func(ctx context.Context)) *MockITransferLogic_PurgeIdempotencyKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_PurgeIdempotencyKeys_Call) Return(n int64, err error) *MockITransferLogic_PurgeIdempotencyKeys_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_PurgeIdempotencyKeys_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *MockITransferLogic_PurgeIdempotencyKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebalanceShards provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) RebalanceShards(ctx context.Context, parentAccountID string) error {
+	ret := _mock.Called(ctx, parentAccountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebalanceShards")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = returnFunc(ctx, parentAccountID)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferLogic_RebalanceShards_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebalanceShards'
+type MockITransferLogic_RebalanceShards_Call struct {
+	*mock.Call
+}
+
+// RebalanceShards is a helper method to define mock.On call
+//   - ctx context.Context
+//   - parentAccountID string
+func (_e *MockITransferLogic_Expecter) RebalanceShards(ctx interface{}, parentAccountID interface{}) *MockITransferLogic_RebalanceShards_Call {
+	return &MockITransferLogic_RebalanceShards_Call{Call: _e.mock.On("RebalanceShards", ctx, parentAccountID)}
+}
+
+func (_c *MockITransferLogic_RebalanceShards_Call) Run(run func(ctx context.Context, parentAccountID string)) *MockITransferLogic_RebalanceShards_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_RebalanceShards_Call) Return(err error) *MockITransferLogic_RebalanceShards_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferLogic_RebalanceShards_Call) RunAndReturn(run func(ctx context.Context, parentAccountID string) error) *MockITransferLogic_RebalanceShards_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseExpiredHolds provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) ReleaseExpiredHolds(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseExpiredHolds")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_ReleaseExpiredHolds_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseExpiredHolds'
+type MockITransferLogic_ReleaseExpiredHolds_Call struct {
+	*mock.Call
+}
+
+// ReleaseExpiredHolds is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockITransferLogic_Expecter) ReleaseExpiredHolds(ctx interface{}) *MockITransferLogic_ReleaseExpiredHolds_Call {
+	return &MockITransferLogic_ReleaseExpiredHolds_Call{Call: _e.mock.On("ReleaseExpiredHolds", ctx)}
+}
+
+func (_c *MockITransferLogic_ReleaseExpiredHolds_Call) Run(run func(ctx context.Context)) *MockITransferLogic_ReleaseExpiredHolds_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_ReleaseExpiredHolds_Call) Return(n int64, err error) *MockITransferLogic_ReleaseExpiredHolds_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_ReleaseExpiredHolds_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *MockITransferLogic_ReleaseExpiredHolds_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseHold provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) ReleaseHold(ctx context.Context, holdID string, opts *transfer.HoldOpts) (*dto.HoldResponse, error) {
+	ret := _mock.Called(ctx, holdID, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseHold")
+	}
+
+	var r0 *dto.HoldResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.HoldOpts) (*dto.HoldResponse, error)); ok {
+		return returnFunc(ctx, holdID, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *transfer.HoldOpts) *dto.HoldResponse); ok {
+		r0 = returnFunc(ctx, holdID, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.HoldResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *transfer.HoldOpts) error); ok {
+		r1 = returnFunc(ctx, holdID, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_ReleaseHold_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseHold'
+type MockITransferLogic_ReleaseHold_Call struct {
+	*mock.Call
+}
+
+// ReleaseHold is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holdID string
+//   - opts *transfer.HoldOpts
+func (_e *MockITransferLogic_Expecter) ReleaseHold(ctx interface{}, holdID interface{}, opts interface{}) *MockITransferLogic_ReleaseHold_Call {
+	return &MockITransferLogic_ReleaseHold_Call{Call: _e.mock.On("ReleaseHold", ctx, holdID, opts)}
+}
+
+func (_c *MockITransferLogic_ReleaseHold_Call) Run(run func(ctx context.Context, holdID string, opts *transfer.HoldOpts)) *MockITransferLogic_ReleaseHold_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *transfer.HoldOpts
+		if args[2] != nil {
+			arg2 = args[2].(*transfer.HoldOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_ReleaseHold_Call) Return(holdResponse *dto.HoldResponse, err error) *MockITransferLogic_ReleaseHold_Call {
+	_c.Call.Return(holdResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_ReleaseHold_Call) RunAndReturn(run func(ctx context.Context, holdID string, opts *transfer.HoldOpts) (*dto.HoldResponse, error)) *MockITransferLogic_ReleaseHold_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RescheduleTransfer provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) RescheduleTransfer(ctx context.Context, transactionID string, req *dto.RescheduleTransferRequest, opts *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error) {
+	ret := _mock.Called(ctx, transactionID, req, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RescheduleTransfer")
+	}
+
+	var r0 *dto.TransferDetailResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *dto.RescheduleTransferRequest, *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error)); ok {
+		return returnFunc(ctx, transactionID, req, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *dto.RescheduleTransferRequest, *transfer.ScheduledTransferOpts) *dto.TransferDetailResponse); ok {
+		r0 = returnFunc(ctx, transactionID, req, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.TransferDetailResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *dto.RescheduleTransferRequest, *transfer.ScheduledTransferOpts) error); ok {
+		r1 = returnFunc(ctx, transactionID, req, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_RescheduleTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RescheduleTransfer'
+type MockITransferLogic_RescheduleTransfer_Call struct {
+	*mock.Call
+}
+
+// RescheduleTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID string
+//   - req *dto.RescheduleTransferRequest
+//   - opts *transfer.ScheduledTransferOpts
+func (_e *MockITransferLogic_Expecter) RescheduleTransfer(ctx interface{}, transactionID interface{}, req interface{}, opts interface{}) *MockITransferLogic_RescheduleTransfer_Call {
+	return &MockITransferLogic_RescheduleTransfer_Call{Call: _e.mock.On("RescheduleTransfer", ctx, transactionID, req, opts)}
+}
+
+func (_c *MockITransferLogic_RescheduleTransfer_Call) Run(run func(ctx context.Context, transactionID string, req *dto.RescheduleTransferRequest, opts *transfer.ScheduledTransferOpts)) *MockITransferLogic_RescheduleTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *dto.RescheduleTransferRequest
+		if args[2] != nil {
+			arg2 = args[2].(*dto.RescheduleTransferRequest)
+		}
+		var arg3 *transfer.ScheduledTransferOpts
+		if args[3] != nil {
+			arg3 = args[3].(*transfer.ScheduledTransferOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_RescheduleTransfer_Call) Return(transferDetailResponse *dto.TransferDetailResponse, err error) *MockITransferLogic_RescheduleTransfer_Call {
+	_c.Call.Return(transferDetailResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_RescheduleTransfer_Call) RunAndReturn(run func(ctx context.Context, transactionID string, req *dto.RescheduleTransferRequest, opts *transfer.ScheduledTransferOpts) (*dto.TransferDetailResponse, error)) *MockITransferLogic_RescheduleTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReverseTransfer provides a mock function for the type MockITransferLogic
+func (_mock *MockITransferLogic) ReverseTransfer(ctx context.Context, transactionID string, req *dto.ReverseTransferRequest, opts *transfer.ReverseTransferOpts) (*dto.CreateTransferResponse, error) {
+	ret := _mock.Called(ctx, transactionID, req, opts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReverseTransfer")
+	}
+
+	var r0 *dto.CreateTransferResponse
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *dto.ReverseTransferRequest, *transfer.ReverseTransferOpts) (*dto.CreateTransferResponse, error)); ok {
+		return returnFunc(ctx, transactionID, req, opts)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *dto.ReverseTransferRequest, *transfer.ReverseTransferOpts) *dto.CreateTransferResponse); ok {
+		r0 = returnFunc(ctx, transactionID, req, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*dto.CreateTransferResponse)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *dto.ReverseTransferRequest, *transfer.ReverseTransferOpts) error); ok {
+		r1 = returnFunc(ctx, transactionID, req, opts)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferLogic_ReverseTransfer_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReverseTransfer'
+type MockITransferLogic_ReverseTransfer_Call struct {
+	*mock.Call
+}
+
+// ReverseTransfer is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID string
+//   - req *dto.ReverseTransferRequest
+//   - opts *transfer.ReverseTransferOpts
+func (_e *MockITransferLogic_Expecter) ReverseTransfer(ctx interface{}, transactionID interface{}, req interface{}, opts interface{}) *MockITransferLogic_ReverseTransfer_Call {
+	return &MockITransferLogic_ReverseTransfer_Call{Call: _e.mock.On("ReverseTransfer", ctx, transactionID, req, opts)}
+}
+
+func (_c *MockITransferLogic_ReverseTransfer_Call) Run(run func(ctx context.Context, transactionID string, req *dto.ReverseTransferRequest, opts *transfer.ReverseTransferOpts)) *MockITransferLogic_ReverseTransfer_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *dto.ReverseTransferRequest
+		if args[2] != nil {
+			arg2 = args[2].(*dto.ReverseTransferRequest)
+		}
+		var arg3 *transfer.ReverseTransferOpts
+		if args[3] != nil {
+			arg3 = args[3].(*transfer.ReverseTransferOpts)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferLogic_ReverseTransfer_Call) Return(createTransferResponse *dto.CreateTransferResponse, err error) *MockITransferLogic_ReverseTransfer_Call {
+	_c.Call.Return(createTransferResponse, err)
+	return _c
+}
+
+func (_c *MockITransferLogic_ReverseTransfer_Call) RunAndReturn(run func(ctx context.Context, transactionID string, req *dto.ReverseTransferRequest, opts *transfer.ReverseTransferOpts) (*dto.CreateTransferResponse, error)) *MockITransferLogic_ReverseTransfer_Call {
+	_c.Call.Return(run)
+	return _c
+}
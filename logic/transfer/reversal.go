@@ -0,0 +1,141 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"slices"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+// reversibleTxTypes are the tx types a reversal may refund. Cross-currency transfers are excluded, since
+// refunding them would need a new FX quote.
+var reversibleTxTypes = []string{
+	string(TxTypeP2PTransfer),
+	string(TxTypeDeposit),
+	string(TxTypeWithdrawal),
+	string(TxTypeCapture),
+}
+
+type ReverseTransferOpts struct {
+	// ClientID is the client asking for the reversal. Only the client that made a transfer may reverse it.
+	ClientID string
+	// UserID is the user asking for the reversal. When set, the user must be allowed to debit the account
+	// the transfer credited, which the refund is taken from.
+	UserID string
+}
+
+// ReverseTransfer refunds all or part of a completed transfer with a REVERSAL transfer linked to it
+// through its parent transaction ID. Without an amount, everything not refunded yet is refunded. Fees
+// charged on the original transfer are not refunded.
+func (l *logicImpl) ReverseTransfer(ctx context.Context, transactionID string, req *dto.ReverseTransferRequest, opts *ReverseTransferOpts) (*dto.CreateTransferResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	parent, err := l.TransferDAO.FindByTransactionID(ctx, transactionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (parent != nil && parent.ClientID != opts.ClientID) {
+		return nil, TransferNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return l.CreateTransfer(ctx, &dto.CreateTransferRequest{
+		Currency:           parent.Currency,
+		Amount:             req.Amount,
+		SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: parent.DestinationAccountID},
+		DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: parent.SourceAccountID},
+		Note:               req.Reason,
+		IdempotencyKey:     req.IdempotencyKey,
+	}, &CreateTransferOpts{
+		TxType:              TxTypeReversal,
+		ClientID:            opts.ClientID,
+		UserID:              opts.UserID,
+		ParentTransactionID: parent.TransactionID,
+	})
+}
+
+// doReversal books req as a refund of its parent transfer: the contra legs of the parent's journal,
+// debiting the account it credited and crediting the account it debited. Reversals charge no fees and
+// are not subject to limits.
+func (l *logicImpl) doReversal(ctx context.Context, req *storage.Transfer) error {
+	parent, err := l.TransferDAO.FindByTransactionID(ctx, req.ParentTransactionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return TransferNotFoundErr
+	}
+	if err != nil {
+		return err
+	}
+	if req.Amount == 0 {
+		req.Amount = parent.Amount - parent.RefundedAmount
+	}
+	if refundErr := checkRefundable(parent, req.Amount); refundErr != nil {
+		return refundErr
+	}
+	if accessErr := l.checkDebitAccess(ctx, req.UserID, parent.DestinationAccountID); accessErr != nil {
+		return accessErr
+	}
+	req.Currency = parent.Currency
+	req.SourceAccountID = parent.DestinationAccountID
+	req.DestinationAccountID = parent.SourceAccountID
+
+	// sharded accounts such as the holding account are refunded through one of their shards
+	debited, findErr := l.resolvePostingAccount(ctx, req.SourceAccountID, req.TransactionID)
+	if findErr != nil {
+		return InvalidSourceAccountErr
+	}
+	credited, findErr := l.resolvePostingAccount(ctx, req.DestinationAccountID, req.TransactionID)
+	if findErr != nil {
+		return InvalidDestinationAccountErr
+	}
+	req.SourceAccount = toAccountInfo(debited)
+	req.DestinationAccount = toAccountInfo(credited)
+
+	accounts := map[string]*storage.Account{
+		debited.AccountID:  debited,
+		credited.AccountID: credited,
+	}
+	legs := []*storage.Transaction{
+		newLeg(req, debited.AccountID, TypeDebit, req.Amount, fmt.Sprintf("Reversal of %s", parent.TransactionID)),
+		newLeg(req, credited.AccountID, TypeCredit, req.Amount, fmt.Sprintf("Reversal of %s", parent.TransactionID)),
+	}
+	return l.commitTransfer(ctx, req, legs, accounts, l.refundParent(ctx, req))
+}
+
+// refundParent returns the step recording req against its parent transfer, to run within the transaction
+// posting req. The parent is locked, so concurrent reversals can never refund more than it moved.
+func (l *logicImpl) refundParent(ctx context.Context, req *storage.Transfer) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		transferDAO := l.TransferDAO.WithTx(tx)
+		parent, err := transferDAO.FindByTransactionIDForUpdate(ctx, req.ParentTransactionID)
+		if err != nil {
+			return err
+		}
+		if err = checkRefundable(parent, req.Amount); err != nil {
+			return err
+		}
+		parent.RefundedAmount += req.Amount
+		parent.Status = TxStatusPARTIALLYREFUNDED
+		if parent.RefundedAmount == parent.Amount {
+			parent.Status = TxStatusREVERSED
+		}
+		parent.UpdatedAt = time.Now()
+		return transferDAO.Save(ctx, parent)
+	}
+}
+
+// checkRefundable checks amount can still be refunded on parent
+func checkRefundable(parent *storage.Transfer, amount int64) error {
+	if parent.Status != TxStatusCOMPLETED && parent.Status != TxStatusPARTIALLYREFUNDED {
+		return TransferNotReversibleErr
+	}
+	if !slices.Contains(reversibleTxTypes, parent.TxType) || parent.DestinationCurrency != "" {
+		return TransferNotReversibleErr
+	}
+	if amount > parent.Amount-parent.RefundedAmount {
+		return RefundAmountExceededErr
+	}
+	return nil
+}
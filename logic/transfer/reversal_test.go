@@ -0,0 +1,192 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_logicImpl_ReverseTransfer(t *testing.T) {
+	td := storagemock.NewMockITransferDAO(t)
+	td.EXPECT().FindByTransactionID(mock.Anything, "tx-parent").Return(&storage.Transfer{TransactionID: "tx-parent", ClientID: "client-b"}, nil).Once()
+
+	l := &logicImpl{TransferDAO: td}
+	_, err := l.ReverseTransfer(context.Background(), "tx-parent", &dto.ReverseTransferRequest{IdempotencyKey: "idempotency-key"}, &ReverseTransferOpts{ClientID: "client-a"})
+	if !errors.Is(err, TransferNotFoundErr) {
+		t.Errorf("ReverseTransfer() error = %v, want %v", err, TransferNotFoundErr)
+	}
+}
+
+func Test_logicImpl_doReversal(t *testing.T) {
+	completedParent := func() *storage.Transfer {
+		return &storage.Transfer{
+			TransactionID:        "tx-parent",
+			TxType:               string(TxTypeP2PTransfer),
+			Status:               TxStatusCOMPLETED,
+			Amount:               1000,
+			RefundedAmount:       300,
+			Currency:             "MYR",
+			SourceAccountID:      "source-account",
+			DestinationAccountID: "destination-account",
+		}
+	}
+
+	tests := []struct {
+		name       string
+		amount     int64
+		userID     string
+		parent     func() *storage.Transfer
+		setupUsers func(ud *storagemock.MockIUserDAO)
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO)
+		wantAmount int64
+		wantErr    error
+	}{
+		{
+			name:    "error - parent not found",
+			parent:  func() *storage.Transfer { return nil },
+			wantErr: TransferNotFoundErr,
+		},
+		{
+			name: "error - parent failed",
+			parent: func() *storage.Transfer {
+				parent := completedParent()
+				parent.Status = TxStatusFAILED
+				return parent
+			},
+			wantErr: TransferNotReversibleErr,
+		},
+		{
+			name: "error - parent already reversed",
+			parent: func() *storage.Transfer {
+				parent := completedParent()
+				parent.Status, parent.RefundedAmount = TxStatusREVERSED, parent.Amount
+				return parent
+			},
+			wantErr: TransferNotReversibleErr,
+		},
+		{
+			name: "error - parent is cross-currency",
+			parent: func() *storage.Transfer {
+				parent := completedParent()
+				parent.DestinationCurrency = "USD"
+				return parent
+			},
+			wantErr: TransferNotReversibleErr,
+		},
+		{
+			name: "error - parent is itself a reversal",
+			parent: func() *storage.Transfer {
+				parent := completedParent()
+				parent.TxType = string(TxTypeReversal)
+				return parent
+			},
+			wantErr: TransferNotReversibleErr,
+		},
+		{
+			name:    "error - more than what is left to refund",
+			amount:  701,
+			parent:  completedParent,
+			wantErr: RefundAmountExceededErr,
+		},
+		{
+			name:   "error - user cannot debit the account refunded from",
+			userID: "user-1",
+			parent: completedParent,
+			setupUsers: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(&storage.User{UserID: "user-1", Status: storage.UserStatusActive}, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "destination-account").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: AccountAccessDeniedErr,
+		},
+		{
+			name:   "happy path - refunds the rest without an amount",
+			parent: completedParent,
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				for _, id := range []string{"destination-account", "source-account"} {
+					ad.EXPECT().FindByParentAccountID(mock.Anything, id).Return(nil, nil).Once()
+					ad.EXPECT().FindByAccountID(mock.Anything, id).Return(&storage.Account{AccountID: id, Currency: "MYR", Balance: 1000}, nil).Once()
+				}
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			},
+			wantAmount: 700,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			ud := storagemock.NewMockIUserDAO(t)
+			if tt.setupUsers != nil {
+				tt.setupUsers(ud)
+			}
+			if parent := tt.parent(); parent != nil {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-parent").Return(parent, nil).Once()
+			} else {
+				td.EXPECT().FindByTransactionID(mock.Anything, "tx-parent").Return(nil, gorm.ErrRecordNotFound).Once()
+			}
+			if tt.setupMocks != nil {
+				tt.setupMocks(ad, td)
+			}
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, UserDAO: ud}
+			req := &storage.Transfer{TransactionID: "tx-123", Amount: tt.amount, ParentTransactionID: "tx-parent", UserID: tt.userID}
+			err := l.doReversal(context.Background(), req)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("doReversal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (req.Amount != tt.wantAmount || req.SourceAccountID != "destination-account" || req.DestinationAccountID != "source-account") {
+				t.Errorf("reversal = %d from %s to %s, want %d from destination-account to source-account",
+					req.Amount, req.SourceAccountID, req.DestinationAccountID, tt.wantAmount)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_refundParent(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       int64
+		refunded     int64
+		wantStatus   string
+		wantRefunded int64
+		wantErr      error
+	}{
+		{name: "partial refund", amount: 400, wantStatus: TxStatusPARTIALLYREFUNDED, wantRefunded: 400},
+		{name: "refunding the rest reverses the parent", amount: 600, refunded: 400, wantStatus: TxStatusREVERSED, wantRefunded: 1000},
+		{name: "refunded concurrently in the meantime", amount: 700, refunded: 400, wantErr: RefundAmountExceededErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := storagemock.NewMockITransferDAO(t)
+			td.EXPECT().WithTx(mock.Anything).Return(td).Once()
+			status := TxStatusCOMPLETED
+			if tt.refunded > 0 {
+				status = TxStatusPARTIALLYREFUNDED
+			}
+			td.EXPECT().FindByTransactionIDForUpdate(mock.Anything, "tx-parent").Return(&storage.Transfer{
+				TransactionID:  "tx-parent",
+				TxType:         string(TxTypeP2PTransfer),
+				Status:         status,
+				Amount:         1000,
+				RefundedAmount: tt.refunded,
+			}, nil).Once()
+			if tt.wantErr == nil {
+				td.EXPECT().Save(mock.Anything, mock.MatchedBy(func(parent *storage.Transfer) bool {
+					return parent.Status == tt.wantStatus && parent.RefundedAmount == tt.wantRefunded
+				})).Return(nil).Once()
+			}
+
+			l := &logicImpl{TransferDAO: td}
+			err := l.refundParent(context.Background(), &storage.Transfer{Amount: tt.amount, ParentTransactionID: "tx-parent"})(nil)
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("refundParent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
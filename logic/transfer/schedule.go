@@ -0,0 +1,138 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+const scheduledBatchSize = 100
+
+var (
+	InvalidExecutionDateErr = apperr.New(apperr.CodeInvalidExecutionDate)
+	TransferNotScheduledErr = apperr.New(apperr.CodeTransferNotScheduled)
+)
+
+type ScheduledTransferOpts struct {
+	// ClientID is the client acting on the transfer. Only the client that scheduled a transfer may change it.
+	ClientID string
+}
+
+// checkSchedulable checks req can be scheduled. Only transfers between accounts without an FX quote can be,
+// since quotes expire long before any execution date. The accounts are checked up front so obvious
+// mistakes are not found out only when the transfer is due; everything else is checked on execution.
+func (l *logicImpl) checkSchedulable(ctx context.Context, req *dto.CreateTransferRequest, opts *CreateTransferOpts) error {
+	if opts.TxType != TxTypeP2PTransfer || req.QuoteID != "" {
+		return InvalidExecutionDateErr
+	}
+	if err := l.checkExecutionDate(*req.ExecuteAt); err != nil {
+		return err
+	}
+	sourceAcc, findErr := l.AccountDAO.FindByAccountID(ctx, req.SourceAccount.Number)
+	if findErr != nil {
+		return InvalidSourceAccountErr
+	}
+	destAcc, findErr := l.AccountDAO.FindByAccountID(ctx, req.DestinationAccount.Number)
+	if findErr != nil {
+		return InvalidDestinationAccountErr
+	}
+	if sourceAcc.Currency != req.Currency || destAcc.Currency != req.Currency {
+		return InvalidCurrencyErr
+	}
+	// checked again when the transfer is executed, the user may have lost access by then
+	return l.checkDebitAccess(ctx, opts.UserID, sourceAcc.AccountID)
+}
+
+func (l *logicImpl) checkExecutionDate(executeAt time.Time) error {
+	now := time.Now()
+	if !executeAt.After(now) || (l.maxScheduleAhead > 0 && executeAt.After(now.Add(l.maxScheduleAhead))) {
+		return InvalidExecutionDateErr
+	}
+	return nil
+}
+
+// ExecuteDueTransfers executes every scheduled transfer that is due, and every transfer left PROCESSING
+// past its claim, and returns how many it executed, whether they completed or failed. Transfers are
+// claimed for ProcessingLease before they are executed, so any number of instances may run it at the same
+// time. A transfer whose journal was committed is COMPLETED, so executing one again never pays twice.
+func (l *logicImpl) ExecuteDueTransfers(ctx context.Context) (int64, error) {
+	var executed int64
+	for {
+		due, err := l.TransferDAO.ClaimDue(ctx, time.Now(), l.processingLease, scheduledBatchSize)
+		if err != nil {
+			return executed, err
+		}
+		for _, trf := range due {
+			// a transfer takes effect when it is executed, not when it was submitted
+			now := time.Now()
+			trf.ValuedAt = &now
+			// failures are recorded on the transfer itself
+			_ = l.execute(ctx, trf, &CreateTransferOpts{
+				TxType:              TxType(trf.TxType),
+				ClientID:            trf.ClientID,
+				HoldID:              trf.HoldID,
+				ParentTransactionID: trf.ParentTransactionID,
+				UserID:              trf.UserID,
+			})
+			executed++
+		}
+		if len(due) < scheduledBatchSize {
+			return executed, nil
+		}
+	}
+}
+
+// CancelTransfer cancels a scheduled transfer that is not due yet
+func (l *logicImpl) CancelTransfer(ctx context.Context, transactionID string, opts *ScheduledTransferOpts) (*dto.TransferDetailResponse, error) {
+	return l.updateScheduled(ctx, transactionID, opts, func(trf *storage.Transfer) error {
+		trf.Status = TxStatusCANCELLED
+		return nil
+	})
+}
+
+// RescheduleTransfer moves the execution date of a scheduled transfer that is not due yet
+func (l *logicImpl) RescheduleTransfer(ctx context.Context, transactionID string, req *dto.RescheduleTransferRequest, opts *ScheduledTransferOpts) (*dto.TransferDetailResponse, error) {
+	if err := l.checkExecutionDate(req.ExecuteAt); err != nil {
+		return nil, err
+	}
+	return l.updateScheduled(ctx, transactionID, opts, func(trf *storage.Transfer) error {
+		trf.ExecuteAt = &req.ExecuteAt
+		return nil
+	})
+}
+
+// updateScheduled applies update to a transfer that is still SCHEDULED. The transfer is locked meanwhile,
+// so it cannot be claimed for execution halfway through.
+func (l *logicImpl) updateScheduled(ctx context.Context, transactionID string, opts *ScheduledTransferOpts, update func(trf *storage.Transfer) error) (*dto.TransferDetailResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+	var trf *storage.Transfer
+	updateErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		var err error
+		transferDAO := l.TransferDAO.WithTx(tx)
+		trf, err = transferDAO.FindByTransactionIDForUpdate(ctx, transactionID)
+		if errors.Is(err, gorm.ErrRecordNotFound) || (trf != nil && trf.ClientID != opts.ClientID) {
+			return TransferNotFoundErr
+		}
+		if err != nil {
+			return err
+		}
+		if trf.Status != TxStatusSCHEDULED {
+			return TransferNotScheduledErr
+		}
+		if err = update(trf); err != nil {
+			return err
+		}
+		trf.UpdatedAt = time.Now()
+		return transferDAO.Save(ctx, trf)
+	})
+	if updateErr != nil {
+		return nil, updateErr
+	}
+	return mapTransferStorageToDetailResponse(trf, nil), nil
+}
@@ -0,0 +1,160 @@
+package transfer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func Test_logicImpl_CreateTransfer_scheduled(t *testing.T) {
+	inAWeek := time.Now().Add(7 * 24 * time.Hour)
+	inTwoYears := time.Now().Add(2 * 365 * 24 * time.Hour)
+	newReq := func(executeAt time.Time) *dto.CreateTransferRequest {
+		return &dto.CreateTransferRequest{
+			Currency:           "MYR",
+			Amount:             1000,
+			SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: "source-account"},
+			DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: "destination-account"},
+			IdempotencyKey:     "idempotency-key",
+			ExecuteAt:          &executeAt,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		req        *dto.CreateTransferRequest
+		txType     TxType
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO)
+		wantErr    error
+	}{
+		{
+			name:   "happy path - stored as scheduled without executing",
+			req:    newReq(inAWeek),
+			txType: TxTypeP2PTransfer,
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR"}, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "MYR"}, nil).Once()
+				td.EXPECT().Create(mock.Anything, mock.MatchedBy(func(trf *storage.Transfer) bool {
+					return trf.Status == TxStatusSCHEDULED && trf.ExecuteAt.Equal(inAWeek)
+				})).Return(nil).Once()
+			},
+		},
+		{
+			name:    "error - too far ahead",
+			req:     newReq(inTwoYears),
+			txType:  TxTypeP2PTransfer,
+			wantErr: InvalidExecutionDateErr,
+		},
+		{
+			name: "error - with an FX quote",
+			req: func() *dto.CreateTransferRequest {
+				req := newReq(inAWeek)
+				req.QuoteID = "quote-1"
+				return req
+			}(),
+			txType:  TxTypeP2PTransfer,
+			wantErr: InvalidExecutionDateErr,
+		},
+		{
+			name:    "error - deposits cannot be scheduled",
+			req:     newReq(inAWeek),
+			txType:  TxTypeDeposit,
+			wantErr: InvalidExecutionDateErr,
+		},
+		{
+			name:   "error - unknown destination account",
+			req:    newReq(inAWeek),
+			txType: TxTypeP2PTransfer,
+			setupMocks: func(ad *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR"}, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: InvalidDestinationAccountErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			td.EXPECT().FindByReferenceID(mock.Anything, "client-a", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+			if tt.setupMocks != nil {
+				tt.setupMocks(ad, td)
+			}
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, maxScheduleAhead: 365 * 24 * time.Hour}
+			got, err := l.CreateTransfer(context.Background(), tt.req, &CreateTransferOpts{TxType: tt.txType, ClientID: "client-a"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CreateTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Status != TxStatusSCHEDULED || got.ExecuteAt == nil) {
+				t.Errorf("CreateTransfer() = %s at %v, want SCHEDULED with an execution date", got.Status, got.ExecuteAt)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_ExecuteDueTransfers(t *testing.T) {
+	td := storagemock.NewMockITransferDAO(t)
+	ad := storagemock.NewMockIAccountDAO(t)
+	claimedUntil := time.Now().Add(time.Minute)
+	td.EXPECT().ClaimDue(mock.Anything, mock.Anything, time.Minute, scheduledBatchSize).Return([]*storage.Transfer{{
+		TransactionID:        "tx-123",
+		TxType:               string(TxTypeP2PTransfer),
+		ClientID:             "client-a",
+		Status:               TxStatusPROCESSING,
+		Amount:               1000,
+		Currency:             "MYR",
+		SourceAccountID:      "source-account",
+		DestinationAccountID: "destination-account",
+		ClaimedUntil:         &claimedUntil,
+	}}, nil).Once()
+	// the source account was closed in the meantime, so the transfer fails and is recorded as such
+	ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(nil, gorm.ErrRecordNotFound).Once()
+	td.EXPECT().SaveClaimed(mock.Anything, mock.MatchedBy(func(trf *storage.Transfer) bool {
+		return trf.Status == TxStatusFAILED && trf.StatusReason == ReasonInvalidSourceAccount && trf.ValuedAt != nil && trf.ClaimedUntil == nil
+	}), &claimedUntil).Return(nil).Once()
+
+	l := &logicImpl{TransferDAO: td, AccountDAO: ad, processingLease: time.Minute}
+	got, err := l.ExecuteDueTransfers(context.Background())
+	if err != nil || got != 1 {
+		t.Errorf("ExecuteDueTransfers() = %v, %v, want 1, nil", got, err)
+	}
+}
+
+func Test_logicImpl_CancelTransfer(t *testing.T) {
+	tests := []struct {
+		name    string
+		found   *storage.Transfer
+		wantErr error
+	}{
+		{name: "happy path - cancels a scheduled transfer", found: &storage.Transfer{TransactionID: "tx-123", ClientID: "client-a", Status: TxStatusSCHEDULED}},
+		{name: "error - already being executed", found: &storage.Transfer{TransactionID: "tx-123", ClientID: "client-a", Status: TxStatusPROCESSING}, wantErr: TransferNotScheduledErr},
+		{name: "error - scheduled by another client", found: &storage.Transfer{TransactionID: "tx-123", ClientID: "client-b", Status: TxStatusSCHEDULED}, wantErr: TransferNotFoundErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := storagemock.NewMockITransferDAO(t)
+			inTransaction(td)
+			td.EXPECT().WithTx(mock.Anything).Return(td).Once()
+			td.EXPECT().FindByTransactionIDForUpdate(mock.Anything, "tx-123").Return(tt.found, nil).Once()
+			if tt.wantErr == nil {
+				td.EXPECT().Save(mock.Anything, mock.MatchedBy(func(trf *storage.Transfer) bool {
+					return trf.Status == TxStatusCANCELLED
+				})).Return(nil).Once()
+			}
+
+			l := &logicImpl{TransferDAO: td}
+			_, err := l.CancelTransfer(context.Background(), "tx-123", &ScheduledTransferOpts{ClientID: "client-a"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("CancelTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
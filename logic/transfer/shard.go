@@ -0,0 +1,154 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"hash/fnv"
+	"sort"
+	"sync/atomic"
+	"time"
+	"wallet/storage"
+)
+
+// ShardSelection decides which shard sub-account of a sharded account a transfer posts to.
+type ShardSelection string
+
+const (
+	// ShardByHash picks the shard from a hash of the transaction ID, so retries land on the same shard.
+	ShardByHash ShardSelection = "HASH"
+	// ShardRoundRobin cycles through the shards in account ID order.
+	ShardRoundRobin ShardSelection = "ROUND_ROBIN"
+
+	TxTypeShardSweep TxType = "SWEEP"
+)
+
+// resolvePostingAccount returns the account a leg for accountID should be booked against. Sharded
+// accounts are backed by sub-accounts pointing to them through parent_account_id; one of those is
+// picked per transfer so concurrent transfers spread over several rows. Unsharded accounts are
+// returned as is.
+func (l *logicImpl) resolvePostingAccount(ctx context.Context, accountID string, transactionID string) (*storage.Account, error) {
+	shards, err := l.AccountDAO.FindByParentAccountID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if len(shards) == 0 {
+		return l.AccountDAO.FindByAccountID(ctx, accountID)
+	}
+	return shards[l.pickShard(len(shards), transactionID)], nil
+}
+
+func (l *logicImpl) pickShard(n int, transactionID string) int {
+	if l.shardSelection == ShardRoundRobin {
+		return int(atomic.AddUint64(&l.roundRobin, 1) % uint64(n))
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(transactionID))
+	return int(h.Sum32() % uint32(n))
+}
+
+// RebalanceShards evens out the balances of the shards backing parentAccountID. Whatever is still held
+// on the parent itself is swept into the shards too, so after a sweep the parent holds nothing and
+// every shard holds the same amount, give or take one minor unit.
+//
+// The parent and its shards are locked in ascending account ID order, the order postJournal locks in, and
+// the sweep is planned from the locked rows, so transfers posting to a shard meanwhile cannot make it move
+// funds that are no longer there.
+func (l *logicImpl) RebalanceShards(ctx context.Context, parentAccountID string) error {
+	shards, err := l.AccountDAO.FindByParentAccountID(ctx, parentAccountID)
+	if err != nil {
+		return err
+	}
+	if len(shards) == 0 {
+		return nil
+	}
+	accountIDs := []string{parentAccountID}
+	for _, shard := range shards {
+		accountIDs = append(accountIDs, shard.AccountID)
+	}
+	sort.Strings(accountIDs)
+
+	return l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		accountDAO := l.AccountDAO.WithTx(tx)
+		accounts := make(map[string]*storage.Account, len(accountIDs))
+		for _, accountID := range accountIDs {
+			locked, lockErr := accountDAO.FindByAccountIDForUpdate(ctx, accountID)
+			if lockErr != nil {
+				return fmt.Errorf("account %s lock failed: %w", accountID, lockErr)
+			}
+			accounts[accountID] = locked
+		}
+		parent := accounts[parentAccountID]
+		lockedShards := make([]*storage.Account, len(shards))
+		for i, shard := range shards {
+			lockedShards[i] = accounts[shard.AccountID]
+		}
+
+		now := time.Now()
+		sweep := &storage.Transfer{
+			Type:                 "INTRA",
+			TxType:               string(TxTypeShardSweep),
+			TransactionID:        uuid.New().String(),
+			Status:               TxStatusPROCESSING,
+			Currency:             parent.Currency,
+			SourceAccountID:      parentAccountID,
+			DestinationAccountID: parentAccountID,
+			Note:                 "Shard rebalancing",
+			CreatedAt:            now,
+			ValuedAt:             &now,
+			UpdatedAt:            now,
+		}
+		sweep.ReferenceID = sweep.TransactionID
+		legs := planShardRebalance(sweep, parent, lockedShards)
+		if len(legs) == 0 {
+			return nil
+		}
+		for _, leg := range legs {
+			if leg.Type == TypeCredit {
+				sweep.Amount += leg.Amount
+			}
+		}
+		sweep.Properties, _ = json.Marshal(map[string]interface{}{"shards": len(shards)})
+
+		if postErr := l.postJournal(ctx, tx, legs, accounts); postErr != nil {
+			return postErr
+		}
+		sweep.Status = TxStatusCOMPLETED
+		return l.TransferDAO.WithTx(tx).Create(ctx, sweep)
+	})
+}
+
+// planShardRebalance returns the legs moving funds from accounts above their target to accounts below it.
+func planShardRebalance(sweep *storage.Transfer, parent *storage.Account, shards []*storage.Account) []*storage.Transaction {
+	total := parent.Balance
+	for _, shard := range shards {
+		total += shard.Balance
+	}
+	n := int64(len(shards))
+	target, remainder := total/n, total%n
+	if remainder < 0 {
+		target, remainder = target-1, remainder+n
+	}
+
+	var legs []*storage.Transaction
+	addLeg := func(acc *storage.Account, want int64) {
+		diff := want - acc.Balance
+		switch {
+		case diff > 0:
+			legs = append(legs, newLeg(sweep, acc.AccountID, TypeCredit, diff, fmt.Sprintf("Shard rebalancing of %s", parent.AccountID)))
+		case diff < 0:
+			legs = append(legs, newLeg(sweep, acc.AccountID, TypeDebit, -diff, fmt.Sprintf("Shard rebalancing of %s", parent.AccountID)))
+		}
+	}
+	addLeg(parent, 0)
+	for i, shard := range shards {
+		want := target
+		if int64(i) < remainder {
+			want++
+		}
+		addLeg(shard, want)
+	}
+	return legs
+}
@@ -0,0 +1,132 @@
+package transfer
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+)
+
+func Test_logicImpl_resolvePostingAccount(t *testing.T) {
+	shards := []*storage.Account{
+		{AccountID: "1000000001-01"},
+		{AccountID: "1000000001-02"},
+		{AccountID: "1000000001-03"},
+	}
+
+	t.Run("unsharded account is returned as is", func(t *testing.T) {
+		mc := storagemock.NewMockIAccountDAO(t)
+		mc.On("FindByParentAccountID", context.Background(), "12345678").Return([]*storage.Account{}, nil).Once()
+		mc.On("FindByAccountID", context.Background(), "12345678").Return(&storage.Account{AccountID: "12345678"}, nil).Once()
+
+		l := &logicImpl{AccountDAO: mc}
+		got, err := l.resolvePostingAccount(context.Background(), "12345678", "tx-1")
+		if err != nil || got.AccountID != "12345678" {
+			t.Errorf("resolvePostingAccount() = %v, %v, want 12345678", got, err)
+		}
+	})
+
+	t.Run("hash selection is stable per transaction", func(t *testing.T) {
+		mc := storagemock.NewMockIAccountDAO(t)
+		mc.On("FindByParentAccountID", context.Background(), "1000000001").Return(shards, nil).Times(2)
+
+		l := &logicImpl{AccountDAO: mc, shardSelection: ShardByHash}
+		first, _ := l.resolvePostingAccount(context.Background(), "1000000001", "tx-1")
+		second, _ := l.resolvePostingAccount(context.Background(), "1000000001", "tx-1")
+		if first.AccountID != second.AccountID {
+			t.Errorf("resolvePostingAccount() picked %v then %v for the same transaction", first.AccountID, second.AccountID)
+		}
+	})
+
+	t.Run("round robin cycles through every shard", func(t *testing.T) {
+		mc := storagemock.NewMockIAccountDAO(t)
+		mc.On("FindByParentAccountID", context.Background(), "1000000001").Return(shards, nil).Times(3)
+
+		l := &logicImpl{AccountDAO: mc, shardSelection: ShardRoundRobin}
+		seen := map[string]bool{}
+		for i := 0; i < 3; i++ {
+			got, _ := l.resolvePostingAccount(context.Background(), "1000000001", "tx-1")
+			seen[got.AccountID] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("resolvePostingAccount() used %d shards, want 3", len(seen))
+		}
+	})
+}
+
+func Test_planShardRebalance(t *testing.T) {
+	sweep := &storage.Transfer{TransactionID: "sweep-1", Currency: "MYR"}
+	parent := &storage.Account{AccountID: "1000000001", Balance: 1000}
+	shards := []*storage.Account{
+		{AccountID: "1000000001-01", Balance: -400},
+		{AccountID: "1000000001-02", Balance: 300},
+		{AccountID: "1000000001-03", Balance: 101},
+	}
+
+	legs := planShardRebalance(sweep, parent, shards)
+	if err := storage.ValidateJournal(legs); err != nil {
+		t.Fatalf("planShardRebalance() produced unbalanced journal: %v", err)
+	}
+
+	deltas := netBalanceDeltas(legs)
+	after := map[string]int64{parent.AccountID: parent.Balance + deltas[parent.AccountID]}
+	for _, shard := range shards {
+		after[shard.AccountID] = shard.Balance + deltas[shard.AccountID]
+	}
+	want := map[string]int64{
+		"1000000001":    0,
+		"1000000001-01": 334,
+		"1000000001-02": 334,
+		"1000000001-03": 333,
+	}
+	for id, balance := range want {
+		if after[id] != balance {
+			t.Errorf("planShardRebalance() %s ends at %d, want %d", id, after[id], balance)
+		}
+	}
+}
+
+func Test_logicImpl_RebalanceShards(t *testing.T) {
+	ad := storagemock.NewMockIAccountDAO(t)
+	td := storagemock.NewMockITransferDAO(t)
+	txd := storagemock.NewMockITransactionDAO(t)
+	// the shards as read before the transaction are already out of date
+	ad.EXPECT().FindByParentAccountID(mock.Anything, "1000000001").Return([]*storage.Account{
+		{AccountID: "1000000001-02", Currency: "MYR", Balance: 0},
+		{AccountID: "1000000001-01", Currency: "MYR", Balance: 0},
+	}, nil).Once()
+	td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn, _ ...*sql.TxOptions) error {
+		return fn(nil)
+	}).Once()
+	ad.EXPECT().WithTx(mock.Anything).Return(ad).Twice()
+	locked := map[string]int64{"1000000001": 0, "1000000001-01": 600, "1000000001-02": 200}
+	var lockOrder []string
+	ad.EXPECT().FindByAccountIDForUpdate(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, accountID string) (*storage.Account, error) {
+		lockOrder = append(lockOrder, accountID)
+		return &storage.Account{AccountID: accountID, Currency: "MYR", Balance: locked[accountID]}, nil
+	}).Times(3)
+	txd.EXPECT().WithTx(mock.Anything).Return(txd).Once()
+	txd.EXPECT().CreateJournal(mock.Anything, mock.Anything).Return(nil).Once()
+	ad.EXPECT().UpdateBalance(mock.Anything, mock.MatchedBy(func(acc *storage.Account) bool {
+		return acc.AccountID == "1000000001-01"
+	}), int64(-200)).Return(nil).Once()
+	ad.EXPECT().UpdateBalance(mock.Anything, mock.MatchedBy(func(acc *storage.Account) bool {
+		return acc.AccountID == "1000000001-02"
+	}), int64(200)).Return(nil).Once()
+	td.EXPECT().WithTx(mock.Anything).Return(td).Once()
+	td.EXPECT().Create(mock.Anything, mock.MatchedBy(func(sweep *storage.Transfer) bool {
+		return sweep.Status == TxStatusCOMPLETED && sweep.Amount == 200 && sweep.Currency == "MYR"
+	})).Return(nil).Once()
+
+	l := &logicImpl{AccountDAO: ad, TransferDAO: td, TransactionDAO: txd}
+	if err := l.RebalanceShards(context.Background(), "1000000001"); err != nil {
+		t.Fatalf("RebalanceShards() error = %v", err)
+	}
+	if want := []string{"1000000001", "1000000001-01", "1000000001-02"}; !reflect.DeepEqual(lockOrder, want) {
+		t.Errorf("RebalanceShards() locked %v, want %v", lockOrder, want)
+	}
+}
@@ -0,0 +1,86 @@
+package transfer
+
+import (
+	"errors"
+	"wallet/apperr"
+	"wallet/logic/limit"
+	"wallet/storage"
+)
+
+// Reason codes stored in transfer.status_reason when a transfer fails. They are the error codes the
+// transfer was rejected with.
+const (
+	ReasonInsufficientBalance       = string(apperr.CodeInsufficientBalance)
+	ReasonInvalidAmount             = string(apperr.CodeInvalidAmount)
+	ReasonInvalidCurrency           = string(apperr.CodeInvalidCurrency)
+	ReasonInvalidSourceAccount      = string(apperr.CodeInvalidSourceAccount)
+	ReasonInvalidDestinationAccount = string(apperr.CodeInvalidDestinationAccount)
+	ReasonIdempotencyKeyReused      = string(apperr.CodeIdempotencyKeyReused)
+	ReasonFxQuoteNotFound           = string(apperr.CodeFxQuoteNotFound)
+	ReasonFxQuoteExpired            = string(apperr.CodeFxQuoteExpired)
+	ReasonFxQuoteMismatch           = string(apperr.CodeFxQuoteMismatch)
+	ReasonHoldNotFound              = string(apperr.CodeHoldNotFound)
+	ReasonHoldNotActive             = string(apperr.CodeHoldNotActive)
+	ReasonHoldAmountExceeded        = string(apperr.CodeHoldAmountExceeded)
+	ReasonTransferNotFound          = string(apperr.CodeTransferNotFound)
+	ReasonTransferNotReversible     = string(apperr.CodeTransferNotReversible)
+	ReasonRefundAmountExceeded      = string(apperr.CodeRefundAmountExceeded)
+	ReasonAccountClosed             = string(apperr.CodeAccountClosed)
+	ReasonAccountDebitFrozen        = string(apperr.CodeAccountDebitFrozen)
+	ReasonAccountCreditFrozen       = string(apperr.CodeAccountCreditFrozen)
+	ReasonAccountAccessDenied       = string(apperr.CodeAccountAccessDenied)
+	ReasonAmountBelowMinimum        = string(apperr.CodeAmountBelowMinimum)
+	ReasonAmountAboveMaximum        = string(apperr.CodeAmountAboveMaximum)
+	ReasonCumulativeLimitExceeded   = string(apperr.CodeCumulativeLimitExceeded)
+	ReasonVelocityLimitExceeded     = string(apperr.CodeVelocityLimitExceeded)
+	ReasonBalanceLimitExceeded      = string(apperr.CodeBalanceLimitExceeded)
+	ReasonInternalError             = string(apperr.CodeInternal)
+)
+
+var reasonCodes = map[error]string{
+	InsufficientBalanceErr:           ReasonInsufficientBalance,
+	InvalidAmountErr:                 ReasonInvalidAmount,
+	InvalidCurrencyErr:               ReasonInvalidCurrency,
+	InvalidSourceAccountErr:          ReasonInvalidSourceAccount,
+	InvalidDestinationAccountErr:     ReasonInvalidDestinationAccount,
+	IdempotencyKeyReusedErr:          ReasonIdempotencyKeyReused,
+	FxQuoteNotFoundErr:               ReasonFxQuoteNotFound,
+	FxQuoteExpiredErr:                ReasonFxQuoteExpired,
+	FxQuoteMismatchErr:               ReasonFxQuoteMismatch,
+	HoldNotFoundErr:                  ReasonHoldNotFound,
+	HoldNotActiveErr:                 ReasonHoldNotActive,
+	HoldAmountExceededErr:            ReasonHoldAmountExceeded,
+	TransferNotFoundErr:              ReasonTransferNotFound,
+	TransferNotReversibleErr:         ReasonTransferNotReversible,
+	RefundAmountExceededErr:          ReasonRefundAmountExceeded,
+	AccountClosedErr:                 ReasonAccountClosed,
+	AccountDebitFrozenErr:            ReasonAccountDebitFrozen,
+	AccountCreditFrozenErr:           ReasonAccountCreditFrozen,
+	AccountAccessDeniedErr:           ReasonAccountAccessDenied,
+	limit.AmountBelowMinimumErr:      ReasonAmountBelowMinimum,
+	limit.AmountAboveMaximumErr:      ReasonAmountAboveMaximum,
+	limit.CumulativeLimitExceededErr: ReasonCumulativeLimitExceeded,
+	limit.VelocityLimitExceededErr:   ReasonVelocityLimitExceeded,
+	limit.BalanceLimitExceededErr:    ReasonBalanceLimitExceeded,
+}
+
+// ReasonCode returns the standard reason code for err. Errors outside PossibleErrors are INTERNAL_ERROR.
+func ReasonCode(err error) string {
+	for _, e := range PossibleErrors {
+		if errors.Is(err, e) {
+			return reasonCodes[e]
+		}
+	}
+	return ReasonInternalError
+}
+
+// failureErr returns the error a failed transfer was rejected with, so that replaying its idempotency key
+// fails the same way
+func failureErr(trf *storage.Transfer) error {
+	for err, code := range reasonCodes {
+		if code == trf.StatusReason {
+			return err
+		}
+	}
+	return errors.New(trf.StatusReasonDescription)
+}
@@ -0,0 +1,45 @@
+package transfer
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"wallet/storage"
+)
+
+func TestReasonCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "insufficient balance", err: InsufficientBalanceErr, want: ReasonInsufficientBalance},
+		{name: "invalid amount", err: InvalidAmountErr, want: ReasonInvalidAmount},
+		{name: "invalid currency", err: InvalidCurrencyErr, want: ReasonInvalidCurrency},
+		{name: "invalid source account", err: InvalidSourceAccountErr, want: ReasonInvalidSourceAccount},
+		{name: "invalid destination account", err: InvalidDestinationAccountErr, want: ReasonInvalidDestinationAccount},
+		{name: "wrapped error", err: fmt.Errorf("posting leg: %w", InsufficientBalanceErr), want: ReasonInsufficientBalance},
+		{name: "unknown error", err: errors.New("connection reset"), want: ReasonInternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReasonCode(tt.err); got != tt.want {
+				t.Errorf("ReasonCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_failureErr(t *testing.T) {
+	for _, err := range PossibleErrors {
+		trf := &storage.Transfer{StatusReason: ReasonCode(err), StatusReasonDescription: err.Error()}
+		if got := failureErr(trf); !errors.Is(got, err) {
+			t.Errorf("failureErr(%s) = %v, want %v", trf.StatusReason, got, err)
+		}
+	}
+
+	trf := &storage.Transfer{StatusReason: ReasonInternalError, StatusReasonDescription: "connection reset"}
+	if got := failureErr(trf); got == nil || got.Error() != "connection reset" {
+		t.Errorf("failureErr(%s) = %v, want connection reset", trf.StatusReason, got)
+	}
+}
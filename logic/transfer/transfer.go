@@ -7,31 +7,79 @@ import (
 	"fmt"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"log"
+	"sort"
 	"time"
+	"wallet/apperr"
+	"wallet/currency"
 	"wallet/dto"
+	"wallet/logic/fee"
+	"wallet/logic/limit"
 	"wallet/storage"
 	"wallet/util"
 )
 
+// Transfer statuses. A transfer is SCHEDULED while it waits for its execution date, PROCESSING from when it
+// is accepted or due until it has been executed, and ends up COMPLETED or FAILED, or CANCELLED if it was
+// cancelled before it was due. A COMPLETED transfer that was partly refunded is
+// PARTIALLY_REFUNDED, and one that was refunded in full is REVERSED.
 const (
-	TxStatusPROCESSING = "PROCESSING"
-	TxStatusCOMPLETED  = "COMPLETED"
-	TypeDebit          = "debit"
-	TypeCredit         = "credit"
+	TxStatusSCHEDULED         = "SCHEDULED"
+	TxStatusPROCESSING        = "PROCESSING"
+	TxStatusCOMPLETED         = "COMPLETED"
+	TxStatusFAILED            = "FAILED"
+	TxStatusCANCELLED         = "CANCELLED"
+	TxStatusPARTIALLYREFUNDED = "PARTIALLY_REFUNDED"
+	TxStatusREVERSED          = "REVERSED"
+	TypeDebit                 = storage.TransactionTypeDebit
+	TypeCredit                = storage.TransactionTypeCredit
 )
 
 var (
-	InsufficientBalanceErr       = errors.New("insufficient balance")
-	InvalidAmountErr             = errors.New("invalid amount")
-	InvalidCurrencyErr           = errors.New("invalid currency")
-	InvalidSourceAccountErr      = errors.New("invalid source account")
-	InvalidDestinationAccountErr = errors.New("invalid destination account")
+	InsufficientBalanceErr       = apperr.New(apperr.CodeInsufficientBalance)
+	InvalidAmountErr             = apperr.New(apperr.CodeInvalidAmount)
+	InvalidCurrencyErr           = apperr.New(apperr.CodeInvalidCurrency)
+	InvalidSourceAccountErr      = apperr.New(apperr.CodeInvalidSourceAccount)
+	InvalidDestinationAccountErr = apperr.New(apperr.CodeInvalidDestinationAccount)
+	IdempotencyKeyReusedErr      = apperr.New(apperr.CodeIdempotencyKeyReused)
+	FxQuoteNotFoundErr           = apperr.New(apperr.CodeFxQuoteNotFound)
+	FxQuoteExpiredErr            = apperr.New(apperr.CodeFxQuoteExpired)
+	FxQuoteMismatchErr           = apperr.New(apperr.CodeFxQuoteMismatch)
+	HoldNotFoundErr              = apperr.New(apperr.CodeHoldNotFound)
+	HoldNotActiveErr             = apperr.New(apperr.CodeHoldNotActive)
+	HoldAmountExceededErr        = apperr.New(apperr.CodeHoldAmountExceeded)
+	TransferNotFoundErr          = apperr.New(apperr.CodeTransferNotFound)
+	TransferNotReversibleErr     = apperr.New(apperr.CodeTransferNotReversible)
+	RefundAmountExceededErr      = apperr.New(apperr.CodeRefundAmountExceeded)
+	AccountClosedErr             = apperr.New(apperr.CodeAccountClosed)
+	AccountDebitFrozenErr        = apperr.New(apperr.CodeAccountDebitFrozen)
+	AccountCreditFrozenErr       = apperr.New(apperr.CodeAccountCreditFrozen)
+	AccountAccessDeniedErr       = apperr.New(apperr.CodeAccountAccessDenied)
 	PossibleErrors               = []error{
 		InsufficientBalanceErr,
 		InvalidAmountErr,
 		InvalidCurrencyErr,
 		InvalidSourceAccountErr,
 		InvalidDestinationAccountErr,
+		IdempotencyKeyReusedErr,
+		FxQuoteNotFoundErr,
+		FxQuoteExpiredErr,
+		FxQuoteMismatchErr,
+		HoldNotFoundErr,
+		HoldNotActiveErr,
+		HoldAmountExceededErr,
+		TransferNotFoundErr,
+		TransferNotReversibleErr,
+		RefundAmountExceededErr,
+		AccountClosedErr,
+		AccountDebitFrozenErr,
+		AccountCreditFrozenErr,
+		AccountAccessDeniedErr,
+		limit.AmountBelowMinimumErr,
+		limit.AmountAboveMaximumErr,
+		limit.CumulativeLimitExceededErr,
+		limit.VelocityLimitExceededErr,
+		limit.BalanceLimitExceededErr,
 	}
 )
 
@@ -48,12 +96,38 @@ type logicImpl struct {
 	TransferDAO    storage.ITransferDAO
 	AccountDAO     storage.IAccountDAO
 	TransactionDAO storage.ITransactionDAO
+	FxQuoteDAO     storage.IFxQuoteDAO
+	HoldDAO        storage.IHoldDAO
+	UserDAO        storage.IUserDAO
 
-	holdingAccountID string
+	holdingAccounts    map[string]string
+	fxPositionAccounts map[string]string
+	feeSchedule        *fee.Schedule
+	feeIncomeAccounts  map[string]string
+	limits             limit.ILimitLogic
+	lockingStrategies  map[string]LockingStrategy
+	shardSelection     ShardSelection
+	roundRobin         uint64
+	maxRetries         int
+	retryDelay         time.Duration
+	keyRetention       time.Duration
+	maxScheduleAhead   time.Duration
+	processingLease    time.Duration
+	holdDefaultTTL     time.Duration
+	holdMaxTTL         time.Duration
 }
 
 type CreateTransferOpts struct {
 	TxType TxType
+	// ClientID scopes the idempotency key, so different clients may use the same key independently.
+	ClientID string
+	// HoldID is the hold a CAPTURE settles
+	HoldID string
+	// ParentTransactionID is the transfer a REVERSAL refunds
+	ParentTransactionID string
+	// UserID is the user initiating the transfer. When set, the user must be allowed to debit the source
+	// account of a TRANSFER or WITHDRAWAL, and may not make a DEPOSIT.
+	UserID string
 }
 
 type TxType string
@@ -62,52 +136,113 @@ const (
 	TxTypeWithdrawal  TxType = "WITHDRAWAL"
 	TxTypeP2PTransfer TxType = "TRANSFER"
 	TxTypeDeposit     TxType = "DEPOSIT"
+	TxTypeCapture     TxType = "CAPTURE"
+	TxTypeReversal    TxType = "REVERSAL"
 )
 
 type ITransferLogic interface {
 	CreateTransfer(context.Context, *dto.CreateTransferRequest, *CreateTransferOpts) (*dto.CreateTransferResponse, error)
+	CreateTransfersAtomically(ctx context.Context, reqs []*dto.CreateTransferRequest, opts *CreateTransferOpts) ([]*dto.CreateTransferResponse, error)
+	RebalanceShards(ctx context.Context, parentAccountID string) error
+	PurgeIdempotencyKeys(ctx context.Context) (int64, error)
+	PreviewFees(ctx context.Context, req *dto.PreviewFeesRequest) (*dto.PreviewFeesResponse, error)
+	PlaceHold(context.Context, *dto.PlaceHoldRequest, *HoldOpts) (*dto.HoldResponse, error)
+	CaptureHold(context.Context, *dto.CaptureHoldRequest, *HoldOpts) (*dto.CreateTransferResponse, error)
+	ExtendHold(context.Context, *dto.ExtendHoldRequest, *HoldOpts) (*dto.HoldResponse, error)
+	ReleaseHold(ctx context.Context, holdID string, opts *HoldOpts) (*dto.HoldResponse, error)
+	GetHold(ctx context.Context, holdID string, opts *HoldOpts) (*dto.HoldResponse, error)
+	ReleaseExpiredHolds(ctx context.Context) (int64, error)
+	ReverseTransfer(ctx context.Context, transactionID string, req *dto.ReverseTransferRequest, opts *ReverseTransferOpts) (*dto.CreateTransferResponse, error)
+	GetTransfer(context.Context, *dto.GetTransferRequest, *GetTransferOpts) (*dto.TransferDetailResponse, error)
+	CancelTransfer(ctx context.Context, transactionID string, opts *ScheduledTransferOpts) (*dto.TransferDetailResponse, error)
+	RescheduleTransfer(ctx context.Context, transactionID string, req *dto.RescheduleTransferRequest, opts *ScheduledTransferOpts) (*dto.TransferDetailResponse, error)
+	ExecuteDueTransfers(ctx context.Context) (int64, error)
+	CheckDebitAccess(ctx context.Context, userID string, accountID string) error
 }
 
 func NewTransferLogic(
 	td storage.ITransferDAO,
 	ad storage.IAccountDAO,
-	txd storage.ITransactionDAO) ITransferLogic {
+	txd storage.ITransactionDAO,
+	qd storage.IFxQuoteDAO,
+	hd storage.IHoldDAO,
+	ud storage.IUserDAO,
+	cfg *Config) ITransferLogic {
 	return &logicImpl{
-		TransferDAO:      td,
-		AccountDAO:       ad,
-		TransactionDAO:   txd,
-		holdingAccountID: "1000000001",
+		TransferDAO:        td,
+		AccountDAO:         ad,
+		TransactionDAO:     txd,
+		FxQuoteDAO:         qd,
+		HoldDAO:            hd,
+		UserDAO:            ud,
+		holdingAccounts:    cfg.HoldingAccountIDs,
+		fxPositionAccounts: cfg.FxPositionAccountIDs,
+		feeSchedule:        cfg.FeeSchedule,
+		feeIncomeAccounts:  cfg.FeeIncomeAccountIDs,
+		limits:             limit.NewLimitLogic(ad, td, cfg.Limits),
+		lockingStrategies:  cfg.LockingStrategies,
+		shardSelection:     cfg.ShardSelection,
+		maxRetries:         cfg.MaxRetries,
+		retryDelay:         cfg.RetryDelay,
+		keyRetention:       cfg.IdempotencyKeyRetention,
+		maxScheduleAhead:   cfg.MaxScheduleAhead,
+		processingLease:    cfg.ProcessingLease,
+		holdDefaultTTL:     cfg.HoldDefaultTTL,
+		holdMaxTTL:         cfg.HoldMaxTTL,
 	}
 }
 
 func (l *logicImpl) CreateTransfer(ctx context.Context, req *dto.CreateTransferRequest, opts *CreateTransferOpts) (*dto.CreateTransferResponse, error) {
+	if opts == nil {
+		return nil, errors.New("invalid options")
+	}
+
 	// Idempotency check
-	existing, err := l.TransferDAO.FindByReferenceID(ctx, req.IdempotencyKey)
+	fingerprint := requestFingerprint(req, opts)
+	existing, err := l.TransferDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey)
 	if existing != nil {
-		return mapTransferStorageToResponse(existing), nil
+		return replayTransfer(existing, fingerprint)
 	}
 	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 
+	// transfers due later are only checked now and executed by ExecuteDueTransfers once due
+	scheduled := req.ExecuteAt != nil && req.ExecuteAt.After(time.Now())
+	if scheduled {
+		if scheduleErr := l.checkSchedulable(ctx, req, opts); scheduleErr != nil {
+			return nil, scheduleErr
+		}
+	}
+
 	transactionID := uuid.New().String()
 
+	// Every attempt is persisted, so rejected transfers stay visible with their reason
 	transferRecord := mapCreateTransferRequestToTransfer(req, transactionID, opts)
+	transferRecord.RequestHash = fingerprint
+	if scheduled {
+		transferRecord.Status = TxStatusSCHEDULED
+		transferRecord.ExecuteAt = req.ExecuteAt
+	} else {
+		transferRecord.ClaimedUntil = l.claimUntil()
+	}
+	if createErr := l.TransferDAO.Create(ctx, transferRecord); createErr != nil {
+		// a concurrent request with the same key got there first
+		if existing, _ = l.TransferDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey); existing != nil {
+			return replayTransfer(existing, fingerprint)
+		}
+		return nil, createErr
+	}
 
-	if doErr := util.Retry(func() error {
-		return l.doTransfer(ctx, transferRecord, opts)
-	}, 3, 100*time.Millisecond,
-		InvalidAmountErr,
-		InvalidCurrencyErr,
-		InvalidSourceAccountErr,
-		InvalidDestinationAccountErr,
-		InsufficientBalanceErr,
-	); doErr != nil {
+	if scheduled {
+		return mapTransferStorageToResponse(transferRecord), nil
+	}
+	if doErr := l.execute(ctx, transferRecord, opts); doErr != nil {
 		return nil, doErr
 	}
 
 	// find data and return
-	finalTx, err := l.TransferDAO.FindByReferenceID(ctx, req.IdempotencyKey)
+	finalTx, err := l.TransferDAO.FindByReferenceID(ctx, opts.ClientID, req.IdempotencyKey)
 	if err != nil {
 		return nil, err
 	}
@@ -115,37 +250,126 @@ func (l *logicImpl) CreateTransfer(ctx context.Context, req *dto.CreateTransferR
 	return mapTransferStorageToResponse(finalTx), nil
 }
 
+// execute runs trf, retrying transient failures. A transfer rejected with one of PossibleErrors is recorded
+// as FAILED. Any other error, such as a lost database connection, says nothing about the transfer itself,
+// so it stays PROCESSING and is executed again by ExecuteDueTransfers once its claim expires.
+func (l *logicImpl) execute(ctx context.Context, trf *storage.Transfer, opts *CreateTransferOpts) error {
+	if doErr := util.Retry(func() error {
+		return l.doTransfer(ctx, trf, opts)
+	}, l.maxRetries, l.retryDelay, PossibleErrors...); doErr != nil {
+		if IsOneOfTransferErrors(doErr) {
+			l.markFailed(ctx, trf, doErr)
+		} else {
+			log.Printf("transfer %s left processing: %v", trf.TransactionID, doErr)
+		}
+		return doErr
+	}
+	return nil
+}
+
+// replayTransfer answers a repeated request with the outcome of the transfer created by the first one
+func replayTransfer(existing *storage.Transfer, fingerprint string) (*dto.CreateTransferResponse, error) {
+	// transfers created before fingerprints were recorded have no hash to compare against
+	if existing.RequestHash != "" && existing.RequestHash != fingerprint {
+		return nil, IdempotencyKeyReusedErr
+	}
+	if existing.Status == TxStatusFAILED {
+		return nil, failureErr(existing)
+	}
+	return mapTransferStorageToResponse(existing), nil
+}
+
+// markFailed records why a transfer failed. Its journal was rolled back, so it never moved any funds nor
+// charged any fees. It is recorded even if ctx was cancelled meanwhile, as the outcome is already known.
+func (l *logicImpl) markFailed(ctx context.Context, trf *storage.Transfer, cause error) {
+	trf.Status = TxStatusFAILED
+	trf.FeeAmount = 0
+	trf.Fees = nil
+	trf.StatusReason = ReasonCode(cause)
+	trf.StatusReasonDescription = cause.Error()
+	trf.UpdatedAt = time.Now()
+	if err := saveClaimed(context.WithoutCancel(ctx), l.TransferDAO, trf); err != nil {
+		log.Printf("failed to mark transfer %s as failed: %v", trf.TransactionID, err)
+	}
+}
+
+// claimUntil returns the end of the claim on a transfer executed now, rounded to the precision of the
+// database so it can be compared with the stored one
+func (l *logicImpl) claimUntil() *time.Time {
+	until := time.Now().Add(l.processingLease).Truncate(time.Microsecond)
+	return &until
+}
+
+// saveClaimed saves trf, which ends its claim, unless the claim was lost to another instance
+func saveClaimed(ctx context.Context, dao storage.ITransferDAO, trf *storage.Transfer) error {
+	claimedUntil := trf.ClaimedUntil
+	trf.ClaimedUntil = nil
+	if err := dao.SaveClaimed(ctx, trf, claimedUntil); err != nil {
+		// the transfer may be retried under the same claim
+		trf.ClaimedUntil = claimedUntil
+		return err
+	}
+	return nil
+}
+
 func (l *logicImpl) doTransfer(ctx context.Context, req *storage.Transfer, opts *CreateTransferOpts) error {
 	var sourceAcc *storage.Account
 	var destAcc *storage.Account
+	var quote *storage.FxQuote
+	var hold *storage.Hold
 	var findErr error
 
 	if opts == nil {
 		return errors.New("invalid options")
 	}
+	// a deposit credits the account from the holding account, which only a partner system may do
+	if opts.TxType == TxTypeDeposit && opts.UserID != "" {
+		return AccountAccessDeniedErr
+	}
+
+	if !currency.IsRegistered(req.Currency) {
+		return InvalidCurrencyErr
+	}
+	// deposits and withdrawals go through the holding account of the transfer currency
+	holdingAccountID, hasHolding := l.holdingAccounts[req.Currency]
+	if !hasHolding && (opts.TxType == TxTypeWithdrawal || opts.TxType == TxTypeDeposit) {
+		return InvalidCurrencyErr
+	}
 
-	// Load source and destination accounts
+	// Load source and destination accounts. Same-currency transfers only touch accounts held in the
+	// transfer currency.
 	switch opts.TxType {
 	case TxTypeWithdrawal:
-		req.DestinationAccountID = l.holdingAccountID
+		req.DestinationAccountID = holdingAccountID
 		sourceAcc, findErr = l.AccountDAO.FindByAccountID(ctx, req.SourceAccountID)
 		if findErr != nil {
 			return InvalidSourceAccountErr
 		}
-		if sourceAcc.Balance < req.Amount {
+		if sourceAcc.Currency != req.Currency {
+			return InvalidCurrencyErr
+		}
+		if sourceAcc.Available() < req.Amount {
 			return InsufficientBalanceErr
 		}
-		destAcc, findErr = l.AccountDAO.FindByAccountID(ctx, l.holdingAccountID)
+		destAcc, findErr = l.resolvePostingAccount(ctx, holdingAccountID, req.TransactionID)
 		if findErr != nil {
 			return InvalidDestinationAccountErr
 		}
 		req.DestinationAccount = toAccountInfo(sourceAcc)
 	case TxTypeP2PTransfer:
+		if req.FxQuoteID != "" {
+			if quote, findErr = l.findUsableQuote(ctx, req, opts); findErr != nil {
+				return findErr
+			}
+		}
 		sourceAcc, findErr = l.AccountDAO.FindByAccountID(ctx, req.SourceAccountID)
 		if findErr != nil {
 			return InvalidSourceAccountErr
 		}
-		if sourceAcc.Balance < req.Amount {
+		if sourceAcc.Currency != req.Currency {
+			return InvalidCurrencyErr
+		}
+		if sourceAcc.Available() < req.Amount {
 			return InsufficientBalanceErr
 		}
 		destAcc, findErr = l.AccountDAO.FindByAccountID(ctx, req.DestinationAccountID)
@@ -154,9 +378,26 @@ func (l *logicImpl) doTransfer(ctx context.Context, req *storage.Transfer, opts
 		}
 		req.SourceAccount = toAccountInfo(sourceAcc)
 		req.DestinationAccount = toAccountInfo(destAcc)
+	case TxTypeCapture:
+		// the captured funds are already reserved, so they are not checked against the available balance
+		if hold, findErr = l.findCapturableHold(ctx, req); findErr != nil {
+			return findErr
+		}
+		sourceAcc, findErr = l.AccountDAO.FindByAccountID(ctx, req.SourceAccountID)
+		if findErr != nil {
+			return InvalidSourceAccountErr
+		}
+		destAcc, findErr = l.AccountDAO.FindByAccountID(ctx, req.DestinationAccountID)
+		if findErr != nil {
+			return InvalidDestinationAccountErr
+		}
+		req.SourceAccount = toAccountInfo(sourceAcc)
+		req.DestinationAccount = toAccountInfo(destAcc)
+	case TxTypeReversal:
+		return l.doReversal(ctx, req)
 	case TxTypeDeposit:
-		req.SourceAccountID = l.holdingAccountID
-		sourceAcc, findErr = l.AccountDAO.FindByAccountID(ctx, l.holdingAccountID)
+		req.SourceAccountID = holdingAccountID
+		sourceAcc, findErr = l.resolvePostingAccount(ctx, holdingAccountID, req.TransactionID)
 		if findErr != nil {
 			return InvalidSourceAccountErr
 		}
@@ -166,55 +407,276 @@ func (l *logicImpl) doTransfer(ctx context.Context, req *storage.Transfer, opts
 		}
 		req.SourceAccount = toAccountInfo(sourceAcc)
 	}
-
-	createTransferErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
-		// Create Source Transaction (debit)
-		if srcTxErr := tx.Create(&storage.Transaction{
-			AccountID: req.SourceAccountID,
-			Type:      TypeDebit,
-			Amount:    req.Amount,
-			Currency:  req.Currency,
-			Note:      fmt.Sprintf("Transfer to %s", req.DestinationAccountID),
-			Timestamp: req.CreatedAt,
-			ValuedAt:  req.CreatedAt,
-			CreatedAt: req.CreatedAt,
-			UpdatedAt: req.CreatedAt,
-		}).Error; srcTxErr != nil {
-			return srcTxErr
+	if opts.TxType == TxTypeWithdrawal || opts.TxType == TxTypeP2PTransfer {
+		if accessErr := l.checkDebitAccess(ctx, opts.UserID, sourceAcc.AccountID); accessErr != nil {
+			return accessErr
 		}
+	}
+	if statusErr := checkPostable(sourceAcc, destAcc); statusErr != nil {
+		return statusErr
+	}
+	if quote != nil {
+		return l.doFxTransfer(ctx, req, quote, sourceAcc, destAcc)
+	}
+	if sourceAcc.Currency != req.Currency || destAcc.Currency != req.Currency {
+		return InvalidCurrencyErr
+	}
 
-		// Create Destination Transaction (credit)
-		if dstTxErr := tx.Create(&storage.Transaction{
-			AccountID: req.DestinationAccountID,
-			Type:      TypeCredit,
-			Amount:    req.Amount,
-			Currency:  req.Currency,
-			Note:      fmt.Sprintf("Transfer from %s", req.SourceAccountID),
-			Timestamp: req.CreatedAt,
-			ValuedAt:  req.CreatedAt,
-			CreatedAt: req.CreatedAt,
-			UpdatedAt: req.CreatedAt,
-		}).Error; dstTxErr != nil {
-			return dstTxErr
-		}
+	accounts := map[string]*storage.Account{
+		sourceAcc.AccountID: sourceAcc,
+		destAcc.AccountID:   destAcc,
+	}
+	legs := buildJournal(req, sourceAcc.AccountID, destAcc.AccountID)
+	payer := sourceAcc
+	if opts.TxType == TxTypeDeposit {
+		payer = destAcc
+	}
+	if limitErr := l.checkAmountLimits(payer, req); limitErr != nil {
+		return limitErr
+	}
+	feeLegs, feeErr := l.chargeFees(ctx, req, payer, accounts)
+	if feeErr != nil {
+		return feeErr
+	}
+	var prepare func(tx *gorm.DB) error
+	if hold != nil {
+		prepare = l.consumeHold(ctx, req, accounts)
+	}
+	return l.commitTransfer(ctx, req, append(legs, feeLegs...), accounts, prepare, l.enforceLimits(ctx, req, payer, destAcc))
+}
 
-		if updateBalanceErr := l.AccountDAO.UpdateBalance(ctx, sourceAcc, -req.Amount); updateBalanceErr != nil {
-			return fmt.Errorf("source account update failed: %w", updateBalanceErr)
+// commitTransfer posts legs and marks req COMPLETED in one database transaction. prepare, if set, runs first
+// within the transaction. The checks run last within the same transaction, in order, and any error they
+// return rolls the transfer back.
+func (l *logicImpl) commitTransfer(
+	ctx context.Context,
+	req *storage.Transfer,
+	legs []*storage.Transaction,
+	accounts map[string]*storage.Account,
+	prepare func(tx *gorm.DB) error,
+	checks ...func(tx *gorm.DB) error,
+) error {
+	createTransferErr := l.TransferDAO.RunInTransaction(func(tx *gorm.DB) error {
+		if prepare != nil {
+			if prepareErr := prepare(tx); prepareErr != nil {
+				return prepareErr
+			}
 		}
-		if updateBalanceErr := l.AccountDAO.UpdateBalance(ctx, destAcc, req.Amount); updateBalanceErr != nil {
-			return fmt.Errorf("destination account update failed: %w", updateBalanceErr)
+		if postErr := l.postJournal(ctx, tx, legs, accounts); postErr != nil {
+			return postErr
 		}
 		// Update transfer status to success
 		req.Status = TxStatusCOMPLETED
 		req.UpdatedAt = time.Now()
-		if saveErr := tx.Save(req).Error; saveErr != nil {
+		if saveErr := saveClaimed(ctx, l.TransferDAO.WithTx(tx), req); saveErr != nil {
 			return saveErr
 		}
+		for _, check := range checks {
+			if checkErr := check(tx); checkErr != nil {
+				return checkErr
+			}
+		}
 		return nil
 	})
 	return createTransferErr
 }
 
+// checkAmountLimits checks req against the per-transfer limits of payer
+func (l *logicImpl) checkAmountLimits(payer *storage.Account, req *storage.Transfer) error {
+	if l.limits == nil {
+		return nil
+	}
+	return l.limits.CheckAmount(payer, req.TxType, req.Amount)
+}
+
+// enforceLimits returns the check of the cumulative limits of payer and the maximum balance of credited,
+// to run within the transaction posting req
+func (l *logicImpl) enforceLimits(ctx context.Context, req *storage.Transfer, payer *storage.Account, credited *storage.Account) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error {
+		if l.limits == nil {
+			return nil
+		}
+		return l.limits.Enforce(ctx, tx, req.TxType, payer, credited)
+	}
+}
+
+// postJournal books legs and applies the net balance movement of every account they touch, all within tx.
+// accounts holds the already loaded accounts; any other account referenced by a leg is loaded here.
+//
+// Accounts are processed in ascending account ID order. Accounts using pessimistic locking are locked and
+// re-read right before their update, so row locks are always acquired in the same global order and two
+// transfers can never wait on each other.
+func (l *logicImpl) postJournal(ctx context.Context, tx *gorm.DB, legs []*storage.Transaction, accounts map[string]*storage.Account) error {
+	accountDAO := l.AccountDAO.WithTx(tx)
+	if journalErr := l.TransactionDAO.WithTx(tx).CreateJournal(ctx, legs); journalErr != nil {
+		return journalErr
+	}
+
+	deltas := netBalanceDeltas(legs)
+	for _, accountID := range sortedKeys(deltas) {
+		delta := deltas[accountID]
+		if delta == 0 {
+			continue
+		}
+		acc, ok := accounts[accountID]
+		if !ok {
+			var findErr error
+			if acc, findErr = accountDAO.FindByAccountID(ctx, accountID); findErr != nil {
+				return fmt.Errorf("account %s lookup failed: %w", accountID, findErr)
+			}
+			accounts[accountID] = acc
+		}
+
+		update := accountDAO.UpdateBalance
+		if l.lockingStrategyFor(acc) == LockingPessimistic {
+			locked, lockErr := accountDAO.FindByAccountIDForUpdate(ctx, accountID)
+			if lockErr != nil {
+				return fmt.Errorf("account %s lock failed: %w", accountID, lockErr)
+			}
+			acc = locked
+			accounts[accountID] = locked
+			update = accountDAO.UpdateBalanceLocked
+		}
+		// the status is checked again here, as the account may have been frozen or closed since it was
+		// first read: locked accounts were just read again, and optimistic updates fail if it changed
+		if statusErr := checkPostableDelta(acc, delta); statusErr != nil {
+			return statusErr
+		}
+		if delta < 0 && !canOverdraw(acc) && acc.Available()+delta < 0 {
+			return InsufficientBalanceErr
+		}
+		if updateBalanceErr := update(ctx, acc, delta); updateBalanceErr != nil {
+			return fmt.Errorf("account %s update failed: %w", accountID, updateBalanceErr)
+		}
+	}
+	return nil
+}
+
+// CheckDebitAccess checks userID may move money out of accountID, for standing instructions and batches
+// whose transfers are made on the user's behalf later. The transfers are checked again when made.
+func (l *logicImpl) CheckDebitAccess(ctx context.Context, userID string, accountID string) error {
+	return l.checkDebitAccess(ctx, userID, accountID)
+}
+
+// checkDebitAccess checks userID may move money out of accountID: the user is ACTIVE and owns the account or
+// holds it jointly. Transfers made without a user, by partners and the wallet itself, are not checked.
+func (l *logicImpl) checkDebitAccess(ctx context.Context, userID string, accountID string) error {
+	if userID == "" {
+		return nil
+	}
+	user, err := l.UserDAO.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return AccountAccessDeniedErr
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active() {
+		return AccountAccessDeniedErr
+	}
+	link, err := l.UserDAO.FindUserAccount(ctx, userID, accountID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return AccountAccessDeniedErr
+	}
+	if err != nil {
+		return err
+	}
+	if !link.CanDebit() {
+		return AccountAccessDeniedErr
+	}
+	return nil
+}
+
+// checkPostable checks debited may be debited and credited may be credited
+func checkPostable(debited *storage.Account, credited *storage.Account) error {
+	if err := checkPostableDelta(debited, -1); err != nil {
+		return err
+	}
+	return checkPostableDelta(credited, 1)
+}
+
+// checkPostableDelta checks the status of acc allows a posting moving its balance by delta
+func checkPostableDelta(acc *storage.Account, delta int64) error {
+	switch {
+	case acc.Closed():
+		return AccountClosedErr
+	case delta < 0 && acc.DebitFrozen():
+		return AccountDebitFrozenErr
+	case delta > 0 && acc.CreditFrozen():
+		return AccountCreditFrozenErr
+	}
+	return nil
+}
+
+func (l *logicImpl) lockingStrategyFor(acc *storage.Account) LockingStrategy {
+	if strategy, ok := l.lockingStrategies[acc.Type]; ok {
+		return strategy
+	}
+	return LockingOptimistic
+}
+
+// canOverdraw reports whether the account may go below zero. Only internal accounts can, since they
+// are the contra side of money entering or leaving the wallet system.
+func canOverdraw(acc *storage.Account) bool {
+	return acc.Type == storage.AccountTypeHolding || acc.Type == storage.AccountTypeSystem || acc.Type == storage.AccountTypeFxPosition
+}
+
+// buildJournal returns the balanced legs that book req. Each leg is tagged with the transfer's
+// TransactionID so the debit and credit sides can always be paired back to their transfer.
+// The legs post to sourceAccountID and destinationAccountID, which differ from the transfer's own
+// account IDs when those are sharded.
+func buildJournal(req *storage.Transfer, sourceAccountID string, destinationAccountID string) []*storage.Transaction {
+	return []*storage.Transaction{
+		newLeg(req, sourceAccountID, TypeDebit, req.Amount, fmt.Sprintf("Transfer to %s", req.DestinationAccountID)),
+		newLeg(req, destinationAccountID, TypeCredit, req.Amount, fmt.Sprintf("Transfer from %s", req.SourceAccountID)),
+	}
+}
+
+// newLeg returns a leg of req. Legs take effect when req does: its value date if set, otherwise when it
+// was created.
+func newLeg(req *storage.Transfer, accountID string, entryType string, amount int64, note string) *storage.Transaction {
+	at := req.CreatedAt
+	if req.ValuedAt != nil {
+		at = *req.ValuedAt
+	}
+	return &storage.Transaction{
+		JournalID: req.TransactionID,
+		AccountID: accountID,
+		Type:      entryType,
+		Amount:    amount,
+		Currency:  req.Currency,
+		Note:      note,
+		Timestamp: at,
+		ValuedAt:  at,
+		CreatedAt: at,
+		UpdatedAt: at,
+	}
+}
+
+// netBalanceDeltas folds the legs of a journal into one signed balance movement per account.
+func netBalanceDeltas(legs []*storage.Transaction) map[string]int64 {
+	deltas := map[string]int64{}
+	for _, leg := range legs {
+		if leg.Type == TypeDebit {
+			deltas[leg.AccountID] -= leg.Amount
+		} else {
+			deltas[leg.AccountID] += leg.Amount
+		}
+	}
+	return deltas
+}
+
+// sortedKeys returns the account IDs of deltas in ascending order so balance updates always
+// happen in the same sequence.
+func sortedKeys(deltas map[string]int64) []string {
+	ids := make([]string, 0, len(deltas))
+	for id := range deltas {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
 func mapCreateTransferRequestToTransfer(req *dto.CreateTransferRequest, transactionID string, opts *CreateTransferOpts) *storage.Transfer {
 	now := time.Now()
 	trf := &storage.Transfer{
@@ -222,12 +684,17 @@ func mapCreateTransferRequestToTransfer(req *dto.CreateTransferRequest, transact
 		Type:                 "INTRA",
 		TransactionID:        transactionID,
 		ReferenceID:          req.IdempotencyKey,
+		ClientID:             opts.ClientID,
+		UserID:               opts.UserID,
 		Status:               TxStatusPROCESSING,
 		Amount:               req.Amount,
 		Currency:             req.Currency,
 		SourceAccountID:      req.SourceAccount.Number,
 		DestinationAccountID: req.DestinationAccount.Number,
 		Note:                 req.Note,
+		FxQuoteID:            req.QuoteID,
+		HoldID:               opts.HoldID,
+		ParentTransactionID:  opts.ParentTransactionID,
 		CreatedAt:            now,
 		UpdatedAt:            now,
 	}
@@ -254,5 +721,17 @@ func mapTransferStorageToResponse(res *storage.Transfer) *dto.CreateTransferResp
 		Amount:         res.Amount,
 		Currency:       res.Currency,
 		Status:         res.Status,
+		StatusReason:   res.StatusReason,
+
+		QuoteID:             res.FxQuoteID,
+		DestinationAmount:   res.DestinationAmount,
+		DestinationCurrency: res.DestinationCurrency,
+
+		ParentTransactionID: res.ParentTransactionID,
+		RefundedAmount:      res.RefundedAmount,
+		ExecuteAt:           res.ExecuteAt,
+
+		Fees:     mapFeesStorageToResponse(res.Fees),
+		TotalFee: res.FeeAmount,
 	}
 }
@@ -0,0 +1,308 @@
+//go:build integration
+
+package transfer
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+	"wallet/dto"
+	"wallet/storage"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// Integration tests run against a real PostgreSQL database initialised with db/init.sql:
+//
+//	WALLET_TEST_DSN="host=localhost dbname=wallet_test port=5432 sslmode=disable" go test -tags integration ./...
+
+var injectedErr = errors.New("injected failure")
+
+func openTestDB(t testing.TB) *gorm.DB {
+	dsn := os.Getenv("WALLET_TEST_DSN")
+	if dsn == "" {
+		t.Skip("WALLET_TEST_DSN not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("failed to connect database: %v", err)
+	}
+	return db
+}
+
+func createTestAccount(t testing.TB, db *gorm.DB, accountType string, balance int64) *storage.Account {
+	acc := &storage.Account{
+		AccountID: uuid.New().String()[:18],
+		Name:      "integration test",
+		Type:      accountType,
+		Currency:  "MYR",
+		Balance:   balance,
+	}
+	if err := db.Create(acc).Error; err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+	return acc
+}
+
+// faultyAccountDAO performs the n-th balance update and then fails, so the caller has to roll it back.
+type faultyAccountDAO struct {
+	storage.IAccountDAO
+	failOnUpdate int
+	updates      *int
+}
+
+func (f *faultyAccountDAO) WithTx(tx *gorm.DB) storage.IAccountDAO {
+	return &faultyAccountDAO{IAccountDAO: f.IAccountDAO.WithTx(tx), failOnUpdate: f.failOnUpdate, updates: f.updates}
+}
+
+func (f *faultyAccountDAO) UpdateBalance(ctx context.Context, acc *storage.Account, delta int64) error {
+	if err := f.IAccountDAO.UpdateBalance(ctx, acc, delta); err != nil {
+		return err
+	}
+	*f.updates++
+	if *f.updates == f.failOnUpdate {
+		return injectedErr
+	}
+	return nil
+}
+
+// faultyTransactionDAO writes the journal and then fails.
+type faultyTransactionDAO struct {
+	storage.ITransactionDAO
+	fail bool
+}
+
+func (f *faultyTransactionDAO) WithTx(tx *gorm.DB) storage.ITransactionDAO {
+	return &faultyTransactionDAO{ITransactionDAO: f.ITransactionDAO.WithTx(tx), fail: f.fail}
+}
+
+func (f *faultyTransactionDAO) CreateJournal(ctx context.Context, legs []*storage.Transaction) error {
+	if err := f.ITransactionDAO.CreateJournal(ctx, legs); err != nil {
+		return err
+	}
+	if f.fail {
+		return injectedErr
+	}
+	return nil
+}
+
+// faultyTransferDAO saves the claimed transfer and then fails.
+type faultyTransferDAO struct {
+	storage.ITransferDAO
+	fail bool
+}
+
+func (f *faultyTransferDAO) WithTx(tx *gorm.DB) storage.ITransferDAO {
+	return &faultyTransferDAO{ITransferDAO: f.ITransferDAO.WithTx(tx), fail: f.fail}
+}
+
+func (f *faultyTransferDAO) SaveClaimed(ctx context.Context, transfer *storage.Transfer, claimedUntil *time.Time) error {
+	if err := f.ITransferDAO.SaveClaimed(ctx, transfer, claimedUntil); err != nil {
+		return err
+	}
+	if f.fail {
+		return injectedErr
+	}
+	return nil
+}
+
+func TestIntegration_doTransfer_rollsBackOnFailure(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		name          string
+		failJournal   bool
+		failOnUpdate  int
+		failSave      bool
+		wantCommitted bool
+	}{
+		{name: "no failure commits everything", wantCommitted: true},
+		{name: "failure after journal insert", failJournal: true},
+		{name: "failure after first balance update", failOnUpdate: 1},
+		{name: "failure after second balance update", failOnUpdate: 2},
+		{name: "failure after transfer save", failSave: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			source := createTestAccount(t, db, "WALLET", 2000)
+			destination := createTestAccount(t, db, "WALLET", 1000)
+
+			updates := 0
+			l := &logicImpl{
+				TransferDAO:     &faultyTransferDAO{ITransferDAO: storage.NewTransferDAO(db), fail: tt.failSave},
+				AccountDAO:      &faultyAccountDAO{IAccountDAO: storage.NewAccountDAO(db), failOnUpdate: tt.failOnUpdate, updates: &updates},
+				TransactionDAO:  &faultyTransactionDAO{ITransactionDAO: storage.NewTransactionDAO(db), fail: tt.failJournal},
+				processingLease: time.Minute,
+			}
+			now := time.Now()
+			transactionID := uuid.New().String()
+			// the transfer is created and claimed before it is executed, as CreateTransfer does
+			trf := &storage.Transfer{
+				Type:                 "INTRA",
+				TxType:               string(TxTypeP2PTransfer),
+				TransactionID:        transactionID,
+				ReferenceID:          transactionID,
+				Status:               TxStatusPROCESSING,
+				Amount:               500,
+				Currency:             "MYR",
+				SourceAccountID:      source.AccountID,
+				DestinationAccountID: destination.AccountID,
+				ClaimedUntil:         l.claimUntil(),
+				CreatedAt:            now,
+				UpdatedAt:            now,
+			}
+			if err := storage.NewTransferDAO(db).Create(ctx, trf); err != nil {
+				t.Fatalf("failed to create transfer: %v", err)
+			}
+			err := l.doTransfer(ctx, trf, &CreateTransferOpts{TxType: TxTypeP2PTransfer})
+			if (err == nil) != tt.wantCommitted {
+				t.Fatalf("doTransfer() error = %v, wantCommitted %v", err, tt.wantCommitted)
+			}
+
+			// a rolled back transfer stays PROCESSING under its claim, for the executor to run again
+			wantSource, wantDestination, wantLegs, wantStatus := int64(2000), int64(1000), int64(0), TxStatusPROCESSING
+			if tt.wantCommitted {
+				wantSource, wantDestination, wantLegs, wantStatus = 1500, 1500, 2, TxStatusCOMPLETED
+			}
+
+			var gotSource, gotDestination storage.Account
+			db.Where("account_id = ?", source.AccountID).First(&gotSource)
+			db.Where("account_id = ?", destination.AccountID).First(&gotDestination)
+			if gotSource.Balance != wantSource || gotDestination.Balance != wantDestination {
+				t.Errorf("balances = %d/%d, want %d/%d", gotSource.Balance, gotDestination.Balance, wantSource, wantDestination)
+			}
+
+			var legs int64
+			var gotTransfer storage.Transfer
+			db.Model(&storage.Transaction{}).Where("journal_id = ?", transactionID).Count(&legs)
+			db.Where("transaction_id = ?", transactionID).First(&gotTransfer)
+			if legs != wantLegs || gotTransfer.Status != wantStatus {
+				t.Errorf("legs/status = %d/%s, want %d/%s", legs, gotTransfer.Status, wantLegs, wantStatus)
+			}
+			if tt.wantCommitted != (gotTransfer.ClaimedUntil == nil) {
+				t.Errorf("claimed until = %v, want the claim ended only once committed", gotTransfer.ClaimedUntil)
+			}
+		})
+	}
+}
+
+func TestIntegration_CreateTransfer_persistsFailedTransfer(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	source := createTestAccount(t, db, storage.AccountTypeWallet, 100)
+	destination := createTestAccount(t, db, storage.AccountTypeWallet, 0)
+
+	l := &logicImpl{
+		TransferDAO:    storage.NewTransferDAO(db),
+		AccountDAO:     storage.NewAccountDAO(db),
+		TransactionDAO: storage.NewTransactionDAO(db),
+	}
+	req := &dto.CreateTransferRequest{
+		Currency:           "MYR",
+		Amount:             500,
+		SourceAccount:      dto.CreateTransferRequestAccountDetail{Number: source.AccountID},
+		DestinationAccount: dto.CreateTransferRequestAccountDetail{Number: destination.AccountID},
+		IdempotencyKey:     uuid.New().String(),
+	}
+	opts := &CreateTransferOpts{TxType: TxTypeP2PTransfer}
+	if _, err := l.CreateTransfer(ctx, req, opts); !errors.Is(err, InsufficientBalanceErr) {
+		t.Fatalf("CreateTransfer() error = %v, want %v", err, InsufficientBalanceErr)
+	}
+	// the retry is answered from the failed transfer instead of being attempted again
+	if _, err := l.CreateTransfer(ctx, req, opts); !errors.Is(err, InsufficientBalanceErr) {
+		t.Fatalf("CreateTransfer() retry error = %v, want %v", err, InsufficientBalanceErr)
+	}
+
+	var transfers []storage.Transfer
+	db.Where("reference_id = ?", req.IdempotencyKey).Find(&transfers)
+	if len(transfers) != 1 {
+		t.Fatalf("got %d transfers, want 1", len(transfers))
+	}
+	if transfers[0].Status != TxStatusFAILED || transfers[0].StatusReason != ReasonInsufficientBalance {
+		t.Errorf("transfer status = %s/%s, want %s/%s", transfers[0].Status, transfers[0].StatusReason, TxStatusFAILED, ReasonInsufficientBalance)
+	}
+}
+
+// conflictCountingAccountDAO counts optimistic locking conflicts.
+type conflictCountingAccountDAO struct {
+	storage.IAccountDAO
+	conflicts *int64
+}
+
+func (c *conflictCountingAccountDAO) WithTx(tx *gorm.DB) storage.IAccountDAO {
+	return &conflictCountingAccountDAO{IAccountDAO: c.IAccountDAO.WithTx(tx), conflicts: c.conflicts}
+}
+
+func (c *conflictCountingAccountDAO) UpdateBalance(ctx context.Context, acc *storage.Account, delta int64) error {
+	err := c.IAccountDAO.UpdateBalance(ctx, acc, delta)
+	if errors.Is(err, storage.ConcurrentUpdateErr) {
+		atomic.AddInt64(c.conflicts, 1)
+	}
+	return err
+}
+
+// BenchmarkIntegration_HoldingAccountContention hammers a single holding account with concurrent deposits
+// and reports throughput and optimistic conflict rate for both locking strategies.
+//
+//	WALLET_TEST_DSN=... go test -tags integration -run '^$' -bench HoldingAccountContention -cpu 16 ./logic/transfer
+func BenchmarkIntegration_HoldingAccountContention(b *testing.B) {
+	db := openTestDB(b)
+
+	for _, strategy := range []LockingStrategy{LockingOptimistic, LockingPessimistic} {
+		b.Run(string(strategy), func(b *testing.B) {
+			holding := createTestAccount(b, db, storage.AccountTypeHolding, 0)
+			wallets := make([]*storage.Account, 32)
+			for i := range wallets {
+				wallets[i] = createTestAccount(b, db, storage.AccountTypeWallet, 0)
+			}
+
+			var conflicts, failures int64
+			l := &logicImpl{
+				TransferDAO:       storage.NewTransferDAO(db),
+				AccountDAO:        &conflictCountingAccountDAO{IAccountDAO: storage.NewAccountDAO(db), conflicts: &conflicts},
+				TransactionDAO:    storage.NewTransactionDAO(db),
+				holdingAccounts:   map[string]string{"MYR": holding.AccountID},
+				lockingStrategies: map[string]LockingStrategy{storage.AccountTypeHolding: strategy},
+				maxRetries:        3,
+				retryDelay:        10 * time.Millisecond,
+			}
+
+			b.ResetTimer()
+			start := time.Now()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					_, err := l.CreateTransfer(context.Background(), &dto.CreateTransferRequest{
+						Currency: "MYR",
+						Amount:   100,
+						DestinationAccount: dto.CreateTransferRequestAccountDetail{
+							Number: wallets[rand.Intn(len(wallets))].AccountID,
+						},
+						IdempotencyKey: uuid.New().String(),
+					}, &CreateTransferOpts{TxType: TxTypeDeposit})
+					if err != nil {
+						atomic.AddInt64(&failures, 1)
+					}
+				}
+			})
+			elapsed := time.Since(start)
+			b.StopTimer()
+
+			b.ReportMetric(float64(b.N)/elapsed.Seconds(), "transfers/s")
+			b.ReportMetric(float64(conflicts)/float64(b.N), "conflicts/op")
+			b.ReportMetric(float64(failures)/float64(b.N), "failures/op")
+			b.Logf("%s: %d transfers, %d conflicts, %d failed after retries", strategy, b.N, conflicts, failures)
+		})
+	}
+}
@@ -2,10 +2,13 @@ package transfer
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"testing"
 	"wallet/dto"
+	"wallet/logic/limit"
+	limitmock "wallet/logic/limit/mocks"
 	"wallet/storage"
 	storagemock "wallet/storage/mocks"
 
@@ -37,7 +40,7 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(&storage.Transfer{
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
 						Status:      "COMPLETED",
 						ReferenceID: "idempotency-key",
 						Amount:      1000,
@@ -61,12 +64,136 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "happy path - record exist with same request",
+			fields: fields{
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("FindByReferenceID", context.Background(), "client-a", "idempotency-key").Return(&storage.Transfer{
+						Status:      "COMPLETED",
+						ReferenceID: "idempotency-key",
+						Amount:      1000,
+						RequestHash: requestFingerprint(&dto.CreateTransferRequest{
+							Amount:         1000,
+							IdempotencyKey: "idempotency-key",
+						}, &CreateTransferOpts{TxType: TxTypeP2PTransfer}),
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &dto.CreateTransferRequest{
+					Amount:         1000,
+					IdempotencyKey: "idempotency-key",
+				},
+				opts: &CreateTransferOpts{
+					TxType:   TxTypeP2PTransfer,
+					ClientID: "client-a",
+				},
+			},
+			want: &dto.CreateTransferResponse{
+				Status:         "COMPLETED",
+				IdempotencyKey: "idempotency-key",
+				Amount:         1000,
+			},
+			wantErr: false,
+		},
+		{
+			name: "error - record exist with different request",
+			fields: fields{
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
+						Status:      "COMPLETED",
+						ReferenceID: "idempotency-key",
+						Amount:      1000,
+						RequestHash: requestFingerprint(&dto.CreateTransferRequest{
+							Amount:         1000,
+							IdempotencyKey: "idempotency-key",
+						}, &CreateTransferOpts{TxType: TxTypeP2PTransfer}),
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &dto.CreateTransferRequest{
+					Amount:         2000,
+					IdempotencyKey: "idempotency-key",
+				},
+				opts: &CreateTransferOpts{
+					TxType: TxTypeP2PTransfer,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error - record exist as failed transfer",
+			fields: fields{
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
+						Status:       TxStatusFAILED,
+						StatusReason: ReasonInsufficientBalance,
+						ReferenceID:  "idempotency-key",
+						Amount:       1000,
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &dto.CreateTransferRequest{
+					Amount:         1000,
+					IdempotencyKey: "idempotency-key",
+				},
+				opts: &CreateTransferOpts{
+					TxType: TxTypeP2PTransfer,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "happy path - concurrent request with same key created the record first",
+			fields: fields{
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(gorm.ErrDuplicatedKey).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
+						Status:      TxStatusPROCESSING,
+						ReferenceID: "idempotency-key",
+						Amount:      1000,
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &dto.CreateTransferRequest{
+					Amount:         1000,
+					IdempotencyKey: "idempotency-key",
+				},
+				opts: &CreateTransferOpts{
+					TxType: TxTypeP2PTransfer,
+				},
+			},
+			want: &dto.CreateTransferResponse{
+				Status:         TxStatusPROCESSING,
+				IdempotencyKey: "idempotency-key",
+				Amount:         1000,
+			},
+			wantErr: false,
+		},
 		{
 			name: "error - FindByReferenceID returns error",
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(nil, errors.New("database error")).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, errors.New("database error")).Once()
 					return mc
 				}(),
 			},
@@ -87,13 +214,18 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(nil).Once()
+					mc.On("SaveClaimed", mock.Anything, mock.MatchedBy(func(trf *storage.Transfer) bool {
+						return trf.Status == TxStatusFAILED && trf.StatusReason == ReasonInsufficientBalance && trf.ClaimedUntil == nil
+					}), mock.AnythingOfType("*time.Time")).Return(nil).Once()
 					return mc
 				}(),
 				AccountDAO: func() storage.IAccountDAO {
 					mc := &storagemock.MockIAccountDAO{}
 					mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{
 						AccountID: "source-account",
+						Currency:  "MYR",
 						Balance:   500, // Less than the requested amount
 					}, nil).Once()
 					return mc
@@ -104,6 +236,53 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 				req: &dto.CreateTransferRequest{
 					IdempotencyKey: "idempotency-key",
 					Amount:         1000,
+					Currency:       "MYR",
+					SourceAccount: dto.CreateTransferRequestAccountDetail{
+						Number: "source-account",
+					},
+					DestinationAccount: dto.CreateTransferRequestAccountDetail{
+						Number: "destination-account",
+					},
+				},
+				opts: &CreateTransferOpts{
+					TxType: TxTypeP2PTransfer,
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "error - unexpected failure leaves the transfer processing",
+			fields: fields{
+				TransferDAO: func() storage.ITransferDAO {
+					mc := &storagemock.MockITransferDAO{}
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(nil).Once()
+					// no Save: the transfer is not marked FAILED
+					mc.On("RunInTransaction", mock.AnythingOfType("storage.TxFn")).Return(errors.New("connection reset")).Once()
+					return mc
+				}(),
+				AccountDAO: func() storage.IAccountDAO {
+					mc := &storagemock.MockIAccountDAO{}
+					mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{
+						AccountID: "source-account",
+						Currency:  "MYR",
+						Balance:   2000,
+					}, nil).Once()
+					mc.On("FindByAccountID", context.Background(), "destination-account").Return(&storage.Account{
+						AccountID: "destination-account",
+						Currency:  "MYR",
+						Balance:   1000,
+					}, nil).Once()
+					return mc
+				}(),
+			},
+			args: args{
+				ctx: context.Background(),
+				req: &dto.CreateTransferRequest{
+					IdempotencyKey: "idempotency-key",
+					Amount:         1000,
+					Currency:       "MYR",
 					SourceAccount: dto.CreateTransferRequestAccountDetail{
 						Number: "source-account",
 					},
@@ -123,9 +302,10 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(nil).Once()
 					mc.On("RunInTransaction", mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(&storage.Transfer{
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
 						Status:      "COMPLETED",
 						ReferenceID: "idempotency-key",
 						Amount:      1000,
@@ -136,10 +316,12 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 					mc := &storagemock.MockIAccountDAO{}
 					mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{
 						AccountID: "source-account",
+						Currency:  "MYR",
 						Balance:   2000,
 					}, nil).Once()
 					mc.On("FindByAccountID", context.Background(), "destination-account").Return(&storage.Account{
 						AccountID: "destination-account",
+						Currency:  "MYR",
 						Balance:   1000,
 					}, nil).Once()
 					mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(-1000)).Return(nil).Once()
@@ -176,9 +358,10 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(nil).Once()
 					mc.On("RunInTransaction", mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(&storage.Transfer{
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
 						Status:      "COMPLETED",
 						ReferenceID: "idempotency-key",
 						Amount:      1000,
@@ -189,10 +372,13 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 					mc := &storagemock.MockIAccountDAO{}
 					mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{
 						AccountID: "source-account",
+						Currency:  "MYR",
 						Balance:   2000,
 					}, nil).Once()
+					mc.On("FindByParentAccountID", context.Background(), "1000000001").Return([]*storage.Account{}, nil).Once()
 					mc.On("FindByAccountID", context.Background(), "1000000001").Return(&storage.Account{
 						AccountID: "1000000001",
+						Currency:  "MYR",
 						Balance:   10000,
 					}, nil).Once()
 					mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(-1000)).Return(nil).Once()
@@ -230,9 +416,10 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 			fields: fields{
 				TransferDAO: func() storage.ITransferDAO {
 					mc := &storagemock.MockITransferDAO{}
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(nil, gorm.ErrRecordNotFound).Once()
+					mc.On("Create", context.Background(), mock.AnythingOfType("*storage.Transfer")).Return(nil).Once()
 					mc.On("RunInTransaction", mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
-					mc.On("FindByReferenceID", context.Background(), "idempotency-key").Return(&storage.Transfer{
+					mc.On("FindByReferenceID", context.Background(), "", "idempotency-key").Return(&storage.Transfer{
 						Status:      "COMPLETED",
 						ReferenceID: "idempotency-key",
 						Amount:      1000,
@@ -241,12 +428,15 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 				}(),
 				AccountDAO: func() storage.IAccountDAO {
 					mc := &storagemock.MockIAccountDAO{}
+					mc.On("FindByParentAccountID", context.Background(), "1000000001").Return([]*storage.Account{}, nil).Once()
 					mc.On("FindByAccountID", context.Background(), "1000000001").Return(&storage.Account{
 						AccountID: "1000000001",
+						Currency:  "MYR",
 						Balance:   10000,
 					}, nil).Once()
 					mc.On("FindByAccountID", context.Background(), "destination-account").Return(&storage.Account{
 						AccountID: "destination-account",
+						Currency:  "MYR",
 						Balance:   1000,
 					}, nil).Once()
 					mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(-1000)).Return(nil).Once()
@@ -283,10 +473,10 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			l := &logicImpl{
-				TransferDAO:      tt.fields.TransferDAO,
-				AccountDAO:       tt.fields.AccountDAO,
-				TransactionDAO:   tt.fields.TransactionDAO,
-				holdingAccountID: tt.fields.holdingAccountID,
+				TransferDAO:     tt.fields.TransferDAO,
+				AccountDAO:      tt.fields.AccountDAO,
+				TransactionDAO:  tt.fields.TransactionDAO,
+				holdingAccounts: map[string]string{"MYR": tt.fields.holdingAccountID},
 			}
 			got, err := l.CreateTransfer(tt.args.ctx, tt.args.req, tt.args.opts)
 			if (err != nil) != tt.wantErr {
@@ -299,3 +489,576 @@ func Test_logicImpl_CreateTransfer(t *testing.T) {
 		})
 	}
 }
+
+func Test_buildJournal(t *testing.T) {
+	req := &storage.Transfer{
+		TransactionID:        "tx-123",
+		Amount:               1000,
+		Currency:             "MYR",
+		SourceAccountID:      "source-account",
+		DestinationAccountID: "destination-account",
+	}
+
+	legs := buildJournal(req, req.SourceAccountID, req.DestinationAccountID)
+	if len(legs) != 2 {
+		t.Fatalf("buildJournal() got %d legs, want 2", len(legs))
+	}
+	for _, leg := range legs {
+		if leg.JournalID != "tx-123" {
+			t.Errorf("buildJournal() leg journal = %v, want tx-123", leg.JournalID)
+		}
+	}
+	if err := storage.ValidateJournal(legs); err != nil {
+		t.Errorf("buildJournal() produced unbalanced journal: %v", err)
+	}
+
+	want := map[string]int64{"source-account": -1000, "destination-account": 1000}
+	if got := netBalanceDeltas(legs); !reflect.DeepEqual(got, want) {
+		t.Errorf("netBalanceDeltas() got = %v, want %v", got, want)
+	}
+}
+
+func Test_netBalanceDeltas_multiLeg(t *testing.T) {
+	legs := []*storage.Transaction{
+		{JournalID: "tx-1", AccountID: "a", Type: TypeDebit, Amount: 1050, Currency: "MYR"},
+		{JournalID: "tx-1", AccountID: "b", Type: TypeCredit, Amount: 600, Currency: "MYR"},
+		{JournalID: "tx-1", AccountID: "c", Type: TypeCredit, Amount: 400, Currency: "MYR"},
+		{JournalID: "tx-1", AccountID: "fee", Type: TypeCredit, Amount: 50, Currency: "MYR"},
+	}
+	if err := storage.ValidateJournal(legs); err != nil {
+		t.Fatalf("ValidateJournal() error = %v", err)
+	}
+
+	want := map[string]int64{"a": -1050, "b": 600, "c": 400, "fee": 50}
+	if got := netBalanceDeltas(legs); !reflect.DeepEqual(got, want) {
+		t.Errorf("netBalanceDeltas() got = %v, want %v", got, want)
+	}
+
+	legs[3].Amount = 49
+	if err := storage.ValidateJournal(legs); !errors.Is(err, storage.UnbalancedJournalErr) {
+		t.Errorf("ValidateJournal() error = %v, want %v", err, storage.UnbalancedJournalErr)
+	}
+}
+
+func Test_logicImpl_doTransfer_boundToTransaction(t *testing.T) {
+	stepErr := errors.New("injected failure")
+	newAccountDAO := func(updateSourceErr, updateDestErr error) *storagemock.MockIAccountDAO {
+		mc := &storagemock.MockIAccountDAO{}
+		mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{
+			AccountID: "source-account",
+			Currency:  "MYR",
+			Balance:   2000,
+		}, nil).Once()
+		mc.On("FindByAccountID", context.Background(), "destination-account").Return(&storage.Account{
+			AccountID: "destination-account",
+			Currency:  "MYR",
+			Balance:   1000,
+		}, nil).Once()
+		mc.On("WithTx", mock.Anything).Return(mc).Once()
+		// balance updates happen in account ID order: destination-account sorts before source-account
+		mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(1000)).Return(updateDestErr).Once()
+		if updateDestErr == nil {
+			mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(-1000)).Return(updateSourceErr).Once()
+		}
+		return mc
+	}
+	newTransactionDAO := func(journalErr error) *storagemock.MockITransactionDAO {
+		mc := &storagemock.MockITransactionDAO{}
+		mc.On("WithTx", mock.Anything).Return(mc).Once()
+		mc.On("CreateJournal", context.Background(), mock.AnythingOfType("[]*storage.Transaction")).Return(journalErr).Once()
+		return mc
+	}
+	newTransferDAO := func(saveErr error, expectSave bool) *storagemock.MockITransferDAO {
+		mc := &storagemock.MockITransferDAO{}
+		mc.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).
+			RunAndReturn(func(fn storage.TxFn, _ ...*sql.TxOptions) error {
+				return fn(nil)
+			}).Once()
+		if expectSave {
+			mc.On("WithTx", mock.Anything).Return(mc).Once()
+			mc.On("SaveClaimed", context.Background(), mock.AnythingOfType("*storage.Transfer"), mock.Anything).Return(saveErr).Once()
+		}
+		return mc
+	}
+
+	tests := []struct {
+		name           string
+		AccountDAO     *storagemock.MockIAccountDAO
+		TransactionDAO *storagemock.MockITransactionDAO
+		TransferDAO    *storagemock.MockITransferDAO
+		wantErr        bool
+	}{
+		{
+			name:           "happy path - all writes go through the transaction",
+			AccountDAO:     newAccountDAO(nil, nil),
+			TransactionDAO: newTransactionDAO(nil),
+			TransferDAO:    newTransferDAO(nil, true),
+		},
+		{
+			name: "error - journal insert fails, nothing else is written",
+			AccountDAO: func() *storagemock.MockIAccountDAO {
+				mc := &storagemock.MockIAccountDAO{}
+				mc.On("FindByAccountID", context.Background(), "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 2000}, nil).Once()
+				mc.On("FindByAccountID", context.Background(), "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "MYR"}, nil).Once()
+				mc.On("WithTx", mock.Anything).Return(mc).Once()
+				return mc
+			}(),
+			TransactionDAO: newTransactionDAO(stepErr),
+			TransferDAO:    newTransferDAO(nil, false),
+			wantErr:        true,
+		},
+		{
+			name:           "error - first balance update fails, transfer is not saved",
+			AccountDAO:     newAccountDAO(nil, stepErr),
+			TransactionDAO: newTransactionDAO(nil),
+			TransferDAO:    newTransferDAO(nil, false),
+			wantErr:        true,
+		},
+		{
+			name:           "error - second balance update fails, transfer is not saved",
+			AccountDAO:     newAccountDAO(stepErr, nil),
+			TransactionDAO: newTransactionDAO(nil),
+			TransferDAO:    newTransferDAO(nil, false),
+			wantErr:        true,
+		},
+		{
+			name:           "error - transfer save fails",
+			AccountDAO:     newAccountDAO(nil, nil),
+			TransactionDAO: newTransactionDAO(nil),
+			TransferDAO:    newTransferDAO(stepErr, true),
+			wantErr:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &logicImpl{
+				TransferDAO:    tt.TransferDAO,
+				AccountDAO:     tt.AccountDAO,
+				TransactionDAO: tt.TransactionDAO,
+			}
+			err := l.doTransfer(context.Background(), &storage.Transfer{
+				TransactionID:        "tx-123",
+				Amount:               1000,
+				Currency:             "MYR",
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}, &CreateTransferOpts{TxType: TxTypeP2PTransfer})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.AccountDAO.AssertExpectations(t)
+			tt.TransactionDAO.AssertExpectations(t)
+			tt.TransferDAO.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_logicImpl_postJournal_lockingStrategy(t *testing.T) {
+	legs := func(amount int64) []*storage.Transaction {
+		return buildJournal(&storage.Transfer{
+			TransactionID:        "tx-123",
+			Amount:               amount,
+			Currency:             "MYR",
+			SourceAccountID:      "1000000001",
+			DestinationAccountID: "12345678",
+		}, "1000000001", "12345678")
+	}
+	tests := []struct {
+		name       string
+		amount     int64
+		accountDAO func() *storagemock.MockIAccountDAO
+		wantErr    error
+	}{
+		{
+			name:   "holding account is locked, wallet is updated optimistically",
+			amount: 1000,
+			accountDAO: func() *storagemock.MockIAccountDAO {
+				mc := &storagemock.MockIAccountDAO{}
+				mc.On("WithTx", mock.Anything).Return(mc).Once()
+				mc.On("FindByAccountIDForUpdate", context.Background(), "1000000001").Return(&storage.Account{
+					AccountID: "1000000001",
+					Type:      storage.AccountTypeHolding,
+					Balance:   5000,
+				}, nil).Once()
+				mc.On("UpdateBalanceLocked", context.Background(), mock.AnythingOfType("*storage.Account"), int64(-1000)).Return(nil).Once()
+				mc.On("UpdateBalance", context.Background(), mock.AnythingOfType("*storage.Account"), int64(1000)).Return(nil).Once()
+				return mc
+			},
+		},
+		{
+			name:   "locked wallet re-checks balance after locking",
+			amount: 1000,
+			accountDAO: func() *storagemock.MockIAccountDAO {
+				mc := &storagemock.MockIAccountDAO{}
+				mc.On("WithTx", mock.Anything).Return(mc).Once()
+				mc.On("FindByAccountIDForUpdate", context.Background(), "1000000001").Return(&storage.Account{
+					AccountID: "1000000001",
+					Type:      storage.AccountTypeWallet,
+					Balance:   500, // drained by a concurrent transfer since it was first read
+				}, nil).Once()
+				return mc
+			},
+			wantErr: InsufficientBalanceErr,
+		},
+		{
+			name:   "locked wallet re-checks its status after locking",
+			amount: 1000,
+			accountDAO: func() *storagemock.MockIAccountDAO {
+				mc := &storagemock.MockIAccountDAO{}
+				mc.On("WithTx", mock.Anything).Return(mc).Once()
+				mc.On("FindByAccountIDForUpdate", context.Background(), "1000000001").Return(&storage.Account{
+					AccountID: "1000000001",
+					Type:      storage.AccountTypeWallet,
+					Balance:   5000,
+					Status:    storage.AccountStatusFrozen, // frozen by a concurrent request since it was first read
+					Freeze:    storage.FreezeDebit,
+				}, nil).Once()
+				return mc
+			},
+			wantErr: AccountDebitFrozenErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			accountDAO := tt.accountDAO()
+			transactionDAO := &storagemock.MockITransactionDAO{}
+			transactionDAO.On("WithTx", mock.Anything).Return(transactionDAO).Once()
+			transactionDAO.On("CreateJournal", context.Background(), mock.AnythingOfType("[]*storage.Transaction")).Return(nil).Once()
+
+			l := &logicImpl{
+				AccountDAO:     accountDAO,
+				TransactionDAO: transactionDAO,
+				lockingStrategies: map[string]LockingStrategy{
+					storage.AccountTypeHolding: LockingPessimistic,
+					storage.AccountTypeWallet:  LockingPessimistic,
+				},
+			}
+			accounts := map[string]*storage.Account{
+				"1000000001": {AccountID: "1000000001", Type: storage.AccountTypeHolding, Balance: 5000},
+				"12345678":   {AccountID: "12345678", Type: "CASA", Balance: 1000},
+			}
+			if tt.wantErr != nil {
+				accounts["1000000001"].Type = storage.AccountTypeWallet
+			}
+			err := l.postJournal(context.Background(), nil, legs(tt.amount), accounts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("postJournal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			accountDAO.AssertExpectations(t)
+		})
+	}
+}
+
+func Test_logicImpl_doTransfer_currency(t *testing.T) {
+	holdingAccounts := map[string]string{"MYR": "1000000001", "SGD": "1000000002"}
+
+	tests := []struct {
+		name       string
+		txType     TxType
+		currency   string
+		setupMocks func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO)
+		wantErr    error
+	}{
+		{
+			name:       "error - currency not registered",
+			txType:     TxTypeP2PTransfer,
+			currency:   "XYZ",
+			setupMocks: func(*storagemock.MockIAccountDAO, *storagemock.MockITransferDAO) {},
+			wantErr:    InvalidCurrencyErr,
+		},
+		{
+			name:       "error - deposit in a currency without holding account",
+			txType:     TxTypeDeposit,
+			currency:   "USD",
+			setupMocks: func(*storagemock.MockIAccountDAO, *storagemock.MockITransferDAO) {},
+			wantErr:    InvalidCurrencyErr,
+		},
+		{
+			name:     "error - source account held in another currency",
+			txType:   TxTypeP2PTransfer,
+			currency: "MYR",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "SGD", Balance: 2000}, nil).Once()
+			},
+			wantErr: InvalidCurrencyErr,
+		},
+		{
+			name:     "error - destination account held in another currency",
+			txType:   TxTypeP2PTransfer,
+			currency: "MYR",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO) {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(&storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 2000}, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "SGD"}, nil).Once()
+			},
+			wantErr: InvalidCurrencyErr,
+		},
+		{
+			name:     "happy path - deposit goes through the holding account of its currency",
+			txType:   TxTypeDeposit,
+			currency: "SGD",
+			setupMocks: func(ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO) {
+				ad.EXPECT().FindByParentAccountID(mock.Anything, "1000000002").Return(nil, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "1000000002").Return(&storage.Account{AccountID: "1000000002", Currency: "SGD", Type: storage.AccountTypeHolding}, nil).Once()
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(&storage.Account{AccountID: "destination-account", Currency: "SGD"}, nil).Once()
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			tt.setupMocks(ad, td)
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, holdingAccounts: holdingAccounts}
+			req := &storage.Transfer{
+				TransactionID:        "tx-123",
+				Amount:               1000,
+				Currency:             tt.currency,
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}
+			err := l.doTransfer(context.Background(), req, &CreateTransferOpts{TxType: tt.txType})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_doTransfer_accountStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		source      *storage.Account
+		destination *storage.Account
+		wantErr     error
+	}{
+		{
+			name:        "error - source account closed",
+			source:      &storage.Account{Status: storage.AccountStatusClosed},
+			destination: &storage.Account{},
+			wantErr:     AccountClosedErr,
+		},
+		{
+			name:        "error - debits frozen on the source account",
+			source:      &storage.Account{Status: storage.AccountStatusFrozen, Freeze: storage.FreezeDebit},
+			destination: &storage.Account{},
+			wantErr:     AccountDebitFrozenErr,
+		},
+		{
+			name:        "error - credits frozen on the destination account",
+			source:      &storage.Account{},
+			destination: &storage.Account{Status: storage.AccountStatusFrozen, Freeze: storage.FreezeAll},
+			wantErr:     AccountCreditFrozenErr,
+		},
+		{
+			name:        "happy path - credits frozen on the source account do not stop debits",
+			source:      &storage.Account{Status: storage.AccountStatusFrozen, Freeze: storage.FreezeCredit},
+			destination: &storage.Account{Status: storage.AccountStatusFrozen, Freeze: storage.FreezeDebit},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			tt.source.AccountID, tt.source.Currency, tt.source.Balance = "source-account", "MYR", 2000
+			tt.destination.AccountID, tt.destination.Currency = "destination-account", "MYR"
+			ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(tt.source, nil).Once()
+			ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(tt.destination, nil).Once()
+			if tt.wantErr == nil {
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			}
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad}
+			req := &storage.Transfer{
+				TransactionID:        "tx-123",
+				Amount:               1000,
+				Currency:             "MYR",
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}
+			err := l.doTransfer(context.Background(), req, &CreateTransferOpts{TxType: TxTypeP2PTransfer})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_doTransfer_userAccess(t *testing.T) {
+	activeUser := &storage.User{UserID: "user-1", Status: storage.UserStatusActive}
+	tests := []struct {
+		name       string
+		txType     TxType
+		userID     string
+		setupMocks func(ud *storagemock.MockIUserDAO)
+		wantErr    error
+	}{
+		{
+			name:   "happy path - no user, made by a partner system",
+			txType: TxTypeP2PTransfer,
+		},
+		{
+			name:   "happy path - owner",
+			txType: TxTypeP2PTransfer,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(activeUser, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "source-account").Return(&storage.UserAccount{Role: storage.UserAccountRoleOwner}, nil).Once()
+			},
+		},
+		{
+			name:   "happy path - joint holder withdraws",
+			txType: TxTypeWithdrawal,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(activeUser, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "source-account").Return(&storage.UserAccount{Role: storage.UserAccountRoleJoint}, nil).Once()
+			},
+		},
+		{
+			name:   "error - viewer",
+			txType: TxTypeP2PTransfer,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(activeUser, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "source-account").Return(&storage.UserAccount{Role: storage.UserAccountRoleViewer}, nil).Once()
+			},
+			wantErr: AccountAccessDeniedErr,
+		},
+		{
+			name:   "error - account not linked to the user",
+			txType: TxTypeP2PTransfer,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(activeUser, nil).Once()
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "source-account").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: AccountAccessDeniedErr,
+		},
+		{
+			name:   "error - suspended owner",
+			txType: TxTypeP2PTransfer,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(&storage.User{UserID: "user-1", Status: storage.UserStatusSuspended}, nil).Once()
+			},
+			wantErr: AccountAccessDeniedErr,
+		},
+		{
+			name:   "error - unknown user",
+			txType: TxTypeP2PTransfer,
+			userID: "user-1",
+			setupMocks: func(ud *storagemock.MockIUserDAO) {
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(nil, gorm.ErrRecordNotFound).Once()
+			},
+			wantErr: AccountAccessDeniedErr,
+		},
+		{
+			name:    "error - user deposits, even to their own account",
+			txType:  TxTypeDeposit,
+			userID:  "user-1",
+			wantErr: AccountAccessDeniedErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			ud := storagemock.NewMockIUserDAO(t)
+			source := &storage.Account{AccountID: "source-account", Currency: "MYR", Balance: 2000}
+			destination := &storage.Account{AccountID: "destination-account", Currency: "MYR"}
+			if tt.txType != TxTypeDeposit {
+				ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+				if tt.txType == TxTypeWithdrawal {
+					ad.EXPECT().FindByParentAccountID(mock.Anything, "destination-account").Return(nil, nil).Once()
+				}
+				ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(destination, nil).Once()
+			}
+			if tt.setupMocks != nil {
+				tt.setupMocks(ud)
+			}
+			if tt.wantErr == nil {
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).Return(nil).Once()
+			}
+
+			l := &logicImpl{
+				TransferDAO:     td,
+				AccountDAO:      ad,
+				UserDAO:         ud,
+				holdingAccounts: map[string]string{"MYR": "destination-account"},
+			}
+			req := &storage.Transfer{
+				TransactionID:        "tx-123",
+				Amount:               1000,
+				Currency:             "MYR",
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}
+			err := l.doTransfer(context.Background(), req, &CreateTransferOpts{TxType: tt.txType, UserID: tt.userID})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_doTransfer_limits(t *testing.T) {
+	source := &storage.Account{AccountID: "source-account", Type: storage.AccountTypeWallet, Currency: "MYR", Balance: 2000}
+	destination := &storage.Account{AccountID: "destination-account", Type: storage.AccountTypeWallet, Currency: "MYR"}
+
+	tests := []struct {
+		name       string
+		setupMocks func(lm *limitmock.MockILimitLogic, ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO)
+		wantErr    error
+	}{
+		{
+			name: "error - amount limit rejects the transfer before posting",
+			setupMocks: func(lm *limitmock.MockILimitLogic, _ *storagemock.MockIAccountDAO, _ *storagemock.MockITransferDAO, _ *storagemock.MockITransactionDAO) {
+				lm.EXPECT().CheckAmount(source, string(TxTypeP2PTransfer), int64(1000)).Return(limit.AmountAboveMaximumErr).Once()
+			},
+			wantErr: limit.AmountAboveMaximumErr,
+		},
+		{
+			name: "error - cumulative limit rolls the posted transfer back",
+			setupMocks: func(lm *limitmock.MockILimitLogic, ad *storagemock.MockIAccountDAO, td *storagemock.MockITransferDAO, txd *storagemock.MockITransactionDAO) {
+				lm.EXPECT().CheckAmount(source, string(TxTypeP2PTransfer), int64(1000)).Return(nil).Once()
+				td.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn, _ ...*sql.TxOptions) error {
+					return fn(nil)
+				}).Once()
+				txd.EXPECT().WithTx(mock.Anything).Return(txd).Once()
+				txd.EXPECT().CreateJournal(mock.Anything, mock.Anything).Return(nil).Once()
+				ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+				ad.EXPECT().UpdateBalance(mock.Anything, mock.Anything, mock.Anything).Return(nil).Twice()
+				td.EXPECT().WithTx(mock.Anything).Return(td).Once()
+				td.EXPECT().SaveClaimed(mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+				lm.EXPECT().Enforce(mock.Anything, mock.Anything, string(TxTypeP2PTransfer), source, destination).Return(limit.CumulativeLimitExceededErr).Once()
+			},
+			wantErr: limit.CumulativeLimitExceededErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lm := limitmock.NewMockILimitLogic(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			td := storagemock.NewMockITransferDAO(t)
+			txd := storagemock.NewMockITransactionDAO(t)
+			ad.EXPECT().FindByAccountID(mock.Anything, "source-account").Return(source, nil).Once()
+			ad.EXPECT().FindByAccountID(mock.Anything, "destination-account").Return(destination, nil).Once()
+			tt.setupMocks(lm, ad, td, txd)
+
+			l := &logicImpl{TransferDAO: td, AccountDAO: ad, TransactionDAO: txd, limits: lm}
+			err := l.doTransfer(context.Background(), &storage.Transfer{
+				TransactionID:        "tx-123",
+				TxType:               string(TxTypeP2PTransfer),
+				Amount:               1000,
+				Currency:             "MYR",
+				SourceAccountID:      "source-account",
+				DestinationAccountID: "destination-account",
+			}, &CreateTransferOpts{TxType: TxTypeP2PTransfer})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("doTransfer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
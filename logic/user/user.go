@@ -0,0 +1,337 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"strings"
+	"time"
+	"wallet/apperr"
+	"wallet/dto"
+	"wallet/storage"
+)
+
+var (
+	UserNotFoundErr        = apperr.New(apperr.CodeUserNotFound)
+	UserAlreadyExistsErr   = apperr.New(apperr.CodeUserAlreadyExists)
+	InvalidUserStatusErr   = apperr.New(apperr.CodeInvalidUserStatus)
+	AccountNotFoundErr     = apperr.New(apperr.CodeAccountNotFound)
+	UserAccountNotFoundErr = apperr.New(apperr.CodeUserAccountNotFound)
+	LastAccountOwnerErr    = apperr.New(apperr.CodeLastAccountOwner)
+)
+
+// IUserLogic manages users and the roles they have on customer accounts. Every account linked to users
+// keeps at least one OWNER.
+type IUserLogic interface {
+	CreateUser(context.Context, *dto.CreateUserRequest) (*dto.UserResponse, error)
+	GetUser(ctx context.Context, userID string) (*dto.UserResponse, error)
+	UpdateUser(context.Context, *dto.UpdateUserRequest) (*dto.UserResponse, error)
+	ChangeUserStatus(context.Context, *dto.UserStatusRequest) (*dto.UserResponse, error)
+	LinkAccount(context.Context, *dto.LinkAccountRequest) (*dto.UserResponse, error)
+	UnlinkAccount(context.Context, *dto.UnlinkAccountRequest) (*dto.UserResponse, error)
+	CheckViewAccess(ctx context.Context, userID string, accountID string) error
+}
+
+type logicImpl struct {
+	UserDAO    storage.IUserDAO
+	AccountDAO storage.IAccountDAO
+}
+
+func NewUserLogic(ud storage.IUserDAO, ad storage.IAccountDAO) IUserLogic {
+	return &logicImpl{UserDAO: ud, AccountDAO: ad}
+}
+
+// CreateUser registers an ACTIVE user with no accounts. Emails identify users, so no two users share one.
+func (l *logicImpl) CreateUser(ctx context.Context, req *dto.CreateUserRequest) (*dto.UserResponse, error) {
+	email := normalizeEmail(req.Email)
+	if err := l.checkEmailFree(ctx, email, ""); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user := &storage.User{
+		UserID:    uuid.New().String(),
+		Name:      req.Name,
+		Email:     email,
+		Phone:     req.Phone,
+		Status:    storage.UserStatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := l.UserDAO.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return mapUserStorageToResponse(user, nil), nil
+}
+
+// GetUser returns the user with the accounts linked to them
+func (l *logicImpl) GetUser(ctx context.Context, userID string) (*dto.UserResponse, error) {
+	user, err := l.UserDAO.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, UserNotFoundErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	links, err := l.UserDAO.FindUserAccounts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return mapUserStorageToResponse(user, links), nil
+}
+
+// CheckViewAccess checks userID may see accountID, which needs an ACTIVE user linked to it with any role.
+// Accounts the user may not see are reported as not found.
+func (l *logicImpl) CheckViewAccess(ctx context.Context, userID string, accountID string) error {
+	user, err := l.UserDAO.FindByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return AccountNotFoundErr
+	}
+	if err != nil {
+		return err
+	}
+	if !user.Active() {
+		return AccountNotFoundErr
+	}
+	_, err = l.UserDAO.FindUserAccount(ctx, userID, accountID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return AccountNotFoundErr
+	}
+	return err
+}
+
+// UpdateUser changes the contact details of the user. Closed users cannot be changed.
+func (l *logicImpl) UpdateUser(ctx context.Context, req *dto.UpdateUserRequest) (*dto.UserResponse, error) {
+	return l.updateUser(ctx, req.UserID, func(userDAO storage.IUserDAO, user *storage.User) error {
+		if req.Email != "" {
+			email := normalizeEmail(req.Email)
+			if err := l.checkEmailFree(ctx, email, user.UserID); err != nil {
+				return err
+			}
+			user.Email = email
+		}
+		if req.Name != "" {
+			user.Name = req.Name
+		}
+		if req.Phone != "" {
+			user.Phone = req.Phone
+		}
+		return nil
+	})
+}
+
+// ChangeUserStatus suspends, reactivates or closes the user. Closing is for good, and only allowed once the
+// user is not the last owner of an open account.
+func (l *logicImpl) ChangeUserStatus(ctx context.Context, req *dto.UserStatusRequest) (*dto.UserResponse, error) {
+	return l.updateUser(ctx, req.UserID, func(userDAO storage.IUserDAO, user *storage.User) error {
+		if req.Status == storage.UserStatusClosed {
+			if err := l.checkNotLastOwner(ctx, userDAO, user.UserID); err != nil {
+				return err
+			}
+		}
+		user.Status = req.Status
+		return nil
+	})
+}
+
+// updateUser applies change to the user userID and saves it, with the user locked. Closed users cannot be
+// changed.
+func (l *logicImpl) updateUser(ctx context.Context, userID string, change func(userDAO storage.IUserDAO, user *storage.User) error) (*dto.UserResponse, error) {
+	var updated *storage.User
+	var links []*storage.UserAccount
+	err := l.UserDAO.RunInTransaction(func(tx *gorm.DB) error {
+		userDAO := l.UserDAO.WithTx(tx)
+		user, findErr := userDAO.FindByUserIDForUpdate(ctx, userID)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return UserNotFoundErr
+		}
+		if findErr != nil {
+			return findErr
+		}
+		if user.Status == storage.UserStatusClosed {
+			return InvalidUserStatusErr
+		}
+		if changeErr := change(userDAO, user); changeErr != nil {
+			return changeErr
+		}
+		user.UpdatedAt = time.Now()
+		if saveErr := userDAO.Save(ctx, user); saveErr != nil {
+			return saveErr
+		}
+		updated = user
+		links, findErr = userDAO.FindUserAccounts(ctx, userID)
+		return findErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapUserStorageToResponse(updated, links), nil
+}
+
+// checkNotLastOwner checks every open account the user owns has another owner
+func (l *logicImpl) checkNotLastOwner(ctx context.Context, userDAO storage.IUserDAO, userID string) error {
+	links, err := userDAO.FindUserAccounts(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, link := range links {
+		if link.Role != storage.UserAccountRoleOwner {
+			continue
+		}
+		acc, findErr := l.AccountDAO.FindByAccountID(ctx, link.AccountID)
+		if findErr != nil && !errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return findErr
+		}
+		if acc == nil || acc.Closed() {
+			continue
+		}
+		owned, ownerErr := hasOtherOwner(ctx, userDAO, link.AccountID, userID)
+		if ownerErr != nil {
+			return ownerErr
+		}
+		if !owned {
+			return LastAccountOwnerErr
+		}
+	}
+	return nil
+}
+
+// LinkAccount gives the user a role on a customer account, or changes the role they have. The account must
+// be left with an owner.
+func (l *logicImpl) LinkAccount(ctx context.Context, req *dto.LinkAccountRequest) (*dto.UserResponse, error) {
+	return l.changeLink(ctx, req.UserID, req.AccountID, func(userDAO storage.IUserDAO, link *storage.UserAccount) error {
+		if req.Role != storage.UserAccountRoleOwner {
+			owned, err := hasOtherOwner(ctx, userDAO, req.AccountID, req.UserID)
+			if err != nil {
+				return err
+			}
+			if !owned {
+				return LastAccountOwnerErr
+			}
+		}
+		now := time.Now()
+		if link == nil {
+			link = &storage.UserAccount{UserID: req.UserID, AccountID: req.AccountID, CreatedAt: now}
+		}
+		link.Role = req.Role
+		link.UpdatedAt = now
+		return userDAO.SaveUserAccount(ctx, link)
+	})
+}
+
+// UnlinkAccount takes the account away from the user. The last owner of an account cannot be unlinked.
+func (l *logicImpl) UnlinkAccount(ctx context.Context, req *dto.UnlinkAccountRequest) (*dto.UserResponse, error) {
+	return l.changeLink(ctx, req.UserID, req.AccountID, func(userDAO storage.IUserDAO, link *storage.UserAccount) error {
+		if link == nil {
+			return UserAccountNotFoundErr
+		}
+		if link.Role == storage.UserAccountRoleOwner {
+			owned, err := hasOtherOwner(ctx, userDAO, req.AccountID, req.UserID)
+			if err != nil {
+				return err
+			}
+			if !owned {
+				return LastAccountOwnerErr
+			}
+		}
+		return userDAO.DeleteUserAccount(ctx, link)
+	})
+}
+
+// changeLink applies change to the link between the user and the account, nil if there is none. The account
+// is locked, so the links of an account change one at a time and it cannot lose its last owner to two
+// concurrent changes.
+func (l *logicImpl) changeLink(ctx context.Context, userID string, accountID string, change func(userDAO storage.IUserDAO, link *storage.UserAccount) error) (*dto.UserResponse, error) {
+	var user *storage.User
+	var links []*storage.UserAccount
+	err := l.UserDAO.RunInTransaction(func(tx *gorm.DB) error {
+		userDAO := l.UserDAO.WithTx(tx)
+		acc, findErr := l.AccountDAO.WithTx(tx).FindByAccountIDForUpdate(ctx, accountID)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) || (acc != nil && !isCustomerAccount(acc)) {
+			return AccountNotFoundErr
+		}
+		if findErr != nil {
+			return findErr
+		}
+		user, findErr = userDAO.FindByUserID(ctx, userID)
+		if errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return UserNotFoundErr
+		}
+		if findErr != nil {
+			return findErr
+		}
+		if user.Status == storage.UserStatusClosed {
+			return InvalidUserStatusErr
+		}
+		link, findErr := userDAO.FindUserAccount(ctx, userID, accountID)
+		if findErr != nil && !errors.Is(findErr, gorm.ErrRecordNotFound) {
+			return findErr
+		}
+		if changeErr := change(userDAO, link); changeErr != nil {
+			return changeErr
+		}
+		links, findErr = userDAO.FindUserAccounts(ctx, userID)
+		return findErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapUserStorageToResponse(user, links), nil
+}
+
+// hasOtherOwner reports whether a user other than userID owns the account
+func hasOtherOwner(ctx context.Context, userDAO storage.IUserDAO, accountID string, userID string) (bool, error) {
+	links, err := userDAO.FindAccountUsers(ctx, accountID)
+	if err != nil {
+		return false, err
+	}
+	for _, link := range links {
+		if link.UserID != userID && link.Role == storage.UserAccountRoleOwner {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkEmailFree checks no user other than userID has the email
+func (l *logicImpl) checkEmailFree(ctx context.Context, email string, userID string) error {
+	existing, err := l.UserDAO.FindByEmail(ctx, email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.UserID != userID {
+		return UserAlreadyExistsErr
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func isCustomerAccount(acc *storage.Account) bool {
+	return acc.Type == storage.AccountTypeWallet || acc.Type == storage.AccountTypeCASA
+}
+
+func mapUserStorageToResponse(user *storage.User, links []*storage.UserAccount) *dto.UserResponse {
+	accounts := make([]*dto.UserAccountResponse, 0, len(links))
+	for _, link := range links {
+		accounts = append(accounts, &dto.UserAccountResponse{
+			AccountID: link.AccountID,
+			Role:      link.Role,
+			LinkedAt:  link.CreatedAt,
+		})
+	}
+	return &dto.UserResponse{
+		UserID:    user.UserID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Phone:     user.Phone,
+		Status:    user.Status,
+		Accounts:  accounts,
+		CreatedAt: user.CreatedAt,
+	}
+}
@@ -0,0 +1,325 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"wallet/dto"
+	"wallet/storage"
+	storagemock "wallet/storage/mocks"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// inTransaction runs the next transaction of ud straight away on ud and ad themselves
+func inTransaction(ud *storagemock.MockIUserDAO, ad *storagemock.MockIAccountDAO) {
+	ud.EXPECT().RunInTransaction(mock.AnythingOfType("storage.TxFn")).RunAndReturn(func(fn storage.TxFn) error {
+		return fn(nil)
+	}).Once()
+	ud.EXPECT().WithTx(mock.Anything).Return(ud).Once()
+	if ad != nil {
+		ad.EXPECT().WithTx(mock.Anything).Return(ad).Once()
+	}
+}
+
+func Test_logicImpl_CreateUser(t *testing.T) {
+	tests := []struct {
+		name     string
+		existing *storage.User
+		wantErr  error
+	}{
+		{
+			name: "happy path",
+		},
+		{
+			name:     "error - email taken",
+			existing: &storage.User{UserID: "someone-else"},
+			wantErr:  UserAlreadyExistsErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := storagemock.NewMockIUserDAO(t)
+			if tt.existing != nil {
+				ud.EXPECT().FindByEmail(mock.Anything, "alice@example.com").Return(tt.existing, nil).Once()
+			} else {
+				ud.EXPECT().FindByEmail(mock.Anything, "alice@example.com").Return(nil, gorm.ErrRecordNotFound).Once()
+				ud.EXPECT().Create(mock.Anything, mock.MatchedBy(func(u *storage.User) bool {
+					return u.UserID != "" && u.Email == "alice@example.com" && u.Status == storage.UserStatusActive
+				})).Return(nil).Once()
+			}
+
+			l := &logicImpl{UserDAO: ud}
+			got, err := l.CreateUser(context.Background(), &dto.CreateUserRequest{Name: "Alice", Email: " Alice@Example.com"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("CreateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (got.Email != "alice@example.com" || len(got.Accounts) != 0) {
+				t.Errorf("CreateUser() = %+v, want a user with no accounts", got)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_LinkAccount(t *testing.T) {
+	tests := []struct {
+		name     string
+		account  *storage.Account
+		user     *storage.User
+		existing *storage.UserAccount
+		others   []*storage.UserAccount
+		role     string
+		wantErr  error
+	}{
+		{
+			name:    "happy path - first owner",
+			account: &storage.Account{Type: storage.AccountTypeWallet},
+			user:    &storage.User{Status: storage.UserStatusActive},
+			role:    storage.UserAccountRoleOwner,
+		},
+		{
+			name:    "happy path - viewer of an owned account",
+			account: &storage.Account{Type: storage.AccountTypeWallet},
+			user:    &storage.User{Status: storage.UserStatusActive},
+			others:  []*storage.UserAccount{{UserID: "owner", Role: storage.UserAccountRoleOwner}},
+			role:    storage.UserAccountRoleViewer,
+		},
+		{
+			name:     "error - last owner cannot become a joint holder",
+			account:  &storage.Account{Type: storage.AccountTypeWallet},
+			user:     &storage.User{Status: storage.UserStatusActive},
+			existing: &storage.UserAccount{Role: storage.UserAccountRoleOwner},
+			others: []*storage.UserAccount{
+				{UserID: "user-1", Role: storage.UserAccountRoleOwner},
+				{UserID: "joint", Role: storage.UserAccountRoleJoint},
+			},
+			role:    storage.UserAccountRoleJoint,
+			wantErr: LastAccountOwnerErr,
+		},
+		{
+			name:    "error - internal account",
+			account: &storage.Account{Type: storage.AccountTypeHolding},
+			role:    storage.UserAccountRoleOwner,
+			wantErr: AccountNotFoundErr,
+		},
+		{
+			name:    "error - closed user",
+			account: &storage.Account{Type: storage.AccountTypeCASA},
+			user:    &storage.User{Status: storage.UserStatusClosed},
+			role:    storage.UserAccountRoleOwner,
+			wantErr: InvalidUserStatusErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := storagemock.NewMockIUserDAO(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			inTransaction(ud, ad)
+			tt.account.AccountID = "12345678"
+			ad.EXPECT().FindByAccountIDForUpdate(mock.Anything, "12345678").Return(tt.account, nil).Once()
+			if tt.user != nil {
+				tt.user.UserID = "user-1"
+				ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(tt.user, nil).Once()
+			}
+			if tt.user != nil && tt.user.Status != storage.UserStatusClosed {
+				if tt.existing != nil {
+					ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(tt.existing, nil).Once()
+				} else {
+					ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(nil, gorm.ErrRecordNotFound).Once()
+				}
+				if tt.role != storage.UserAccountRoleOwner {
+					ud.EXPECT().FindAccountUsers(mock.Anything, "12345678").Return(tt.others, nil).Once()
+				}
+			}
+			if tt.wantErr == nil {
+				ud.EXPECT().SaveUserAccount(mock.Anything, mock.MatchedBy(func(link *storage.UserAccount) bool {
+					return link.UserID == "user-1" && link.AccountID == "12345678" && link.Role == tt.role
+				})).Return(nil).Once()
+				ud.EXPECT().FindUserAccounts(mock.Anything, "user-1").Return([]*storage.UserAccount{{AccountID: "12345678", Role: tt.role}}, nil).Once()
+			}
+
+			l := &logicImpl{UserDAO: ud, AccountDAO: ad}
+			got, err := l.LinkAccount(context.Background(), &dto.LinkAccountRequest{UserID: "user-1", AccountID: "12345678", Role: tt.role})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("LinkAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && (len(got.Accounts) != 1 || got.Accounts[0].Role != tt.role) {
+				t.Errorf("LinkAccount() accounts = %+v, want the account as %s", got.Accounts, tt.role)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_UnlinkAccount(t *testing.T) {
+	tests := []struct {
+		name    string
+		link    *storage.UserAccount
+		others  []*storage.UserAccount
+		wantErr error
+	}{
+		{
+			name: "happy path - viewer",
+			link: &storage.UserAccount{UserID: "user-1", AccountID: "12345678", Role: storage.UserAccountRoleViewer},
+		},
+		{
+			name:   "happy path - owner of an account with another owner",
+			link:   &storage.UserAccount{UserID: "user-1", AccountID: "12345678", Role: storage.UserAccountRoleOwner},
+			others: []*storage.UserAccount{{UserID: "user-2", Role: storage.UserAccountRoleOwner}},
+		},
+		{
+			name:    "error - last owner",
+			link:    &storage.UserAccount{UserID: "user-1", AccountID: "12345678", Role: storage.UserAccountRoleOwner},
+			others:  []*storage.UserAccount{{UserID: "user-1", Role: storage.UserAccountRoleOwner}},
+			wantErr: LastAccountOwnerErr,
+		},
+		{
+			name:    "error - not linked",
+			wantErr: UserAccountNotFoundErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := storagemock.NewMockIUserDAO(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			inTransaction(ud, ad)
+			ad.EXPECT().FindByAccountIDForUpdate(mock.Anything, "12345678").Return(&storage.Account{AccountID: "12345678", Type: storage.AccountTypeWallet}, nil).Once()
+			ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(&storage.User{UserID: "user-1", Status: storage.UserStatusActive}, nil).Once()
+			if tt.link != nil {
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(tt.link, nil).Once()
+			} else {
+				ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(nil, gorm.ErrRecordNotFound).Once()
+			}
+			if tt.link != nil && tt.link.Role == storage.UserAccountRoleOwner {
+				ud.EXPECT().FindAccountUsers(mock.Anything, "12345678").Return(tt.others, nil).Once()
+			}
+			if tt.wantErr == nil {
+				ud.EXPECT().DeleteUserAccount(mock.Anything, tt.link).Return(nil).Once()
+				ud.EXPECT().FindUserAccounts(mock.Anything, "user-1").Return(nil, nil).Once()
+			}
+
+			l := &logicImpl{UserDAO: ud, AccountDAO: ad}
+			_, err := l.UnlinkAccount(context.Background(), &dto.UnlinkAccountRequest{UserID: "user-1", AccountID: "12345678"})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("UnlinkAccount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_CheckViewAccess(t *testing.T) {
+	activeUser := &storage.User{UserID: "user-1", Status: storage.UserStatusActive}
+	tests := []struct {
+		name    string
+		user    *storage.User
+		findErr error
+		link    *storage.UserAccount
+		wantErr error
+	}{
+		{
+			name: "happy path - viewer",
+			user: activeUser,
+			link: &storage.UserAccount{UserID: "user-1", AccountID: "12345678", Role: storage.UserAccountRoleViewer},
+		},
+		{
+			name:    "error - account not linked to the user",
+			user:    activeUser,
+			wantErr: AccountNotFoundErr,
+		},
+		{
+			name:    "error - suspended user",
+			user:    &storage.User{UserID: "user-1", Status: storage.UserStatusSuspended},
+			wantErr: AccountNotFoundErr,
+		},
+		{
+			name:    "error - unknown user",
+			findErr: gorm.ErrRecordNotFound,
+			wantErr: AccountNotFoundErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := storagemock.NewMockIUserDAO(t)
+			ud.EXPECT().FindByUserID(mock.Anything, "user-1").Return(tt.user, tt.findErr).Once()
+			if tt.user != nil && tt.user.Active() {
+				if tt.link != nil {
+					ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(tt.link, nil).Once()
+				} else {
+					ud.EXPECT().FindUserAccount(mock.Anything, "user-1", "12345678").Return(nil, gorm.ErrRecordNotFound).Once()
+				}
+			}
+
+			l := &logicImpl{UserDAO: ud}
+			err := l.CheckViewAccess(context.Background(), "user-1", "12345678")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Errorf("CheckViewAccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_logicImpl_ChangeUserStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *storage.User
+		status  string
+		links   []*storage.UserAccount
+		owners  []*storage.UserAccount
+		wantErr error
+	}{
+		{
+			name:   "happy path - suspend",
+			user:   &storage.User{Status: storage.UserStatusActive},
+			status: storage.UserStatusSuspended,
+		},
+		{
+			name:   "happy path - close a user who only views accounts",
+			user:   &storage.User{Status: storage.UserStatusSuspended},
+			status: storage.UserStatusClosed,
+			links:  []*storage.UserAccount{{AccountID: "12345678", Role: storage.UserAccountRoleViewer}},
+		},
+		{
+			name:    "error - close the last owner of an open account",
+			user:    &storage.User{Status: storage.UserStatusActive},
+			status:  storage.UserStatusClosed,
+			links:   []*storage.UserAccount{{AccountID: "12345678", Role: storage.UserAccountRoleOwner}},
+			owners:  []*storage.UserAccount{{UserID: "user-1", Role: storage.UserAccountRoleOwner}},
+			wantErr: LastAccountOwnerErr,
+		},
+		{
+			name:    "error - already closed",
+			user:    &storage.User{Status: storage.UserStatusClosed},
+			status:  storage.UserStatusActive,
+			wantErr: InvalidUserStatusErr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ud := storagemock.NewMockIUserDAO(t)
+			ad := storagemock.NewMockIAccountDAO(t)
+			inTransaction(ud, nil)
+			tt.user.UserID = "user-1"
+			ud.EXPECT().FindByUserIDForUpdate(mock.Anything, "user-1").Return(tt.user, nil).Once()
+			if tt.status == storage.UserStatusClosed && tt.user.Status != storage.UserStatusClosed {
+				ud.EXPECT().FindUserAccounts(mock.Anything, "user-1").Return(tt.links, nil).Once()
+			}
+			if tt.owners != nil {
+				ad.EXPECT().FindByAccountID(mock.Anything, "12345678").Return(&storage.Account{AccountID: "12345678"}, nil).Once()
+				ud.EXPECT().FindAccountUsers(mock.Anything, "12345678").Return(tt.owners, nil).Once()
+			}
+			if tt.wantErr == nil {
+				ud.EXPECT().Save(mock.Anything, tt.user).Return(nil).Once()
+				ud.EXPECT().FindUserAccounts(mock.Anything, "user-1").Return(tt.links, nil).Once()
+			}
+
+			l := &logicImpl{UserDAO: ud, AccountDAO: ad}
+			got, err := l.ChangeUserStatus(context.Background(), &dto.UserStatusRequest{UserID: "user-1", Status: tt.status})
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) {
+				t.Fatalf("ChangeUserStatus() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.Status != tt.status {
+				t.Errorf("ChangeUserStatus() status = %s, want %s", got.Status, tt.status)
+			}
+		})
+	}
+}
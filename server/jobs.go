@@ -0,0 +1,23 @@
+package server
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// runPeriodically calls fn every interval until ctx is done. Failures are logged and retried on the next tick.
+func runPeriodically(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx); err != nil {
+				log.Printf("%s failed: %v", name, err)
+			}
+		}
+	}
+}
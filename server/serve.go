@@ -1,16 +1,26 @@
 package server
 
 import (
+	"context"
+	"errors"
 	"github.com/gin-gonic/gin"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/schema"
+	"time"
+	"wallet/accountnumber"
+	"wallet/auth"
 	"wallet/handler"
+	"wallet/logic/apikey"
+	"wallet/logic/batch"
+	"wallet/logic/fx"
+	"wallet/logic/standing"
+	"wallet/logic/transfer"
 	"wallet/storage"
 )
 
-// Serve ...
-func Serve() {
+// OpenDB connects to the wallet database
+func OpenDB() *gorm.DB {
 	dsn := "host=localhost dbname=wallet port=5432 sslmode=disable TimeZone=Asia/Kuala_Lumpur"
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		NamingStrategy: schema.NamingStrategy{
@@ -20,12 +30,130 @@ func Serve() {
 	if err != nil {
 		panic("failed to connect database")
 	}
+	return db
+}
+
+// Serve ...
+func Serve() {
+	db := OpenDB()
+	accountDAO := storage.NewAccountDAO(db)
+	transactionDAO := storage.NewTransactionDAO(db)
+	transferDAO := storage.NewTransferDAO(db)
+	idempotencyRecordDAO := storage.NewIdempotencyRecordDAO(db)
+	fxQuoteDAO := storage.NewFxQuoteDAO(db)
+	holdDAO := storage.NewHoldDAO(db)
+	standingInstructionDAO := storage.NewStandingInstructionDAO(db)
+	transferBatchDAO := storage.NewTransferBatchDAO(db)
+	userDAO := storage.NewUserDAO(db)
+	apiKeyDAO := storage.NewAPIKeyDAO(db)
+	transferConfig := transfer.DefaultConfig()
+	fxConfig := fx.DefaultConfig()
+	standingConfig := standing.DefaultConfig()
+	batchConfig := batch.DefaultConfig()
+	accountNumberScheme := accountnumber.DefaultScheme()
+	if err := accountNumberScheme.Check(); err != nil {
+		panic(err)
+	}
+	accountnumber.Use(accountNumberScheme)
+	authConfig, err := auth.ConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	verifier, err := auth.NewVerifier(authConfig)
+	if err != nil {
+		panic(err)
+	}
+	apiKeyConfig, err := apikey.ConfigFromEnv()
+	if err != nil {
+		panic(err)
+	}
+	if fxConfig.RatesFile != "" {
+		rates, err := fx.NewFileRateSource(fxConfig.RatesFile)
+		if err != nil {
+			panic(err)
+		}
+		fxConfig.RateSource = rates
+	}
+
+	ctx := context.Background()
+	transferLogic := transfer.NewTransferLogic(transferDAO, accountDAO, transactionDAO, fxQuoteDAO, holdDAO, userDAO, transferConfig)
+	if transferConfig.ShardSweepInterval > 0 {
+		go runPeriodically(ctx, "holding account shard sweep", transferConfig.ShardSweepInterval, func(ctx context.Context) error {
+			var errs []error
+			for _, holdingAccountID := range transferConfig.HoldingAccountIDs {
+				errs = append(errs, transferLogic.RebalanceShards(ctx, holdingAccountID))
+			}
+			return errors.Join(errs...)
+		})
+	}
+
+	if transferConfig.IdempotencyKeyRetention > 0 && transferConfig.IdempotencyKeyPurgeInterval > 0 {
+		go runPeriodically(ctx, "idempotency key purge", transferConfig.IdempotencyKeyPurgeInterval, func(ctx context.Context) error {
+			if _, err := transferLogic.PurgeIdempotencyKeys(ctx); err != nil {
+				return err
+			}
+			_, err := idempotencyRecordDAO.DeleteCreatedBefore(ctx, time.Now().Add(-transferConfig.IdempotencyKeyRetention))
+			return err
+		})
+	}
+
+	if transferConfig.ScheduledExecutionInterval > 0 {
+		go runPeriodically(ctx, "scheduled transfer execution", transferConfig.ScheduledExecutionInterval, func(ctx context.Context) error {
+			_, err := transferLogic.ExecuteDueTransfers(ctx)
+			return err
+		})
+	}
+
+	if transferConfig.HoldSweepInterval > 0 {
+		go runPeriodically(ctx, "hold expiry sweep", transferConfig.HoldSweepInterval, func(ctx context.Context) error {
+			_, err := transferLogic.ReleaseExpiredHolds(ctx)
+			return err
+		})
+	}
+
+	if standingConfig.RunInterval > 0 {
+		standingLogic := standing.NewStandingLogic(standingInstructionDAO, accountDAO, transferLogic, standingConfig)
+		go runPeriodically(ctx, "standing instruction run", standingConfig.RunInterval, func(ctx context.Context) error {
+			_, err := standingLogic.RunDueInstructions(ctx)
+			return err
+		})
+	}
+
+	if batchConfig.ProcessInterval > 0 {
+		batchLogic := batch.NewBatchLogic(transferBatchDAO, accountDAO, transferLogic, batchConfig)
+		go runPeriodically(ctx, "transfer batch processing", batchConfig.ProcessInterval, func(ctx context.Context) error {
+			_, err := batchLogic.ProcessPendingBatches(ctx)
+			return err
+		})
+	}
+
+	if apiKeyConfig.Enabled() && apiKeyConfig.NoncePurgeInterval > 0 {
+		apiKeyLogic := apikey.NewAPIKeyLogic(apiKeyDAO, apiKeyConfig)
+		go runPeriodically(ctx, "API nonce purge", apiKeyConfig.NoncePurgeInterval, func(ctx context.Context) error {
+			_, err := apiKeyLogic.PurgeNonces(ctx)
+			return err
+		})
+	}
 
 	r := gin.Default()
 	service := handler.NewWalletService(
-		storage.NewAccountDAO(db),
-		storage.NewTransactionDAO(db),
-		storage.NewTransferDAO(db),
+		accountDAO,
+		transactionDAO,
+		transferDAO,
+		idempotencyRecordDAO,
+		fxQuoteDAO,
+		holdDAO,
+		standingInstructionDAO,
+		transferBatchDAO,
+		userDAO,
+		apiKeyDAO,
+		transferConfig,
+		fxConfig,
+		standingConfig,
+		batchConfig,
+		accountNumberScheme,
+		apiKeyConfig,
+		verifier,
 	)
 	service.RegisterRoutes(r)
 	r.Run()
@@ -4,18 +4,72 @@ import (
 	"context"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
+const (
+	AccountTypeWallet  = "WALLET"
+	AccountTypeCASA    = "CASA"
+	AccountTypeHolding = "HOLDING"
+	AccountTypeSystem  = "SYSTEM"
+	// AccountTypeFxPosition accounts hold the bank's open position in a currency from cross-currency transfers
+	AccountTypeFxPosition = "FX_POSITION"
+	// AccountTypeFeeIncome accounts collect the fees charged on transfers
+	AccountTypeFeeIncome = "FEE_INCOME"
+)
+
+const (
+	AccountStatusActive = "ACTIVE"
+	// AccountStatusFrozen accounts reject debits, credits or both, as set by their Freeze
+	AccountStatusFrozen = "FROZEN"
+	// AccountStatusClosed accounts reject every posting, for good
+	AccountStatusClosed = "CLOSED"
+)
+
+const (
+	FreezeDebit  = "DEBIT"
+	FreezeCredit = "CREDIT"
+	FreezeAll    = "ALL"
+)
+
+var ConcurrentUpdateErr = errors.New("concurrent balance update")
+
 type Account struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement" json:"id"`
-	AccountID string    `gorm:"type:varchar(64);uniqueIndex;not null" json:"account_id"`
-	Name      string    `gorm:"type:text;not null" json:"name"`
-	Type      string    `gorm:"type:varchar(20);not null;default:wallet" json:"type"`
-	Currency  string    `gorm:"type:char(3);not null;default:MYR" json:"currency"`
-	Balance   int64     `gorm:"not null;default:0" json:"balance"`
-	CreatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
-	UpdatedAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"updated_at"`
+	ID              uint64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	AccountID       string     `gorm:"type:varchar(64);uniqueIndex;not null" json:"account_id"`
+	Name            string     `gorm:"type:text;not null" json:"name"`
+	Type            string     `gorm:"type:varchar(20);not null;default:WALLET" json:"type"`
+	Currency        string     `gorm:"type:char(3);not null;default:MYR" json:"currency"`
+	Balance         int64      `gorm:"not null;default:0" json:"balance"`                         // ledger balance
+	HeldBalance     int64      `gorm:"not null;default:0" json:"held_balance"`                    // reserved by active holds
+	Tier            string     `gorm:"type:varchar(20);not null;default:''" json:"tier"`          // KYC tier selecting the account's limits, empty for the basic tier
+	ParentAccountID *string    `gorm:"type:varchar(64);index" json:"parent_account_id,omitempty"` // set on shard sub-accounts, points to the logical account they back
+	Status          string     `gorm:"type:varchar(16);not null;default:ACTIVE" json:"status"`
+	Freeze          string     `gorm:"type:varchar(8);not null;default:''" json:"freeze"` // DEBIT, CREDIT or ALL while the account is FROZEN
+	ClosedAt        *time.Time `json:"closed_at,omitempty"`
+	CreatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"not null;default:CURRENT_TIMESTAMP;index" json:"updated_at"`
+}
+
+// Available returns the part of the balance not reserved by holds
+func (a *Account) Available() int64 {
+	return a.Balance - a.HeldBalance
+}
+
+// Closed reports whether the account was closed
+func (a *Account) Closed() bool {
+	return a.Status == AccountStatusClosed
+}
+
+// DebitFrozen reports whether debits to the account are frozen
+func (a *Account) DebitFrozen() bool {
+	return a.Freeze == FreezeDebit || a.Freeze == FreezeAll
+}
+
+// CreditFrozen reports whether credits to the account are frozen
+func (a *Account) CreditFrozen() bool {
+	return a.Freeze == FreezeCredit || a.Freeze == FreezeAll
 }
 
 // accountDAO handles DB operations for accounts
@@ -23,16 +77,35 @@ type accountDAO struct {
 	DB *gorm.DB
 }
 
-// todo add mockery
 type IAccountDAO interface {
+	Create(context.Context, *Account) (*Account, error)
 	FindByAccountID(context.Context, string) (*Account, error)
+	FindByAccountIDForUpdate(context.Context, string) (*Account, error)
+	FindByAccountIDs(ctx context.Context, accountIDs []string) ([]*Account, error)
+	FindByParentAccountID(context.Context, string) ([]*Account, error)
 	UpdateBalance(context.Context, *Account, int64) error
+	UpdateBalanceLocked(context.Context, *Account, int64) error
+	ReserveBalance(ctx context.Context, accountID string, amount int64) (bool, error)
+	ReleaseBalance(ctx context.Context, accountID string, amount int64) error
+	UpdateDetails(context.Context, *Account) error
+	RunInTransaction(fn TxFn) error
+	WithTx(tx *gorm.DB) IAccountDAO
 }
 
 func NewAccountDAO(db *gorm.DB) IAccountDAO {
 	return &accountDAO{DB: db}
 }
 
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *accountDAO) WithTx(tx *gorm.DB) IAccountDAO {
+	return &accountDAO{DB: tx}
+}
+
+// RunInTransaction runs fn inside a database transaction
+func (dao *accountDAO) RunInTransaction(fn TxFn) error {
+	return dao.DB.Transaction(fn)
+}
+
 func (dao *accountDAO) FindByAccountID(ctx context.Context, accountID string) (*Account, error) {
 	var acc Account
 	err := dao.DB.WithContext(ctx).
@@ -44,6 +117,45 @@ func (dao *accountDAO) FindByAccountID(ctx context.Context, accountID string) (*
 	return &acc, nil
 }
 
+// FindByAccountIDs returns the accounts among accountIDs that exist, in no particular order
+func (dao *accountDAO) FindByAccountIDs(ctx context.Context, accountIDs []string) ([]*Account, error) {
+	var accounts []*Account
+	err := dao.DB.WithContext(ctx).
+		Where("account_id IN ?", accountIDs).
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+// FindByAccountIDForUpdate reads the account with SELECT ... FOR UPDATE. The row stays locked until the
+// surrounding transaction ends, so it must be called on a DAO bound with WithTx.
+func (dao *accountDAO) FindByAccountIDForUpdate(ctx context.Context, accountID string) (*Account, error) {
+	var acc Account
+	err := dao.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id = ?", accountID).
+		First(&acc).Error
+	if err != nil {
+		return nil, err
+	}
+	return &acc, nil
+}
+
+// FindByParentAccountID returns the shard sub-accounts of a logical account, ordered by account ID
+func (dao *accountDAO) FindByParentAccountID(ctx context.Context, parentAccountID string) ([]*Account, error) {
+	var shards []*Account
+	err := dao.DB.WithContext(ctx).
+		Where("parent_account_id = ?", parentAccountID).
+		Order("account_id ASC").
+		Find(&shards).Error
+	if err != nil {
+		return nil, err
+	}
+	return shards, nil
+}
+
 func (dao *accountDAO) Create(ctx context.Context, account *Account) (*Account, error) {
 	if createErr := dao.DB.WithContext(ctx).Create(account).Error; createErr != nil {
 		return nil, createErr
@@ -51,6 +163,24 @@ func (dao *accountDAO) Create(ctx context.Context, account *Account) (*Account,
 	return dao.FindByAccountID(ctx, account.AccountID)
 }
 
+// UpdateDetails writes the name, type and status of the account, leaving its balances alone. It moves
+// updated_at, so transfers that read the account before the change fail their optimistic balance update and
+// read it again.
+func (dao *accountDAO) UpdateDetails(ctx context.Context, acc *Account) error {
+	acc.UpdatedAt = time.Now()
+	return dao.DB.WithContext(ctx).
+		Model(&Account{}).
+		Where("account_id = ?", acc.AccountID).
+		UpdateColumns(map[string]interface{}{
+			"name":       acc.Name,
+			"type":       acc.Type,
+			"status":     acc.Status,
+			"freeze":     acc.Freeze,
+			"closed_at":  acc.ClosedAt,
+			"updated_at": acc.UpdatedAt,
+		}).Error
+}
+
 func (dao *accountDAO) UpdateBalance(ctx context.Context, selectedAccount *Account, amountDelta int64) error {
 	result := dao.DB.WithContext(ctx).
 		Model(&Account{}).
@@ -63,7 +193,46 @@ func (dao *accountDAO) UpdateBalance(ctx context.Context, selectedAccount *Accou
 		return result.Error
 	}
 	if result.RowsAffected <= 0 {
-		return errors.New("concurrent balance update")
+		return ConcurrentUpdateErr
 	}
 	return nil
 }
+
+// UpdateBalanceLocked applies amountDelta without the optimistic updated_at check. Only use it on
+// an account locked with FindByAccountIDForUpdate in the same transaction.
+func (dao *accountDAO) UpdateBalanceLocked(ctx context.Context, lockedAccount *Account, amountDelta int64) error {
+	return dao.DB.WithContext(ctx).
+		Model(&Account{}).
+		Where("account_id = ?", lockedAccount.AccountID).
+		UpdateColumns(map[string]interface{}{
+			"balance":    gorm.Expr("balance + ?", amountDelta),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// ReserveBalance adds amount to the held balance of the account if its available balance covers it, and
+// reports whether it did
+func (dao *accountDAO) ReserveBalance(ctx context.Context, accountID string, amount int64) (bool, error) {
+	result := dao.DB.WithContext(ctx).
+		Model(&Account{}).
+		Where("account_id = ? AND balance - held_balance >= ?", accountID, amount).
+		UpdateColumns(map[string]interface{}{
+			"held_balance": gorm.Expr("held_balance + ?", amount),
+			"updated_at":   time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// ReleaseBalance takes amount off the held balance of the account
+func (dao *accountDAO) ReleaseBalance(ctx context.Context, accountID string, amount int64) error {
+	return dao.DB.WithContext(ctx).
+		Model(&Account{}).
+		Where("account_id = ?", accountID).
+		UpdateColumns(map[string]interface{}{
+			"held_balance": gorm.Expr("held_balance - ?", amount),
+			"updated_at":   time.Now(),
+		}).Error
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	APIKeyStatusActive = "ACTIVE"
+	// APIKeyStatusRevoked keys are rejected straight away
+	APIKeyStatusRevoked = "REVOKED"
+)
+
+// APIKey is a credential a partner's backend signs requests with. Its secret is kept encrypted under the
+// server's master key.
+type APIKey struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	KeyID        string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_api_key_id" json:"key_id"`
+	ClientID     string     `gorm:"type:varchar(64);not null;index" json:"client_id"` // partner the key was issued to
+	SealedSecret string     `gorm:"type:varchar(255);not null" json:"-"`              // secret encrypted with AES-256-GCM under the master key
+	Scopes       string     `gorm:"type:varchar(255);not null;default:''" json:"scopes"`
+	Status       string     `gorm:"type:varchar(16);not null" json:"status"`
+	ExpiresAt    *time.Time `json:"expires_at,omitempty"` // set on a rotated key, which keeps working until then
+	RevokedAt    *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// Usable reports whether requests signed with the key are accepted at now
+func (k *APIKey) Usable(now time.Time) bool {
+	return k.Status == APIKeyStatusActive && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
+
+// APINonce records a nonce a signed request used, until the request's timestamp is too old to be accepted
+// anyway
+type APINonce struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	KeyID     string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_api_nonce,priority:1" json:"key_id"`
+	Nonce     string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_api_nonce,priority:2" json:"nonce"`
+	ExpiresAt time.Time `gorm:"not null;index" json:"expires_at"`
+}
+
+// apiKeyDAO handles DB operations for API keys and the nonces used with them
+type apiKeyDAO struct {
+	DB *gorm.DB
+}
+
+type IAPIKeyDAO interface {
+	Create(ctx context.Context, key *APIKey) error
+	Save(ctx context.Context, key *APIKey) error
+	FindByKeyID(ctx context.Context, keyID string) (*APIKey, error)
+	FindByKeyIDForUpdate(ctx context.Context, keyID string) (*APIKey, error)
+	FindByClientID(ctx context.Context, clientID string) ([]*APIKey, error)
+	ClaimNonce(ctx context.Context, nonce *APINonce) (bool, error)
+	DeleteExpiredNonces(ctx context.Context, now time.Time) (int64, error)
+	RunInTransaction(fn TxFn) error
+	WithTx(tx *gorm.DB) IAPIKeyDAO
+}
+
+func NewAPIKeyDAO(db *gorm.DB) IAPIKeyDAO {
+	return &apiKeyDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *apiKeyDAO) WithTx(tx *gorm.DB) IAPIKeyDAO {
+	return &apiKeyDAO{DB: tx}
+}
+
+// RunInTransaction runs fn inside a database transaction
+func (dao *apiKeyDAO) RunInTransaction(fn TxFn) error {
+	return dao.DB.Transaction(fn)
+}
+
+func (dao *apiKeyDAO) Create(ctx context.Context, key *APIKey) error {
+	return dao.DB.WithContext(ctx).Create(key).Error
+}
+
+func (dao *apiKeyDAO) Save(ctx context.Context, key *APIKey) error {
+	return dao.DB.WithContext(ctx).Save(key).Error
+}
+
+func (dao *apiKeyDAO) FindByKeyID(ctx context.Context, keyID string) (*APIKey, error) {
+	var key APIKey
+	err := dao.DB.WithContext(ctx).
+		Where("key_id = ?", keyID).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByKeyIDForUpdate reads the key with SELECT ... FOR UPDATE. The row stays locked until the
+// surrounding transaction ends, so it must be called on a DAO bound with WithTx.
+func (dao *apiKeyDAO) FindByKeyIDForUpdate(ctx context.Context, keyID string) (*APIKey, error) {
+	var key APIKey
+	err := dao.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("key_id = ?", keyID).
+		First(&key).Error
+	if err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// FindByClientID returns the keys issued to the client, oldest first
+func (dao *apiKeyDAO) FindByClientID(ctx context.Context, clientID string) ([]*APIKey, error) {
+	var keys []*APIKey
+	err := dao.DB.WithContext(ctx).
+		Where("client_id = ?", clientID).
+		Order("id ASC").
+		Find(&keys).Error
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// ClaimNonce records the nonce. It returns false without error if the key already used it.
+func (dao *apiKeyDAO) ClaimNonce(ctx context.Context, nonce *APINonce) (bool, error) {
+	res := dao.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(nonce)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// DeleteExpiredNonces purges the nonces expired at now and returns how many were deleted
+func (dao *apiKeyDAO) DeleteExpiredNonces(ctx context.Context, now time.Time) (int64, error) {
+	res := dao.DB.WithContext(ctx).
+		Where("expires_at < ?", now).
+		Delete(&APINonce{})
+	return res.RowsAffected, res.Error
+}
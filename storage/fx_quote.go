@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+// FxQuote is an exchange rate locked for a client until ExpiresAt. A quote converts a fixed amount and can
+// be used by exactly one transfer.
+type FxQuote struct {
+	ID                  int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	QuoteID             string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_fx_quote_id" json:"quote_id"`
+	ClientID            string     `gorm:"type:varchar(64);not null;default:''" json:"client_id"`
+	SourceCurrency      string     `gorm:"type:char(3);not null" json:"source_currency"`
+	DestinationCurrency string     `gorm:"type:char(3);not null" json:"destination_currency"`
+	Rate                string     `gorm:"type:varchar(32);not null" json:"rate"` // destination units per source unit, as a decimal string
+	SourceAmount        int64      `gorm:"not null" json:"source_amount"`         // in minor unit of the source currency
+	DestinationAmount   int64      `gorm:"not null" json:"destination_amount"`    // in minor unit of the destination currency
+	ExpiresAt           time.Time  `gorm:"not null" json:"expires_at"`
+	TransactionID       *string    `gorm:"type:varchar(36)" json:"transaction_id,omitempty"` // set once a transfer used the quote
+	UsedAt              *time.Time `json:"used_at,omitempty"`
+	CreatedAt           time.Time  `gorm:"not null;default:now()" json:"created_at"`
+}
+
+// fxQuoteDAO handles DB operations for FX quotes
+type fxQuoteDAO struct {
+	DB *gorm.DB
+}
+
+type IFxQuoteDAO interface {
+	Create(ctx context.Context, quote *FxQuote) error
+	FindByQuoteID(ctx context.Context, quoteID string) (*FxQuote, error)
+	MarkUsed(ctx context.Context, quoteID string, transactionID string) (bool, error)
+	WithTx(tx *gorm.DB) IFxQuoteDAO
+}
+
+func NewFxQuoteDAO(db *gorm.DB) IFxQuoteDAO {
+	return &fxQuoteDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *fxQuoteDAO) WithTx(tx *gorm.DB) IFxQuoteDAO {
+	return &fxQuoteDAO{DB: tx}
+}
+
+func (dao *fxQuoteDAO) Create(ctx context.Context, quote *FxQuote) error {
+	return dao.DB.WithContext(ctx).Create(quote).Error
+}
+
+func (dao *fxQuoteDAO) FindByQuoteID(ctx context.Context, quoteID string) (*FxQuote, error) {
+	var quote FxQuote
+	err := dao.DB.WithContext(ctx).
+		Where("quote_id = ?", quoteID).
+		First(&quote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+// MarkUsed assigns the quote to the transfer transactionID. It returns false if the quote has expired or
+// was already used by another transfer.
+func (dao *fxQuoteDAO) MarkUsed(ctx context.Context, quoteID string, transactionID string) (bool, error) {
+	now := time.Now()
+	res := dao.DB.WithContext(ctx).
+		Model(&FxQuote{}).
+		Where("quote_id = ? AND transaction_id IS NULL AND expires_at > ?", quoteID, now).
+		Updates(map[string]interface{}{"transaction_id": transactionID, "used_at": now})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
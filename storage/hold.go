@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	HoldStatusActive   = "ACTIVE"
+	HoldStatusCaptured = "CAPTURED"
+	HoldStatusReleased = "RELEASED"
+	HoldStatusExpired  = "EXPIRED"
+)
+
+// Hold reserves part of an account's balance for a later capture into DestinationAccountID. While it is
+// active, Amount - CapturedAmount counts towards the account's held balance.
+type Hold struct {
+	ID                   int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	HoldID               string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_hold_id" json:"hold_id"`
+	ClientID             string    `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_hold_client_reference_id,priority:1" json:"client_id"`
+	ReferenceID          string    `gorm:"type:varchar(255);not null;uniqueIndex:uk_hold_client_reference_id,priority:2" json:"reference_id"` // idempotency key of the request that placed the hold
+	AccountID            string    `gorm:"type:varchar(64);not null;index" json:"account_id"`
+	DestinationAccountID string    `gorm:"type:varchar(64);not null" json:"destination_account_id"`
+	Currency             string    `gorm:"type:char(3);not null" json:"currency"`
+	Amount               int64     `gorm:"not null" json:"amount"`                    // in minor unit
+	CapturedAmount       int64     `gorm:"not null;default:0" json:"captured_amount"` // in minor unit
+	ReleasedAmount       int64     `gorm:"not null;default:0" json:"released_amount"` // in minor unit, set once the hold is released or expired
+	Status               string    `gorm:"type:varchar(16);not null" json:"status"`
+	Reason               string    `gorm:"type:varchar(255);not null;default:''" json:"reason"`
+	ExpiresAt            time.Time `gorm:"not null" json:"expires_at"`
+	CreatedAt            time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt            time.Time `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// Remaining returns the amount still held
+func (h *Hold) Remaining() int64 {
+	if h.Status != HoldStatusActive {
+		return 0
+	}
+	return h.Amount - h.CapturedAmount
+}
+
+// holdDAO handles DB operations for holds
+type holdDAO struct {
+	DB *gorm.DB
+}
+
+type IHoldDAO interface {
+	Create(ctx context.Context, hold *Hold) error
+	Save(ctx context.Context, hold *Hold) error
+	FindByHoldID(ctx context.Context, holdID string) (*Hold, error)
+	FindByHoldIDForUpdate(ctx context.Context, holdID string) (*Hold, error)
+	FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*Hold, error)
+	FindExpired(ctx context.Context, now time.Time, limit int) ([]*Hold, error)
+	WithTx(tx *gorm.DB) IHoldDAO
+}
+
+func NewHoldDAO(db *gorm.DB) IHoldDAO {
+	return &holdDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *holdDAO) WithTx(tx *gorm.DB) IHoldDAO {
+	return &holdDAO{DB: tx}
+}
+
+func (dao *holdDAO) Create(ctx context.Context, hold *Hold) error {
+	return dao.DB.WithContext(ctx).Create(hold).Error
+}
+
+func (dao *holdDAO) Save(ctx context.Context, hold *Hold) error {
+	return dao.DB.WithContext(ctx).Save(hold).Error
+}
+
+func (dao *holdDAO) FindByHoldID(ctx context.Context, holdID string) (*Hold, error) {
+	var hold Hold
+	err := dao.DB.WithContext(ctx).
+		Where("hold_id = ?", holdID).
+		First(&hold).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// FindByHoldIDForUpdate reads the hold with SELECT ... FOR UPDATE. It must be called on a DAO bound with WithTx.
+func (dao *holdDAO) FindByHoldIDForUpdate(ctx context.Context, holdID string) (*Hold, error) {
+	var hold Hold
+	err := dao.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("hold_id = ?", holdID).
+		First(&hold).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// FindByReferenceID finds the hold placed with idempotency key referenceID by client clientID
+func (dao *holdDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*Hold, error) {
+	var hold Hold
+	err := dao.DB.WithContext(ctx).
+		Where("client_id = ? AND reference_id = ?", clientID, referenceID).
+		First(&hold).Error
+	if err != nil {
+		return nil, err
+	}
+	return &hold, nil
+}
+
+// FindExpired returns up to limit active holds that expired before now, oldest first
+func (dao *holdDAO) FindExpired(ctx context.Context, now time.Time, limit int) ([]*Hold, error) {
+	var holds []*Hold
+	err := dao.DB.WithContext(ctx).
+		Where("status = ? AND expires_at < ?", HoldStatusActive, now).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&holds).Error
+	if err != nil {
+		return nil, err
+	}
+	return holds, nil
+}
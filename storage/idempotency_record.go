@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	IdempotencyStatusInFlight  = "IN_FLIGHT"
+	IdempotencyStatusCompleted = "COMPLETED"
+)
+
+// IdempotencyRecord remembers the outcome of a request made with an idempotency key, so a retry of the
+// same request can be answered with the original response.
+type IdempotencyRecord struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	ClientID       string    `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_idempotency_client_key,priority:1" json:"client_id"`
+	IdempotencyKey string    `gorm:"type:varchar(255);not null;uniqueIndex:uk_idempotency_client_key,priority:2" json:"idempotency_key"`
+	RequestHash    string    `gorm:"type:varchar(64);not null;default:''" json:"request_hash"`
+	Status         string    `gorm:"type:varchar(16);not null" json:"status"`
+	ResponseCode   int       `gorm:"not null;default:0" json:"response_code"`
+	ContentType    string    `gorm:"type:varchar(255);not null;default:''" json:"content_type"`
+	ResponseBody   []byte    `gorm:"type:bytea" json:"response_body"`
+	CreatedAt      time.Time `gorm:"not null;default:now();index" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// idempotencyRecordDAO handles DB operations for idempotency records
+type idempotencyRecordDAO struct {
+	DB *gorm.DB
+}
+
+type IIdempotencyRecordDAO interface {
+	Claim(ctx context.Context, record *IdempotencyRecord) (bool, error)
+	ClaimStale(ctx context.Context, record *IdempotencyRecord, staleBefore time.Time) (bool, error)
+	Find(ctx context.Context, clientID string, idempotencyKey string) (*IdempotencyRecord, error)
+	Complete(ctx context.Context, record *IdempotencyRecord) error
+	Release(ctx context.Context, record *IdempotencyRecord) error
+	DeleteCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error)
+}
+
+func NewIdempotencyRecordDAO(db *gorm.DB) IIdempotencyRecordDAO {
+	return &idempotencyRecordDAO{DB: db}
+}
+
+// Claim inserts record as in flight. It returns false without error if the client already has a record
+// for the key.
+func (dao *idempotencyRecordDAO) Claim(ctx context.Context, record *IdempotencyRecord) (bool, error) {
+	record.Status = IdempotencyStatusInFlight
+	res := dao.DB.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected == 1, nil
+}
+
+// ClaimStale takes over an in-flight record last touched before staleBefore, left behind by a request
+// that never finished. It returns false if the record was completed or claimed by someone else meanwhile.
+func (dao *idempotencyRecordDAO) ClaimStale(ctx context.Context, record *IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	now := time.Now()
+	res := dao.DB.WithContext(ctx).
+		Model(&IdempotencyRecord{}).
+		Where("id = ? AND status = ? AND updated_at < ?", record.ID, IdempotencyStatusInFlight, staleBefore).
+		Updates(map[string]interface{}{"request_hash": record.RequestHash, "updated_at": now})
+	if res.Error != nil {
+		return false, res.Error
+	}
+	record.UpdatedAt = now
+	return res.RowsAffected == 1, nil
+}
+
+func (dao *idempotencyRecordDAO) Find(ctx context.Context, clientID string, idempotencyKey string) (*IdempotencyRecord, error) {
+	var record IdempotencyRecord
+	err := dao.DB.WithContext(ctx).
+		Where("client_id = ? AND idempotency_key = ?", clientID, idempotencyKey).
+		First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// Complete stores the response of a finished request
+func (dao *idempotencyRecordDAO) Complete(ctx context.Context, record *IdempotencyRecord) error {
+	record.Status = IdempotencyStatusCompleted
+	record.UpdatedAt = time.Now()
+	return dao.DB.WithContext(ctx).
+		Model(&IdempotencyRecord{}).
+		Where("id = ?", record.ID).
+		Updates(map[string]interface{}{
+			"status":        record.Status,
+			"response_code": record.ResponseCode,
+			"content_type":  record.ContentType,
+			"response_body": record.ResponseBody,
+			"updated_at":    record.UpdatedAt,
+		}).Error
+}
+
+// Release deletes the record of a request that did not reach a terminal outcome, so it can be retried
+func (dao *idempotencyRecordDAO) Release(ctx context.Context, record *IdempotencyRecord) error {
+	return dao.DB.WithContext(ctx).
+		Where("id = ? AND status = ?", record.ID, IdempotencyStatusInFlight).
+		Delete(&IdempotencyRecord{}).Error
+}
+
+// DeleteCreatedBefore purges records created before createdBefore and returns how many were deleted
+func (dao *idempotencyRecordDAO) DeleteCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error) {
+	res := dao.DB.WithContext(ctx).
+		Where("created_at < ?", createdBefore).
+		Delete(&IdempotencyRecord{})
+	return res.RowsAffected, res.Error
+}
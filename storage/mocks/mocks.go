@@ -11,15 +11,16 @@ import (
 	"wallet/storage"
 
 	mock "github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
-// NewMockIAccountDAO creates a new instance of MockIAccountDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockIAPIKeyDAO creates a new instance of MockIAPIKeyDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockIAccountDAO(t interface {
+func NewMockIAPIKeyDAO(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockIAccountDAO {
-	mock := &MockIAccountDAO{}
+}) *MockIAPIKeyDAO {
+	mock := &MockIAPIKeyDAO{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -27,68 +28,66 @@ func NewMockIAccountDAO(t interface {
 	return mock
 }
 
-// MockIAccountDAO is an autogenerated mock type for the IAccountDAO type
-type MockIAccountDAO struct {
+// MockIAPIKeyDAO is an autogenerated mock type for the IAPIKeyDAO type
+type MockIAPIKeyDAO struct {
 	mock.Mock
 }
 
-type MockIAccountDAO_Expecter struct {
+type MockIAPIKeyDAO_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockIAccountDAO) EXPECT() *MockIAccountDAO_Expecter {
-	return &MockIAccountDAO_Expecter{mock: &_m.Mock}
+func (_m *MockIAPIKeyDAO) EXPECT() *MockIAPIKeyDAO_Expecter {
+	return &MockIAPIKeyDAO_Expecter{mock: &_m.Mock}
 }
 
-// FindByAccountID provides a mock function for the type MockIAccountDAO
-func (_mock *MockIAccountDAO) FindByAccountID(context1 context.Context, s string) (*storage.Account, error) {
-	ret := _mock.Called(context1, s)
+// ClaimNonce provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) ClaimNonce(ctx context.Context, nonce *storage.APINonce) (bool, error) {
+	ret := _mock.Called(ctx, nonce)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByAccountID")
+		panic("no return value specified for ClaimNonce")
 	}
 
-	var r0 *storage.Account
+	var r0 bool
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Account, error)); ok {
-		return returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.APINonce) (bool, error)); ok {
+		return returnFunc(ctx, nonce)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Account); ok {
-		r0 = returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.APINonce) bool); ok {
+		r0 = returnFunc(ctx, nonce)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*storage.Account)
-		}
+		r0 = ret.Get(0).(bool)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(context1, s)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.APINonce) error); ok {
+		r1 = returnFunc(ctx, nonce)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockIAccountDAO_FindByAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountID'
-type MockIAccountDAO_FindByAccountID_Call struct {
+// MockIAPIKeyDAO_ClaimNonce_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimNonce'
+type MockIAPIKeyDAO_ClaimNonce_Call struct {
 	*mock.Call
 }
 
-// FindByAccountID is a helper method to define mock.On call
-//   - context1 context.Context
-//   - s string
-func (_e *MockIAccountDAO_Expecter) FindByAccountID(context1 interface{}, s interface{}) *MockIAccountDAO_FindByAccountID_Call {
-	return &MockIAccountDAO_FindByAccountID_Call{Call: _e.mock.On("FindByAccountID", context1, s)}
+// ClaimNonce is a helper method to define mock.On call
+//   - ctx context.Context
+//   - nonce *storage.APINonce
+func (_e *MockIAPIKeyDAO_Expecter) ClaimNonce(ctx interface{}, nonce interface{}) *MockIAPIKeyDAO_ClaimNonce_Call {
+	return &MockIAPIKeyDAO_ClaimNonce_Call{Call: _e.mock.On("ClaimNonce", ctx, nonce)}
 }
 
-func (_c *MockIAccountDAO_FindByAccountID_Call) Run(run func(context1 context.Context, s string)) *MockIAccountDAO_FindByAccountID_Call {
+func (_c *MockIAPIKeyDAO_ClaimNonce_Call) Run(run func(ctx context.Context, nonce *storage.APINonce)) *MockIAPIKeyDAO_ClaimNonce_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 string
+		var arg1 *storage.APINonce
 		if args[1] != nil {
-			arg1 = args[1].(string)
+			arg1 = args[1].(*storage.APINonce)
 		}
 		run(
 			arg0,
@@ -98,176 +97,248 @@ func (_c *MockIAccountDAO_FindByAccountID_Call) Run(run func(context1 context.Co
 	return _c
 }
 
-func (_c *MockIAccountDAO_FindByAccountID_Call) Return(account *storage.Account, err error) *MockIAccountDAO_FindByAccountID_Call {
-	_c.Call.Return(account, err)
+func (_c *MockIAPIKeyDAO_ClaimNonce_Call) Return(b bool, err error) *MockIAPIKeyDAO_ClaimNonce_Call {
+	_c.Call.Return(b, err)
 	return _c
 }
 
-func (_c *MockIAccountDAO_FindByAccountID_Call) RunAndReturn(run func(context1 context.Context, s string) (*storage.Account, error)) *MockIAccountDAO_FindByAccountID_Call {
+func (_c *MockIAPIKeyDAO_ClaimNonce_Call) RunAndReturn(run func(ctx context.Context, nonce *storage.APINonce) (bool, error)) *MockIAPIKeyDAO_ClaimNonce_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateBalance provides a mock function for the type MockIAccountDAO
-func (_mock *MockIAccountDAO) UpdateBalance(context1 context.Context, account *storage.Account, n int64) error {
-	ret := _mock.Called(context1, account, n)
+// Create provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) Create(ctx context.Context, key *storage.APIKey) error {
+	ret := _mock.Called(ctx, key)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateBalance")
+		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account, int64) error); ok {
-		r0 = returnFunc(context1, account, n)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.APIKey) error); ok {
+		r0 = returnFunc(ctx, key)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockIAccountDAO_UpdateBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateBalance'
-type MockIAccountDAO_UpdateBalance_Call struct {
+// MockIAPIKeyDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIAPIKeyDAO_Create_Call struct {
 	*mock.Call
 }
 
-// UpdateBalance is a helper method to define mock.On call
-//   - context1 context.Context
-//   - account *storage.Account
-//   - n int64
-func (_e *MockIAccountDAO_Expecter) UpdateBalance(context1 interface{}, account interface{}, n interface{}) *MockIAccountDAO_UpdateBalance_Call {
-	return &MockIAccountDAO_UpdateBalance_Call{Call: _e.mock.On("UpdateBalance", context1, account, n)}
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key *storage.APIKey
+func (_e *MockIAPIKeyDAO_Expecter) Create(ctx interface{}, key interface{}) *MockIAPIKeyDAO_Create_Call {
+	return &MockIAPIKeyDAO_Create_Call{Call: _e.mock.On("Create", ctx, key)}
 }
 
-func (_c *MockIAccountDAO_UpdateBalance_Call) Run(run func(context1 context.Context, account *storage.Account, n int64)) *MockIAccountDAO_UpdateBalance_Call {
+func (_c *MockIAPIKeyDAO_Create_Call) Run(run func(ctx context.Context, key *storage.APIKey)) *MockIAPIKeyDAO_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *storage.Account
+		var arg1 *storage.APIKey
 		if args[1] != nil {
-			arg1 = args[1].(*storage.Account)
-		}
-		var arg2 int64
-		if args[2] != nil {
-			arg2 = args[2].(int64)
+			arg1 = args[1].(*storage.APIKey)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockIAccountDAO_UpdateBalance_Call) Return(err error) *MockIAccountDAO_UpdateBalance_Call {
+func (_c *MockIAPIKeyDAO_Create_Call) Return(err error) *MockIAPIKeyDAO_Create_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockIAccountDAO_UpdateBalance_Call) RunAndReturn(run func(context1 context.Context, account *storage.Account, n int64) error) *MockIAccountDAO_UpdateBalance_Call {
+func (_c *MockIAPIKeyDAO_Create_Call) RunAndReturn(run func(ctx context.Context, key *storage.APIKey) error) *MockIAPIKeyDAO_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// NewMockITransactionDAO creates a new instance of MockITransactionDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockITransactionDAO(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockITransactionDAO {
-	mock := &MockITransactionDAO{}
-	mock.Mock.Test(t)
+// DeleteExpiredNonces provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) DeleteExpiredNonces(ctx context.Context, now time.Time) (int64, error) {
+	ret := _mock.Called(ctx, now)
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredNonces")
+	}
 
-	return mock
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, now)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockITransactionDAO is an autogenerated mock type for the ITransactionDAO type
-type MockITransactionDAO struct {
-	mock.Mock
+// MockIAPIKeyDAO_DeleteExpiredNonces_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredNonces'
+type MockIAPIKeyDAO_DeleteExpiredNonces_Call struct {
+	*mock.Call
 }
 
-type MockITransactionDAO_Expecter struct {
-	mock *mock.Mock
+// DeleteExpiredNonces is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+func (_e *MockIAPIKeyDAO_Expecter) DeleteExpiredNonces(ctx interface{}, now interface{}) *MockIAPIKeyDAO_DeleteExpiredNonces_Call {
+	return &MockIAPIKeyDAO_DeleteExpiredNonces_Call{Call: _e.mock.On("DeleteExpiredNonces", ctx, now)}
 }
 
-func (_m *MockITransactionDAO) EXPECT() *MockITransactionDAO_Expecter {
-	return &MockITransactionDAO_Expecter{mock: &_m.Mock}
+func (_c *MockIAPIKeyDAO_DeleteExpiredNonces_Call) Run(run func(ctx context.Context, now time.Time)) *MockIAPIKeyDAO_DeleteExpiredNonces_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
 }
 
-// NewMockITransferDAO creates a new instance of MockITransferDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
-// The first argument is typically a *testing.T value.
-func NewMockITransferDAO(t interface {
-	mock.TestingT
-	Cleanup(func())
-}) *MockITransferDAO {
-	mock := &MockITransferDAO{}
-	mock.Mock.Test(t)
+func (_c *MockIAPIKeyDAO_DeleteExpiredNonces_Call) Return(n int64, err error) *MockIAPIKeyDAO_DeleteExpiredNonces_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
 
-	t.Cleanup(func() { mock.AssertExpectations(t) })
+func (_c *MockIAPIKeyDAO_DeleteExpiredNonces_Call) RunAndReturn(run func(ctx context.Context, now time.Time) (int64, error)) *MockIAPIKeyDAO_DeleteExpiredNonces_Call {
+	_c.Call.Return(run)
+	return _c
+}
 
-	return mock
+// FindByClientID provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) FindByClientID(ctx context.Context, clientID string) ([]*storage.APIKey, error) {
+	ret := _mock.Called(ctx, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByClientID")
+	}
+
+	var r0 []*storage.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*storage.APIKey, error)); ok {
+		return returnFunc(ctx, clientID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*storage.APIKey); ok {
+		r0 = returnFunc(ctx, clientID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
 }
 
-// MockITransferDAO is an autogenerated mock type for the ITransferDAO type
-type MockITransferDAO struct {
-	mock.Mock
+// MockIAPIKeyDAO_FindByClientID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByClientID'
+type MockIAPIKeyDAO_FindByClientID_Call struct {
+	*mock.Call
 }
 
-type MockITransferDAO_Expecter struct {
-	mock *mock.Mock
+// FindByClientID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+func (_e *MockIAPIKeyDAO_Expecter) FindByClientID(ctx interface{}, clientID interface{}) *MockIAPIKeyDAO_FindByClientID_Call {
+	return &MockIAPIKeyDAO_FindByClientID_Call{Call: _e.mock.On("FindByClientID", ctx, clientID)}
 }
 
-func (_m *MockITransferDAO) EXPECT() *MockITransferDAO_Expecter {
-	return &MockITransferDAO_Expecter{mock: &_m.Mock}
+func (_c *MockIAPIKeyDAO_FindByClientID_Call) Run(run func(ctx context.Context, clientID string)) *MockIAPIKeyDAO_FindByClientID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
 }
 
-// FindByAccountIDWithCursor provides a mock function for the type MockITransferDAO
-func (_mock *MockITransferDAO) FindByAccountIDWithCursor(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time) ([]*storage.Transfer, error) {
-	ret := _mock.Called(ctx, accountID, limit, beforeTimestamp)
+func (_c *MockIAPIKeyDAO_FindByClientID_Call) Return(aPIKeys []*storage.APIKey, err error) *MockIAPIKeyDAO_FindByClientID_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockIAPIKeyDAO_FindByClientID_Call) RunAndReturn(run func(ctx context.Context, clientID string) ([]*storage.APIKey, error)) *MockIAPIKeyDAO_FindByClientID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByKeyID provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) FindByKeyID(ctx context.Context, keyID string) (*storage.APIKey, error) {
+	ret := _mock.Called(ctx, keyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByAccountIDWithCursor")
+		panic("no return value specified for FindByKeyID")
 	}
 
-	var r0 []*storage.Transfer
+	var r0 *storage.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, *time.Time) ([]*storage.Transfer, error)); ok {
-		return returnFunc(ctx, accountID, limit, beforeTimestamp)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.APIKey, error)); ok {
+		return returnFunc(ctx, keyID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, *time.Time) []*storage.Transfer); ok {
-		r0 = returnFunc(ctx, accountID, limit, beforeTimestamp)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.APIKey); ok {
+		r0 = returnFunc(ctx, keyID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]*storage.Transfer)
+			r0 = ret.Get(0).(*storage.APIKey)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, *time.Time) error); ok {
-		r1 = returnFunc(ctx, accountID, limit, beforeTimestamp)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, keyID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockITransferDAO_FindByAccountIDWithCursor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountIDWithCursor'
-type MockITransferDAO_FindByAccountIDWithCursor_Call struct {
+// MockIAPIKeyDAO_FindByKeyID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByKeyID'
+type MockIAPIKeyDAO_FindByKeyID_Call struct {
 	*mock.Call
 }
 
-// FindByAccountIDWithCursor is a helper method to define mock.On call
+// FindByKeyID is a helper method to define mock.On call
 //   - ctx context.Context
-//   - accountID string
-//   - limit int
-//   - beforeTimestamp *time.Time
-func (_e *MockITransferDAO_Expecter) FindByAccountIDWithCursor(ctx interface{}, accountID interface{}, limit interface{}, beforeTimestamp interface{}) *MockITransferDAO_FindByAccountIDWithCursor_Call {
-	return &MockITransferDAO_FindByAccountIDWithCursor_Call{Call: _e.mock.On("FindByAccountIDWithCursor", ctx, accountID, limit, beforeTimestamp)}
+//   - keyID string
+func (_e *MockIAPIKeyDAO_Expecter) FindByKeyID(ctx interface{}, keyID interface{}) *MockIAPIKeyDAO_FindByKeyID_Call {
+	return &MockIAPIKeyDAO_FindByKeyID_Call{Call: _e.mock.On("FindByKeyID", ctx, keyID)}
 }
 
-func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) Run(run func(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time)) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+func (_c *MockIAPIKeyDAO_FindByKeyID_Call) Run(run func(ctx context.Context, keyID string)) *MockIAPIKeyDAO_FindByKeyID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -277,75 +348,65 @@ func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) Run(run func(ctx cont
 		if args[1] != nil {
 			arg1 = args[1].(string)
 		}
-		var arg2 int
-		if args[2] != nil {
-			arg2 = args[2].(int)
-		}
-		var arg3 *time.Time
-		if args[3] != nil {
-			arg3 = args[3].(*time.Time)
-		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) Return(transfers []*storage.Transfer, err error) *MockITransferDAO_FindByAccountIDWithCursor_Call {
-	_c.Call.Return(transfers, err)
+func (_c *MockIAPIKeyDAO_FindByKeyID_Call) Return(aPIKey *storage.APIKey, err error) *MockIAPIKeyDAO_FindByKeyID_Call {
+	_c.Call.Return(aPIKey, err)
 	return _c
 }
 
-func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) RunAndReturn(run func(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time) ([]*storage.Transfer, error)) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+func (_c *MockIAPIKeyDAO_FindByKeyID_Call) RunAndReturn(run func(ctx context.Context, keyID string) (*storage.APIKey, error)) *MockIAPIKeyDAO_FindByKeyID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// FindByReferenceID provides a mock function for the type MockITransferDAO
-func (_mock *MockITransferDAO) FindByReferenceID(ctx context.Context, referenceID string) (*storage.Transfer, error) {
-	ret := _mock.Called(ctx, referenceID)
+// FindByKeyIDForUpdate provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) FindByKeyIDForUpdate(ctx context.Context, keyID string) (*storage.APIKey, error) {
+	ret := _mock.Called(ctx, keyID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for FindByReferenceID")
+		panic("no return value specified for FindByKeyIDForUpdate")
 	}
 
-	var r0 *storage.Transfer
+	var r0 *storage.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Transfer, error)); ok {
-		return returnFunc(ctx, referenceID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.APIKey, error)); ok {
+		return returnFunc(ctx, keyID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Transfer); ok {
-		r0 = returnFunc(ctx, referenceID)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.APIKey); ok {
+		r0 = returnFunc(ctx, keyID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*storage.Transfer)
+			r0 = ret.Get(0).(*storage.APIKey)
 		}
 	}
 	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = returnFunc(ctx, referenceID)
+		r1 = returnFunc(ctx, keyID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockITransferDAO_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
-type MockITransferDAO_FindByReferenceID_Call struct {
+// MockIAPIKeyDAO_FindByKeyIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByKeyIDForUpdate'
+type MockIAPIKeyDAO_FindByKeyIDForUpdate_Call struct {
 	*mock.Call
 }
 
-// FindByReferenceID is a helper method to define mock.On call
+// FindByKeyIDForUpdate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - referenceID string
-func (_e *MockITransferDAO_Expecter) FindByReferenceID(ctx interface{}, referenceID interface{}) *MockITransferDAO_FindByReferenceID_Call {
-	return &MockITransferDAO_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, referenceID)}
+//   - keyID string
+func (_e *MockIAPIKeyDAO_Expecter) FindByKeyIDForUpdate(ctx interface{}, keyID interface{}) *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call {
+	return &MockIAPIKeyDAO_FindByKeyIDForUpdate_Call{Call: _e.mock.On("FindByKeyIDForUpdate", ctx, keyID)}
 }
 
-func (_c *MockITransferDAO_FindByReferenceID_Call) Run(run func(ctx context.Context, referenceID string)) *MockITransferDAO_FindByReferenceID_Call {
+func (_c *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call) Run(run func(ctx context.Context, keyID string)) *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -363,78 +424,5388 @@ func (_c *MockITransferDAO_FindByReferenceID_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockITransferDAO_FindByReferenceID_Call) Return(transfer *storage.Transfer, err error) *MockITransferDAO_FindByReferenceID_Call {
-	_c.Call.Return(transfer, err)
+func (_c *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call) Return(aPIKey *storage.APIKey, err error) *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call {
+	_c.Call.Return(aPIKey, err)
 	return _c
 }
 
-func (_c *MockITransferDAO_FindByReferenceID_Call) RunAndReturn(run func(ctx context.Context, referenceID string) (*storage.Transfer, error)) *MockITransferDAO_FindByReferenceID_Call {
+func (_c *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, keyID string) (*storage.APIKey, error)) *MockIAPIKeyDAO_FindByKeyIDForUpdate_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// RunInTransaction provides a mock function for the type MockITransferDAO
-func (_mock *MockITransferDAO) RunInTransaction(fn storage.TxFn, opts ...*sql.TxOptions) error {
-	var tmpRet mock.Arguments
-	if len(opts) > 0 {
-		tmpRet = _mock.Called(fn, opts)
-	} else {
-		tmpRet = _mock.Called(fn)
-	}
-	ret := tmpRet
+// RunInTransaction provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) RunInTransaction(fn storage.TxFn) error {
+	ret := _mock.Called(fn)
 
 	if len(ret) == 0 {
 		panic("no return value specified for RunInTransaction")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(storage.TxFn, ...*sql.TxOptions) error); ok {
-		r0 = returnFunc(fn, opts...)
+	if returnFunc, ok := ret.Get(0).(func(storage.TxFn) error); ok {
+		r0 = returnFunc(fn)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockITransferDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
-type MockITransferDAO_RunInTransaction_Call struct {
+// MockIAPIKeyDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
+type MockIAPIKeyDAO_RunInTransaction_Call struct {
 	*mock.Call
 }
 
 // RunInTransaction is a helper method to define mock.On call
 //   - fn storage.TxFn
-//   - opts ...*sql.TxOptions
-func (_e *MockITransferDAO_Expecter) RunInTransaction(fn interface{}, opts ...interface{}) *MockITransferDAO_RunInTransaction_Call {
-	return &MockITransferDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction",
-		append([]interface{}{fn}, opts...)...)}
+func (_e *MockIAPIKeyDAO_Expecter) RunInTransaction(fn interface{}) *MockIAPIKeyDAO_RunInTransaction_Call {
+	return &MockIAPIKeyDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction", fn)}
 }
 
-func (_c *MockITransferDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn, opts ...*sql.TxOptions)) *MockITransferDAO_RunInTransaction_Call {
+func (_c *MockIAPIKeyDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn)) *MockIAPIKeyDAO_RunInTransaction_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 storage.TxFn
 		if args[0] != nil {
 			arg0 = args[0].(storage.TxFn)
 		}
-		var arg1 []*sql.TxOptions
-		var variadicArgs []*sql.TxOptions
-		if len(args) > 1 {
-			variadicArgs = args[1].([]*sql.TxOptions)
-		}
-		arg1 = variadicArgs
 		run(
 			arg0,
-			arg1...,
 		)
 	})
 	return _c
 }
 
-func (_c *MockITransferDAO_RunInTransaction_Call) Return(err error) *MockITransferDAO_RunInTransaction_Call {
+func (_c *MockIAPIKeyDAO_RunInTransaction_Call) Return(err error) *MockIAPIKeyDAO_RunInTransaction_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockITransferDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn, opts ...*sql.TxOptions) error) *MockITransferDAO_RunInTransaction_Call {
+func (_c *MockIAPIKeyDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn) error) *MockIAPIKeyDAO_RunInTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) Save(ctx context.Context, key *storage.APIKey) error {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.APIKey) error); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAPIKeyDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockIAPIKeyDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key *storage.APIKey
+func (_e *MockIAPIKeyDAO_Expecter) Save(ctx interface{}, key interface{}) *MockIAPIKeyDAO_Save_Call {
+	return &MockIAPIKeyDAO_Save_Call{Call: _e.mock.On("Save", ctx, key)}
+}
+
+func (_c *MockIAPIKeyDAO_Save_Call) Run(run func(ctx context.Context, key *storage.APIKey)) *MockIAPIKeyDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.APIKey
+		if args[1] != nil {
+			arg1 = args[1].(*storage.APIKey)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyDAO_Save_Call) Return(err error) *MockIAPIKeyDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAPIKeyDAO_Save_Call) RunAndReturn(run func(ctx context.Context, key *storage.APIKey) error) *MockIAPIKeyDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIAPIKeyDAO
+func (_mock *MockIAPIKeyDAO) WithTx(tx *gorm.DB) storage.IAPIKeyDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IAPIKeyDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IAPIKeyDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IAPIKeyDAO)
+		}
+	}
+	return r0
+}
+
+// MockIAPIKeyDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIAPIKeyDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIAPIKeyDAO_Expecter) WithTx(tx interface{}) *MockIAPIKeyDAO_WithTx_Call {
+	return &MockIAPIKeyDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIAPIKeyDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIAPIKeyDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAPIKeyDAO_WithTx_Call) Return(iAPIKeyDAO storage.IAPIKeyDAO) *MockIAPIKeyDAO_WithTx_Call {
+	_c.Call.Return(iAPIKeyDAO)
+	return _c
+}
+
+func (_c *MockIAPIKeyDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IAPIKeyDAO) *MockIAPIKeyDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIAccountDAO creates a new instance of MockIAccountDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIAccountDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIAccountDAO {
+	mock := &MockIAccountDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIAccountDAO is an autogenerated mock type for the IAccountDAO type
+type MockIAccountDAO struct {
+	mock.Mock
+}
+
+type MockIAccountDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIAccountDAO) EXPECT() *MockIAccountDAO_Expecter {
+	return &MockIAccountDAO_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) Create(context1 context.Context, account *storage.Account) (*storage.Account, error) {
+	ret := _mock.Called(context1, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 *storage.Account
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account) (*storage.Account, error)); ok {
+		return returnFunc(context1, account)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account) *storage.Account); ok {
+		r0 = returnFunc(context1, account)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Account)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.Account) error); ok {
+		r1 = returnFunc(context1, account)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIAccountDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - context1 context.Context
+//   - account *storage.Account
+func (_e *MockIAccountDAO_Expecter) Create(context1 interface{}, account interface{}) *MockIAccountDAO_Create_Call {
+	return &MockIAccountDAO_Create_Call{Call: _e.mock.On("Create", context1, account)}
+}
+
+func (_c *MockIAccountDAO_Create_Call) Run(run func(context1 context.Context, account *storage.Account)) *MockIAccountDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Account
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Account)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_Create_Call) Return(account *storage.Account, err error) *MockIAccountDAO_Create_Call {
+	_c.Call.Return(account, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_Create_Call) RunAndReturn(run func(context1 context.Context, account *storage.Account) (*storage.Account, error)) *MockIAccountDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByAccountID provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) FindByAccountID(context1 context.Context, s string) (*storage.Account, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAccountID")
+	}
+
+	var r0 *storage.Account
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Account, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Account); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Account)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_FindByAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountID'
+type MockIAccountDAO_FindByAccountID_Call struct {
+	*mock.Call
+}
+
+// FindByAccountID is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockIAccountDAO_Expecter) FindByAccountID(context1 interface{}, s interface{}) *MockIAccountDAO_FindByAccountID_Call {
+	return &MockIAccountDAO_FindByAccountID_Call{Call: _e.mock.On("FindByAccountID", context1, s)}
+}
+
+func (_c *MockIAccountDAO_FindByAccountID_Call) Run(run func(context1 context.Context, s string)) *MockIAccountDAO_FindByAccountID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountID_Call) Return(account *storage.Account, err error) *MockIAccountDAO_FindByAccountID_Call {
+	_c.Call.Return(account, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountID_Call) RunAndReturn(run func(context1 context.Context, s string) (*storage.Account, error)) *MockIAccountDAO_FindByAccountID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByAccountIDForUpdate provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) FindByAccountIDForUpdate(context1 context.Context, s string) (*storage.Account, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAccountIDForUpdate")
+	}
+
+	var r0 *storage.Account
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Account, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Account); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Account)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_FindByAccountIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountIDForUpdate'
+type MockIAccountDAO_FindByAccountIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByAccountIDForUpdate is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockIAccountDAO_Expecter) FindByAccountIDForUpdate(context1 interface{}, s interface{}) *MockIAccountDAO_FindByAccountIDForUpdate_Call {
+	return &MockIAccountDAO_FindByAccountIDForUpdate_Call{Call: _e.mock.On("FindByAccountIDForUpdate", context1, s)}
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDForUpdate_Call) Run(run func(context1 context.Context, s string)) *MockIAccountDAO_FindByAccountIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDForUpdate_Call) Return(account *storage.Account, err error) *MockIAccountDAO_FindByAccountIDForUpdate_Call {
+	_c.Call.Return(account, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDForUpdate_Call) RunAndReturn(run func(context1 context.Context, s string) (*storage.Account, error)) *MockIAccountDAO_FindByAccountIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByAccountIDs provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) FindByAccountIDs(ctx context.Context, accountIDs []string) ([]*storage.Account, error) {
+	ret := _mock.Called(ctx, accountIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAccountIDs")
+	}
+
+	var r0 []*storage.Account
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]*storage.Account, error)); ok {
+		return returnFunc(ctx, accountIDs)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []*storage.Account); ok {
+		r0 = returnFunc(ctx, accountIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Account)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, accountIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_FindByAccountIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountIDs'
+type MockIAccountDAO_FindByAccountIDs_Call struct {
+	*mock.Call
+}
+
+// FindByAccountIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountIDs []string
+func (_e *MockIAccountDAO_Expecter) FindByAccountIDs(ctx interface{}, accountIDs interface{}) *MockIAccountDAO_FindByAccountIDs_Call {
+	return &MockIAccountDAO_FindByAccountIDs_Call{Call: _e.mock.On("FindByAccountIDs", ctx, accountIDs)}
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDs_Call) Run(run func(ctx context.Context, accountIDs []string)) *MockIAccountDAO_FindByAccountIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDs_Call) Return(accounts []*storage.Account, err error) *MockIAccountDAO_FindByAccountIDs_Call {
+	_c.Call.Return(accounts, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByAccountIDs_Call) RunAndReturn(run func(ctx context.Context, accountIDs []string) ([]*storage.Account, error)) *MockIAccountDAO_FindByAccountIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByParentAccountID provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) FindByParentAccountID(context1 context.Context, s string) ([]*storage.Account, error) {
+	ret := _mock.Called(context1, s)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByParentAccountID")
+	}
+
+	var r0 []*storage.Account
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*storage.Account, error)); ok {
+		return returnFunc(context1, s)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*storage.Account); ok {
+		r0 = returnFunc(context1, s)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Account)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(context1, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_FindByParentAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByParentAccountID'
+type MockIAccountDAO_FindByParentAccountID_Call struct {
+	*mock.Call
+}
+
+// FindByParentAccountID is a helper method to define mock.On call
+//   - context1 context.Context
+//   - s string
+func (_e *MockIAccountDAO_Expecter) FindByParentAccountID(context1 interface{}, s interface{}) *MockIAccountDAO_FindByParentAccountID_Call {
+	return &MockIAccountDAO_FindByParentAccountID_Call{Call: _e.mock.On("FindByParentAccountID", context1, s)}
+}
+
+func (_c *MockIAccountDAO_FindByParentAccountID_Call) Run(run func(context1 context.Context, s string)) *MockIAccountDAO_FindByParentAccountID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByParentAccountID_Call) Return(accounts []*storage.Account, err error) *MockIAccountDAO_FindByParentAccountID_Call {
+	_c.Call.Return(accounts, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_FindByParentAccountID_Call) RunAndReturn(run func(context1 context.Context, s string) ([]*storage.Account, error)) *MockIAccountDAO_FindByParentAccountID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseBalance provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) ReleaseBalance(ctx context.Context, accountID string, amount int64) error {
+	ret := _mock.Called(ctx, accountID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseBalance")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = returnFunc(ctx, accountID, amount)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAccountDAO_ReleaseBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseBalance'
+type MockIAccountDAO_ReleaseBalance_Call struct {
+	*mock.Call
+}
+
+// ReleaseBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount int64
+func (_e *MockIAccountDAO_Expecter) ReleaseBalance(ctx interface{}, accountID interface{}, amount interface{}) *MockIAccountDAO_ReleaseBalance_Call {
+	return &MockIAccountDAO_ReleaseBalance_Call{Call: _e.mock.On("ReleaseBalance", ctx, accountID, amount)}
+}
+
+func (_c *MockIAccountDAO_ReleaseBalance_Call) Run(run func(ctx context.Context, accountID string, amount int64)) *MockIAccountDAO_ReleaseBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_ReleaseBalance_Call) Return(err error) *MockIAccountDAO_ReleaseBalance_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_ReleaseBalance_Call) RunAndReturn(run func(ctx context.Context, accountID string, amount int64) error) *MockIAccountDAO_ReleaseBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReserveBalance provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) ReserveBalance(ctx context.Context, accountID string, amount int64) (bool, error) {
+	ret := _mock.Called(ctx, accountID, amount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReserveBalance")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) (bool, error)); ok {
+		return returnFunc(ctx, accountID, amount)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int64) bool); ok {
+		r0 = returnFunc(ctx, accountID, amount)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = returnFunc(ctx, accountID, amount)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIAccountDAO_ReserveBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReserveBalance'
+type MockIAccountDAO_ReserveBalance_Call struct {
+	*mock.Call
+}
+
+// ReserveBalance is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - amount int64
+func (_e *MockIAccountDAO_Expecter) ReserveBalance(ctx interface{}, accountID interface{}, amount interface{}) *MockIAccountDAO_ReserveBalance_Call {
+	return &MockIAccountDAO_ReserveBalance_Call{Call: _e.mock.On("ReserveBalance", ctx, accountID, amount)}
+}
+
+func (_c *MockIAccountDAO_ReserveBalance_Call) Run(run func(ctx context.Context, accountID string, amount int64)) *MockIAccountDAO_ReserveBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_ReserveBalance_Call) Return(b bool, err error) *MockIAccountDAO_ReserveBalance_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_ReserveBalance_Call) RunAndReturn(run func(ctx context.Context, accountID string, amount int64) (bool, error)) *MockIAccountDAO_ReserveBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunInTransaction provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) RunInTransaction(fn storage.TxFn) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunInTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(storage.TxFn) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAccountDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
+type MockIAccountDAO_RunInTransaction_Call struct {
+	*mock.Call
+}
+
+// RunInTransaction is a helper method to define mock.On call
+//   - fn storage.TxFn
+func (_e *MockIAccountDAO_Expecter) RunInTransaction(fn interface{}) *MockIAccountDAO_RunInTransaction_Call {
+	return &MockIAccountDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction", fn)}
+}
+
+func (_c *MockIAccountDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn)) *MockIAccountDAO_RunInTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 storage.TxFn
+		if args[0] != nil {
+			arg0 = args[0].(storage.TxFn)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_RunInTransaction_Call) Return(err error) *MockIAccountDAO_RunInTransaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn) error) *MockIAccountDAO_RunInTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateBalance provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) UpdateBalance(context1 context.Context, account *storage.Account, n int64) error {
+	ret := _mock.Called(context1, account, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBalance")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account, int64) error); ok {
+		r0 = returnFunc(context1, account, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAccountDAO_UpdateBalance_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateBalance'
+type MockIAccountDAO_UpdateBalance_Call struct {
+	*mock.Call
+}
+
+// UpdateBalance is a helper method to define mock.On call
+//   - context1 context.Context
+//   - account *storage.Account
+//   - n int64
+func (_e *MockIAccountDAO_Expecter) UpdateBalance(context1 interface{}, account interface{}, n interface{}) *MockIAccountDAO_UpdateBalance_Call {
+	return &MockIAccountDAO_UpdateBalance_Call{Call: _e.mock.On("UpdateBalance", context1, account, n)}
+}
+
+func (_c *MockIAccountDAO_UpdateBalance_Call) Run(run func(context1 context.Context, account *storage.Account, n int64)) *MockIAccountDAO_UpdateBalance_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Account
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Account)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateBalance_Call) Return(err error) *MockIAccountDAO_UpdateBalance_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateBalance_Call) RunAndReturn(run func(context1 context.Context, account *storage.Account, n int64) error) *MockIAccountDAO_UpdateBalance_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateBalanceLocked provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) UpdateBalanceLocked(context1 context.Context, account *storage.Account, n int64) error {
+	ret := _mock.Called(context1, account, n)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateBalanceLocked")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account, int64) error); ok {
+		r0 = returnFunc(context1, account, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAccountDAO_UpdateBalanceLocked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateBalanceLocked'
+type MockIAccountDAO_UpdateBalanceLocked_Call struct {
+	*mock.Call
+}
+
+// UpdateBalanceLocked is a helper method to define mock.On call
+//   - context1 context.Context
+//   - account *storage.Account
+//   - n int64
+func (_e *MockIAccountDAO_Expecter) UpdateBalanceLocked(context1 interface{}, account interface{}, n interface{}) *MockIAccountDAO_UpdateBalanceLocked_Call {
+	return &MockIAccountDAO_UpdateBalanceLocked_Call{Call: _e.mock.On("UpdateBalanceLocked", context1, account, n)}
+}
+
+func (_c *MockIAccountDAO_UpdateBalanceLocked_Call) Run(run func(context1 context.Context, account *storage.Account, n int64)) *MockIAccountDAO_UpdateBalanceLocked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Account
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Account)
+		}
+		var arg2 int64
+		if args[2] != nil {
+			arg2 = args[2].(int64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateBalanceLocked_Call) Return(err error) *MockIAccountDAO_UpdateBalanceLocked_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateBalanceLocked_Call) RunAndReturn(run func(context1 context.Context, account *storage.Account, n int64) error) *MockIAccountDAO_UpdateBalanceLocked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateDetails provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) UpdateDetails(context1 context.Context, account *storage.Account) error {
+	ret := _mock.Called(context1, account)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateDetails")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Account) error); ok {
+		r0 = returnFunc(context1, account)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIAccountDAO_UpdateDetails_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateDetails'
+type MockIAccountDAO_UpdateDetails_Call struct {
+	*mock.Call
+}
+
+// UpdateDetails is a helper method to define mock.On call
+//   - context1 context.Context
+//   - account *storage.Account
+func (_e *MockIAccountDAO_Expecter) UpdateDetails(context1 interface{}, account interface{}) *MockIAccountDAO_UpdateDetails_Call {
+	return &MockIAccountDAO_UpdateDetails_Call{Call: _e.mock.On("UpdateDetails", context1, account)}
+}
+
+func (_c *MockIAccountDAO_UpdateDetails_Call) Run(run func(context1 context.Context, account *storage.Account)) *MockIAccountDAO_UpdateDetails_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Account
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Account)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateDetails_Call) Return(err error) *MockIAccountDAO_UpdateDetails_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIAccountDAO_UpdateDetails_Call) RunAndReturn(run func(context1 context.Context, account *storage.Account) error) *MockIAccountDAO_UpdateDetails_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIAccountDAO
+func (_mock *MockIAccountDAO) WithTx(tx *gorm.DB) storage.IAccountDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IAccountDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IAccountDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IAccountDAO)
+		}
+	}
+	return r0
+}
+
+// MockIAccountDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIAccountDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIAccountDAO_Expecter) WithTx(tx interface{}) *MockIAccountDAO_WithTx_Call {
+	return &MockIAccountDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIAccountDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIAccountDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIAccountDAO_WithTx_Call) Return(iAccountDAO storage.IAccountDAO) *MockIAccountDAO_WithTx_Call {
+	_c.Call.Return(iAccountDAO)
+	return _c
+}
+
+func (_c *MockIAccountDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IAccountDAO) *MockIAccountDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIFxQuoteDAO creates a new instance of MockIFxQuoteDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIFxQuoteDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIFxQuoteDAO {
+	mock := &MockIFxQuoteDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIFxQuoteDAO is an autogenerated mock type for the IFxQuoteDAO type
+type MockIFxQuoteDAO struct {
+	mock.Mock
+}
+
+type MockIFxQuoteDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIFxQuoteDAO) EXPECT() *MockIFxQuoteDAO_Expecter {
+	return &MockIFxQuoteDAO_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIFxQuoteDAO
+func (_mock *MockIFxQuoteDAO) Create(ctx context.Context, quote *storage.FxQuote) error {
+	ret := _mock.Called(ctx, quote)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.FxQuote) error); ok {
+		r0 = returnFunc(ctx, quote)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIFxQuoteDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIFxQuoteDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - quote *storage.FxQuote
+func (_e *MockIFxQuoteDAO_Expecter) Create(ctx interface{}, quote interface{}) *MockIFxQuoteDAO_Create_Call {
+	return &MockIFxQuoteDAO_Create_Call{Call: _e.mock.On("Create", ctx, quote)}
+}
+
+func (_c *MockIFxQuoteDAO_Create_Call) Run(run func(ctx context.Context, quote *storage.FxQuote)) *MockIFxQuoteDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.FxQuote
+		if args[1] != nil {
+			arg1 = args[1].(*storage.FxQuote)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_Create_Call) Return(err error) *MockIFxQuoteDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_Create_Call) RunAndReturn(run func(ctx context.Context, quote *storage.FxQuote) error) *MockIFxQuoteDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByQuoteID provides a mock function for the type MockIFxQuoteDAO
+func (_mock *MockIFxQuoteDAO) FindByQuoteID(ctx context.Context, quoteID string) (*storage.FxQuote, error) {
+	ret := _mock.Called(ctx, quoteID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByQuoteID")
+	}
+
+	var r0 *storage.FxQuote
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.FxQuote, error)); ok {
+		return returnFunc(ctx, quoteID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.FxQuote); ok {
+		r0 = returnFunc(ctx, quoteID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.FxQuote)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, quoteID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIFxQuoteDAO_FindByQuoteID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByQuoteID'
+type MockIFxQuoteDAO_FindByQuoteID_Call struct {
+	*mock.Call
+}
+
+// FindByQuoteID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - quoteID string
+func (_e *MockIFxQuoteDAO_Expecter) FindByQuoteID(ctx interface{}, quoteID interface{}) *MockIFxQuoteDAO_FindByQuoteID_Call {
+	return &MockIFxQuoteDAO_FindByQuoteID_Call{Call: _e.mock.On("FindByQuoteID", ctx, quoteID)}
+}
+
+func (_c *MockIFxQuoteDAO_FindByQuoteID_Call) Run(run func(ctx context.Context, quoteID string)) *MockIFxQuoteDAO_FindByQuoteID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_FindByQuoteID_Call) Return(fxQuote *storage.FxQuote, err error) *MockIFxQuoteDAO_FindByQuoteID_Call {
+	_c.Call.Return(fxQuote, err)
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_FindByQuoteID_Call) RunAndReturn(run func(ctx context.Context, quoteID string) (*storage.FxQuote, error)) *MockIFxQuoteDAO_FindByQuoteID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkUsed provides a mock function for the type MockIFxQuoteDAO
+func (_mock *MockIFxQuoteDAO) MarkUsed(ctx context.Context, quoteID string, transactionID string) (bool, error) {
+	ret := _mock.Called(ctx, quoteID, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return returnFunc(ctx, quoteID, transactionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = returnFunc(ctx, quoteID, transactionID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, quoteID, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIFxQuoteDAO_MarkUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkUsed'
+type MockIFxQuoteDAO_MarkUsed_Call struct {
+	*mock.Call
+}
+
+// MarkUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - quoteID string
+//   - transactionID string
+func (_e *MockIFxQuoteDAO_Expecter) MarkUsed(ctx interface{}, quoteID interface{}, transactionID interface{}) *MockIFxQuoteDAO_MarkUsed_Call {
+	return &MockIFxQuoteDAO_MarkUsed_Call{Call: _e.mock.On("MarkUsed", ctx, quoteID, transactionID)}
+}
+
+func (_c *MockIFxQuoteDAO_MarkUsed_Call) Run(run func(ctx context.Context, quoteID string, transactionID string)) *MockIFxQuoteDAO_MarkUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_MarkUsed_Call) Return(b bool, err error) *MockIFxQuoteDAO_MarkUsed_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_MarkUsed_Call) RunAndReturn(run func(ctx context.Context, quoteID string, transactionID string) (bool, error)) *MockIFxQuoteDAO_MarkUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIFxQuoteDAO
+func (_mock *MockIFxQuoteDAO) WithTx(tx *gorm.DB) storage.IFxQuoteDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IFxQuoteDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IFxQuoteDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IFxQuoteDAO)
+		}
+	}
+	return r0
+}
+
+// MockIFxQuoteDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIFxQuoteDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIFxQuoteDAO_Expecter) WithTx(tx interface{}) *MockIFxQuoteDAO_WithTx_Call {
+	return &MockIFxQuoteDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIFxQuoteDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIFxQuoteDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_WithTx_Call) Return(iFxQuoteDAO storage.IFxQuoteDAO) *MockIFxQuoteDAO_WithTx_Call {
+	_c.Call.Return(iFxQuoteDAO)
+	return _c
+}
+
+func (_c *MockIFxQuoteDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IFxQuoteDAO) *MockIFxQuoteDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIHoldDAO creates a new instance of MockIHoldDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIHoldDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIHoldDAO {
+	mock := &MockIHoldDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIHoldDAO is an autogenerated mock type for the IHoldDAO type
+type MockIHoldDAO struct {
+	mock.Mock
+}
+
+type MockIHoldDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIHoldDAO) EXPECT() *MockIHoldDAO_Expecter {
+	return &MockIHoldDAO_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) Create(ctx context.Context, hold *storage.Hold) error {
+	ret := _mock.Called(ctx, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Hold) error); ok {
+		r0 = returnFunc(ctx, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIHoldDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIHoldDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hold *storage.Hold
+func (_e *MockIHoldDAO_Expecter) Create(ctx interface{}, hold interface{}) *MockIHoldDAO_Create_Call {
+	return &MockIHoldDAO_Create_Call{Call: _e.mock.On("Create", ctx, hold)}
+}
+
+func (_c *MockIHoldDAO_Create_Call) Run(run func(ctx context.Context, hold *storage.Hold)) *MockIHoldDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Hold
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Hold)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_Create_Call) Return(err error) *MockIHoldDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_Create_Call) RunAndReturn(run func(ctx context.Context, hold *storage.Hold) error) *MockIHoldDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByHoldID provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) FindByHoldID(ctx context.Context, holdID string) (*storage.Hold, error) {
+	ret := _mock.Called(ctx, holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByHoldID")
+	}
+
+	var r0 *storage.Hold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Hold, error)); ok {
+		return returnFunc(ctx, holdID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Hold); ok {
+		r0 = returnFunc(ctx, holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Hold)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIHoldDAO_FindByHoldID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByHoldID'
+type MockIHoldDAO_FindByHoldID_Call struct {
+	*mock.Call
+}
+
+// FindByHoldID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holdID string
+func (_e *MockIHoldDAO_Expecter) FindByHoldID(ctx interface{}, holdID interface{}) *MockIHoldDAO_FindByHoldID_Call {
+	return &MockIHoldDAO_FindByHoldID_Call{Call: _e.mock.On("FindByHoldID", ctx, holdID)}
+}
+
+func (_c *MockIHoldDAO_FindByHoldID_Call) Run(run func(ctx context.Context, holdID string)) *MockIHoldDAO_FindByHoldID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByHoldID_Call) Return(hold *storage.Hold, err error) *MockIHoldDAO_FindByHoldID_Call {
+	_c.Call.Return(hold, err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByHoldID_Call) RunAndReturn(run func(ctx context.Context, holdID string) (*storage.Hold, error)) *MockIHoldDAO_FindByHoldID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByHoldIDForUpdate provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) FindByHoldIDForUpdate(ctx context.Context, holdID string) (*storage.Hold, error) {
+	ret := _mock.Called(ctx, holdID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByHoldIDForUpdate")
+	}
+
+	var r0 *storage.Hold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Hold, error)); ok {
+		return returnFunc(ctx, holdID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Hold); ok {
+		r0 = returnFunc(ctx, holdID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Hold)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, holdID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIHoldDAO_FindByHoldIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByHoldIDForUpdate'
+type MockIHoldDAO_FindByHoldIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByHoldIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - holdID string
+func (_e *MockIHoldDAO_Expecter) FindByHoldIDForUpdate(ctx interface{}, holdID interface{}) *MockIHoldDAO_FindByHoldIDForUpdate_Call {
+	return &MockIHoldDAO_FindByHoldIDForUpdate_Call{Call: _e.mock.On("FindByHoldIDForUpdate", ctx, holdID)}
+}
+
+func (_c *MockIHoldDAO_FindByHoldIDForUpdate_Call) Run(run func(ctx context.Context, holdID string)) *MockIHoldDAO_FindByHoldIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByHoldIDForUpdate_Call) Return(hold *storage.Hold, err error) *MockIHoldDAO_FindByHoldIDForUpdate_Call {
+	_c.Call.Return(hold, err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByHoldIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, holdID string) (*storage.Hold, error)) *MockIHoldDAO_FindByHoldIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferenceID provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*storage.Hold, error) {
+	ret := _mock.Called(ctx, clientID, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferenceID")
+	}
+
+	var r0 *storage.Hold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.Hold, error)); ok {
+		return returnFunc(ctx, clientID, referenceID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.Hold); ok {
+		r0 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Hold)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIHoldDAO_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
+type MockIHoldDAO_FindByReferenceID_Call struct {
+	*mock.Call
+}
+
+// FindByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+//   - referenceID string
+func (_e *MockIHoldDAO_Expecter) FindByReferenceID(ctx interface{}, clientID interface{}, referenceID interface{}) *MockIHoldDAO_FindByReferenceID_Call {
+	return &MockIHoldDAO_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, clientID, referenceID)}
+}
+
+func (_c *MockIHoldDAO_FindByReferenceID_Call) Run(run func(ctx context.Context, clientID string, referenceID string)) *MockIHoldDAO_FindByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByReferenceID_Call) Return(hold *storage.Hold, err error) *MockIHoldDAO_FindByReferenceID_Call {
+	_c.Call.Return(hold, err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindByReferenceID_Call) RunAndReturn(run func(ctx context.Context, clientID string, referenceID string) (*storage.Hold, error)) *MockIHoldDAO_FindByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindExpired provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) FindExpired(ctx context.Context, now time.Time, limit int) ([]*storage.Hold, error) {
+	ret := _mock.Called(ctx, now, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindExpired")
+	}
+
+	var r0 []*storage.Hold
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) ([]*storage.Hold, error)); ok {
+		return returnFunc(ctx, now, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int) []*storage.Hold); ok {
+		r0 = returnFunc(ctx, now, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Hold)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = returnFunc(ctx, now, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIHoldDAO_FindExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindExpired'
+type MockIHoldDAO_FindExpired_Call struct {
+	*mock.Call
+}
+
+// FindExpired is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - limit int
+func (_e *MockIHoldDAO_Expecter) FindExpired(ctx interface{}, now interface{}, limit interface{}) *MockIHoldDAO_FindExpired_Call {
+	return &MockIHoldDAO_FindExpired_Call{Call: _e.mock.On("FindExpired", ctx, now, limit)}
+}
+
+func (_c *MockIHoldDAO_FindExpired_Call) Run(run func(ctx context.Context, now time.Time, limit int)) *MockIHoldDAO_FindExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindExpired_Call) Return(holds []*storage.Hold, err error) *MockIHoldDAO_FindExpired_Call {
+	_c.Call.Return(holds, err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_FindExpired_Call) RunAndReturn(run func(ctx context.Context, now time.Time, limit int) ([]*storage.Hold, error)) *MockIHoldDAO_FindExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) Save(ctx context.Context, hold *storage.Hold) error {
+	ret := _mock.Called(ctx, hold)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Hold) error); ok {
+		r0 = returnFunc(ctx, hold)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIHoldDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockIHoldDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hold *storage.Hold
+func (_e *MockIHoldDAO_Expecter) Save(ctx interface{}, hold interface{}) *MockIHoldDAO_Save_Call {
+	return &MockIHoldDAO_Save_Call{Call: _e.mock.On("Save", ctx, hold)}
+}
+
+func (_c *MockIHoldDAO_Save_Call) Run(run func(ctx context.Context, hold *storage.Hold)) *MockIHoldDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Hold
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Hold)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_Save_Call) Return(err error) *MockIHoldDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIHoldDAO_Save_Call) RunAndReturn(run func(ctx context.Context, hold *storage.Hold) error) *MockIHoldDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIHoldDAO
+func (_mock *MockIHoldDAO) WithTx(tx *gorm.DB) storage.IHoldDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IHoldDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IHoldDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IHoldDAO)
+		}
+	}
+	return r0
+}
+
+// MockIHoldDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIHoldDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIHoldDAO_Expecter) WithTx(tx interface{}) *MockIHoldDAO_WithTx_Call {
+	return &MockIHoldDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIHoldDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIHoldDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIHoldDAO_WithTx_Call) Return(iHoldDAO storage.IHoldDAO) *MockIHoldDAO_WithTx_Call {
+	_c.Call.Return(iHoldDAO)
+	return _c
+}
+
+func (_c *MockIHoldDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IHoldDAO) *MockIHoldDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIIdempotencyRecordDAO creates a new instance of MockIIdempotencyRecordDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIIdempotencyRecordDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIIdempotencyRecordDAO {
+	mock := &MockIIdempotencyRecordDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIIdempotencyRecordDAO is an autogenerated mock type for the IIdempotencyRecordDAO type
+type MockIIdempotencyRecordDAO struct {
+	mock.Mock
+}
+
+type MockIIdempotencyRecordDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIIdempotencyRecordDAO) EXPECT() *MockIIdempotencyRecordDAO_Expecter {
+	return &MockIIdempotencyRecordDAO_Expecter{mock: &_m.Mock}
+}
+
+// Claim provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) Claim(ctx context.Context, record *storage.IdempotencyRecord) (bool, error) {
+	ret := _mock.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Claim")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord) (bool, error)); ok {
+		return returnFunc(ctx, record)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord) bool); ok {
+		r0 = returnFunc(ctx, record)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.IdempotencyRecord) error); ok {
+		r1 = returnFunc(ctx, record)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIIdempotencyRecordDAO_Claim_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Claim'
+type MockIIdempotencyRecordDAO_Claim_Call struct {
+	*mock.Call
+}
+
+// Claim is a helper method to define mock.On call
+//   - ctx context.Context
+//   - record *storage.IdempotencyRecord
+func (_e *MockIIdempotencyRecordDAO_Expecter) Claim(ctx interface{}, record interface{}) *MockIIdempotencyRecordDAO_Claim_Call {
+	return &MockIIdempotencyRecordDAO_Claim_Call{Call: _e.mock.On("Claim", ctx, record)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_Claim_Call) Run(run func(ctx context.Context, record *storage.IdempotencyRecord)) *MockIIdempotencyRecordDAO_Claim_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.IdempotencyRecord
+		if args[1] != nil {
+			arg1 = args[1].(*storage.IdempotencyRecord)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Claim_Call) Return(b bool, err error) *MockIIdempotencyRecordDAO_Claim_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Claim_Call) RunAndReturn(run func(ctx context.Context, record *storage.IdempotencyRecord) (bool, error)) *MockIIdempotencyRecordDAO_Claim_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ClaimStale provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) ClaimStale(ctx context.Context, record *storage.IdempotencyRecord, staleBefore time.Time) (bool, error) {
+	ret := _mock.Called(ctx, record, staleBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimStale")
+	}
+
+	var r0 bool
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord, time.Time) (bool, error)); ok {
+		return returnFunc(ctx, record, staleBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord, time.Time) bool); ok {
+		r0 = returnFunc(ctx, record, staleBefore)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.IdempotencyRecord, time.Time) error); ok {
+		r1 = returnFunc(ctx, record, staleBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIIdempotencyRecordDAO_ClaimStale_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimStale'
+type MockIIdempotencyRecordDAO_ClaimStale_Call struct {
+	*mock.Call
+}
+
+// ClaimStale is a helper method to define mock.On call
+//   - ctx context.Context
+//   - record *storage.IdempotencyRecord
+//   - staleBefore time.Time
+func (_e *MockIIdempotencyRecordDAO_Expecter) ClaimStale(ctx interface{}, record interface{}, staleBefore interface{}) *MockIIdempotencyRecordDAO_ClaimStale_Call {
+	return &MockIIdempotencyRecordDAO_ClaimStale_Call{Call: _e.mock.On("ClaimStale", ctx, record, staleBefore)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_ClaimStale_Call) Run(run func(ctx context.Context, record *storage.IdempotencyRecord, staleBefore time.Time)) *MockIIdempotencyRecordDAO_ClaimStale_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.IdempotencyRecord
+		if args[1] != nil {
+			arg1 = args[1].(*storage.IdempotencyRecord)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_ClaimStale_Call) Return(b bool, err error) *MockIIdempotencyRecordDAO_ClaimStale_Call {
+	_c.Call.Return(b, err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_ClaimStale_Call) RunAndReturn(run func(ctx context.Context, record *storage.IdempotencyRecord, staleBefore time.Time) (bool, error)) *MockIIdempotencyRecordDAO_ClaimStale_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Complete provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) Complete(ctx context.Context, record *storage.IdempotencyRecord) error {
+	ret := _mock.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord) error); ok {
+		r0 = returnFunc(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIIdempotencyRecordDAO_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type MockIIdempotencyRecordDAO_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - record *storage.IdempotencyRecord
+func (_e *MockIIdempotencyRecordDAO_Expecter) Complete(ctx interface{}, record interface{}) *MockIIdempotencyRecordDAO_Complete_Call {
+	return &MockIIdempotencyRecordDAO_Complete_Call{Call: _e.mock.On("Complete", ctx, record)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_Complete_Call) Run(run func(ctx context.Context, record *storage.IdempotencyRecord)) *MockIIdempotencyRecordDAO_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.IdempotencyRecord
+		if args[1] != nil {
+			arg1 = args[1].(*storage.IdempotencyRecord)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Complete_Call) Return(err error) *MockIIdempotencyRecordDAO_Complete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Complete_Call) RunAndReturn(run func(ctx context.Context, record *storage.IdempotencyRecord) error) *MockIIdempotencyRecordDAO_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCreatedBefore provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) DeleteCreatedBefore(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ret := _mock.Called(ctx, createdBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCreatedBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, createdBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, createdBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, createdBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCreatedBefore'
+type MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteCreatedBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBefore time.Time
+func (_e *MockIIdempotencyRecordDAO_Expecter) DeleteCreatedBefore(ctx interface{}, createdBefore interface{}) *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call {
+	return &MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call{Call: _e.mock.On("DeleteCreatedBefore", ctx, createdBefore)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call) Run(run func(ctx context.Context, createdBefore time.Time)) *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call) Return(n int64, err error) *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call) RunAndReturn(run func(ctx context.Context, createdBefore time.Time) (int64, error)) *MockIIdempotencyRecordDAO_DeleteCreatedBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Find provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) Find(ctx context.Context, clientID string, idempotencyKey string) (*storage.IdempotencyRecord, error) {
+	ret := _mock.Called(ctx, clientID, idempotencyKey)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Find")
+	}
+
+	var r0 *storage.IdempotencyRecord
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.IdempotencyRecord, error)); ok {
+		return returnFunc(ctx, clientID, idempotencyKey)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.IdempotencyRecord); ok {
+		r0 = returnFunc(ctx, clientID, idempotencyKey)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.IdempotencyRecord)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, clientID, idempotencyKey)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIIdempotencyRecordDAO_Find_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Find'
+type MockIIdempotencyRecordDAO_Find_Call struct {
+	*mock.Call
+}
+
+// Find is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+//   - idempotencyKey string
+func (_e *MockIIdempotencyRecordDAO_Expecter) Find(ctx interface{}, clientID interface{}, idempotencyKey interface{}) *MockIIdempotencyRecordDAO_Find_Call {
+	return &MockIIdempotencyRecordDAO_Find_Call{Call: _e.mock.On("Find", ctx, clientID, idempotencyKey)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_Find_Call) Run(run func(ctx context.Context, clientID string, idempotencyKey string)) *MockIIdempotencyRecordDAO_Find_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Find_Call) Return(idempotencyRecord *storage.IdempotencyRecord, err error) *MockIIdempotencyRecordDAO_Find_Call {
+	_c.Call.Return(idempotencyRecord, err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Find_Call) RunAndReturn(run func(ctx context.Context, clientID string, idempotencyKey string) (*storage.IdempotencyRecord, error)) *MockIIdempotencyRecordDAO_Find_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Release provides a mock function for the type MockIIdempotencyRecordDAO
+func (_mock *MockIIdempotencyRecordDAO) Release(ctx context.Context, record *storage.IdempotencyRecord) error {
+	ret := _mock.Called(ctx, record)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Release")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.IdempotencyRecord) error); ok {
+		r0 = returnFunc(ctx, record)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIIdempotencyRecordDAO_Release_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Release'
+type MockIIdempotencyRecordDAO_Release_Call struct {
+	*mock.Call
+}
+
+// Release is a helper method to define mock.On call
+//   - ctx context.Context
+//   - record *storage.IdempotencyRecord
+func (_e *MockIIdempotencyRecordDAO_Expecter) Release(ctx interface{}, record interface{}) *MockIIdempotencyRecordDAO_Release_Call {
+	return &MockIIdempotencyRecordDAO_Release_Call{Call: _e.mock.On("Release", ctx, record)}
+}
+
+func (_c *MockIIdempotencyRecordDAO_Release_Call) Run(run func(ctx context.Context, record *storage.IdempotencyRecord)) *MockIIdempotencyRecordDAO_Release_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.IdempotencyRecord
+		if args[1] != nil {
+			arg1 = args[1].(*storage.IdempotencyRecord)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Release_Call) Return(err error) *MockIIdempotencyRecordDAO_Release_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIIdempotencyRecordDAO_Release_Call) RunAndReturn(run func(ctx context.Context, record *storage.IdempotencyRecord) error) *MockIIdempotencyRecordDAO_Release_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIStandingInstructionDAO creates a new instance of MockIStandingInstructionDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIStandingInstructionDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIStandingInstructionDAO {
+	mock := &MockIStandingInstructionDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIStandingInstructionDAO is an autogenerated mock type for the IStandingInstructionDAO type
+type MockIStandingInstructionDAO struct {
+	mock.Mock
+}
+
+type MockIStandingInstructionDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIStandingInstructionDAO) EXPECT() *MockIStandingInstructionDAO_Expecter {
+	return &MockIStandingInstructionDAO_Expecter{mock: &_m.Mock}
+}
+
+// ClaimDue provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.StandingInstruction, error) {
+	ret := _mock.Called(ctx, now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 []*storage.StandingInstruction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) ([]*storage.StandingInstruction, error)); ok {
+		return returnFunc(ctx, now, lease, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) []*storage.StandingInstruction); ok {
+		r0 = returnFunc(ctx, now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.StandingInstruction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, int) error); ok {
+		r1 = returnFunc(ctx, now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStandingInstructionDAO_ClaimDue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimDue'
+type MockIStandingInstructionDAO_ClaimDue_Call struct {
+	*mock.Call
+}
+
+// ClaimDue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *MockIStandingInstructionDAO_Expecter) ClaimDue(ctx interface{}, now interface{}, lease interface{}, limit interface{}) *MockIStandingInstructionDAO_ClaimDue_Call {
+	return &MockIStandingInstructionDAO_ClaimDue_Call{Call: _e.mock.On("ClaimDue", ctx, now, lease, limit)}
+}
+
+func (_c *MockIStandingInstructionDAO_ClaimDue_Call) Run(run func(ctx context.Context, now time.Time, lease time.Duration, limit int)) *MockIStandingInstructionDAO_ClaimDue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_ClaimDue_Call) Return(standingInstructions []*storage.StandingInstruction, err error) *MockIStandingInstructionDAO_ClaimDue_Call {
+	_c.Call.Return(standingInstructions, err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_ClaimDue_Call) RunAndReturn(run func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.StandingInstruction, error)) *MockIStandingInstructionDAO_ClaimDue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) Create(ctx context.Context, si *storage.StandingInstruction) error {
+	ret := _mock.Called(ctx, si)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.StandingInstruction) error); ok {
+		r0 = returnFunc(ctx, si)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStandingInstructionDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIStandingInstructionDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - si *storage.StandingInstruction
+func (_e *MockIStandingInstructionDAO_Expecter) Create(ctx interface{}, si interface{}) *MockIStandingInstructionDAO_Create_Call {
+	return &MockIStandingInstructionDAO_Create_Call{Call: _e.mock.On("Create", ctx, si)}
+}
+
+func (_c *MockIStandingInstructionDAO_Create_Call) Run(run func(ctx context.Context, si *storage.StandingInstruction)) *MockIStandingInstructionDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.StandingInstruction
+		if args[1] != nil {
+			arg1 = args[1].(*storage.StandingInstruction)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_Create_Call) Return(err error) *MockIStandingInstructionDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_Create_Call) RunAndReturn(run func(ctx context.Context, si *storage.StandingInstruction) error) *MockIStandingInstructionDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByInstructionID provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) FindByInstructionID(ctx context.Context, instructionID string) (*storage.StandingInstruction, error) {
+	ret := _mock.Called(ctx, instructionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByInstructionID")
+	}
+
+	var r0 *storage.StandingInstruction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.StandingInstruction, error)); ok {
+		return returnFunc(ctx, instructionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.StandingInstruction); ok {
+		r0 = returnFunc(ctx, instructionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.StandingInstruction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, instructionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStandingInstructionDAO_FindByInstructionID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByInstructionID'
+type MockIStandingInstructionDAO_FindByInstructionID_Call struct {
+	*mock.Call
+}
+
+// FindByInstructionID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - instructionID string
+func (_e *MockIStandingInstructionDAO_Expecter) FindByInstructionID(ctx interface{}, instructionID interface{}) *MockIStandingInstructionDAO_FindByInstructionID_Call {
+	return &MockIStandingInstructionDAO_FindByInstructionID_Call{Call: _e.mock.On("FindByInstructionID", ctx, instructionID)}
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionID_Call) Run(run func(ctx context.Context, instructionID string)) *MockIStandingInstructionDAO_FindByInstructionID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionID_Call) Return(standingInstruction *storage.StandingInstruction, err error) *MockIStandingInstructionDAO_FindByInstructionID_Call {
+	_c.Call.Return(standingInstruction, err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionID_Call) RunAndReturn(run func(ctx context.Context, instructionID string) (*storage.StandingInstruction, error)) *MockIStandingInstructionDAO_FindByInstructionID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByInstructionIDForUpdate provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) FindByInstructionIDForUpdate(ctx context.Context, instructionID string) (*storage.StandingInstruction, error) {
+	ret := _mock.Called(ctx, instructionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByInstructionIDForUpdate")
+	}
+
+	var r0 *storage.StandingInstruction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.StandingInstruction, error)); ok {
+		return returnFunc(ctx, instructionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.StandingInstruction); ok {
+		r0 = returnFunc(ctx, instructionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.StandingInstruction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, instructionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByInstructionIDForUpdate'
+type MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByInstructionIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - instructionID string
+func (_e *MockIStandingInstructionDAO_Expecter) FindByInstructionIDForUpdate(ctx interface{}, instructionID interface{}) *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call {
+	return &MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call{Call: _e.mock.On("FindByInstructionIDForUpdate", ctx, instructionID)}
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call) Run(run func(ctx context.Context, instructionID string)) *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call) Return(standingInstruction *storage.StandingInstruction, err error) *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call {
+	_c.Call.Return(standingInstruction, err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, instructionID string) (*storage.StandingInstruction, error)) *MockIStandingInstructionDAO_FindByInstructionIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferenceID provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*storage.StandingInstruction, error) {
+	ret := _mock.Called(ctx, clientID, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferenceID")
+	}
+
+	var r0 *storage.StandingInstruction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.StandingInstruction, error)); ok {
+		return returnFunc(ctx, clientID, referenceID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.StandingInstruction); ok {
+		r0 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.StandingInstruction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIStandingInstructionDAO_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
+type MockIStandingInstructionDAO_FindByReferenceID_Call struct {
+	*mock.Call
+}
+
+// FindByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+//   - referenceID string
+func (_e *MockIStandingInstructionDAO_Expecter) FindByReferenceID(ctx interface{}, clientID interface{}, referenceID interface{}) *MockIStandingInstructionDAO_FindByReferenceID_Call {
+	return &MockIStandingInstructionDAO_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, clientID, referenceID)}
+}
+
+func (_c *MockIStandingInstructionDAO_FindByReferenceID_Call) Run(run func(ctx context.Context, clientID string, referenceID string)) *MockIStandingInstructionDAO_FindByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByReferenceID_Call) Return(standingInstruction *storage.StandingInstruction, err error) *MockIStandingInstructionDAO_FindByReferenceID_Call {
+	_c.Call.Return(standingInstruction, err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_FindByReferenceID_Call) RunAndReturn(run func(ctx context.Context, clientID string, referenceID string) (*storage.StandingInstruction, error)) *MockIStandingInstructionDAO_FindByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunInTransaction provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) RunInTransaction(fn storage.TxFn) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunInTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(storage.TxFn) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStandingInstructionDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
+type MockIStandingInstructionDAO_RunInTransaction_Call struct {
+	*mock.Call
+}
+
+// RunInTransaction is a helper method to define mock.On call
+//   - fn storage.TxFn
+func (_e *MockIStandingInstructionDAO_Expecter) RunInTransaction(fn interface{}) *MockIStandingInstructionDAO_RunInTransaction_Call {
+	return &MockIStandingInstructionDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction", fn)}
+}
+
+func (_c *MockIStandingInstructionDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn)) *MockIStandingInstructionDAO_RunInTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 storage.TxFn
+		if args[0] != nil {
+			arg0 = args[0].(storage.TxFn)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_RunInTransaction_Call) Return(err error) *MockIStandingInstructionDAO_RunInTransaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn) error) *MockIStandingInstructionDAO_RunInTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) Save(ctx context.Context, si *storage.StandingInstruction) error {
+	ret := _mock.Called(ctx, si)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.StandingInstruction) error); ok {
+		r0 = returnFunc(ctx, si)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIStandingInstructionDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockIStandingInstructionDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - si *storage.StandingInstruction
+func (_e *MockIStandingInstructionDAO_Expecter) Save(ctx interface{}, si interface{}) *MockIStandingInstructionDAO_Save_Call {
+	return &MockIStandingInstructionDAO_Save_Call{Call: _e.mock.On("Save", ctx, si)}
+}
+
+func (_c *MockIStandingInstructionDAO_Save_Call) Run(run func(ctx context.Context, si *storage.StandingInstruction)) *MockIStandingInstructionDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.StandingInstruction
+		if args[1] != nil {
+			arg1 = args[1].(*storage.StandingInstruction)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_Save_Call) Return(err error) *MockIStandingInstructionDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_Save_Call) RunAndReturn(run func(ctx context.Context, si *storage.StandingInstruction) error) *MockIStandingInstructionDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIStandingInstructionDAO
+func (_mock *MockIStandingInstructionDAO) WithTx(tx *gorm.DB) storage.IStandingInstructionDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IStandingInstructionDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IStandingInstructionDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IStandingInstructionDAO)
+		}
+	}
+	return r0
+}
+
+// MockIStandingInstructionDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIStandingInstructionDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIStandingInstructionDAO_Expecter) WithTx(tx interface{}) *MockIStandingInstructionDAO_WithTx_Call {
+	return &MockIStandingInstructionDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIStandingInstructionDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIStandingInstructionDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_WithTx_Call) Return(iStandingInstructionDAO storage.IStandingInstructionDAO) *MockIStandingInstructionDAO_WithTx_Call {
+	_c.Call.Return(iStandingInstructionDAO)
+	return _c
+}
+
+func (_c *MockIStandingInstructionDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IStandingInstructionDAO) *MockIStandingInstructionDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockITransactionDAO creates a new instance of MockITransactionDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITransactionDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITransactionDAO {
+	mock := &MockITransactionDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockITransactionDAO is an autogenerated mock type for the ITransactionDAO type
+type MockITransactionDAO struct {
+	mock.Mock
+}
+
+type MockITransactionDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITransactionDAO) EXPECT() *MockITransactionDAO_Expecter {
+	return &MockITransactionDAO_Expecter{mock: &_m.Mock}
+}
+
+// CreateJournal provides a mock function for the type MockITransactionDAO
+func (_mock *MockITransactionDAO) CreateJournal(ctx context.Context, legs []*storage.Transaction) error {
+	ret := _mock.Called(ctx, legs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateJournal")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*storage.Transaction) error); ok {
+		r0 = returnFunc(ctx, legs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransactionDAO_CreateJournal_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateJournal'
+type MockITransactionDAO_CreateJournal_Call struct {
+	*mock.Call
+}
+
+// CreateJournal is a helper method to define mock.On call
+//   - ctx context.Context
+//   - legs []*storage.Transaction
+func (_e *MockITransactionDAO_Expecter) CreateJournal(ctx interface{}, legs interface{}) *MockITransactionDAO_CreateJournal_Call {
+	return &MockITransactionDAO_CreateJournal_Call{Call: _e.mock.On("CreateJournal", ctx, legs)}
+}
+
+func (_c *MockITransactionDAO_CreateJournal_Call) Run(run func(ctx context.Context, legs []*storage.Transaction)) *MockITransactionDAO_CreateJournal_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*storage.Transaction
+		if args[1] != nil {
+			arg1 = args[1].([]*storage.Transaction)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransactionDAO_CreateJournal_Call) Return(err error) *MockITransactionDAO_CreateJournal_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransactionDAO_CreateJournal_Call) RunAndReturn(run func(ctx context.Context, legs []*storage.Transaction) error) *MockITransactionDAO_CreateJournal_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByJournalID provides a mock function for the type MockITransactionDAO
+func (_mock *MockITransactionDAO) FindByJournalID(ctx context.Context, journalID string) ([]*storage.Transaction, error) {
+	ret := _mock.Called(ctx, journalID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByJournalID")
+	}
+
+	var r0 []*storage.Transaction
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*storage.Transaction, error)); ok {
+		return returnFunc(ctx, journalID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*storage.Transaction); ok {
+		r0 = returnFunc(ctx, journalID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Transaction)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, journalID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransactionDAO_FindByJournalID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByJournalID'
+type MockITransactionDAO_FindByJournalID_Call struct {
+	*mock.Call
+}
+
+// FindByJournalID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - journalID string
+func (_e *MockITransactionDAO_Expecter) FindByJournalID(ctx interface{}, journalID interface{}) *MockITransactionDAO_FindByJournalID_Call {
+	return &MockITransactionDAO_FindByJournalID_Call{Call: _e.mock.On("FindByJournalID", ctx, journalID)}
+}
+
+func (_c *MockITransactionDAO_FindByJournalID_Call) Run(run func(ctx context.Context, journalID string)) *MockITransactionDAO_FindByJournalID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransactionDAO_FindByJournalID_Call) Return(transactions []*storage.Transaction, err error) *MockITransactionDAO_FindByJournalID_Call {
+	_c.Call.Return(transactions, err)
+	return _c
+}
+
+func (_c *MockITransactionDAO_FindByJournalID_Call) RunAndReturn(run func(ctx context.Context, journalID string) ([]*storage.Transaction, error)) *MockITransactionDAO_FindByJournalID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindLedgerByAccountID provides a mock function for the type MockITransactionDAO
+func (_mock *MockITransactionDAO) FindLedgerByAccountID(ctx context.Context, filter *storage.LedgerFilter) ([]*storage.LedgerEntry, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindLedgerByAccountID")
+	}
+
+	var r0 []*storage.LedgerEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.LedgerFilter) ([]*storage.LedgerEntry, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.LedgerFilter) []*storage.LedgerEntry); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.LedgerEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.LedgerFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransactionDAO_FindLedgerByAccountID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindLedgerByAccountID'
+type MockITransactionDAO_FindLedgerByAccountID_Call struct {
+	*mock.Call
+}
+
+// FindLedgerByAccountID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *storage.LedgerFilter
+func (_e *MockITransactionDAO_Expecter) FindLedgerByAccountID(ctx interface{}, filter interface{}) *MockITransactionDAO_FindLedgerByAccountID_Call {
+	return &MockITransactionDAO_FindLedgerByAccountID_Call{Call: _e.mock.On("FindLedgerByAccountID", ctx, filter)}
+}
+
+func (_c *MockITransactionDAO_FindLedgerByAccountID_Call) Run(run func(ctx context.Context, filter *storage.LedgerFilter)) *MockITransactionDAO_FindLedgerByAccountID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.LedgerFilter
+		if args[1] != nil {
+			arg1 = args[1].(*storage.LedgerFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransactionDAO_FindLedgerByAccountID_Call) Return(ledgerEntrys []*storage.LedgerEntry, err error) *MockITransactionDAO_FindLedgerByAccountID_Call {
+	_c.Call.Return(ledgerEntrys, err)
+	return _c
+}
+
+func (_c *MockITransactionDAO_FindLedgerByAccountID_Call) RunAndReturn(run func(ctx context.Context, filter *storage.LedgerFilter) ([]*storage.LedgerEntry, error)) *MockITransactionDAO_FindLedgerByAccountID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumLedgerByAccount provides a mock function for the type MockITransactionDAO
+func (_mock *MockITransactionDAO) SumLedgerByAccount(ctx context.Context, afterID uint64, limit int) ([]*storage.AccountLedgerBalance, error) {
+	ret := _mock.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumLedgerByAccount")
+	}
+
+	var r0 []*storage.AccountLedgerBalance
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) ([]*storage.AccountLedgerBalance, error)); ok {
+		return returnFunc(ctx, afterID, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uint64, int) []*storage.AccountLedgerBalance); ok {
+		r0 = returnFunc(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.AccountLedgerBalance)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uint64, int) error); ok {
+		r1 = returnFunc(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransactionDAO_SumLedgerByAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumLedgerByAccount'
+type MockITransactionDAO_SumLedgerByAccount_Call struct {
+	*mock.Call
+}
+
+// SumLedgerByAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID uint64
+//   - limit int
+func (_e *MockITransactionDAO_Expecter) SumLedgerByAccount(ctx interface{}, afterID interface{}, limit interface{}) *MockITransactionDAO_SumLedgerByAccount_Call {
+	return &MockITransactionDAO_SumLedgerByAccount_Call{Call: _e.mock.On("SumLedgerByAccount", ctx, afterID, limit)}
+}
+
+func (_c *MockITransactionDAO_SumLedgerByAccount_Call) Run(run func(ctx context.Context, afterID uint64, limit int)) *MockITransactionDAO_SumLedgerByAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uint64
+		if args[1] != nil {
+			arg1 = args[1].(uint64)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransactionDAO_SumLedgerByAccount_Call) Return(accountLedgerBalances []*storage.AccountLedgerBalance, err error) *MockITransactionDAO_SumLedgerByAccount_Call {
+	_c.Call.Return(accountLedgerBalances, err)
+	return _c
+}
+
+func (_c *MockITransactionDAO_SumLedgerByAccount_Call) RunAndReturn(run func(ctx context.Context, afterID uint64, limit int) ([]*storage.AccountLedgerBalance, error)) *MockITransactionDAO_SumLedgerByAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockITransactionDAO
+func (_mock *MockITransactionDAO) WithTx(tx *gorm.DB) storage.ITransactionDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.ITransactionDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.ITransactionDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.ITransactionDAO)
+		}
+	}
+	return r0
+}
+
+// MockITransactionDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockITransactionDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockITransactionDAO_Expecter) WithTx(tx interface{}) *MockITransactionDAO_WithTx_Call {
+	return &MockITransactionDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockITransactionDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockITransactionDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransactionDAO_WithTx_Call) Return(iTransactionDAO storage.ITransactionDAO) *MockITransactionDAO_WithTx_Call {
+	_c.Call.Return(iTransactionDAO)
+	return _c
+}
+
+func (_c *MockITransactionDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.ITransactionDAO) *MockITransactionDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockITransferBatchDAO creates a new instance of MockITransferBatchDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITransferBatchDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITransferBatchDAO {
+	mock := &MockITransferBatchDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockITransferBatchDAO is an autogenerated mock type for the ITransferBatchDAO type
+type MockITransferBatchDAO struct {
+	mock.Mock
+}
+
+type MockITransferBatchDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITransferBatchDAO) EXPECT() *MockITransferBatchDAO_Expecter {
+	return &MockITransferBatchDAO_Expecter{mock: &_m.Mock}
+}
+
+// ClaimPending provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.TransferBatch, error) {
+	ret := _mock.Called(ctx, now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPending")
+	}
+
+	var r0 []*storage.TransferBatch
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) ([]*storage.TransferBatch, error)); ok {
+		return returnFunc(ctx, now, lease, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) []*storage.TransferBatch); ok {
+		r0 = returnFunc(ctx, now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.TransferBatch)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, int) error); ok {
+		r1 = returnFunc(ctx, now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferBatchDAO_ClaimPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPending'
+type MockITransferBatchDAO_ClaimPending_Call struct {
+	*mock.Call
+}
+
+// ClaimPending is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *MockITransferBatchDAO_Expecter) ClaimPending(ctx interface{}, now interface{}, lease interface{}, limit interface{}) *MockITransferBatchDAO_ClaimPending_Call {
+	return &MockITransferBatchDAO_ClaimPending_Call{Call: _e.mock.On("ClaimPending", ctx, now, lease, limit)}
+}
+
+func (_c *MockITransferBatchDAO_ClaimPending_Call) Run(run func(ctx context.Context, now time.Time, lease time.Duration, limit int)) *MockITransferBatchDAO_ClaimPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_ClaimPending_Call) Return(transferBatchs []*storage.TransferBatch, err error) *MockITransferBatchDAO_ClaimPending_Call {
+	_c.Call.Return(transferBatchs, err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_ClaimPending_Call) RunAndReturn(run func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.TransferBatch, error)) *MockITransferBatchDAO_ClaimPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CountItemsByStatus provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) CountItemsByStatus(ctx context.Context, batchID string) (map[string]int, error) {
+	ret := _mock.Called(ctx, batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountItemsByStatus")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (map[string]int, error)); ok {
+		return returnFunc(ctx, batchID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) map[string]int); ok {
+		r0 = returnFunc(ctx, batchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, batchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferBatchDAO_CountItemsByStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CountItemsByStatus'
+type MockITransferBatchDAO_CountItemsByStatus_Call struct {
+	*mock.Call
+}
+
+// CountItemsByStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchID string
+func (_e *MockITransferBatchDAO_Expecter) CountItemsByStatus(ctx interface{}, batchID interface{}) *MockITransferBatchDAO_CountItemsByStatus_Call {
+	return &MockITransferBatchDAO_CountItemsByStatus_Call{Call: _e.mock.On("CountItemsByStatus", ctx, batchID)}
+}
+
+func (_c *MockITransferBatchDAO_CountItemsByStatus_Call) Run(run func(ctx context.Context, batchID string)) *MockITransferBatchDAO_CountItemsByStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_CountItemsByStatus_Call) Return(m map[string]int, err error) *MockITransferBatchDAO_CountItemsByStatus_Call {
+	_c.Call.Return(m, err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_CountItemsByStatus_Call) RunAndReturn(run func(ctx context.Context, batchID string) (map[string]int, error)) *MockITransferBatchDAO_CountItemsByStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) Create(ctx context.Context, batch *storage.TransferBatch, items []*storage.TransferBatchItem) error {
+	ret := _mock.Called(ctx, batch, items)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.TransferBatch, []*storage.TransferBatchItem) error); ok {
+		r0 = returnFunc(ctx, batch, items)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferBatchDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockITransferBatchDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batch *storage.TransferBatch
+//   - items []*storage.TransferBatchItem
+func (_e *MockITransferBatchDAO_Expecter) Create(ctx interface{}, batch interface{}, items interface{}) *MockITransferBatchDAO_Create_Call {
+	return &MockITransferBatchDAO_Create_Call{Call: _e.mock.On("Create", ctx, batch, items)}
+}
+
+func (_c *MockITransferBatchDAO_Create_Call) Run(run func(ctx context.Context, batch *storage.TransferBatch, items []*storage.TransferBatchItem)) *MockITransferBatchDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.TransferBatch
+		if args[1] != nil {
+			arg1 = args[1].(*storage.TransferBatch)
+		}
+		var arg2 []*storage.TransferBatchItem
+		if args[2] != nil {
+			arg2 = args[2].([]*storage.TransferBatchItem)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_Create_Call) Return(err error) *MockITransferBatchDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_Create_Call) RunAndReturn(run func(ctx context.Context, batch *storage.TransferBatch, items []*storage.TransferBatchItem) error) *MockITransferBatchDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByBatchID provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) FindByBatchID(ctx context.Context, batchID string) (*storage.TransferBatch, error) {
+	ret := _mock.Called(ctx, batchID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByBatchID")
+	}
+
+	var r0 *storage.TransferBatch
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.TransferBatch, error)); ok {
+		return returnFunc(ctx, batchID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.TransferBatch); ok {
+		r0 = returnFunc(ctx, batchID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.TransferBatch)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, batchID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferBatchDAO_FindByBatchID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByBatchID'
+type MockITransferBatchDAO_FindByBatchID_Call struct {
+	*mock.Call
+}
+
+// FindByBatchID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchID string
+func (_e *MockITransferBatchDAO_Expecter) FindByBatchID(ctx interface{}, batchID interface{}) *MockITransferBatchDAO_FindByBatchID_Call {
+	return &MockITransferBatchDAO_FindByBatchID_Call{Call: _e.mock.On("FindByBatchID", ctx, batchID)}
+}
+
+func (_c *MockITransferBatchDAO_FindByBatchID_Call) Run(run func(ctx context.Context, batchID string)) *MockITransferBatchDAO_FindByBatchID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindByBatchID_Call) Return(transferBatch *storage.TransferBatch, err error) *MockITransferBatchDAO_FindByBatchID_Call {
+	_c.Call.Return(transferBatch, err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindByBatchID_Call) RunAndReturn(run func(ctx context.Context, batchID string) (*storage.TransferBatch, error)) *MockITransferBatchDAO_FindByBatchID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferenceID provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*storage.TransferBatch, error) {
+	ret := _mock.Called(ctx, clientID, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferenceID")
+	}
+
+	var r0 *storage.TransferBatch
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.TransferBatch, error)); ok {
+		return returnFunc(ctx, clientID, referenceID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.TransferBatch); ok {
+		r0 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.TransferBatch)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferBatchDAO_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
+type MockITransferBatchDAO_FindByReferenceID_Call struct {
+	*mock.Call
+}
+
+// FindByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+//   - referenceID string
+func (_e *MockITransferBatchDAO_Expecter) FindByReferenceID(ctx interface{}, clientID interface{}, referenceID interface{}) *MockITransferBatchDAO_FindByReferenceID_Call {
+	return &MockITransferBatchDAO_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, clientID, referenceID)}
+}
+
+func (_c *MockITransferBatchDAO_FindByReferenceID_Call) Run(run func(ctx context.Context, clientID string, referenceID string)) *MockITransferBatchDAO_FindByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindByReferenceID_Call) Return(transferBatch *storage.TransferBatch, err error) *MockITransferBatchDAO_FindByReferenceID_Call {
+	_c.Call.Return(transferBatch, err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindByReferenceID_Call) RunAndReturn(run func(ctx context.Context, clientID string, referenceID string) (*storage.TransferBatch, error)) *MockITransferBatchDAO_FindByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindItems provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) FindItems(ctx context.Context, filter *storage.BatchItemFilter) ([]*storage.TransferBatchItem, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindItems")
+	}
+
+	var r0 []*storage.TransferBatchItem
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.BatchItemFilter) ([]*storage.TransferBatchItem, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.BatchItemFilter) []*storage.TransferBatchItem); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.TransferBatchItem)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *storage.BatchItemFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferBatchDAO_FindItems_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindItems'
+type MockITransferBatchDAO_FindItems_Call struct {
+	*mock.Call
+}
+
+// FindItems is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter *storage.BatchItemFilter
+func (_e *MockITransferBatchDAO_Expecter) FindItems(ctx interface{}, filter interface{}) *MockITransferBatchDAO_FindItems_Call {
+	return &MockITransferBatchDAO_FindItems_Call{Call: _e.mock.On("FindItems", ctx, filter)}
+}
+
+func (_c *MockITransferBatchDAO_FindItems_Call) Run(run func(ctx context.Context, filter *storage.BatchItemFilter)) *MockITransferBatchDAO_FindItems_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.BatchItemFilter
+		if args[1] != nil {
+			arg1 = args[1].(*storage.BatchItemFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindItems_Call) Return(transferBatchItems []*storage.TransferBatchItem, err error) *MockITransferBatchDAO_FindItems_Call {
+	_c.Call.Return(transferBatchItems, err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_FindItems_Call) RunAndReturn(run func(ctx context.Context, filter *storage.BatchItemFilter) ([]*storage.TransferBatchItem, error)) *MockITransferBatchDAO_FindItems_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RenewLease provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) RenewLease(ctx context.Context, batchID string, until time.Time) error {
+	ret := _mock.Called(ctx, batchID, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RenewLease")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = returnFunc(ctx, batchID, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferBatchDAO_RenewLease_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RenewLease'
+type MockITransferBatchDAO_RenewLease_Call struct {
+	*mock.Call
+}
+
+// RenewLease is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batchID string
+//   - until time.Time
+func (_e *MockITransferBatchDAO_Expecter) RenewLease(ctx interface{}, batchID interface{}, until interface{}) *MockITransferBatchDAO_RenewLease_Call {
+	return &MockITransferBatchDAO_RenewLease_Call{Call: _e.mock.On("RenewLease", ctx, batchID, until)}
+}
+
+func (_c *MockITransferBatchDAO_RenewLease_Call) Run(run func(ctx context.Context, batchID string, until time.Time)) *MockITransferBatchDAO_RenewLease_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_RenewLease_Call) Return(err error) *MockITransferBatchDAO_RenewLease_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_RenewLease_Call) RunAndReturn(run func(ctx context.Context, batchID string, until time.Time) error) *MockITransferBatchDAO_RenewLease_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) Save(ctx context.Context, batch *storage.TransferBatch) error {
+	ret := _mock.Called(ctx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.TransferBatch) error); ok {
+		r0 = returnFunc(ctx, batch)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferBatchDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockITransferBatchDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - batch *storage.TransferBatch
+func (_e *MockITransferBatchDAO_Expecter) Save(ctx interface{}, batch interface{}) *MockITransferBatchDAO_Save_Call {
+	return &MockITransferBatchDAO_Save_Call{Call: _e.mock.On("Save", ctx, batch)}
+}
+
+func (_c *MockITransferBatchDAO_Save_Call) Run(run func(ctx context.Context, batch *storage.TransferBatch)) *MockITransferBatchDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.TransferBatch
+		if args[1] != nil {
+			arg1 = args[1].(*storage.TransferBatch)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_Save_Call) Return(err error) *MockITransferBatchDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_Save_Call) RunAndReturn(run func(ctx context.Context, batch *storage.TransferBatch) error) *MockITransferBatchDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveItem provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) SaveItem(ctx context.Context, item *storage.TransferBatchItem) error {
+	ret := _mock.Called(ctx, item)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveItem")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.TransferBatchItem) error); ok {
+		r0 = returnFunc(ctx, item)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferBatchDAO_SaveItem_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveItem'
+type MockITransferBatchDAO_SaveItem_Call struct {
+	*mock.Call
+}
+
+// SaveItem is a helper method to define mock.On call
+//   - ctx context.Context
+//   - item *storage.TransferBatchItem
+func (_e *MockITransferBatchDAO_Expecter) SaveItem(ctx interface{}, item interface{}) *MockITransferBatchDAO_SaveItem_Call {
+	return &MockITransferBatchDAO_SaveItem_Call{Call: _e.mock.On("SaveItem", ctx, item)}
+}
+
+func (_c *MockITransferBatchDAO_SaveItem_Call) Run(run func(ctx context.Context, item *storage.TransferBatchItem)) *MockITransferBatchDAO_SaveItem_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.TransferBatchItem
+		if args[1] != nil {
+			arg1 = args[1].(*storage.TransferBatchItem)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_SaveItem_Call) Return(err error) *MockITransferBatchDAO_SaveItem_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_SaveItem_Call) RunAndReturn(run func(ctx context.Context, item *storage.TransferBatchItem) error) *MockITransferBatchDAO_SaveItem_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockITransferBatchDAO
+func (_mock *MockITransferBatchDAO) WithTx(tx *gorm.DB) storage.ITransferBatchDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.ITransferBatchDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.ITransferBatchDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.ITransferBatchDAO)
+		}
+	}
+	return r0
+}
+
+// MockITransferBatchDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockITransferBatchDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockITransferBatchDAO_Expecter) WithTx(tx interface{}) *MockITransferBatchDAO_WithTx_Call {
+	return &MockITransferBatchDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockITransferBatchDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockITransferBatchDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_WithTx_Call) Return(iTransferBatchDAO storage.ITransferBatchDAO) *MockITransferBatchDAO_WithTx_Call {
+	_c.Call.Return(iTransferBatchDAO)
+	return _c
+}
+
+func (_c *MockITransferBatchDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.ITransferBatchDAO) *MockITransferBatchDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockITransferDAO creates a new instance of MockITransferDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockITransferDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockITransferDAO {
+	mock := &MockITransferDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockITransferDAO is an autogenerated mock type for the ITransferDAO type
+type MockITransferDAO struct {
+	mock.Mock
+}
+
+type MockITransferDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockITransferDAO) EXPECT() *MockITransferDAO_Expecter {
+	return &MockITransferDAO_Expecter{mock: &_m.Mock}
+}
+
+// ClaimDue provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.Transfer, error) {
+	ret := _mock.Called(ctx, now, lease, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimDue")
+	}
+
+	var r0 []*storage.Transfer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) ([]*storage.Transfer, error)); ok {
+		return returnFunc(ctx, now, lease, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, time.Duration, int) []*storage.Transfer); ok {
+		r0 = returnFunc(ctx, now, lease, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Transfer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time, time.Duration, int) error); ok {
+		r1 = returnFunc(ctx, now, lease, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_ClaimDue_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimDue'
+type MockITransferDAO_ClaimDue_Call struct {
+	*mock.Call
+}
+
+// ClaimDue is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+//   - lease time.Duration
+//   - limit int
+func (_e *MockITransferDAO_Expecter) ClaimDue(ctx interface{}, now interface{}, lease interface{}, limit interface{}) *MockITransferDAO_ClaimDue_Call {
+	return &MockITransferDAO_ClaimDue_Call{Call: _e.mock.On("ClaimDue", ctx, now, lease, limit)}
+}
+
+func (_c *MockITransferDAO_ClaimDue_Call) Run(run func(ctx context.Context, now time.Time, lease time.Duration, limit int)) *MockITransferDAO_ClaimDue_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
+		}
+		var arg3 int
+		if args[3] != nil {
+			arg3 = args[3].(int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_ClaimDue_Call) Return(transfers []*storage.Transfer, err error) *MockITransferDAO_ClaimDue_Call {
+	_c.Call.Return(transfers, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_ClaimDue_Call) RunAndReturn(run func(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*storage.Transfer, error)) *MockITransferDAO_ClaimDue_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) Create(ctx context.Context, transfer *storage.Transfer) error {
+	ret := _mock.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Transfer) error); ok {
+		r0 = returnFunc(ctx, transfer)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockITransferDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *storage.Transfer
+func (_e *MockITransferDAO_Expecter) Create(ctx interface{}, transfer interface{}) *MockITransferDAO_Create_Call {
+	return &MockITransferDAO_Create_Call{Call: _e.mock.On("Create", ctx, transfer)}
+}
+
+func (_c *MockITransferDAO_Create_Call) Run(run func(ctx context.Context, transfer *storage.Transfer)) *MockITransferDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Transfer
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Transfer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_Create_Call) Return(err error) *MockITransferDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferDAO_Create_Call) RunAndReturn(run func(ctx context.Context, transfer *storage.Transfer) error) *MockITransferDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByAccountIDWithCursor provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) FindByAccountIDWithCursor(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time) ([]*storage.Transfer, error) {
+	ret := _mock.Called(ctx, accountID, limit, beforeTimestamp)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByAccountIDWithCursor")
+	}
+
+	var r0 []*storage.Transfer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, *time.Time) ([]*storage.Transfer, error)); ok {
+		return returnFunc(ctx, accountID, limit, beforeTimestamp)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, int, *time.Time) []*storage.Transfer); ok {
+		r0 = returnFunc(ctx, accountID, limit, beforeTimestamp)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.Transfer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, int, *time.Time) error); ok {
+		r1 = returnFunc(ctx, accountID, limit, beforeTimestamp)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_FindByAccountIDWithCursor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByAccountIDWithCursor'
+type MockITransferDAO_FindByAccountIDWithCursor_Call struct {
+	*mock.Call
+}
+
+// FindByAccountIDWithCursor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - limit int
+//   - beforeTimestamp *time.Time
+func (_e *MockITransferDAO_Expecter) FindByAccountIDWithCursor(ctx interface{}, accountID interface{}, limit interface{}, beforeTimestamp interface{}) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+	return &MockITransferDAO_FindByAccountIDWithCursor_Call{Call: _e.mock.On("FindByAccountIDWithCursor", ctx, accountID, limit, beforeTimestamp)}
+}
+
+func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) Run(run func(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time)) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 *time.Time
+		if args[3] != nil {
+			arg3 = args[3].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) Return(transfers []*storage.Transfer, err error) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+	_c.Call.Return(transfers, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByAccountIDWithCursor_Call) RunAndReturn(run func(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time) ([]*storage.Transfer, error)) *MockITransferDAO_FindByAccountIDWithCursor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByReferenceID provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*storage.Transfer, error) {
+	ret := _mock.Called(ctx, clientID, referenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByReferenceID")
+	}
+
+	var r0 *storage.Transfer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.Transfer, error)); ok {
+		return returnFunc(ctx, clientID, referenceID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.Transfer); ok {
+		r0 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Transfer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, clientID, referenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_FindByReferenceID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByReferenceID'
+type MockITransferDAO_FindByReferenceID_Call struct {
+	*mock.Call
+}
+
+// FindByReferenceID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - clientID string
+//   - referenceID string
+func (_e *MockITransferDAO_Expecter) FindByReferenceID(ctx interface{}, clientID interface{}, referenceID interface{}) *MockITransferDAO_FindByReferenceID_Call {
+	return &MockITransferDAO_FindByReferenceID_Call{Call: _e.mock.On("FindByReferenceID", ctx, clientID, referenceID)}
+}
+
+func (_c *MockITransferDAO_FindByReferenceID_Call) Run(run func(ctx context.Context, clientID string, referenceID string)) *MockITransferDAO_FindByReferenceID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByReferenceID_Call) Return(transfer *storage.Transfer, err error) *MockITransferDAO_FindByReferenceID_Call {
+	_c.Call.Return(transfer, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByReferenceID_Call) RunAndReturn(run func(ctx context.Context, clientID string, referenceID string) (*storage.Transfer, error)) *MockITransferDAO_FindByReferenceID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByTransactionID provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) FindByTransactionID(ctx context.Context, transactionID string) (*storage.Transfer, error) {
+	ret := _mock.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByTransactionID")
+	}
+
+	var r0 *storage.Transfer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Transfer, error)); ok {
+		return returnFunc(ctx, transactionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Transfer); ok {
+		r0 = returnFunc(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Transfer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_FindByTransactionID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByTransactionID'
+type MockITransferDAO_FindByTransactionID_Call struct {
+	*mock.Call
+}
+
+// FindByTransactionID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID string
+func (_e *MockITransferDAO_Expecter) FindByTransactionID(ctx interface{}, transactionID interface{}) *MockITransferDAO_FindByTransactionID_Call {
+	return &MockITransferDAO_FindByTransactionID_Call{Call: _e.mock.On("FindByTransactionID", ctx, transactionID)}
+}
+
+func (_c *MockITransferDAO_FindByTransactionID_Call) Run(run func(ctx context.Context, transactionID string)) *MockITransferDAO_FindByTransactionID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByTransactionID_Call) Return(transfer *storage.Transfer, err error) *MockITransferDAO_FindByTransactionID_Call {
+	_c.Call.Return(transfer, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByTransactionID_Call) RunAndReturn(run func(ctx context.Context, transactionID string) (*storage.Transfer, error)) *MockITransferDAO_FindByTransactionID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByTransactionIDForUpdate provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) FindByTransactionIDForUpdate(ctx context.Context, transactionID string) (*storage.Transfer, error) {
+	ret := _mock.Called(ctx, transactionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByTransactionIDForUpdate")
+	}
+
+	var r0 *storage.Transfer
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.Transfer, error)); ok {
+		return returnFunc(ctx, transactionID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.Transfer); ok {
+		r0 = returnFunc(ctx, transactionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.Transfer)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, transactionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_FindByTransactionIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByTransactionIDForUpdate'
+type MockITransferDAO_FindByTransactionIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByTransactionIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transactionID string
+func (_e *MockITransferDAO_Expecter) FindByTransactionIDForUpdate(ctx interface{}, transactionID interface{}) *MockITransferDAO_FindByTransactionIDForUpdate_Call {
+	return &MockITransferDAO_FindByTransactionIDForUpdate_Call{Call: _e.mock.On("FindByTransactionIDForUpdate", ctx, transactionID)}
+}
+
+func (_c *MockITransferDAO_FindByTransactionIDForUpdate_Call) Run(run func(ctx context.Context, transactionID string)) *MockITransferDAO_FindByTransactionIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByTransactionIDForUpdate_Call) Return(transfer *storage.Transfer, err error) *MockITransferDAO_FindByTransactionIDForUpdate_Call {
+	_c.Call.Return(transfer, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_FindByTransactionIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, transactionID string) (*storage.Transfer, error)) *MockITransferDAO_FindByTransactionIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReleaseReferenceIDs provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) ReleaseReferenceIDs(ctx context.Context, createdBefore time.Time) (int64, error) {
+	ret := _mock.Called(ctx, createdBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReleaseReferenceIDs")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return returnFunc(ctx, createdBefore)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = returnFunc(ctx, createdBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = returnFunc(ctx, createdBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_ReleaseReferenceIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReleaseReferenceIDs'
+type MockITransferDAO_ReleaseReferenceIDs_Call struct {
+	*mock.Call
+}
+
+// ReleaseReferenceIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - createdBefore time.Time
+func (_e *MockITransferDAO_Expecter) ReleaseReferenceIDs(ctx interface{}, createdBefore interface{}) *MockITransferDAO_ReleaseReferenceIDs_Call {
+	return &MockITransferDAO_ReleaseReferenceIDs_Call{Call: _e.mock.On("ReleaseReferenceIDs", ctx, createdBefore)}
+}
+
+func (_c *MockITransferDAO_ReleaseReferenceIDs_Call) Run(run func(ctx context.Context, createdBefore time.Time)) *MockITransferDAO_ReleaseReferenceIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_ReleaseReferenceIDs_Call) Return(n int64, err error) *MockITransferDAO_ReleaseReferenceIDs_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_ReleaseReferenceIDs_Call) RunAndReturn(run func(ctx context.Context, createdBefore time.Time) (int64, error)) *MockITransferDAO_ReleaseReferenceIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunInTransaction provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) RunInTransaction(fn storage.TxFn, opts ...*sql.TxOptions) error {
+	var tmpRet mock.Arguments
+	if len(opts) > 0 {
+		tmpRet = _mock.Called(fn, opts)
+	} else {
+		tmpRet = _mock.Called(fn)
+	}
+	ret := tmpRet
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunInTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(storage.TxFn, ...*sql.TxOptions) error); ok {
+		r0 = returnFunc(fn, opts...)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
+type MockITransferDAO_RunInTransaction_Call struct {
+	*mock.Call
+}
+
+// RunInTransaction is a helper method to define mock.On call
+//   - fn storage.TxFn
+//   - opts ...*sql.TxOptions
+func (_e *MockITransferDAO_Expecter) RunInTransaction(fn interface{}, opts ...interface{}) *MockITransferDAO_RunInTransaction_Call {
+	return &MockITransferDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction",
+		append([]interface{}{fn}, opts...)...)}
+}
+
+func (_c *MockITransferDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn, opts ...*sql.TxOptions)) *MockITransferDAO_RunInTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 storage.TxFn
+		if args[0] != nil {
+			arg0 = args[0].(storage.TxFn)
+		}
+		var arg1 []*sql.TxOptions
+		var variadicArgs []*sql.TxOptions
+		if len(args) > 1 {
+			variadicArgs = args[1].([]*sql.TxOptions)
+		}
+		arg1 = variadicArgs
+		run(
+			arg0,
+			arg1...,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_RunInTransaction_Call) Return(err error) *MockITransferDAO_RunInTransaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn, opts ...*sql.TxOptions) error) *MockITransferDAO_RunInTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) Save(ctx context.Context, transfer *storage.Transfer) error {
+	ret := _mock.Called(ctx, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Transfer) error); ok {
+		r0 = returnFunc(ctx, transfer)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockITransferDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *storage.Transfer
+func (_e *MockITransferDAO_Expecter) Save(ctx interface{}, transfer interface{}) *MockITransferDAO_Save_Call {
+	return &MockITransferDAO_Save_Call{Call: _e.mock.On("Save", ctx, transfer)}
+}
+
+func (_c *MockITransferDAO_Save_Call) Run(run func(ctx context.Context, transfer *storage.Transfer)) *MockITransferDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Transfer
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Transfer)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_Save_Call) Return(err error) *MockITransferDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferDAO_Save_Call) RunAndReturn(run func(ctx context.Context, transfer *storage.Transfer) error) *MockITransferDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveClaimed provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) SaveClaimed(ctx context.Context, transfer *storage.Transfer, claimedUntil *time.Time) error {
+	ret := _mock.Called(ctx, transfer, claimedUntil)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveClaimed")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.Transfer, *time.Time) error); ok {
+		r0 = returnFunc(ctx, transfer, claimedUntil)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockITransferDAO_SaveClaimed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveClaimed'
+type MockITransferDAO_SaveClaimed_Call struct {
+	*mock.Call
+}
+
+// SaveClaimed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - transfer *storage.Transfer
+//   - claimedUntil *time.Time
+func (_e *MockITransferDAO_Expecter) SaveClaimed(ctx interface{}, transfer interface{}, claimedUntil interface{}) *MockITransferDAO_SaveClaimed_Call {
+	return &MockITransferDAO_SaveClaimed_Call{Call: _e.mock.On("SaveClaimed", ctx, transfer, claimedUntil)}
+}
+
+func (_c *MockITransferDAO_SaveClaimed_Call) Run(run func(ctx context.Context, transfer *storage.Transfer, claimedUntil *time.Time)) *MockITransferDAO_SaveClaimed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.Transfer
+		if args[1] != nil {
+			arg1 = args[1].(*storage.Transfer)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_SaveClaimed_Call) Return(err error) *MockITransferDAO_SaveClaimed_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockITransferDAO_SaveClaimed_Call) RunAndReturn(run func(ctx context.Context, transfer *storage.Transfer, claimedUntil *time.Time) error) *MockITransferDAO_SaveClaimed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumCompletedByDestinationAccount provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) SumCompletedByDestinationAccount(ctx context.Context, accountID string, txType string, since time.Time) (*storage.TransferUsage, error) {
+	ret := _mock.Called(ctx, accountID, txType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumCompletedByDestinationAccount")
+	}
+
+	var r0 *storage.TransferUsage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*storage.TransferUsage, error)); ok {
+		return returnFunc(ctx, accountID, txType, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *storage.TransferUsage); ok {
+		r0 = returnFunc(ctx, accountID, txType, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.TransferUsage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, accountID, txType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_SumCompletedByDestinationAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumCompletedByDestinationAccount'
+type MockITransferDAO_SumCompletedByDestinationAccount_Call struct {
+	*mock.Call
+}
+
+// SumCompletedByDestinationAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - txType string
+//   - since time.Time
+func (_e *MockITransferDAO_Expecter) SumCompletedByDestinationAccount(ctx interface{}, accountID interface{}, txType interface{}, since interface{}) *MockITransferDAO_SumCompletedByDestinationAccount_Call {
+	return &MockITransferDAO_SumCompletedByDestinationAccount_Call{Call: _e.mock.On("SumCompletedByDestinationAccount", ctx, accountID, txType, since)}
+}
+
+func (_c *MockITransferDAO_SumCompletedByDestinationAccount_Call) Run(run func(ctx context.Context, accountID string, txType string, since time.Time)) *MockITransferDAO_SumCompletedByDestinationAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_SumCompletedByDestinationAccount_Call) Return(transferUsage *storage.TransferUsage, err error) *MockITransferDAO_SumCompletedByDestinationAccount_Call {
+	_c.Call.Return(transferUsage, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_SumCompletedByDestinationAccount_Call) RunAndReturn(run func(ctx context.Context, accountID string, txType string, since time.Time) (*storage.TransferUsage, error)) *MockITransferDAO_SumCompletedByDestinationAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SumCompletedBySourceAccount provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) SumCompletedBySourceAccount(ctx context.Context, accountID string, txType string, since time.Time) (*storage.TransferUsage, error) {
+	ret := _mock.Called(ctx, accountID, txType, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SumCompletedBySourceAccount")
+	}
+
+	var r0 *storage.TransferUsage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) (*storage.TransferUsage, error)); ok {
+		return returnFunc(ctx, accountID, txType, since)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string, time.Time) *storage.TransferUsage); ok {
+		r0 = returnFunc(ctx, accountID, txType, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.TransferUsage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string, time.Time) error); ok {
+		r1 = returnFunc(ctx, accountID, txType, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockITransferDAO_SumCompletedBySourceAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SumCompletedBySourceAccount'
+type MockITransferDAO_SumCompletedBySourceAccount_Call struct {
+	*mock.Call
+}
+
+// SumCompletedBySourceAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+//   - txType string
+//   - since time.Time
+func (_e *MockITransferDAO_Expecter) SumCompletedBySourceAccount(ctx interface{}, accountID interface{}, txType interface{}, since interface{}) *MockITransferDAO_SumCompletedBySourceAccount_Call {
+	return &MockITransferDAO_SumCompletedBySourceAccount_Call{Call: _e.mock.On("SumCompletedBySourceAccount", ctx, accountID, txType, since)}
+}
+
+func (_c *MockITransferDAO_SumCompletedBySourceAccount_Call) Run(run func(ctx context.Context, accountID string, txType string, since time.Time)) *MockITransferDAO_SumCompletedBySourceAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_SumCompletedBySourceAccount_Call) Return(transferUsage *storage.TransferUsage, err error) *MockITransferDAO_SumCompletedBySourceAccount_Call {
+	_c.Call.Return(transferUsage, err)
+	return _c
+}
+
+func (_c *MockITransferDAO_SumCompletedBySourceAccount_Call) RunAndReturn(run func(ctx context.Context, accountID string, txType string, since time.Time) (*storage.TransferUsage, error)) *MockITransferDAO_SumCompletedBySourceAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockITransferDAO
+func (_mock *MockITransferDAO) WithTx(tx *gorm.DB) storage.ITransferDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.ITransferDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.ITransferDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.ITransferDAO)
+		}
+	}
+	return r0
+}
+
+// MockITransferDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockITransferDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockITransferDAO_Expecter) WithTx(tx interface{}) *MockITransferDAO_WithTx_Call {
+	return &MockITransferDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockITransferDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockITransferDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockITransferDAO_WithTx_Call) Return(iTransferDAO storage.ITransferDAO) *MockITransferDAO_WithTx_Call {
+	_c.Call.Return(iTransferDAO)
+	return _c
+}
+
+func (_c *MockITransferDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.ITransferDAO) *MockITransferDAO_WithTx_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIUserDAO creates a new instance of MockIUserDAO. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIUserDAO(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIUserDAO {
+	mock := &MockIUserDAO{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIUserDAO is an autogenerated mock type for the IUserDAO type
+type MockIUserDAO struct {
+	mock.Mock
+}
+
+type MockIUserDAO_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIUserDAO) EXPECT() *MockIUserDAO_Expecter {
+	return &MockIUserDAO_Expecter{mock: &_m.Mock}
+}
+
+// Create provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) Create(ctx context.Context, user *storage.User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserDAO_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockIUserDAO_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *storage.User
+func (_e *MockIUserDAO_Expecter) Create(ctx interface{}, user interface{}) *MockIUserDAO_Create_Call {
+	return &MockIUserDAO_Create_Call{Call: _e.mock.On("Create", ctx, user)}
+}
+
+func (_c *MockIUserDAO_Create_Call) Run(run func(ctx context.Context, user *storage.User)) *MockIUserDAO_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.User
+		if args[1] != nil {
+			arg1 = args[1].(*storage.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_Create_Call) Return(err error) *MockIUserDAO_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserDAO_Create_Call) RunAndReturn(run func(ctx context.Context, user *storage.User) error) *MockIUserDAO_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUserAccount provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) DeleteUserAccount(ctx context.Context, link *storage.UserAccount) error {
+	ret := _mock.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.UserAccount) error); ok {
+		r0 = returnFunc(ctx, link)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserDAO_DeleteUserAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserAccount'
+type MockIUserDAO_DeleteUserAccount_Call struct {
+	*mock.Call
+}
+
+// DeleteUserAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - link *storage.UserAccount
+func (_e *MockIUserDAO_Expecter) DeleteUserAccount(ctx interface{}, link interface{}) *MockIUserDAO_DeleteUserAccount_Call {
+	return &MockIUserDAO_DeleteUserAccount_Call{Call: _e.mock.On("DeleteUserAccount", ctx, link)}
+}
+
+func (_c *MockIUserDAO_DeleteUserAccount_Call) Run(run func(ctx context.Context, link *storage.UserAccount)) *MockIUserDAO_DeleteUserAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.UserAccount
+		if args[1] != nil {
+			arg1 = args[1].(*storage.UserAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_DeleteUserAccount_Call) Return(err error) *MockIUserDAO_DeleteUserAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserDAO_DeleteUserAccount_Call) RunAndReturn(run func(ctx context.Context, link *storage.UserAccount) error) *MockIUserDAO_DeleteUserAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindAccountUsers provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindAccountUsers(ctx context.Context, accountID string) ([]*storage.UserAccount, error) {
+	ret := _mock.Called(ctx, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindAccountUsers")
+	}
+
+	var r0 []*storage.UserAccount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*storage.UserAccount, error)); ok {
+		return returnFunc(ctx, accountID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*storage.UserAccount); ok {
+		r0 = returnFunc(ctx, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.UserAccount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindAccountUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindAccountUsers'
+type MockIUserDAO_FindAccountUsers_Call struct {
+	*mock.Call
+}
+
+// FindAccountUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accountID string
+func (_e *MockIUserDAO_Expecter) FindAccountUsers(ctx interface{}, accountID interface{}) *MockIUserDAO_FindAccountUsers_Call {
+	return &MockIUserDAO_FindAccountUsers_Call{Call: _e.mock.On("FindAccountUsers", ctx, accountID)}
+}
+
+func (_c *MockIUserDAO_FindAccountUsers_Call) Run(run func(ctx context.Context, accountID string)) *MockIUserDAO_FindAccountUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindAccountUsers_Call) Return(userAccounts []*storage.UserAccount, err error) *MockIUserDAO_FindAccountUsers_Call {
+	_c.Call.Return(userAccounts, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindAccountUsers_Call) RunAndReturn(run func(ctx context.Context, accountID string) ([]*storage.UserAccount, error)) *MockIUserDAO_FindAccountUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByEmail provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindByEmail(ctx context.Context, email string) (*storage.User, error) {
+	ret := _mock.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByEmail")
+	}
+
+	var r0 *storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.User, error)); ok {
+		return returnFunc(ctx, email)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.User); ok {
+		r0 = returnFunc(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByEmail'
+type MockIUserDAO_FindByEmail_Call struct {
+	*mock.Call
+}
+
+// FindByEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *MockIUserDAO_Expecter) FindByEmail(ctx interface{}, email interface{}) *MockIUserDAO_FindByEmail_Call {
+	return &MockIUserDAO_FindByEmail_Call{Call: _e.mock.On("FindByEmail", ctx, email)}
+}
+
+func (_c *MockIUserDAO_FindByEmail_Call) Run(run func(ctx context.Context, email string)) *MockIUserDAO_FindByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByEmail_Call) Return(user *storage.User, err error) *MockIUserDAO_FindByEmail_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByEmail_Call) RunAndReturn(run func(ctx context.Context, email string) (*storage.User, error)) *MockIUserDAO_FindByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByUserID provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindByUserID(ctx context.Context, userID string) (*storage.User, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUserID")
+	}
+
+	var r0 *storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.User, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.User); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindByUserID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByUserID'
+type MockIUserDAO_FindByUserID_Call struct {
+	*mock.Call
+}
+
+// FindByUserID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *MockIUserDAO_Expecter) FindByUserID(ctx interface{}, userID interface{}) *MockIUserDAO_FindByUserID_Call {
+	return &MockIUserDAO_FindByUserID_Call{Call: _e.mock.On("FindByUserID", ctx, userID)}
+}
+
+func (_c *MockIUserDAO_FindByUserID_Call) Run(run func(ctx context.Context, userID string)) *MockIUserDAO_FindByUserID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByUserID_Call) Return(user *storage.User, err error) *MockIUserDAO_FindByUserID_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByUserID_Call) RunAndReturn(run func(ctx context.Context, userID string) (*storage.User, error)) *MockIUserDAO_FindByUserID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindByUserIDForUpdate provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindByUserIDForUpdate(ctx context.Context, userID string) (*storage.User, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindByUserIDForUpdate")
+	}
+
+	var r0 *storage.User
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*storage.User, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *storage.User); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.User)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindByUserIDForUpdate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindByUserIDForUpdate'
+type MockIUserDAO_FindByUserIDForUpdate_Call struct {
+	*mock.Call
+}
+
+// FindByUserIDForUpdate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *MockIUserDAO_Expecter) FindByUserIDForUpdate(ctx interface{}, userID interface{}) *MockIUserDAO_FindByUserIDForUpdate_Call {
+	return &MockIUserDAO_FindByUserIDForUpdate_Call{Call: _e.mock.On("FindByUserIDForUpdate", ctx, userID)}
+}
+
+func (_c *MockIUserDAO_FindByUserIDForUpdate_Call) Run(run func(ctx context.Context, userID string)) *MockIUserDAO_FindByUserIDForUpdate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByUserIDForUpdate_Call) Return(user *storage.User, err error) *MockIUserDAO_FindByUserIDForUpdate_Call {
+	_c.Call.Return(user, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindByUserIDForUpdate_Call) RunAndReturn(run func(ctx context.Context, userID string) (*storage.User, error)) *MockIUserDAO_FindByUserIDForUpdate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserAccount provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindUserAccount(ctx context.Context, userID string, accountID string) (*storage.UserAccount, error) {
+	ret := _mock.Called(ctx, userID, accountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserAccount")
+	}
+
+	var r0 *storage.UserAccount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*storage.UserAccount, error)); ok {
+		return returnFunc(ctx, userID, accountID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *storage.UserAccount); ok {
+		r0 = returnFunc(ctx, userID, accountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*storage.UserAccount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, userID, accountID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindUserAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserAccount'
+type MockIUserDAO_FindUserAccount_Call struct {
+	*mock.Call
+}
+
+// FindUserAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+//   - accountID string
+func (_e *MockIUserDAO_Expecter) FindUserAccount(ctx interface{}, userID interface{}, accountID interface{}) *MockIUserDAO_FindUserAccount_Call {
+	return &MockIUserDAO_FindUserAccount_Call{Call: _e.mock.On("FindUserAccount", ctx, userID, accountID)}
+}
+
+func (_c *MockIUserDAO_FindUserAccount_Call) Run(run func(ctx context.Context, userID string, accountID string)) *MockIUserDAO_FindUserAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindUserAccount_Call) Return(userAccount *storage.UserAccount, err error) *MockIUserDAO_FindUserAccount_Call {
+	_c.Call.Return(userAccount, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindUserAccount_Call) RunAndReturn(run func(ctx context.Context, userID string, accountID string) (*storage.UserAccount, error)) *MockIUserDAO_FindUserAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindUserAccounts provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) FindUserAccounts(ctx context.Context, userID string) ([]*storage.UserAccount, error) {
+	ret := _mock.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindUserAccounts")
+	}
+
+	var r0 []*storage.UserAccount
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) ([]*storage.UserAccount, error)); ok {
+		return returnFunc(ctx, userID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) []*storage.UserAccount); ok {
+		r0 = returnFunc(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*storage.UserAccount)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIUserDAO_FindUserAccounts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindUserAccounts'
+type MockIUserDAO_FindUserAccounts_Call struct {
+	*mock.Call
+}
+
+// FindUserAccounts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID string
+func (_e *MockIUserDAO_Expecter) FindUserAccounts(ctx interface{}, userID interface{}) *MockIUserDAO_FindUserAccounts_Call {
+	return &MockIUserDAO_FindUserAccounts_Call{Call: _e.mock.On("FindUserAccounts", ctx, userID)}
+}
+
+func (_c *MockIUserDAO_FindUserAccounts_Call) Run(run func(ctx context.Context, userID string)) *MockIUserDAO_FindUserAccounts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_FindUserAccounts_Call) Return(userAccounts []*storage.UserAccount, err error) *MockIUserDAO_FindUserAccounts_Call {
+	_c.Call.Return(userAccounts, err)
+	return _c
+}
+
+func (_c *MockIUserDAO_FindUserAccounts_Call) RunAndReturn(run func(ctx context.Context, userID string) ([]*storage.UserAccount, error)) *MockIUserDAO_FindUserAccounts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RunInTransaction provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) RunInTransaction(fn storage.TxFn) error {
+	ret := _mock.Called(fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunInTransaction")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(storage.TxFn) error); ok {
+		r0 = returnFunc(fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserDAO_RunInTransaction_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RunInTransaction'
+type MockIUserDAO_RunInTransaction_Call struct {
+	*mock.Call
+}
+
+// RunInTransaction is a helper method to define mock.On call
+//   - fn storage.TxFn
+func (_e *MockIUserDAO_Expecter) RunInTransaction(fn interface{}) *MockIUserDAO_RunInTransaction_Call {
+	return &MockIUserDAO_RunInTransaction_Call{Call: _e.mock.On("RunInTransaction", fn)}
+}
+
+func (_c *MockIUserDAO_RunInTransaction_Call) Run(run func(fn storage.TxFn)) *MockIUserDAO_RunInTransaction_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 storage.TxFn
+		if args[0] != nil {
+			arg0 = args[0].(storage.TxFn)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_RunInTransaction_Call) Return(err error) *MockIUserDAO_RunInTransaction_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserDAO_RunInTransaction_Call) RunAndReturn(run func(fn storage.TxFn) error) *MockIUserDAO_RunInTransaction_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) Save(ctx context.Context, user *storage.User) error {
+	ret := _mock.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.User) error); ok {
+		r0 = returnFunc(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserDAO_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockIUserDAO_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *storage.User
+func (_e *MockIUserDAO_Expecter) Save(ctx interface{}, user interface{}) *MockIUserDAO_Save_Call {
+	return &MockIUserDAO_Save_Call{Call: _e.mock.On("Save", ctx, user)}
+}
+
+func (_c *MockIUserDAO_Save_Call) Run(run func(ctx context.Context, user *storage.User)) *MockIUserDAO_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.User
+		if args[1] != nil {
+			arg1 = args[1].(*storage.User)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_Save_Call) Return(err error) *MockIUserDAO_Save_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserDAO_Save_Call) RunAndReturn(run func(ctx context.Context, user *storage.User) error) *MockIUserDAO_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveUserAccount provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) SaveUserAccount(ctx context.Context, link *storage.UserAccount) error {
+	ret := _mock.Called(ctx, link)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveUserAccount")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *storage.UserAccount) error); ok {
+		r0 = returnFunc(ctx, link)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIUserDAO_SaveUserAccount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveUserAccount'
+type MockIUserDAO_SaveUserAccount_Call struct {
+	*mock.Call
+}
+
+// SaveUserAccount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - link *storage.UserAccount
+func (_e *MockIUserDAO_Expecter) SaveUserAccount(ctx interface{}, link interface{}) *MockIUserDAO_SaveUserAccount_Call {
+	return &MockIUserDAO_SaveUserAccount_Call{Call: _e.mock.On("SaveUserAccount", ctx, link)}
+}
+
+func (_c *MockIUserDAO_SaveUserAccount_Call) Run(run func(ctx context.Context, link *storage.UserAccount)) *MockIUserDAO_SaveUserAccount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *storage.UserAccount
+		if args[1] != nil {
+			arg1 = args[1].(*storage.UserAccount)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_SaveUserAccount_Call) Return(err error) *MockIUserDAO_SaveUserAccount_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIUserDAO_SaveUserAccount_Call) RunAndReturn(run func(ctx context.Context, link *storage.UserAccount) error) *MockIUserDAO_SaveUserAccount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// WithTx provides a mock function for the type MockIUserDAO
+func (_mock *MockIUserDAO) WithTx(tx *gorm.DB) storage.IUserDAO {
+	ret := _mock.Called(tx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithTx")
+	}
+
+	var r0 storage.IUserDAO
+	if returnFunc, ok := ret.Get(0).(func(*gorm.DB) storage.IUserDAO); ok {
+		r0 = returnFunc(tx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(storage.IUserDAO)
+		}
+	}
+	return r0
+}
+
+// MockIUserDAO_WithTx_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'WithTx'
+type MockIUserDAO_WithTx_Call struct {
+	*mock.Call
+}
+
+// WithTx is a helper method to define mock.On call
+//   - tx *gorm.DB
+func (_e *MockIUserDAO_Expecter) WithTx(tx interface{}) *MockIUserDAO_WithTx_Call {
+	return &MockIUserDAO_WithTx_Call{Call: _e.mock.On("WithTx", tx)}
+}
+
+func (_c *MockIUserDAO_WithTx_Call) Run(run func(tx *gorm.DB)) *MockIUserDAO_WithTx_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 *gorm.DB
+		if args[0] != nil {
+			arg0 = args[0].(*gorm.DB)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIUserDAO_WithTx_Call) Return(iUserDAO storage.IUserDAO) *MockIUserDAO_WithTx_Call {
+	_c.Call.Return(iUserDAO)
+	return _c
+}
+
+func (_c *MockIUserDAO_WithTx_Call) RunAndReturn(run func(tx *gorm.DB) storage.IUserDAO) *MockIUserDAO_WithTx_Call {
 	_c.Call.Return(run)
 	return _c
 }
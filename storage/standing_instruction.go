@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	StandingInstructionStatusActive    = "ACTIVE"
+	StandingInstructionStatusSuspended = "SUSPENDED"
+	StandingInstructionStatusCancelled = "CANCELLED"
+	StandingInstructionStatusCompleted = "COMPLETED"
+)
+
+// StandingInstruction repeatedly transfers Amount from SourceAccountID to DestinationAccountID on the
+// occurrences of Schedule, an RRULE, from StartAt until EndAt or MaxOccurrences is reached.
+type StandingInstruction struct {
+	ID                   int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	InstructionID        string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_standing_instruction_id" json:"instruction_id"`
+	ClientID             string     `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_standing_instruction_client_reference_id,priority:1" json:"client_id"`
+	ReferenceID          string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_standing_instruction_client_reference_id,priority:2" json:"reference_id"` // idempotency key of the request that created the instruction
+	UserID               string     `gorm:"type:varchar(36)" json:"user_id,omitempty"`                                                                         // user the transfers are made for, empty for partner instructions
+	SourceAccountID      string     `gorm:"type:varchar(64);not null;index" json:"source_account_id"`
+	DestinationAccountID string     `gorm:"type:varchar(64);not null" json:"destination_account_id"`
+	Currency             string     `gorm:"type:char(3);not null" json:"currency"`
+	Amount               int64      `gorm:"not null" json:"amount"` // in minor unit
+	Note                 string     `gorm:"type:varchar(255);not null;default:''" json:"note"`
+	Schedule             string     `gorm:"type:varchar(255);not null" json:"schedule"`
+	StartAt              time.Time  `gorm:"not null" json:"start_at"`
+	EndAt                *time.Time `json:"end_at"`
+	MaxOccurrences       int        `gorm:"not null;default:0" json:"max_occurrences"` // zero for no maximum
+	FailurePolicy        string     `gorm:"type:varchar(16);not null" json:"failure_policy"`
+	MaxRetries           int        `gorm:"not null;default:0" json:"max_retries"`
+	Status               string     `gorm:"type:varchar(16);not null" json:"status"`
+	NextOccurrenceAt     *time.Time `json:"next_occurrence_at"`                   // date of the next occurrence, nil once there is none left
+	NextRunAt            *time.Time `json:"next_run_at"`                          // when the next occurrence is run, later than its date while retrying
+	Occurrence           int        `gorm:"not null;default:0" json:"occurrence"` // number of occurrences already run, paid or not
+	Attempt              int        `gorm:"not null;default:0" json:"attempt"`    // failed attempts of the next occurrence
+	FailedCount          int        `gorm:"not null;default:0" json:"failed_count"`
+	LastTransactionID    string     `gorm:"type:varchar(36);not null;default:''" json:"last_transaction_id"`
+	LastError            string     `gorm:"type:varchar(64);not null;default:''" json:"last_error"`
+	LockedUntil          *time.Time `json:"locked_until"` // set while an instance runs the next occurrence
+	CreatedAt            time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt            time.Time  `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// standingInstructionDAO handles DB operations for standing instructions
+type standingInstructionDAO struct {
+	DB *gorm.DB
+}
+
+type IStandingInstructionDAO interface {
+	Create(ctx context.Context, si *StandingInstruction) error
+	Save(ctx context.Context, si *StandingInstruction) error
+	FindByInstructionID(ctx context.Context, instructionID string) (*StandingInstruction, error)
+	FindByInstructionIDForUpdate(ctx context.Context, instructionID string) (*StandingInstruction, error)
+	FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*StandingInstruction, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*StandingInstruction, error)
+	RunInTransaction(fn TxFn) error
+	WithTx(tx *gorm.DB) IStandingInstructionDAO
+}
+
+func NewStandingInstructionDAO(db *gorm.DB) IStandingInstructionDAO {
+	return &standingInstructionDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *standingInstructionDAO) WithTx(tx *gorm.DB) IStandingInstructionDAO {
+	return &standingInstructionDAO{DB: tx}
+}
+
+// RunInTransaction runs fn inside a database transaction
+func (dao *standingInstructionDAO) RunInTransaction(fn TxFn) error {
+	return dao.DB.Transaction(fn)
+}
+
+func (dao *standingInstructionDAO) Create(ctx context.Context, si *StandingInstruction) error {
+	return dao.DB.WithContext(ctx).Create(si).Error
+}
+
+func (dao *standingInstructionDAO) Save(ctx context.Context, si *StandingInstruction) error {
+	return dao.DB.WithContext(ctx).Save(si).Error
+}
+
+func (dao *standingInstructionDAO) FindByInstructionID(ctx context.Context, instructionID string) (*StandingInstruction, error) {
+	var si StandingInstruction
+	err := dao.DB.WithContext(ctx).
+		Where("instruction_id = ?", instructionID).
+		First(&si).Error
+	if err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+// FindByInstructionIDForUpdate reads the instruction with SELECT ... FOR UPDATE. It must be called on a DAO
+// bound with WithTx.
+func (dao *standingInstructionDAO) FindByInstructionIDForUpdate(ctx context.Context, instructionID string) (*StandingInstruction, error) {
+	var si StandingInstruction
+	err := dao.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("instruction_id = ?", instructionID).
+		First(&si).Error
+	if err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+// FindByReferenceID finds the instruction created with idempotency key referenceID by client clientID
+func (dao *standingInstructionDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*StandingInstruction, error) {
+	var si StandingInstruction
+	err := dao.DB.WithContext(ctx).
+		Where("client_id = ? AND reference_id = ?", clientID, referenceID).
+		First(&si).Error
+	if err != nil {
+		return nil, err
+	}
+	return &si, nil
+}
+
+// ClaimDue leases up to limit active instructions due at now for lease and returns them, oldest due first.
+// Instructions leased by another instance are skipped until their lease runs out, so an instance that
+// crashed while running one only delays it.
+func (dao *standingInstructionDAO) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*StandingInstruction, error) {
+	var claimed []*StandingInstruction
+	err := dao.DB.WithContext(ctx).Raw(`
+		UPDATE standing_instruction SET locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM standing_instruction
+			WHERE status = ? AND next_run_at <= ? AND (locked_until IS NULL OR locked_until < ?)
+			ORDER BY next_run_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, StandingInstructionStatusActive, now, now, limit).
+		Scan(&claimed).Error
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
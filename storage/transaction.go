@@ -1,13 +1,27 @@
 package storage
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"gorm.io/gorm"
 	"time"
 )
 
+const (
+	TransactionTypeDebit  = "debit"
+	TransactionTypeCredit = "credit"
+)
+
+var UnbalancedJournalErr = errors.New("unbalanced journal")
+
+// Transaction is a single ledger leg. Every leg belongs to exactly one journal, identified by the
+// transaction_id of the transfer that owns it.
 type Transaction struct {
 	ID         int64           `gorm:"primaryKey;autoIncrement" json:"id"`
+	JournalID  string          `gorm:"type:varchar(36);not null;index" json:"journal_id"`
+	LegNo      int             `gorm:"not null;default:0" json:"leg_no"`
 	AccountID  string          `gorm:"type:varchar(64);not null" json:"account_id"`
 	Type       string          `gorm:"type:varchar(10);not null;check:type IN ('credit','debit')" json:"type"`
 	Amount     int64           `gorm:"not null;check:amount >= 0" json:"amount"`
@@ -20,15 +34,170 @@ type Transaction struct {
 	Properties json.RawMessage `gorm:"type:jsonb;default:'{}'" json:"properties"`
 }
 
+// LedgerEntry is a ledger leg together with the account balance right after it was booked.
+type LedgerEntry struct {
+	Transaction
+	RunningBalance int64 `gorm:"->;column:running_balance" json:"running_balance"`
+}
+
+// LedgerFilter narrows down the ledger of a single account. Results are ordered newest first;
+// BeforeTimestamp and BeforeID form the keyset cursor of the last entry already returned.
+type LedgerFilter struct {
+	AccountID       string
+	Type            string
+	Currency        string
+	From            *time.Time
+	To              *time.Time
+	BeforeTimestamp *time.Time
+	BeforeID        int64
+	Limit           int
+}
+
+// AccountLedgerBalance puts the stored balance of an account next to the balance derived from its legs.
+type AccountLedgerBalance struct {
+	ID            uint64 `json:"id"`
+	AccountID     string `json:"account_id"`
+	Currency      string `json:"currency"`
+	Balance       int64  `json:"balance"`
+	LedgerBalance int64  `json:"ledger_balance"`
+}
+
 // TransactionDAO handles DB operations for transactions
 type TransactionDAO struct {
 	DB *gorm.DB
 }
 
-// todo add mockery
 type ITransactionDAO interface {
+	CreateJournal(ctx context.Context, legs []*Transaction) error
+	FindByJournalID(ctx context.Context, journalID string) ([]*Transaction, error)
+	FindLedgerByAccountID(ctx context.Context, filter *LedgerFilter) ([]*LedgerEntry, error)
+	SumLedgerByAccount(ctx context.Context, afterID uint64, limit int) ([]*AccountLedgerBalance, error)
+	WithTx(tx *gorm.DB) ITransactionDAO
 }
 
 func NewTransactionDAO(db *gorm.DB) ITransactionDAO {
 	return &TransactionDAO{DB: db}
 }
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *TransactionDAO) WithTx(tx *gorm.DB) ITransactionDAO {
+	return &TransactionDAO{DB: tx}
+}
+
+// CreateJournal inserts all legs of a journal, refusing to write anything unless the legs balance.
+func (dao *TransactionDAO) CreateJournal(ctx context.Context, legs []*Transaction) error {
+	if err := ValidateJournal(legs); err != nil {
+		return err
+	}
+	for i, leg := range legs {
+		leg.LegNo = i + 1
+	}
+	return dao.DB.WithContext(ctx).Create(&legs).Error
+}
+
+func (dao *TransactionDAO) FindByJournalID(ctx context.Context, journalID string) ([]*Transaction, error) {
+	var legs []*Transaction
+	err := dao.DB.WithContext(ctx).
+		Where("journal_id = ?", journalID).
+		Order("leg_no ASC, id ASC").
+		Find(&legs).Error
+	if err != nil {
+		return nil, err
+	}
+	return legs, nil
+}
+
+// FindLedgerByAccountID lists the legs of one account with a running balance. The running balance is
+// computed over the whole account history before any filter is applied, so it stays correct for
+// filtered and paginated views.
+func (dao *TransactionDAO) FindLedgerByAccountID(ctx context.Context, filter *LedgerFilter) ([]*LedgerEntry, error) {
+	ledger := dao.DB.WithContext(ctx).
+		Model(&Transaction{}).
+		Select("*, SUM(CASE WHEN type = ? THEN amount ELSE -amount END) "+
+			"OVER (PARTITION BY currency ORDER BY created_at, id) AS running_balance", TransactionTypeCredit).
+		Where("account_id = ?", filter.AccountID)
+
+	query := dao.DB.WithContext(ctx).Table("(?) AS ledger", ledger)
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if filter.Currency != "" {
+		query = query.Where("currency = ?", filter.Currency)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	// Only fetch older entries if cursor exists
+	if filter.BeforeTimestamp != nil {
+		query = query.Where("(created_at, id) < (?, ?)", *filter.BeforeTimestamp, filter.BeforeID)
+	}
+
+	var entries []*LedgerEntry
+	err := query.
+		Order("created_at DESC, id DESC").
+		Limit(filter.Limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SumLedgerByAccount returns up to limit accounts with an id greater than afterID, each with its stored
+// balance and the sum of its legs. Both are read by the same statement so they come from one snapshot.
+func (dao *TransactionDAO) SumLedgerByAccount(ctx context.Context, afterID uint64, limit int) ([]*AccountLedgerBalance, error) {
+	var balances []*AccountLedgerBalance
+	err := dao.DB.WithContext(ctx).
+		Table("account").
+		Select("account.id, account.account_id, account.currency, account.balance, "+
+			"COALESCE(SUM(CASE WHEN transaction.type = ? THEN transaction.amount ELSE -transaction.amount END), 0) AS ledger_balance",
+			TransactionTypeCredit).
+		Joins("LEFT JOIN transaction ON transaction.account_id = account.account_id").
+		Where("account.id > ?", afterID).
+		Group("account.id").
+		Order("account.id ASC").
+		Limit(limit).
+		Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+	return balances, nil
+}
+
+// ValidateJournal checks that legs share one journal and that debits equal credits in every currency.
+func ValidateJournal(legs []*Transaction) error {
+	if len(legs) < 2 {
+		return fmt.Errorf("%w: a journal needs at least 2 legs", UnbalancedJournalErr)
+	}
+	journalID := legs[0].JournalID
+	if journalID == "" {
+		return fmt.Errorf("%w: missing journal id", UnbalancedJournalErr)
+	}
+
+	sums := map[string]int64{}
+	for _, leg := range legs {
+		if leg.JournalID != journalID {
+			return fmt.Errorf("%w: legs belong to different journals", UnbalancedJournalErr)
+		}
+		if leg.Amount <= 0 {
+			return fmt.Errorf("%w: leg amount must be positive", UnbalancedJournalErr)
+		}
+		switch leg.Type {
+		case TransactionTypeCredit:
+			sums[leg.Currency] += leg.Amount
+		case TransactionTypeDebit:
+			sums[leg.Currency] -= leg.Amount
+		default:
+			return fmt.Errorf("%w: unknown leg type %q", UnbalancedJournalErr, leg.Type)
+		}
+	}
+	for currency, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("%w: %s legs off by %d", UnbalancedJournalErr, currency, sum)
+		}
+	}
+	return nil
+}
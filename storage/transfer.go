@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"time"
 )
 
@@ -12,11 +14,22 @@ type Transfer struct {
 	ID                      int64           `gorm:"primaryKey;autoIncrement" json:"id"`
 	Type                    string          `gorm:"type:varchar(36);not null;default:''" json:"type"`
 	TxType                  string          `gorm:"type:varchar(36);not null;default:''" json:"tx_type"`
+	UserID                  string          `gorm:"type:varchar(36)" json:"user_id,omitempty"` // user who initiated the transfer, empty for partner and system transfers
 	TransactionID           string          `gorm:"type:varchar(36);not null;uniqueIndex:uk_transaction_id" json:"transaction_id"`
-	ReferenceID             string          `gorm:"type:varchar(36);not null;uniqueIndex:uk_reference_id" json:"reference_id"`
+	ReferenceID             string          `gorm:"type:varchar(36);not null;default:'';uniqueIndex:uk_client_reference_id,priority:2,where:reference_id <> ''" json:"reference_id"`
+	ClientID                string          `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_client_reference_id,priority:1" json:"client_id"`
+	RequestHash             string          `gorm:"type:varchar(64);not null;default:''" json:"request_hash"`
 	Status                  string          `gorm:"type:varchar(36);not null;default:''" json:"status"`
 	Amount                  int64           `gorm:"not null" json:"amount"`
 	Currency                string          `gorm:"type:varchar(3);not null;default:''" json:"currency"`
+	FxQuoteID               string          `gorm:"type:varchar(36);not null;default:''" json:"fx_quote_id,omitempty"`
+	HoldID                  string          `gorm:"type:varchar(36);not null;default:''" json:"hold_id,omitempty"`               // hold a capture settles
+	ParentTransactionID     string          `gorm:"type:varchar(36);not null;default:''" json:"parent_transaction_id,omitempty"` // transfer a reversal refunds
+	RefundedAmount          int64           `gorm:"not null;default:0" json:"refunded_amount"`                                   // refunded by reversals so far, in minor unit
+	DestinationAmount       int64           `gorm:"not null;default:0" json:"destination_amount,omitempty"`                      // set on cross-currency transfers
+	DestinationCurrency     string          `gorm:"type:varchar(3);not null;default:''" json:"destination_currency,omitempty"`   // set on cross-currency transfers
+	FeeAmount               int64           `gorm:"not null;default:0" json:"fee_amount"`                                        // total fees in minor unit of Currency
+	Fees                    json.RawMessage `gorm:"type:jsonb" json:"fees,omitempty"`                                            // fee breakdown
 	SourceAccountID         string          `gorm:"type:varchar(36)" json:"source_account_id,omitempty"`
 	SourceAccount           json.RawMessage `gorm:"type:jsonb;not null;default:'{}'" json:"source_account"`
 	DestinationAccountID    string          `gorm:"type:varchar(36)" json:"destination_account_id,omitempty"`
@@ -26,31 +39,87 @@ type Transfer struct {
 	Note                    string          `gorm:"type:varchar(255);not null;default:''" json:"note"`
 	Properties              json.RawMessage `gorm:"type:jsonb;default:'{}'" json:"properties"`
 	CreatedAt               time.Time       `gorm:"not null;default:now()" json:"created_at"`
+	ExecuteAt               *time.Time      `json:"execute_at,omitempty"`    // set on scheduled transfers, when they are due
+	ClaimedUntil            *time.Time      `json:"claimed_until,omitempty"` // while PROCESSING, until when the instance executing it holds it
 	ValuedAt                *time.Time      `json:"valued_at,omitempty"`
 	UpdatedAt               time.Time       `gorm:"not null;default:now()" json:"updated_at"`
 }
 
 type TxFn func(tx *gorm.DB) error
 
+var ClaimLostErr = errors.New("transfer claim lost")
+
+// TransferUsage is the number and total amount of a set of transfers
+type TransferUsage struct {
+	Count  int64
+	Amount int64
+}
+
 // TransferDAO handles DB operations for transfer
 type transferDAO struct {
 	DB *gorm.DB
 }
 
 type ITransferDAO interface {
-	FindByReferenceID(ctx context.Context, referenceID string) (*Transfer, error)
+	Create(ctx context.Context, transfer *Transfer) error
+	Save(ctx context.Context, transfer *Transfer) error
+	SaveClaimed(ctx context.Context, transfer *Transfer, claimedUntil *time.Time) error
+	FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*Transfer, error)
+	FindByTransactionID(ctx context.Context, transactionID string) (*Transfer, error)
+	FindByTransactionIDForUpdate(ctx context.Context, transactionID string) (*Transfer, error)
+	ReleaseReferenceIDs(ctx context.Context, createdBefore time.Time) (int64, error)
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Transfer, error)
 	FindByAccountIDWithCursor(ctx context.Context, accountID string, limit int, beforeTimestamp *time.Time) ([]*Transfer, error)
+	SumCompletedBySourceAccount(ctx context.Context, accountID string, txType string, since time.Time) (*TransferUsage, error)
+	SumCompletedByDestinationAccount(ctx context.Context, accountID string, txType string, since time.Time) (*TransferUsage, error)
 	RunInTransaction(fn TxFn, opts ...*sql.TxOptions) error
+	WithTx(tx *gorm.DB) ITransferDAO
 }
 
 func NewTransferDAO(db *gorm.DB) ITransferDAO {
 	return &transferDAO{DB: db}
 }
 
-func (t *transferDAO) FindByReferenceID(ctx context.Context, referenceID string) (*Transfer, error) {
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (t *transferDAO) WithTx(tx *gorm.DB) ITransferDAO {
+	return &transferDAO{DB: tx}
+}
+
+func (t *transferDAO) Create(ctx context.Context, transfer *Transfer) error {
+	return t.DB.WithContext(ctx).Create(transfer).Error
+}
+
+func (t *transferDAO) Save(ctx context.Context, transfer *Transfer) error {
+	return t.DB.WithContext(ctx).Save(transfer).Error
+}
+
+// SaveClaimed saves transfer if it is still PROCESSING under the claim that ends at claimedUntil. Once a
+// claim has expired the transfer may have been claimed again, and finished, by another instance; then
+// nothing is saved and ClaimLostErr is returned. transfer must have been created, so only its own row is
+// updated.
+func (t *transferDAO) SaveClaimed(ctx context.Context, transfer *Transfer, claimedUntil *time.Time) error {
+	if transfer.ID == 0 {
+		return errors.New("transfer has not been created")
+	}
+	res := t.DB.WithContext(ctx).
+		Model(transfer).
+		Where("id = ? AND status = ? AND claimed_until IS NOT DISTINCT FROM ?", transfer.ID, "PROCESSING", claimedUntil).
+		Select("*").
+		Updates(transfer)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected <= 0 {
+		return ClaimLostErr
+	}
+	return nil
+}
+
+// FindByReferenceID finds the transfer created with idempotency key referenceID by client clientID
+func (t *transferDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*Transfer, error) {
 	var transfer Transfer
 	err := t.DB.WithContext(ctx).
-		Where("reference_id = ?", referenceID).
+		Where("client_id = ? AND reference_id = ?", clientID, referenceID).
 		First(&transfer).Error
 	if err != nil {
 		return nil, err
@@ -58,6 +127,69 @@ func (t *transferDAO) FindByReferenceID(ctx context.Context, referenceID string)
 	return &transfer, nil
 }
 
+func (t *transferDAO) FindByTransactionID(ctx context.Context, transactionID string) (*Transfer, error) {
+	var transfer Transfer
+	err := t.DB.WithContext(ctx).
+		Where("transaction_id = ?", transactionID).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// FindByTransactionIDForUpdate reads the transfer with SELECT ... FOR UPDATE. It must be called on a DAO bound with WithTx.
+func (t *transferDAO) FindByTransactionIDForUpdate(ctx context.Context, transactionID string) (*Transfer, error) {
+	var transfer Transfer
+	err := t.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("transaction_id = ?", transactionID).
+		First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// finishedStatuses are the statuses of transfers that will not be executed any more
+var finishedStatuses = []string{"COMPLETED", "FAILED", "CANCELLED", "REVERSED", "PARTIALLY_REFUNDED"}
+
+// ReleaseReferenceIDs clears the idempotency key of finished transfers created before createdBefore, so
+// clients may reuse those keys. Transfers still scheduled or processing keep theirs, so a retry cannot make
+// them a second time. It returns the number of transfers released.
+func (t *transferDAO) ReleaseReferenceIDs(ctx context.Context, createdBefore time.Time) (int64, error) {
+	res := t.DB.WithContext(ctx).
+		Model(&Transfer{}).
+		Where("reference_id <> '' AND status IN ? AND created_at < ?", finishedStatuses, createdBefore).
+		Updates(map[string]interface{}{"reference_id": "", "updated_at": time.Now()})
+	return res.RowsAffected, res.Error
+}
+
+// ClaimDue claims up to limit transfers for lease and returns them, moved to PROCESSING, oldest first: the
+// scheduled transfers due at now, and the PROCESSING transfers whose claim expired before now, left by an
+// instance that crashed or lost the database half way through. Rows locked by another instance, e.g. one
+// claiming or cancelling them, are skipped, so every transfer is claimed by one instance at a time.
+func (t *transferDAO) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*Transfer, error) {
+	var claimed []*Transfer
+	err := t.DB.WithContext(ctx).Raw(`
+		UPDATE transfer SET status = ?, claimed_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM transfer
+			WHERE (status = ? AND execute_at <= ?) OR (status = ? AND claimed_until < ?)
+			ORDER BY COALESCE(claimed_until, execute_at)
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, "PROCESSING", now.Add(lease), now, "SCHEDULED", now, "PROCESSING", now, limit).
+		Scan(&claimed).Error
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// RunInTransaction runs fn inside a database transaction. DAOs used by fn must be bound to the
+// transaction with WithTx, otherwise their writes commit independently of it.
 func (t *transferDAO) RunInTransaction(fn TxFn, opts ...*sql.TxOptions) error {
 	return t.DB.Transaction(fn, opts...)
 }
@@ -89,3 +221,34 @@ func (t *transferDAO) FindByAccountIDWithCursor(
 	}
 	return txs, nil
 }
+
+// settledStatuses are the statuses of transfers that moved funds. Refunded transfers still count, their
+// refunds are transfers of their own.
+var settledStatuses = []string{"COMPLETED", "PARTIALLY_REFUNDED", "REVERSED"}
+
+// SumCompletedBySourceAccount returns the settled transfers of txType out of accountID that took effect at or
+// after since
+func (t *transferDAO) SumCompletedBySourceAccount(ctx context.Context, accountID string, txType string, since time.Time) (*TransferUsage, error) {
+	return t.sumCompleted(ctx, "source_account_id", accountID, txType, since)
+}
+
+// SumCompletedByDestinationAccount returns the settled transfers of txType into accountID that took effect at
+// or after since
+func (t *transferDAO) SumCompletedByDestinationAccount(ctx context.Context, accountID string, txType string, since time.Time) (*TransferUsage, error) {
+	return t.sumCompleted(ctx, "destination_account_id", accountID, txType, since)
+}
+
+// sumCompleted counts transfers by when they took effect: a scheduled transfer counts towards the day it
+// was executed, not the day it was submitted
+func (t *transferDAO) sumCompleted(ctx context.Context, accountColumn string, accountID string, txType string, since time.Time) (*TransferUsage, error) {
+	var usage TransferUsage
+	err := t.DB.WithContext(ctx).
+		Model(&Transfer{}).
+		Select("COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where(accountColumn+" = ? AND tx_type = ? AND status IN ? AND COALESCE(valued_at, created_at) >= ?", accountID, txType, settledStatuses, since).
+		Scan(&usage).Error
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	BatchModeIndependent = "INDEPENDENT"
+	BatchModeAtomic      = "ATOMIC"
+
+	BatchStatusPending            = "PENDING"
+	BatchStatusProcessing         = "PROCESSING"
+	BatchStatusCompleted          = "COMPLETED"
+	BatchStatusPartiallyCompleted = "PARTIALLY_COMPLETED"
+	BatchStatusFailed             = "FAILED"
+
+	BatchItemStatusPending   = "PENDING"
+	BatchItemStatusCompleted = "COMPLETED"
+	BatchItemStatusFailed    = "FAILED"
+)
+
+// TransferBatch is a list of transfers submitted together. In ATOMIC mode they are all made in one database
+// transaction; in INDEPENDENT mode each succeeds or fails on its own.
+type TransferBatch struct {
+	ID             int64      `gorm:"primaryKey;autoIncrement" json:"id"`
+	BatchID        string     `gorm:"type:varchar(36);not null;uniqueIndex:uk_transfer_batch_id" json:"batch_id"`
+	ClientID       string     `gorm:"type:varchar(64);not null;default:'';uniqueIndex:uk_transfer_batch_client_reference_id,priority:1" json:"client_id"`
+	ReferenceID    string     `gorm:"type:varchar(255);not null;uniqueIndex:uk_transfer_batch_client_reference_id,priority:2" json:"reference_id"` // idempotency key of the request that submitted the batch
+	RequestHash    string     `gorm:"type:varchar(64);not null" json:"request_hash"`                                                               // fingerprint of the submitted items
+	UserID         string     `gorm:"type:varchar(36)" json:"user_id,omitempty"`                                                                   // user the transfers are made for, empty for partner batches
+	Mode           string     `gorm:"type:varchar(16);not null" json:"mode"`
+	Status         string     `gorm:"type:varchar(24);not null" json:"status"`
+	ItemCount      int        `gorm:"not null" json:"item_count"`
+	CompletedCount int        `gorm:"not null;default:0" json:"completed_count"`
+	FailedCount    int        `gorm:"not null;default:0" json:"failed_count"`
+	LockedUntil    *time.Time `json:"locked_until"` // set while an instance processes the batch
+	CreatedAt      time.Time  `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"not null;default:now()" json:"updated_at"`
+	FinishedAt     *time.Time `json:"finished_at"`
+}
+
+// TransferBatchItem is one transfer of a batch, with its outcome once processed
+type TransferBatchItem struct {
+	ID                   int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	BatchID              string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_transfer_batch_item,priority:1" json:"batch_id"`
+	ItemIndex            int       `gorm:"not null;uniqueIndex:uk_transfer_batch_item,priority:2" json:"item_index"` // position in the submitted list, from 0
+	Reference            string    `gorm:"type:varchar(255);not null;default:''" json:"reference"`                   // the client's own reference, e.g. an employee ID
+	SourceAccountID      string    `gorm:"type:varchar(64);not null" json:"source_account_id"`
+	DestinationAccountID string    `gorm:"type:varchar(64);not null" json:"destination_account_id"`
+	Currency             string    `gorm:"type:char(3);not null" json:"currency"`
+	Amount               int64     `gorm:"not null" json:"amount"` // in minor unit
+	Note                 string    `gorm:"type:varchar(255);not null;default:''" json:"note"`
+	Status               string    `gorm:"type:varchar(16);not null" json:"status"`
+	StatusReason         string    `gorm:"type:varchar(64);not null;default:''" json:"status_reason"`
+	TransactionID        string    `gorm:"type:varchar(36);not null;default:''" json:"transaction_id"`
+	CreatedAt            time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt            time.Time `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// BatchItemFilter narrows down the items of a batch. Results are ordered by index; AfterIndex is the index
+// of the last item already returned, -1 to start from the first.
+type BatchItemFilter struct {
+	BatchID    string
+	Status     string
+	AfterIndex int
+	Limit      int
+}
+
+// transferBatchDAO handles DB operations for transfer batches and their items
+type transferBatchDAO struct {
+	DB *gorm.DB
+}
+
+type ITransferBatchDAO interface {
+	Create(ctx context.Context, batch *TransferBatch, items []*TransferBatchItem) error
+	Save(ctx context.Context, batch *TransferBatch) error
+	SaveItem(ctx context.Context, item *TransferBatchItem) error
+	FindByBatchID(ctx context.Context, batchID string) (*TransferBatch, error)
+	FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*TransferBatch, error)
+	FindItems(ctx context.Context, filter *BatchItemFilter) ([]*TransferBatchItem, error)
+	CountItemsByStatus(ctx context.Context, batchID string) (map[string]int, error)
+	ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*TransferBatch, error)
+	RenewLease(ctx context.Context, batchID string, until time.Time) error
+	WithTx(tx *gorm.DB) ITransferBatchDAO
+}
+
+func NewTransferBatchDAO(db *gorm.DB) ITransferBatchDAO {
+	return &transferBatchDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *transferBatchDAO) WithTx(tx *gorm.DB) ITransferBatchDAO {
+	return &transferBatchDAO{DB: tx}
+}
+
+// Create stores batch and its items in one transaction
+func (dao *transferBatchDAO) Create(ctx context.Context, batch *TransferBatch, items []*TransferBatchItem) error {
+	return dao.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(batch).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(items, 500).Error
+	})
+}
+
+func (dao *transferBatchDAO) Save(ctx context.Context, batch *TransferBatch) error {
+	return dao.DB.WithContext(ctx).Save(batch).Error
+}
+
+func (dao *transferBatchDAO) SaveItem(ctx context.Context, item *TransferBatchItem) error {
+	return dao.DB.WithContext(ctx).Save(item).Error
+}
+
+func (dao *transferBatchDAO) FindByBatchID(ctx context.Context, batchID string) (*TransferBatch, error) {
+	var batch TransferBatch
+	err := dao.DB.WithContext(ctx).
+		Where("batch_id = ?", batchID).
+		First(&batch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// FindByReferenceID finds the batch submitted with idempotency key referenceID by client clientID
+func (dao *transferBatchDAO) FindByReferenceID(ctx context.Context, clientID string, referenceID string) (*TransferBatch, error) {
+	var batch TransferBatch
+	err := dao.DB.WithContext(ctx).
+		Where("client_id = ? AND reference_id = ?", clientID, referenceID).
+		First(&batch).Error
+	if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+func (dao *transferBatchDAO) FindItems(ctx context.Context, filter *BatchItemFilter) ([]*TransferBatchItem, error) {
+	var items []*TransferBatchItem
+	query := dao.DB.WithContext(ctx).
+		Where("batch_id = ? AND item_index > ?", filter.BatchID, filter.AfterIndex)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	err := query.
+		Order("item_index ASC").
+		Limit(filter.Limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CountItemsByStatus returns how many items of the batch are in each status
+func (dao *transferBatchDAO) CountItemsByStatus(ctx context.Context, batchID string) (map[string]int, error) {
+	var rows []struct {
+		Status string
+		Count  int
+	}
+	err := dao.DB.WithContext(ctx).
+		Model(&TransferBatchItem{}).
+		Select("status, COUNT(*) AS count").
+		Where("batch_id = ?", batchID).
+		Group("status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// ClaimPending leases up to limit batches waiting to be processed for lease, moves them to PROCESSING and
+// returns them, oldest first. Batches whose lease expired, because the instance processing them crashed,
+// are claimed again.
+func (dao *transferBatchDAO) ClaimPending(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*TransferBatch, error) {
+	var claimed []*TransferBatch
+	err := dao.DB.WithContext(ctx).Raw(`
+		UPDATE transfer_batch SET status = ?, locked_until = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM transfer_batch
+			WHERE status = ? OR (status = ? AND locked_until < ?)
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, BatchStatusProcessing, now.Add(lease), now, BatchStatusPending, BatchStatusProcessing, now, limit).
+		Scan(&claimed).Error
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
+}
+
+// RenewLease extends the lease of a batch being processed until until
+func (dao *transferBatchDAO) RenewLease(ctx context.Context, batchID string, until time.Time) error {
+	return dao.DB.WithContext(ctx).
+		Model(&TransferBatch{}).
+		Where("batch_id = ?", batchID).
+		Updates(map[string]interface{}{"locked_until": until, "updated_at": time.Now()}).Error
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+const (
+	UserStatusActive = "ACTIVE"
+	// UserStatusSuspended users keep their accounts but cannot move money out of them until made ACTIVE again
+	UserStatusSuspended = "SUSPENDED"
+	// UserStatusClosed users are gone for good
+	UserStatusClosed = "CLOSED"
+)
+
+const (
+	// UserAccountRoleOwner users hold the account and may debit it
+	UserAccountRoleOwner = "OWNER"
+	// UserAccountRoleJoint users hold the account together with its owners and may debit it
+	UserAccountRoleJoint = "JOINT"
+	// UserAccountRoleViewer users may only see the account
+	UserAccountRoleViewer = "VIEWER"
+)
+
+// User is a customer of the wallet. Users reach their accounts through UserAccount links.
+type User struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_id" json:"user_id"`
+	Name      string    `gorm:"type:text;not null" json:"name"`
+	Email     string    `gorm:"type:varchar(255);not null;uniqueIndex:uk_user_email" json:"email"`
+	Phone     string    `gorm:"type:varchar(32);not null;default:''" json:"phone"`
+	Status    string    `gorm:"type:varchar(16);not null;default:ACTIVE" json:"status"`
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// TableName is users, since user is a reserved word in PostgreSQL
+func (User) TableName() string {
+	return "users"
+}
+
+// Active reports whether the user may use their accounts
+func (u *User) Active() bool {
+	return u.Status == UserStatusActive
+}
+
+// UserAccount links a user to an account they hold or may see
+type UserAccount struct {
+	ID        int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string    `gorm:"type:varchar(36);not null;uniqueIndex:uk_user_account,priority:1" json:"user_id"`
+	AccountID string    `gorm:"type:varchar(64);not null;uniqueIndex:uk_user_account,priority:2;index" json:"account_id"`
+	Role      string    `gorm:"type:varchar(16);not null" json:"role"` // OWNER, JOINT or VIEWER
+	CreatedAt time.Time `gorm:"not null;default:now()" json:"created_at"`
+	UpdatedAt time.Time `gorm:"not null;default:now()" json:"updated_at"`
+}
+
+// CanDebit reports whether the role allows moving money out of the account
+func (ua *UserAccount) CanDebit() bool {
+	return ua.Role == UserAccountRoleOwner || ua.Role == UserAccountRoleJoint
+}
+
+// userDAO handles DB operations for users and their account links
+type userDAO struct {
+	DB *gorm.DB
+}
+
+type IUserDAO interface {
+	Create(ctx context.Context, user *User) error
+	Save(ctx context.Context, user *User) error
+	FindByUserID(ctx context.Context, userID string) (*User, error)
+	FindByUserIDForUpdate(ctx context.Context, userID string) (*User, error)
+	FindByEmail(ctx context.Context, email string) (*User, error)
+	SaveUserAccount(ctx context.Context, link *UserAccount) error
+	DeleteUserAccount(ctx context.Context, link *UserAccount) error
+	FindUserAccount(ctx context.Context, userID string, accountID string) (*UserAccount, error)
+	FindUserAccounts(ctx context.Context, userID string) ([]*UserAccount, error)
+	FindAccountUsers(ctx context.Context, accountID string) ([]*UserAccount, error)
+	RunInTransaction(fn TxFn) error
+	WithTx(tx *gorm.DB) IUserDAO
+}
+
+func NewUserDAO(db *gorm.DB) IUserDAO {
+	return &userDAO{DB: db}
+}
+
+// WithTx returns a copy of the DAO bound to the in-flight transaction tx
+func (dao *userDAO) WithTx(tx *gorm.DB) IUserDAO {
+	return &userDAO{DB: tx}
+}
+
+// RunInTransaction runs fn inside a database transaction
+func (dao *userDAO) RunInTransaction(fn TxFn) error {
+	return dao.DB.Transaction(fn)
+}
+
+func (dao *userDAO) Create(ctx context.Context, user *User) error {
+	return dao.DB.WithContext(ctx).Create(user).Error
+}
+
+func (dao *userDAO) Save(ctx context.Context, user *User) error {
+	return dao.DB.WithContext(ctx).Save(user).Error
+}
+
+func (dao *userDAO) FindByUserID(ctx context.Context, userID string) (*User, error) {
+	var user User
+	err := dao.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByUserIDForUpdate reads the user with SELECT ... FOR UPDATE. The row stays locked until the
+// surrounding transaction ends, so it must be called on a DAO bound with WithTx.
+func (dao *userDAO) FindByUserIDForUpdate(ctx context.Context, userID string) (*User, error) {
+	var user User
+	err := dao.DB.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (dao *userDAO) FindByEmail(ctx context.Context, email string) (*User, error) {
+	var user User
+	err := dao.DB.WithContext(ctx).
+		Where("email = ?", email).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SaveUserAccount creates the link, or updates its role if it was loaded from the database
+func (dao *userDAO) SaveUserAccount(ctx context.Context, link *UserAccount) error {
+	return dao.DB.WithContext(ctx).Save(link).Error
+}
+
+func (dao *userDAO) DeleteUserAccount(ctx context.Context, link *UserAccount) error {
+	return dao.DB.WithContext(ctx).
+		Where("user_id = ? AND account_id = ?", link.UserID, link.AccountID).
+		Delete(&UserAccount{}).Error
+}
+
+func (dao *userDAO) FindUserAccount(ctx context.Context, userID string, accountID string) (*UserAccount, error) {
+	var link UserAccount
+	err := dao.DB.WithContext(ctx).
+		Where("user_id = ? AND account_id = ?", userID, accountID).
+		First(&link).Error
+	if err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// FindUserAccounts returns the accounts linked to the user, oldest link first
+func (dao *userDAO) FindUserAccounts(ctx context.Context, userID string) ([]*UserAccount, error) {
+	var links []*UserAccount
+	err := dao.DB.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id ASC").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// FindAccountUsers returns the users linked to the account, oldest link first
+func (dao *userDAO) FindAccountUsers(ctx context.Context, accountID string) ([]*UserAccount, error) {
+	var links []*UserAccount
+	err := dao.DB.WithContext(ctx).
+		Where("account_id = ?", accountID).
+		Order("id ASC").
+		Find(&links).Error
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}